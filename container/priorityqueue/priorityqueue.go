@@ -0,0 +1,255 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package priorityqueue
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/MDGSF/utils/umath"
+)
+
+// Less reports whether a must be popped before b.
+type Less[T any] func(a, b T) bool
+
+// Handle is an item pushed on a PriorityQueue, used to update or remove
+// it later.
+type Handle[T any] struct {
+	value T
+	index int // -1 once popped or removed
+	owner *PriorityQueue[T]
+}
+
+// Value returns the value of h.
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// pqItems implements heap.Interface.
+type pqItems[T any] struct {
+	less  Less[T]
+	items []*Handle[T]
+}
+
+func (p *pqItems[T]) Len() int { return len(p.items) }
+
+func (p *pqItems[T]) Less(i, j int) bool { return p.less(p.items[i].value, p.items[j].value) }
+
+func (p *pqItems[T]) Swap(i, j int) {
+	p.items[i], p.items[j] = p.items[j], p.items[i]
+	p.items[i].index = i
+	p.items[j].index = j
+}
+
+func (p *pqItems[T]) Push(x interface{}) {
+	h := x.(*Handle[T])
+	h.index = len(p.items)
+	p.items = append(p.items, h)
+}
+
+func (p *pqItems[T]) Pop() interface{} {
+	n := len(p.items)
+	h := p.items[n-1]
+	p.items[n-1] = nil
+	p.items = p.items[:n-1]
+	h.index = -1
+	return h
+}
+
+/*
+PriorityQueue is a binary heap popping its items in the order given by
+less. It is not safe for concurrent use, see SyncPriorityQueue.
+
+	pq := priorityqueue.New(func(a, b *Task) bool {
+		return a.Deadline.Before(b.Deadline)
+	})
+	h := pq.Push(task)
+	pq.Update(h, task) // after changing task.Deadline
+	next, ok := pq.Pop()
+*/
+type PriorityQueue[T any] struct {
+	items pqItems[T]
+}
+
+// New returns an empty queue ordered by less.
+func New[T any](less Less[T]) *PriorityQueue[T] {
+	return &PriorityQueue[T]{items: pqItems[T]{less: less}}
+}
+
+// NewMinHeap returns an empty queue popping the smallest value first.
+func NewMinHeap[T umath.Ordered]() *PriorityQueue[T] {
+	return New(func(a, b T) bool { return a < b })
+}
+
+// NewMaxHeap returns an empty queue popping the largest value first.
+func NewMaxHeap[T umath.Ordered]() *PriorityQueue[T] {
+	return New(func(a, b T) bool { return a > b })
+}
+
+// Push adds v to pq in O(log n).
+func (pq *PriorityQueue[T]) Push(v T) *Handle[T] {
+	h := &Handle[T]{value: v, owner: pq}
+	heap.Push(&pq.items, h)
+	return h
+}
+
+// Pop removes and returns the first value of pq in O(log n), ok is false
+// when pq is empty.
+func (pq *PriorityQueue[T]) Pop() (v T, ok bool) {
+	if len(pq.items.items) == 0 {
+		return v, false
+	}
+	return heap.Pop(&pq.items).(*Handle[T]).value, true
+}
+
+// Peek returns the first value of pq without removing it.
+func (pq *PriorityQueue[T]) Peek() (v T, ok bool) {
+	if len(pq.items.items) == 0 {
+		return v, false
+	}
+	return pq.items.items[0].value, true
+}
+
+func (pq *PriorityQueue[T]) contains(h *Handle[T]) bool {
+	return h != nil && h.owner == pq && h.index >= 0
+}
+
+// Update sets the value of h to v and moves it to its new place, it
+// returns false when h is no longer in pq.
+func (pq *PriorityQueue[T]) Update(h *Handle[T], v T) bool {
+	if !pq.contains(h) {
+		return false
+	}
+	h.value = v
+	heap.Fix(&pq.items, h.index)
+	return true
+}
+
+// Remove removes h from pq, it returns false when h is no longer in pq.
+func (pq *PriorityQueue[T]) Remove(h *Handle[T]) bool {
+	if !pq.contains(h) {
+		return false
+	}
+	heap.Remove(&pq.items, h.index)
+	return true
+}
+
+// Len returns the number of items in pq.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items.items)
+}
+
+// Clear removes all the items of pq.
+func (pq *PriorityQueue[T]) Clear() {
+	for _, h := range pq.items.items {
+		h.index = -1
+	}
+	pq.items.items = nil
+}
+
+/*
+TopK returns the k first values of items in the order given by less, in
+O(n log k), e.g. the 10 largest scores:
+
+	priorityqueue.TopK(scores, 10, func(a, b int) bool { return a > b })
+*/
+func TopK[T any](items []T, k int, less Less[T]) []T {
+	if k <= 0 {
+		return nil
+	}
+	// keep the k first items in a heap popping the last of them first
+	pq := New(func(a, b T) bool { return less(b, a) })
+	for _, v := range items {
+		if pq.Len() < k {
+			pq.Push(v)
+		} else if last, _ := pq.Peek(); less(v, last) {
+			pq.Pop()
+			pq.Push(v)
+		}
+	}
+	out := make([]T, pq.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i], _ = pq.Pop()
+	}
+	return out
+}
+
+// SyncPriorityQueue is a PriorityQueue safe for concurrent use.
+type SyncPriorityQueue[T any] struct {
+	lock sync.Mutex
+	pq   *PriorityQueue[T]
+}
+
+// NewSync returns an empty queue ordered by less.
+func NewSync[T any](less Less[T]) *SyncPriorityQueue[T] {
+	return &SyncPriorityQueue[T]{pq: New(less)}
+}
+
+// Push adds v to q.
+func (q *SyncPriorityQueue[T]) Push(v T) *Handle[T] {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pq.Push(v)
+}
+
+// Pop removes and returns the first value of q.
+func (q *SyncPriorityQueue[T]) Pop() (T, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pq.Pop()
+}
+
+// Peek returns the first value of q without removing it.
+func (q *SyncPriorityQueue[T]) Peek() (T, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pq.Peek()
+}
+
+// Update sets the value of h to v, see PriorityQueue.Update.
+func (q *SyncPriorityQueue[T]) Update(h *Handle[T], v T) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pq.Update(h, v)
+}
+
+// Remove removes h from q, see PriorityQueue.Remove.
+func (q *SyncPriorityQueue[T]) Remove(h *Handle[T]) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pq.Remove(h)
+}
+
+// Len returns the number of items in q.
+func (q *SyncPriorityQueue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pq.Len()
+}
+
+// Clear removes all the items of q.
+func (q *SyncPriorityQueue[T]) Clear() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.pq.Clear()
+}
@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package priorityqueue
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinMaxHeap(t *testing.T) {
+	values := rand.Perm(100)
+	min, max := NewMinHeap[int](), NewMaxHeap[int]()
+	for _, v := range values {
+		min.Push(v)
+		max.Push(v)
+	}
+	assert.Equal(t, 100, min.Len(), "they should be equal")
+	for i := 0; i < 100; i++ {
+		v, ok := min.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, i, v, "they should be equal")
+		v, _ = max.Pop()
+		assert.Equal(t, 99-i, v, "they should be equal")
+	}
+	_, ok := min.Pop()
+	assert.False(t, ok)
+	_, ok = min.Peek()
+	assert.False(t, ok)
+}
+
+type task struct {
+	name     string
+	priority int
+}
+
+func TestPriorityQueueUpdateRemove(t *testing.T) {
+	pq := New(func(a, b task) bool { return a.priority > b.priority })
+	hj := pq.Push(task{"huangjian", 1})
+	md := pq.Push(task{"MDGSF", 2})
+	pq.Push(task{"utils", 3})
+
+	v, _ := pq.Peek()
+	assert.Equal(t, "utils", v.name, "they should be equal")
+	assert.True(t, pq.Update(hj, task{"huangjian", 10}))
+	v, _ = pq.Peek()
+	assert.Equal(t, "huangjian", v.name, "they should be equal")
+	assert.Equal(t, 10, hj.Value().priority, "they should be equal")
+
+	assert.True(t, pq.Remove(md))
+	assert.False(t, pq.Remove(md))
+	assert.False(t, pq.Update(md, task{"MDGSF", 5}))
+
+	v, _ = pq.Pop()
+	assert.Equal(t, "huangjian", v.name, "they should be equal")
+	assert.False(t, pq.Remove(hj))
+	v, _ = pq.Pop()
+	assert.Equal(t, "utils", v.name, "they should be equal")
+	assert.Equal(t, 0, pq.Len(), "they should be equal")
+
+	other := NewMinHeap[int]()
+	h := other.Push(1)
+	assert.False(t, NewMinHeap[int]().Remove(h))
+	other.Clear()
+	assert.False(t, other.Remove(h))
+}
+
+func TestTopK(t *testing.T) {
+	values := rand.Perm(1000)
+	got := TopK(values, 5, func(a, b int) bool { return a > b })
+	assert.Equal(t, []int{999, 998, 997, 996, 995}, got, "they should be equal")
+	assert.Equal(t, []int{1, 2}, TopK([]int{2, 1}, 5, func(a, b int) bool { return a < b }), "they should be equal")
+	assert.Equal(t, 0, len(TopK(values, 0, func(a, b int) bool { return a < b })), "they should be equal")
+}
+
+func TestSyncPriorityQueue(t *testing.T) {
+	q := NewSync(func(a, b int) bool { return a < b })
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				q.Push(i*100 + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, q.Len(), "they should be equal")
+	var got []int
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	assert.True(t, sort.IntsAreSorted(got))
+}
@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrFull is returned by Push when a bounded queue is full.
+var ErrFull = errors.New("queue: full")
+
+/*
+Queue is a thread-safe FIFO queue, bounded when created with a capacity.
+
+	q := queue.New[string](100)
+	q.Push("huangjian")
+	v, ok := q.Pop()
+*/
+type Queue[T any] struct {
+	lock     sync.Mutex
+	items    []T
+	head     int
+	capacity int
+	// changed is closed and replaced on every push and pop, waking up the
+	// blocked Put and Take.
+	changed chan struct{}
+}
+
+// New returns an empty queue holding at most capacity items, capacity <= 0
+// means unbounded.
+func New[T any](capacity int) *Queue[T] {
+	return &Queue[T]{capacity: capacity, changed: make(chan struct{})}
+}
+
+func (q *Queue[T]) len() int {
+	return len(q.items) - q.head
+}
+
+func (q *Queue[T]) full() bool {
+	return q.capacity > 0 && q.len() >= q.capacity
+}
+
+func (q *Queue[T]) broadcast() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+func (q *Queue[T]) push(v T) {
+	q.items = append(q.items, v)
+	q.broadcast()
+}
+
+func (q *Queue[T]) pop() T {
+	var zero T
+	v := q.items[q.head]
+	q.items[q.head] = zero
+	q.head++
+	// reuse the slice once half of it is consumed
+	if q.head > len(q.items)/2 {
+		n := copy(q.items, q.items[q.head:])
+		for i := n; i < len(q.items); i++ {
+			q.items[i] = zero
+		}
+		q.items = q.items[:n]
+		q.head = 0
+	}
+	q.broadcast()
+	return v
+}
+
+// Push appends v to the back of q, or returns ErrFull.
+func (q *Queue[T]) Push(v T) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.full() {
+		return ErrFull
+	}
+	q.push(v)
+	return nil
+}
+
+// Pop removes and returns the front of q, ok is false when q is empty.
+func (q *Queue[T]) Pop() (v T, ok bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.len() == 0 {
+		return v, false
+	}
+	return q.pop(), true
+}
+
+// Peek returns the front of q without removing it.
+func (q *Queue[T]) Peek() (v T, ok bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.len() == 0 {
+		return v, false
+	}
+	return q.items[q.head], true
+}
+
+// Put appends v to the back of q, waiting for room until ctx is done.
+func (q *Queue[T]) Put(ctx context.Context, v T) error {
+	for {
+		q.lock.Lock()
+		if !q.full() {
+			q.push(v)
+			q.lock.Unlock()
+			return nil
+		}
+		changed := q.changed
+		q.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Take removes and returns the front of q, waiting for an item until ctx
+// is done.
+func (q *Queue[T]) Take(ctx context.Context) (T, error) {
+	for {
+		q.lock.Lock()
+		if q.len() > 0 {
+			v := q.pop()
+			q.lock.Unlock()
+			return v, nil
+		}
+		changed := q.changed
+		q.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of items in q.
+func (q *Queue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.len()
+}
+
+// Cap returns the capacity of q, 0 when unbounded.
+func (q *Queue[T]) Cap() int {
+	if q.capacity <= 0 {
+		return 0
+	}
+	return q.capacity
+}
+
+// Clear removes all the items of q.
+func (q *Queue[T]) Clear() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.items = nil
+	q.head = 0
+	q.broadcast()
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	q := New[string](2)
+	assert.Nil(t, q.Push("huangjian"))
+	assert.Nil(t, q.Push("MDGSF"))
+	assert.Equal(t, ErrFull, q.Push("full"), "they should be equal")
+	assert.Equal(t, 2, q.Len(), "they should be equal")
+	assert.Equal(t, 2, q.Cap(), "they should be equal")
+
+	v, ok := q.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, "huangjian", v, "they should be equal")
+	v, _ = q.Pop()
+	assert.Equal(t, "huangjian", v, "they should be equal")
+	v, _ = q.Pop()
+	assert.Equal(t, "MDGSF", v, "they should be equal")
+	_, ok = q.Pop()
+	assert.False(t, ok)
+}
+
+func TestQueueUnbounded(t *testing.T) {
+	q := New[int](0)
+	for i := 0; i < 1000; i++ {
+		assert.Nil(t, q.Push(i))
+		if i%3 == 0 {
+			q.Pop()
+		}
+	}
+	assert.Equal(t, 666, q.Len(), "they should be equal")
+	v, _ := q.Peek()
+	assert.Equal(t, 334, v, "they should be equal")
+	q.Clear()
+	assert.Equal(t, 0, q.Len(), "they should be equal")
+}
+
+func TestQueuePutTake(t *testing.T) {
+	q := New[int](1)
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	var got []int
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			v, err := q.Take(ctx)
+			assert.Nil(t, err)
+			got = append(got, v)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, q.Put(ctx, i))
+	}
+	wg.Wait()
+	assert.Equal(t, 100, len(got), "they should be equal")
+	assert.Equal(t, 99, got[99], "they should be equal")
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := q.Take(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err, "they should be equal")
+	q.Push(1)
+	assert.Equal(t, context.DeadlineExceeded, q.Put(ctx, 2), "they should be equal")
+}
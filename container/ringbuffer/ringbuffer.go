@@ -0,0 +1,176 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ringbuffer
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrFull is returned by Push when the buffer is full and does not
+// overwrite.
+var ErrFull = errors.New("ringbuffer: full")
+
+type options struct {
+	overwrite bool
+}
+
+// Option configures a RingBuffer.
+type Option func(*options)
+
+// WithOverwrite makes Push overwrite the oldest item when the buffer is
+// full, instead of returning ErrFull.
+func WithOverwrite() Option {
+	return func(o *options) {
+		o.overwrite = true
+	}
+}
+
+/*
+RingBuffer is a thread-safe FIFO buffer of fixed capacity.
+
+	rb := ringbuffer.New[string](1024, ringbuffer.WithOverwrite())
+	rb.Push("huangjian")
+	for _, v := range rb.Items() {
+		...
+	}
+*/
+type RingBuffer[T any] struct {
+	opts options
+
+	lock    sync.Mutex
+	items   []T
+	start   int
+	size    int
+	dropped uint64
+}
+
+// New returns an empty buffer of capacity items, capacity <= 0 means 1.
+func New[T any](capacity int, opts ...Option) *RingBuffer[T] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	rb := &RingBuffer[T]{items: make([]T, capacity)}
+	for _, opt := range opts {
+		opt(&rb.opts)
+	}
+	return rb
+}
+
+// Push appends v. When rb is full it overwrites the oldest item if rb was
+// created WithOverwrite, or returns ErrFull.
+func (rb *RingBuffer[T]) Push(v T) error {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	if rb.size == len(rb.items) {
+		if !rb.opts.overwrite {
+			return ErrFull
+		}
+		rb.items[rb.start] = v
+		rb.start = (rb.start + 1) % len(rb.items)
+		rb.dropped++
+		return nil
+	}
+	rb.items[(rb.start+rb.size)%len(rb.items)] = v
+	rb.size++
+	return nil
+}
+
+// Pop removes and returns the oldest item, ok is false when rb is empty.
+func (rb *RingBuffer[T]) Pop() (v T, ok bool) {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	if rb.size == 0 {
+		return v, false
+	}
+	var zero T
+	v = rb.items[rb.start]
+	rb.items[rb.start] = zero
+	rb.start = (rb.start + 1) % len(rb.items)
+	rb.size--
+	return v, true
+}
+
+// Peek returns the oldest item without removing it.
+func (rb *RingBuffer[T]) Peek() (v T, ok bool) {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	if rb.size == 0 {
+		return v, false
+	}
+	return rb.items[rb.start], true
+}
+
+// Drain removes and returns all the items, oldest first.
+func (rb *RingBuffer[T]) Drain() []T {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	items := rb.copyItems()
+	var zero T
+	for i := range rb.items {
+		rb.items[i] = zero
+	}
+	rb.start, rb.size = 0, 0
+	return items
+}
+
+// Items returns a copy of the items, oldest first.
+func (rb *RingBuffer[T]) Items() []T {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	return rb.copyItems()
+}
+
+func (rb *RingBuffer[T]) copyItems() []T {
+	items := make([]T, rb.size)
+	for i := range items {
+		items[i] = rb.items[(rb.start+i)%len(rb.items)]
+	}
+	return items
+}
+
+// Len returns the number of items in rb.
+func (rb *RingBuffer[T]) Len() int {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	return rb.size
+}
+
+// Cap returns the capacity of rb.
+func (rb *RingBuffer[T]) Cap() int {
+	return len(rb.items)
+}
+
+// Full tells whether rb is full.
+func (rb *RingBuffer[T]) Full() bool {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	return rb.size == len(rb.items)
+}
+
+// Dropped returns the number of items overwritten by Push.
+func (rb *RingBuffer[T]) Dropped() uint64 {
+	rb.lock.Lock()
+	defer rb.lock.Unlock()
+	return rb.dropped
+}
@@ -0,0 +1,60 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ringbuffer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingBuffer(t *testing.T) {
+	rb := New[int](3)
+	for i := 1; i <= 3; i++ {
+		assert.Nil(t, rb.Push(i))
+	}
+	assert.True(t, rb.Full())
+	assert.Equal(t, ErrFull, rb.Push(4), "they should be equal")
+	assert.Equal(t, []int{1, 2, 3}, rb.Items(), "they should be equal")
+
+	v, ok := rb.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, 1, v, "they should be equal")
+	assert.Nil(t, rb.Push(4))
+	v, _ = rb.Peek()
+	assert.Equal(t, 2, v, "they should be equal")
+	assert.Equal(t, []int{2, 3, 4}, rb.Drain(), "they should be equal")
+	assert.Equal(t, 0, rb.Len(), "they should be equal")
+	_, ok = rb.Pop()
+	assert.False(t, ok)
+}
+
+func TestRingBufferOverwrite(t *testing.T) {
+	rb := New[string](2, WithOverwrite())
+	rb.Push("huangjian")
+	rb.Push("MDGSF")
+	assert.Nil(t, rb.Push("utils"))
+	assert.Equal(t, []string{"MDGSF", "utils"}, rb.Items(), "they should be equal")
+	assert.Equal(t, uint64(1), rb.Dropped(), "they should be equal")
+	assert.Equal(t, 2, rb.Cap(), "they should be equal")
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package stack
+
+import "sync"
+
+// Stack is a thread-safe LIFO stack.
+type Stack[T any] struct {
+	lock  sync.Mutex
+	items []T
+}
+
+// New returns an empty stack.
+func New[T any]() *Stack[T] {
+	return &Stack[T]{}
+}
+
+// Push puts v on the top of s.
+func (s *Stack[T]) Push(v T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.items = append(s.items, v)
+}
+
+// Pop removes and returns the top of s, ok is false when s is empty.
+func (s *Stack[T]) Pop() (v T, ok bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	n := len(s.items)
+	if n == 0 {
+		return v, false
+	}
+	v = s.items[n-1]
+	var zero T
+	s.items[n-1] = zero
+	s.items = s.items[:n-1]
+	return v, true
+}
+
+// Peek returns the top of s without removing it.
+func (s *Stack[T]) Peek() (v T, ok bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.items) == 0 {
+		return v, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Len returns the number of items in s.
+func (s *Stack[T]) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.items)
+}
+
+// Clear removes all the items of s.
+func (s *Stack[T]) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.items = nil
+}
@@ -0,0 +1,49 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package stack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack(t *testing.T) {
+	s := New[string]()
+	_, ok := s.Pop()
+	assert.False(t, ok)
+
+	s.Push("huangjian")
+	s.Push("MDGSF")
+	assert.Equal(t, 2, s.Len(), "they should be equal")
+	v, _ := s.Peek()
+	assert.Equal(t, "MDGSF", v, "they should be equal")
+	v, _ = s.Pop()
+	assert.Equal(t, "MDGSF", v, "they should be equal")
+	v, _ = s.Pop()
+	assert.Equal(t, "huangjian", v, "they should be equal")
+
+	s.Push("huangjian")
+	s.Clear()
+	assert.Equal(t, 0, s.Len(), "they should be equal")
+}
@@ -48,6 +48,12 @@ type Logger struct {
 	isTerminal int
 
 	callDepth int
+
+	// nowFunc returns the time of log entries, nil means time.Now.
+	nowFunc func() time.Time
+
+	// redact rewrites the message of log entries, nil leaves it unchanged.
+	redact func(string) string
 }
 
 // New creates a new Logger. The out variable sets the
@@ -82,6 +88,8 @@ func (l *Logger) Clone() *Logger {
 	newLog.out = l.out
 	newLog.isTerminal = l.isTerminal
 	newLog.callDepth = l.callDepth
+	newLog.nowFunc = l.nowFunc
+	newLog.redact = l.redact
 	return newLog
 }
 
@@ -113,6 +121,23 @@ func (l *Logger) SetOutput(w io.Writer) {
 	l.out = w
 }
 
+// SetNowFunc sets the function giving the time of log entries, nil restores
+// time.Now. Pass the Now method of a utime.Clock to log fake time in tests.
+func (l *Logger) SetNowFunc(now func() time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nowFunc = now
+}
+
+// SetRedactor sets the function applied to every log message before it is
+// written, nil disables it. Pass uredact.Text to mask emails, phone numbers
+// and secrets.
+func (l *Logger) SetRedactor(redact func(string) string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.redact = redact
+}
+
 // Cheap integer to fixed-width decimal ASCII. Give a negative width to avoid zero-padding.
 func itoa(buf *[]byte, i int, wid int) {
 	// Assemble decimal in reverse order.
@@ -207,6 +232,12 @@ func (l *Logger) Output(calldepth int, s string, level Level) error {
 	var line int
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if l.nowFunc != nil {
+		now = l.nowFunc()
+	}
+	if l.redact != nil {
+		s = l.redact(s)
+	}
 	if l.flag&(Lshortfile|Llongfile) != 0 {
 		// Release lock while getting caller info - it's expensive.
 		l.mu.Unlock()
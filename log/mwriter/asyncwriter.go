@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mwriter
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/MDGSF/utils/container/ringbuffer"
+)
+
+// ErrClosed is returned by the Write of a closed AsyncWriter.
+var ErrClosed = errors.New("mwriter: writer closed")
+
+// AsyncWriter writes to an underlying writer in a background goroutine, so
+// logging never blocks on a slow disk. It buffers at most size writes, and
+// drops the oldest ones when the underlying writer can not keep up.
+type AsyncWriter struct {
+	w       io.Writer
+	buf     *ringbuffer.RingBuffer[[]byte]
+	notify  chan struct{}
+	flushed chan chan struct{}
+	done    chan struct{}
+
+	lock   sync.Mutex
+	closed bool
+}
+
+// NewAsyncWriter returns an AsyncWriter writing to w, buffering at most
+// size writes.
+func NewAsyncWriter(w io.Writer, size int) *AsyncWriter {
+	aw := &AsyncWriter{
+		w:       w,
+		buf:     ringbuffer.New[[]byte](size, ringbuffer.WithOverwrite()),
+		notify:  make(chan struct{}, 1),
+		flushed: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go aw.run()
+	return aw
+}
+
+func (aw *AsyncWriter) run() {
+	defer close(aw.done)
+	for {
+		select {
+		case _, ok := <-aw.notify:
+			aw.writeAll()
+			if !ok {
+				return
+			}
+		case ch := <-aw.flushed:
+			aw.writeAll()
+			close(ch)
+		}
+	}
+}
+
+func (aw *AsyncWriter) writeAll() {
+	for _, p := range aw.buf.Drain() {
+		aw.w.Write(p)
+	}
+}
+
+// Write satisfies the io.Writer interface, it copies p and returns
+// immediately.
+func (aw *AsyncWriter) Write(p []byte) (int, error) {
+	aw.lock.Lock()
+	defer aw.lock.Unlock()
+	if aw.closed {
+		return 0, ErrClosed
+	}
+	aw.buf.Push(append([]byte(nil), p...))
+	select {
+	case aw.notify <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// Flush waits until the buffered writes are written.
+func (aw *AsyncWriter) Flush() {
+	ch := make(chan struct{})
+	select {
+	case aw.flushed <- ch:
+		<-ch
+	case <-aw.done:
+	}
+}
+
+// Dropped returns the number of writes dropped because the buffer was
+// full.
+func (aw *AsyncWriter) Dropped() uint64 {
+	return aw.buf.Dropped()
+}
+
+// Close writes the buffered writes and stops the background goroutine. It
+// does not close the underlying writer.
+func (aw *AsyncWriter) Close() error {
+	aw.lock.Lock()
+	if aw.closed {
+		aw.lock.Unlock()
+		return nil
+	}
+	aw.closed = true
+	close(aw.notify)
+	aw.lock.Unlock()
+	<-aw.done
+	return nil
+}
@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mwriter
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+	// block, when not nil, holds up Write until it is closed
+	block chan struct{}
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	if b.block != nil {
+		<-b.block
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func TestAsyncWriterOrder(t *testing.T) {
+	var out syncBuffer
+	aw := NewAsyncWriter(&out, 1000)
+	var want bytes.Buffer
+	for i := 0; i < 500; i++ {
+		line := "huangjian " + strconv.Itoa(i) + "\n"
+		want.WriteString(line)
+		n, err := aw.Write([]byte(line))
+		assert.Nil(t, err)
+		assert.Equal(t, len(line), n, "they should be equal")
+	}
+	aw.Flush()
+	assert.Equal(t, want.String(), out.String(), "they should be equal")
+	assert.Equal(t, uint64(0), aw.Dropped(), "they should be equal")
+	aw.Close()
+}
+
+func TestAsyncWriterCopiesInput(t *testing.T) {
+	var out syncBuffer
+	aw := NewAsyncWriter(&out, 10)
+	p := []byte("huangjian")
+	aw.Write(p)
+	copy(p, "MDGSFMDGS")
+	aw.Flush()
+	assert.Equal(t, "huangjian", out.String(), "they should be equal")
+	aw.Close()
+}
+
+func TestAsyncWriterDropped(t *testing.T) {
+	out := syncBuffer{block: make(chan struct{})}
+	aw := NewAsyncWriter(&out, 2)
+	aw.Write([]byte("0"))
+	// wait until the background goroutine is blocked writing "0"
+	for aw.buf.Len() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i <= 5; i++ {
+		aw.Write([]byte(strconv.Itoa(i)))
+	}
+	assert.Equal(t, uint64(3), aw.Dropped(), "they should be equal")
+	close(out.block)
+	aw.Close()
+	assert.Equal(t, "045", out.String(), "they should be equal")
+}
+
+func TestAsyncWriterClose(t *testing.T) {
+	out := syncBuffer{block: make(chan struct{})}
+	aw := NewAsyncWriter(&out, 100)
+	for i := 0; i < 10; i++ {
+		aw.Write([]byte(strconv.Itoa(i)))
+	}
+	close(out.block)
+	assert.Nil(t, aw.Close())
+	assert.Equal(t, "0123456789", out.String(), "they should be equal")
+
+	_, err := aw.Write([]byte("MDGSF"))
+	assert.Equal(t, ErrClosed, err, "they should be equal")
+	assert.Nil(t, aw.Close())
+	aw.Flush()
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/MDGSF/utils"
 	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/utime"
 )
 
 type RotateWriter struct {
@@ -21,10 +22,17 @@ type RotateWriter struct {
 	fp              *os.File
 	maxsize         int64
 	maxFileDuration time.Duration
+	clock           utime.Clock
 }
 
 // Make a new RotateWriter. Return nil if error occurs during setup.
 func New(filename string, maxsize int, maxFileDuration time.Duration) *RotateWriter {
+	return NewWithClock(filename, maxsize, maxFileDuration, utime.RealClock())
+}
+
+// NewWithClock is New with the clock used to name rotated files and to
+// expire them.
+func NewWithClock(filename string, maxsize int, maxFileDuration time.Duration, clock utime.Clock) *RotateWriter {
 	if len(filename) == 0 {
 		panic("empty file name")
 	}
@@ -40,6 +48,7 @@ func New(filename string, maxsize int, maxFileDuration time.Duration) *RotateWri
 		filename:        filename,
 		maxsize:         int64(maxsize),
 		maxFileDuration: maxFileDuration,
+		clock:           clock,
 	}
 	w.createLogFile()
 	go w.autoClean()
@@ -50,13 +59,13 @@ func (w *RotateWriter) autoClean() {
 	for {
 		w.cleanExpiredFile()
 
-		time.Sleep(time.Minute)
+		w.clock.Sleep(time.Minute)
 	}
 }
 
 func (w *RotateWriter) cleanExpiredFile() {
 
-	curTime := time.Now()
+	curTime := w.clock.Now()
 	if curTime.Year() < 2020 {
 		log.Error("invalid current time: %v", curTime)
 		return
@@ -111,7 +120,7 @@ func (w *RotateWriter) createLogFile() {
 	var err error
 
 	if utils.FileExists(w.filename) {
-		err = os.Rename(w.filename, w.filename+"."+time.Now().Format(time.RFC3339))
+		err = os.Rename(w.filename, w.filename+"."+w.clock.Now().Format(time.RFC3339))
 		if err != nil {
 			log.Error("rename file [%v] failed, err = %v", w.filename, err)
 			return
@@ -170,7 +179,7 @@ func (w *RotateWriter) reduceFileSize() {
 	// Rename dest file if it already exists
 	_, err = os.Stat(w.filename)
 	if err == nil {
-		err = os.Rename(w.filename, w.filename+"."+time.Now().Format(time.RFC3339))
+		err = os.Rename(w.filename, w.filename+"."+w.clock.Now().Format(time.RFC3339))
 		//err = os.Rename(w.filename, w.filename+".bak")
 		if err != nil {
 			return
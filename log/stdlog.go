@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 )
 
 var std = New(os.Stdout, "", "", LLevel|LstdFlags|Lshortfile, InfoLevel, IsTerminal)
@@ -56,6 +57,22 @@ func SetOutput(w io.Writer) {
 	std.out = w
 }
 
+// SetNowFunc sets the function giving the time of log entries for the
+// standard logger, nil restores time.Now.
+func SetNowFunc(now func() time.Time) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.nowFunc = now
+}
+
+// SetRedactor sets the function applied to every message of the standard
+// logger before it is written, nil disables it.
+func SetRedactor(redact func(string) string) {
+	std.mu.Lock()
+	defer std.mu.Unlock()
+	std.redact = redact
+}
+
 // Flags returns the output flags for the standard logger.
 func Flags() int {
 	return std.Flags()
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nanoid
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/bits"
+)
+
+// DefaultAlphabet URL-safe alphabet, 64 characters.
+const DefaultAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// DefaultSize default id length, about as hard to collide as uuid v4.
+const DefaultSize = 21
+
+// New generate an id of DefaultSize characters from DefaultAlphabet.
+func New() (string, error) {
+	return Generate(DefaultAlphabet, DefaultSize)
+}
+
+// MustNew is like New but panics if random source fails.
+func MustNew() string {
+	id, err := New()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+/*
+Generate generate an id of size characters from alphabet, using crypto/rand.
+Every character of alphabet has the same probability, there is no modulo bias.
+@param alphabet: 2 to 256 single byte characters.
+@param size: id length.
+*/
+func Generate(alphabet string, size int) (string, error) {
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		return "", errors.New("nanoid: alphabet must contain 2 to 256 characters")
+	}
+	if size <= 0 {
+		return "", errors.New("nanoid: size must be positive")
+	}
+
+	// Use the smallest 2^n-1 mask that covers the alphabet, random bytes
+	// bigger than the alphabet are dropped.
+	mask := (1 << uint(bits.Len(uint(len(alphabet)-1)))) - 1
+	step := int(math.Ceil(1.6 * float64(mask*size) / float64(len(alphabet))))
+
+	id := make([]byte, 0, size)
+	buf := make([]byte, step)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			idx := int(b) & mask
+			if idx < len(alphabet) {
+				id = append(id, alphabet[idx])
+				if len(id) == size {
+					return string(id), nil
+				}
+			}
+		}
+	}
+}
+
+/*
+CollisionProbability Returns the probability that at least two of count ids
+are the same, with ids of size characters from an alphabet of alphabetLen
+characters. It uses the birthday problem approximation 1 - e^(-n^2/2N).
+*/
+func CollisionProbability(alphabetLen, size int, count float64) float64 {
+	space := math.Pow(float64(alphabetLen), float64(size))
+	return -math.Expm1(-count * (count - 1) / (2 * space))
+}
+
+/*
+CountForProbability Returns how many ids can be generated before the
+collision probability reaches p, the inverse of CollisionProbability.
+For example, with DefaultAlphabet and DefaultSize, about 1.3e16 ids can be
+generated before the collision probability reaches one in a million.
+*/
+func CountForProbability(alphabetLen, size int, p float64) float64 {
+	space := math.Pow(float64(alphabetLen), float64(size))
+	return math.Sqrt(2 * space * -math.Log1p(-p))
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package nanoid
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id, err := New()
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, DefaultSize, len(id), "they should be equal")
+		for _, c := range id {
+			assert.Equal(t, true, strings.ContainsRune(DefaultAlphabet, c), id)
+		}
+		assert.Equal(t, false, seen[id], "they should be equal")
+		seen[id] = true
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	id, err := Generate("0123456789", 6)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 6, len(id), "they should be equal")
+	for _, c := range id {
+		assert.Equal(t, true, c >= '0' && c <= '9', id)
+	}
+
+	_, err = Generate("a", 6)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = Generate("ab", 0)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestGenerateDistribution(t *testing.T) {
+	// 3 characters use mask 3, so this would be biased with modulo.
+	id, err := Generate("abc", 30000)
+	assert.Equal(t, nil, err, "they should be equal")
+	for _, c := range "abc" {
+		n := strings.Count(id, string(c))
+		assert.Equal(t, true, n > 9000 && n < 11000, "count of %c is %v", c, n)
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	assert.Equal(t, 0.0, CollisionProbability(64, 21, 1), "they should be equal")
+
+	// birthday problem: 23 people, 365 days, about 50%.
+	p := CollisionProbability(365, 1, 23)
+	assert.Equal(t, true, math.Abs(p-0.5) < 0.01, p)
+
+	n := CountForProbability(64, 21, 1e-6)
+	assert.Equal(t, true, math.Abs(CollisionProbability(64, 21, n)-1e-6) < 1e-8, n)
+}
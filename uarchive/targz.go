@@ -0,0 +1,170 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uarchive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// TarGzDir writes the content of srcDir to the gzip compressed tar file
+// tarPath.
+func TarGzDir(srcDir, tarPath string, opts ...Option) (err error) {
+	o := newOptions(opts)
+	entries, err := collect(srcDir, o)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(tarPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(tarPath)
+		}
+	}()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for _, e := range entries {
+		if err = writeTarEntry(tw, e); err != nil {
+			return err
+		}
+		o.report(e.name, e.info.Size())
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeTarEntry(tw *tar.Writer, e entry) error {
+	header, err := tar.FileInfoHeader(e.info, e.target)
+	if err != nil {
+		return err
+	}
+	header.Name = e.name
+	if e.info.IsDir() {
+		header.Name += "/"
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !e.info.Mode().IsRegular() {
+		return nil
+	}
+	src, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(tw, src)
+	return err
+}
+
+/*
+UntarGz extracts the gzip compressed tar file tarPath into dstDir. Entries
+which would land outside dstDir are rejected with ErrUnsafePath; hard links,
+devices and other special files are skipped.
+*/
+func UntarGz(tarPath, dstDir string, opts ...Option) error {
+	o := newOptions(opts)
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := extractTarEntry(tr, header, dstDir, o); err != nil {
+			return err
+		}
+	}
+}
+
+func extractTarEntry(tr *tar.Reader, header *tar.Header, dstDir string, o *options) error {
+	target, err := safeJoin(dstDir, header.Name)
+	if err != nil {
+		return err
+	}
+	name := cleanName(header.Name)
+	if o.excluded(name) {
+		return nil
+	}
+	mode := header.FileInfo().Mode()
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return mkdir(dstDir, target, mode)
+	case tar.TypeSymlink:
+		if o.symlinks == SymlinkSkip || !o.included(name) {
+			return nil
+		}
+		if err := createLink(dstDir, target, header.Linkname); err != nil {
+			return err
+		}
+	case tar.TypeReg, tar.TypeRegA:
+		if !o.included(name) {
+			return nil
+		}
+		out, err := createFile(dstDir, target, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		os.Chtimes(target, header.ModTime, header.ModTime)
+	default:
+		return nil
+	}
+	o.report(name, header.Size)
+	return nil
+}
@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uarchive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTarGz(t *testing.T, p string, headers []*tar.Header, contents []string) {
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for i, header := range headers {
+		header.Size = int64(len(contents[i]))
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(contents[i]))
+	}
+	tw.Close()
+	gw.Close()
+}
+
+func TestUntarGzSlip(t *testing.T) {
+	dir := tempDir(t)
+	archive := filepath.Join(dir, "evil.tar.gz")
+	writeTarGz(t, archive, []*tar.Header{
+		{Name: "huangjian", Mode: 0644, Typeflag: tar.TypeReg},
+		{Name: "../evil", Mode: 0644, Typeflag: tar.TypeReg},
+	}, []string{"MDGSF", "evil"})
+
+	err := UntarGz(archive, filepath.Join(dir, "dst"))
+	assert.Equal(t, true, errors.Is(err, ErrUnsafePath), "they should be equal")
+	_, err = os.Stat(filepath.Join(dir, "evil"))
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+}
+
+func TestUntarGzSymlinkEscape(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	dir := tempDir(t)
+	archive := filepath.Join(dir, "evil.tar.gz")
+	writeTarGz(t, archive, []*tar.Header{
+		{Name: "link", Linkname: "/etc", Mode: 0777, Typeflag: tar.TypeSymlink},
+	}, []string{""})
+	err := UntarGz(archive, filepath.Join(dir, "dst"))
+	assert.Equal(t, true, errors.Is(err, ErrUnsafePath), "they should be equal")
+}
+
+func TestUntarGzSkipsSpecial(t *testing.T) {
+	dir := tempDir(t)
+	archive := filepath.Join(dir, "special.tar.gz")
+	writeTarGz(t, archive, []*tar.Header{
+		{Name: "huangjian", Mode: 0644, Typeflag: tar.TypeReg},
+		{Name: "fifo", Mode: 0644, Typeflag: tar.TypeFifo},
+		{Name: "hard", Linkname: "huangjian", Typeflag: tar.TypeLink},
+	}, []string{"MDGSF", "", ""})
+
+	dst := filepath.Join(dir, "dst")
+	assert.Equal(t, nil, UntarGz(archive, dst), "they should be equal")
+	assert.Equal(t, map[string]string{"huangjian": "MDGSF"}, readTree(t, dst), "they should be equal")
+}
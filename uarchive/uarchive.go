@@ -0,0 +1,289 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uarchive
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/MDGSF/utils/ufile"
+)
+
+// ErrUnsafePath is returned when an archive entry would be written outside
+// the destination directory ("zip slip").
+var ErrUnsafePath = errors.New("uarchive: unsafe path in archive")
+
+// SymlinkPolicy decides how symlinks are archived and extracted.
+type SymlinkPolicy int
+
+const (
+	// SymlinkPreserve stores symlinks as links. On extraction links whose
+	// target points outside the destination are rejected with
+	// ErrUnsafePath.
+	SymlinkPreserve SymlinkPolicy = iota
+	// SymlinkSkip ignores symlinks.
+	SymlinkSkip
+	// SymlinkFollow archives what the link points to, on extraction it
+	// behaves like SymlinkPreserve.
+	SymlinkFollow
+)
+
+type options struct {
+	include  []string
+	exclude  []string
+	symlinks SymlinkPolicy
+	progress func(name string, size int64)
+}
+
+// Option configures archiving and extraction.
+type Option func(*options)
+
+// WithInclude only processes files matching one of the globs, see
+// ufile.MatchGlob. A pattern without "/" matches the base name.
+func WithInclude(patterns ...string) Option {
+	return func(o *options) {
+		o.include = append(o.include, patterns...)
+	}
+}
+
+// WithExclude skips files and directories matching one of the globs.
+func WithExclude(patterns ...string) Option {
+	return func(o *options) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// WithSymlinks set the symlink policy, default SymlinkPreserve.
+func WithSymlinks(policy SymlinkPolicy) Option {
+	return func(o *options) {
+		o.symlinks = policy
+	}
+}
+
+// WithProgress calls fn after every entry with its slash separated name
+// and size in bytes.
+func WithProgress(fn func(name string, size int64)) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// excluded reports whether name or one of its parent directories matches
+// an exclude pattern.
+func (o *options) excluded(name string) bool {
+	for name != "." && name != "/" && name != "" {
+		if ufile.MatchAny(o.exclude, name) {
+			return true
+		}
+		name = path.Dir(name)
+	}
+	return false
+}
+
+func (o *options) included(name string) bool {
+	return len(o.include) == 0 || ufile.MatchAny(o.include, name)
+}
+
+func (o *options) report(name string, size int64) {
+	if o.progress != nil {
+		o.progress(name, size)
+	}
+}
+
+// entry is a file system object to archive.
+type entry struct {
+	name   string // slash separated, relative to the source dir
+	path   string // where to read it from
+	info   os.FileInfo
+	target string // link target for symlinks
+}
+
+// collect lists the entries of dir in a stable order.
+func collect(dir string, o *options) ([]entry, error) {
+	var entries []entry
+	visited := make(map[string]bool)
+	var walk func(dir, prefix string) error
+	walk = func(dir, prefix string) error {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			if visited[real] {
+				return nil
+			}
+			visited[real] = true
+		}
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, info := range infos {
+			name := prefix + info.Name()
+			p := filepath.Join(dir, info.Name())
+			if o.excluded(name) {
+				continue
+			}
+
+			e := entry{name: name, path: p, info: info}
+			if info.Mode()&os.ModeSymlink != 0 {
+				switch o.symlinks {
+				case SymlinkSkip:
+					continue
+				case SymlinkFollow:
+					target, err := os.Stat(p)
+					if err != nil {
+						// broken link, nothing to follow
+						continue
+					}
+					e.info = target
+				default:
+					if e.target, err = os.Readlink(p); err != nil {
+						return err
+					}
+				}
+			}
+
+			if e.info.IsDir() {
+				entries = append(entries, e)
+				if err := walk(p, name+"/"); err != nil {
+					return err
+				}
+				continue
+			}
+			if o.included(name) {
+				entries = append(entries, e)
+			}
+		}
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("uarchive: %s is not a directory", dir)
+	}
+	return entries, walk(dir, "")
+}
+
+// safeJoin returns the path of an entry inside dst, or ErrUnsafePath.
+func safeJoin(dst, name string) (string, error) {
+	name = strings.Replace(name, "\\", "/", -1)
+	if path.IsAbs(name) || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	clean := path.Clean(name)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: %s", ErrUnsafePath, name)
+	}
+	return filepath.Join(dst, filepath.FromSlash(clean)), nil
+}
+
+// checkLink rejects a link at linkPath whose target escapes dst.
+func checkLink(dst, linkPath, target string) error {
+	if filepath.IsAbs(target) {
+		return fmt.Errorf("%w: link to %s", ErrUnsafePath, target)
+	}
+	resolved := filepath.Join(filepath.Dir(linkPath), target)
+	rel, err := filepath.Rel(dst, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: link to %s", ErrUnsafePath, target)
+	}
+	return nil
+}
+
+// checkParents rejects paths whose parent directories inside dst include
+// a symlink, which an earlier entry could have planted to redirect writes.
+func checkParents(dst, p string) error {
+	rel, err := filepath.Rel(dst, filepath.Dir(p))
+	if err != nil || rel == "." {
+		return err
+	}
+	cur := dst
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		cur = filepath.Join(cur, part)
+		info, err := os.Lstat(cur)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: %s is a symlink", ErrUnsafePath, cur)
+		}
+	}
+	return nil
+}
+
+func mkdir(dst, p string, mode os.FileMode) error {
+	if err := checkParents(dst, p); err != nil {
+		return err
+	}
+	return os.MkdirAll(p, mode.Perm()|0700)
+}
+
+// createFile creates p with mode, creating parent directories.
+func createFile(dst, p string, mode os.FileMode) (*os.File, error) {
+	if err := checkParents(dst, p); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return nil, err
+	}
+	// never write through an existing link
+	if info, err := os.Lstat(p); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		os.Remove(p)
+	}
+	return os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+}
+
+func createLink(dst, p, target string) error {
+	if err := checkLink(dst, p, target); err != nil {
+		return err
+	}
+	if err := checkParents(dst, p); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	os.Remove(p)
+	return os.Symlink(target, p)
+}
+
+// cleanName normalizes an archive entry name for filtering and reporting.
+func cleanName(name string) string {
+	return path.Clean(strings.Replace(name, "\\", "/", -1))
+}
@@ -0,0 +1,218 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uarchive
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "uarchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree returns the regular files below dir keyed by slash separated name.
+func readTree(t *testing.T, dir string) map[string]string {
+	files := make(map[string]string)
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		data, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel, _ := filepath.Rel(dir, p)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	return files
+}
+
+var testTree = map[string]string{
+	"huangjian.txt":         "huangjian",
+	"MDGSF.log":             "MDGSF",
+	"sub/a.txt":             "a",
+	"sub/deep/b.txt":        "b",
+	"node_modules/x/ok.txt": "x",
+}
+
+// archiver bundles the matching pack and unpack functions of a format.
+type archiver struct {
+	name   string
+	ext    string
+	pack   func(src, dst string, opts ...Option) error
+	unpack func(src, dst string, opts ...Option) error
+}
+
+var archivers = []archiver{
+	{"zip", ".zip", ZipDir, Unzip},
+	{"targz", ".tar.gz", TarGzDir, UntarGz},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, a := range archivers {
+		dir := tempDir(t)
+		src := filepath.Join(dir, "src")
+		writeTree(t, src, testTree)
+		archive := filepath.Join(dir, "out"+a.ext)
+		dst := filepath.Join(dir, "dst")
+
+		assert.Equal(t, nil, a.pack(src, archive), a.name)
+		assert.Equal(t, nil, a.unpack(archive, dst), a.name)
+		assert.Equal(t, testTree, readTree(t, dst), a.name)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	for _, a := range archivers {
+		dir := tempDir(t)
+		src := filepath.Join(dir, "src")
+		writeTree(t, src, testTree)
+		archive := filepath.Join(dir, "out"+a.ext)
+
+		err := a.pack(src, archive, WithInclude("*.txt"), WithExclude("node_modules"))
+		assert.Equal(t, nil, err, a.name)
+		dst := filepath.Join(dir, "dst")
+		assert.Equal(t, nil, a.unpack(archive, dst), a.name)
+		assert.Equal(t, map[string]string{
+			"huangjian.txt":  "huangjian",
+			"sub/a.txt":      "a",
+			"sub/deep/b.txt": "b",
+		}, readTree(t, dst), a.name)
+
+		dst = filepath.Join(dir, "dst2")
+		assert.Equal(t, nil, a.unpack(archive, dst, WithExclude("sub/deep")), a.name)
+		assert.Equal(t, map[string]string{
+			"huangjian.txt": "huangjian",
+			"sub/a.txt":     "a",
+		}, readTree(t, dst), a.name)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	for _, a := range archivers {
+		dir := tempDir(t)
+		src := filepath.Join(dir, "src")
+		writeTree(t, src, map[string]string{"huangjian": "MDGSF", "sub/a": "a"})
+		archive := filepath.Join(dir, "out"+a.ext)
+
+		var names []string
+		var total int64
+		progress := WithProgress(func(name string, size int64) {
+			names = append(names, name)
+			total += size
+		})
+		assert.Equal(t, nil, a.pack(src, archive, WithInclude("*"), progress), a.name)
+		assert.Equal(t, []string{"huangjian", "sub", "sub/a"}, names, a.name)
+
+		names, total = nil, 0
+		assert.Equal(t, nil, a.unpack(archive, filepath.Join(dir, "dst"), progress), a.name)
+		sort.Strings(names)
+		assert.Equal(t, []string{"huangjian", "sub/a"}, names, a.name)
+		assert.Equal(t, int64(6), total, a.name)
+	}
+}
+
+func TestSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	for _, a := range archivers {
+		dir := tempDir(t)
+		src := filepath.Join(dir, "src")
+		writeTree(t, src, map[string]string{"huangjian": "MDGSF"})
+		assert.Equal(t, nil, os.Symlink("huangjian", filepath.Join(src, "link")), a.name)
+
+		archive := filepath.Join(dir, "out"+a.ext)
+		assert.Equal(t, nil, a.pack(src, archive), a.name)
+		dst := filepath.Join(dir, "dst")
+		assert.Equal(t, nil, a.unpack(archive, dst), a.name)
+		target, err := os.Readlink(filepath.Join(dst, "link"))
+		assert.Equal(t, nil, err, a.name)
+		assert.Equal(t, "huangjian", target, a.name)
+
+		dst = filepath.Join(dir, "skip")
+		assert.Equal(t, nil, a.unpack(archive, dst, WithSymlinks(SymlinkSkip)), a.name)
+		assert.Equal(t, map[string]string{"huangjian": "MDGSF"}, readTree(t, dst), a.name)
+		_, err = os.Lstat(filepath.Join(dst, "link"))
+		assert.Equal(t, true, os.IsNotExist(err), a.name)
+
+		archive = filepath.Join(dir, "follow"+a.ext)
+		assert.Equal(t, nil, a.pack(src, archive, WithSymlinks(SymlinkFollow)), a.name)
+		dst = filepath.Join(dir, "follow")
+		assert.Equal(t, nil, a.unpack(archive, dst), a.name)
+		info, err := os.Lstat(filepath.Join(dst, "link"))
+		assert.Equal(t, nil, err, a.name)
+		assert.Equal(t, true, info.Mode().IsRegular(), a.name)
+	}
+}
+
+func TestSafeJoin(t *testing.T) {
+	dst := filepath.FromSlash("/tmp/dst")
+	p, err := safeJoin(dst, "sub/huangjian")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, filepath.Join(dst, "sub", "huangjian"), p, "they should be equal")
+
+	p, err = safeJoin(dst, "sub/../huangjian")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, filepath.Join(dst, "huangjian"), p, "they should be equal")
+
+	for _, name := range []string{"../evil", "sub/../../evil", "/etc/passwd", "..\\evil", ".."} {
+		_, err = safeJoin(dst, name)
+		assert.Equal(t, true, errors.Is(err, ErrUnsafePath), name)
+	}
+}
+
+func TestCheckLink(t *testing.T) {
+	dst := filepath.FromSlash("/tmp/dst")
+	link := filepath.Join(dst, "sub", "link")
+	assert.Equal(t, nil, checkLink(dst, link, "../huangjian"), "they should be equal")
+	assert.Equal(t, nil, checkLink(dst, link, "MDGSF"), "they should be equal")
+	assert.Equal(t, true, errors.Is(checkLink(dst, link, "../../evil"), ErrUnsafePath), "they should be equal")
+	assert.Equal(t, true, errors.Is(checkLink(dst, link, "/etc/passwd"), ErrUnsafePath), "they should be equal")
+}
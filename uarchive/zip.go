@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uarchive
+
+import (
+	"archive/zip"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// ZipDir writes the content of srcDir to the zip file zipPath.
+func ZipDir(srcDir, zipPath string, opts ...Option) (err error) {
+	o := newOptions(opts)
+	entries, err := collect(srcDir, o)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(zipPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(zipPath)
+		}
+	}()
+
+	zw := zip.NewWriter(f)
+	for _, e := range entries {
+		if err = writeZipEntry(zw, e); err != nil {
+			return err
+		}
+		o.report(e.name, e.info.Size())
+	}
+	return zw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, e entry) error {
+	header, err := zip.FileInfoHeader(e.info)
+	if err != nil {
+		return err
+	}
+	header.Name = e.name
+	if e.info.IsDir() {
+		header.Name += "/"
+	} else if e.target == "" {
+		header.Method = zip.Deflate
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil || e.info.IsDir() {
+		return err
+	}
+	if e.target != "" {
+		_, err = io.WriteString(w, e.target)
+		return err
+	}
+	src, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = io.Copy(w, src)
+	return err
+}
+
+/*
+Unzip extracts the zip file zipPath into dstDir. Entries which would land
+outside dstDir are rejected with ErrUnsafePath before anything is written
+for them.
+*/
+func Unzip(zipPath, dstDir string, opts ...Option) error {
+	o := newOptions(opts)
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	if err := os.MkdirAll(dstDir, 0755); err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if err := extractZipEntry(f, dstDir, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipEntry(f *zip.File, dstDir string, o *options) error {
+	target, err := safeJoin(dstDir, f.Name)
+	if err != nil {
+		return err
+	}
+	name := cleanName(f.Name)
+	if o.excluded(name) {
+		return nil
+	}
+	mode := f.Mode()
+
+	switch {
+	case mode.IsDir():
+		return mkdir(dstDir, target, mode)
+	case !o.included(name):
+		return nil
+	case mode&os.ModeSymlink != 0:
+		if o.symlinks == SymlinkSkip {
+			return nil
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		link, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := createLink(dstDir, target, string(link)); err != nil {
+			return err
+		}
+	default:
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		out, err := createFile(dstDir, target, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, rc); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+		os.Chtimes(target, f.Modified, f.Modified)
+	}
+	o.report(name, int64(f.UncompressedSize64))
+	return nil
+}
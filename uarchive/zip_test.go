@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uarchive
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zipEntry describes an entry of a crafted zip file.
+type zipEntry struct {
+	name    string
+	mode    os.FileMode
+	content string
+}
+
+func writeZip(t *testing.T, p string, entries []zipEntry) {
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name}
+		header.SetMode(e.mode)
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, e.content)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnzipSlip(t *testing.T) {
+	dir := tempDir(t)
+	archive := filepath.Join(dir, "evil.zip")
+	writeZip(t, archive, []zipEntry{
+		{"huangjian", 0644, "MDGSF"},
+		{"../evil", 0644, "evil"},
+	})
+
+	err := Unzip(archive, filepath.Join(dir, "dst"))
+	assert.Equal(t, true, errors.Is(err, ErrUnsafePath), "they should be equal")
+	_, err = os.Stat(filepath.Join(dir, "evil"))
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+}
+
+func TestUnzipSymlinkEscape(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	dir := tempDir(t)
+	archive := filepath.Join(dir, "evil.zip")
+	writeZip(t, archive, []zipEntry{
+		{"link", os.ModeSymlink | 0777, ".."},
+		{"link/evil", 0644, "evil"},
+	})
+	err := Unzip(archive, filepath.Join(dir, "dst"))
+	assert.Equal(t, true, errors.Is(err, ErrUnsafePath), "they should be equal")
+
+	// a link that stays inside dst must not be used to write through
+	writeZip(t, archive, []zipEntry{
+		{"sub/", os.ModeDir | 0755, ""},
+		{"link", os.ModeSymlink | 0777, "sub"},
+		{"link/evil", 0644, "evil"},
+	})
+	err = Unzip(archive, filepath.Join(dir, "dst2"))
+	assert.Equal(t, true, errors.Is(err, ErrUnsafePath), "they should be equal")
+	_, err = os.Stat(filepath.Join(dir, "dst2", "sub", "evil"))
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uasync
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/uerrors"
+)
+
+// PanicError is the error of a task which panicked, print it with %+v for
+// the stack trace.
+type PanicError = uerrors.PanicError
+
+type groupOptions struct {
+	limit   int
+	timeout time.Duration
+}
+
+// GroupOption configures a Group.
+type GroupOption func(*groupOptions)
+
+// WithLimit limits the number of tasks running at once, Go blocks while
+// the limit is reached. n <= 0 means no limit.
+func WithLimit(n int) GroupOption {
+	return func(o *groupOptions) {
+		o.limit = n
+	}
+}
+
+// WithTaskTimeout gives every task a context which is done after d.
+func WithTaskTimeout(d time.Duration) GroupOption {
+	return func(o *groupOptions) {
+		o.timeout = d
+	}
+}
+
+/*
+Group runs tasks in goroutines and waits for them, like errgroup. The first
+task error cancels the group context and is returned by Wait. A panicking
+task does not crash the process, it fails with a *PanicError holding the
+stack trace.
+*/
+type Group struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	options groupOptions
+	sem     chan struct{}
+	wg      sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup create a Group and the context passed to its tasks, the
+// context is cancelled by the first error or when Wait returns.
+func NewGroup(ctx context.Context, opts ...GroupOption) (*Group, context.Context) {
+	g := &Group{}
+	for _, opt := range opts {
+		opt(&g.options)
+	}
+	if g.options.limit > 0 {
+		g.sem = make(chan struct{}, g.options.limit)
+	}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	return g, g.ctx
+}
+
+// Go runs task in a new goroutine, blocking while the limit is reached.
+func (g *Group) Go(task func(ctx context.Context) error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+			g.wg.Done()
+		}()
+		if err := g.run(task); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+func (g *Group) run(task func(ctx context.Context) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = uerrors.NewPanicError(v)
+		}
+	}()
+	ctx := g.ctx
+	if g.options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.options.timeout)
+		defer cancel()
+	}
+	return task(ctx)
+}
+
+// Wait waits for all tasks and returns the first error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uasync
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/uerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup(t *testing.T) {
+	g, _ := NewGroup(context.Background(), WithLimit(2))
+	var running, maxRunning, done int32
+	for i := 0; i < 10; i++ {
+		g.Go(func(ctx context.Context) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			atomic.AddInt32(&done, 1)
+			return nil
+		})
+	}
+	assert.Equal(t, nil, g.Wait(), "they should be equal")
+	assert.Equal(t, int32(10), done, "they should be equal")
+	assert.Equal(t, true, maxRunning <= 2, "they should be equal")
+}
+
+func TestGroupError(t *testing.T) {
+	errTest := errors.New("huangjian")
+	g, ctx := NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error {
+		return errTest
+	})
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(t, errTest, g.Wait(), "they should be equal")
+	assert.Equal(t, context.Canceled, ctx.Err(), "they should be equal")
+}
+
+func TestGroupPanic(t *testing.T) {
+	g, _ := NewGroup(context.Background())
+	g.Go(func(ctx context.Context) error {
+		var m map[string]int
+		m["MDGSF"] = 1
+		return nil
+	})
+	err := g.Wait()
+	pe, ok := err.(*PanicError)
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, true, strings.Contains(err.Error(), "nil map"), "they should be equal")
+	assert.NotEqual(t, 0, len(pe.StackTrace()), "they should not be equal")
+	assert.Equal(t, true, strings.Contains(pe.StackTrace()[0].Func(), "TestGroupPanic"), "they should be equal")
+
+	var perr *uerrors.PanicError
+	assert.Equal(t, true, errors.As(err, &perr), "they should be equal")
+}
+
+func TestGroupTaskTimeout(t *testing.T) {
+	g, ctx := NewGroup(context.Background(), WithTaskTimeout(10*time.Millisecond))
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.Equal(t, context.DeadlineExceeded, g.Wait(), "they should be equal")
+	assert.Equal(t, context.Canceled, ctx.Err(), "they should be equal")
+}
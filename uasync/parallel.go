@@ -0,0 +1,151 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uasync
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Errors is a list of errors returned by ParallelMap in collect mode,
+// ordered by item index.
+type Errors []error
+
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
+type parallelOptions struct {
+	collectErrors bool
+}
+
+// ParallelOption configures ParallelMap and ParallelForEach.
+type ParallelOption func(*parallelOptions)
+
+// WithCollectErrors runs every item even if some fail and returns all
+// errors as Errors, instead of cancelling on the first error.
+func WithCollectErrors() ParallelOption {
+	return func(o *parallelOptions) {
+		o.collectErrors = true
+	}
+}
+
+/*
+ParallelMap calls fn for every item with at most concurrency calls running
+at once, concurrency <= 0 means one goroutine per item. results[i] is the
+result of items[i].
+
+By default the first error cancels the context passed to fn, no further
+items are started, and that error is returned. With WithCollectErrors all
+items are run and the failures are returned as Errors. If ctx is done
+before all items started, ctx.Err() is returned.
+*/
+func ParallelMap[T, R any](ctx context.Context, items []T,
+	fn func(ctx context.Context, item T) (R, error),
+	concurrency int, opts ...ParallelOption) ([]R, error) {
+
+	options := parallelOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	if len(items) == 0 {
+		return results, ctx.Err()
+	}
+	if concurrency <= 0 || concurrency > len(items) {
+		concurrency = len(items)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		next      int64 = -1
+		started   int64
+		firstOnce sync.Once
+		firstErr  error
+		wg        sync.WaitGroup
+	)
+	wg.Add(concurrency)
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(items) || ctx.Err() != nil {
+					return
+				}
+				atomic.AddInt64(&started, 1)
+				results[i], errs[i] = fn(ctx, items[i])
+				if errs[i] != nil && !options.collectErrors {
+					firstOnce.Do(func() {
+						firstErr = errs[i]
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	if int(started) < len(items) {
+		// stopped early, the parent context is done
+		return results, ctx.Err()
+	}
+	var collected Errors
+	for _, err := range errs {
+		if err != nil {
+			collected = append(collected, err)
+		}
+	}
+	if len(collected) > 0 {
+		return results, collected
+	}
+	return results, nil
+}
+
+// ParallelForEach is ParallelMap for functions without a result.
+func ParallelForEach[T any](ctx context.Context, items []T,
+	fn func(ctx context.Context, item T) error,
+	concurrency int, opts ...ParallelOption) error {
+
+	_, err := ParallelMap(ctx, items, func(ctx context.Context, item T) (struct{}, error) {
+		return struct{}{}, fn(ctx, item)
+	}, concurrency, opts...)
+	return err
+}
@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uasync
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelMap(t *testing.T) {
+	items := []int{5, 3, 8, 1, 9, 2}
+	var running, maxRunning int32
+	results, err := ParallelMap(context.Background(), items, func(ctx context.Context, item int) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(item) * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return strconv.Itoa(item), nil
+	}, 2)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []string{"5", "3", "8", "1", "9", "2"}, results, "they should be equal")
+	assert.Equal(t, true, atomic.LoadInt32(&maxRunning) <= 2, "they should be equal")
+
+	results, err = ParallelMap(context.Background(), []int{}, func(ctx context.Context, item int) (string, error) {
+		return "", nil
+	}, 2)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 0, len(results), "they should be equal")
+}
+
+func TestParallelMapFailFast(t *testing.T) {
+	errTest := errors.New("huangjian")
+	var calls int32
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+	_, err := ParallelMap(context.Background(), items, func(ctx context.Context, item int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if item == 3 {
+			return 0, errTest
+		}
+		time.Sleep(time.Millisecond)
+		return item, nil
+	}, 2)
+	assert.Equal(t, errTest, err, "they should be equal")
+	assert.Equal(t, true, atomic.LoadInt32(&calls) < 100, "they should be equal")
+}
+
+func TestParallelMapCollectErrors(t *testing.T) {
+	results, err := ParallelMap(context.Background(), []int{1, 2, 3, 4}, func(ctx context.Context, item int) (int, error) {
+		if item%2 == 0 {
+			return 0, errors.New("even " + strconv.Itoa(item))
+		}
+		return item * 10, nil
+	}, 0, WithCollectErrors())
+	assert.Equal(t, []int{10, 0, 30, 0}, results, "they should be equal")
+	errs, ok := err.(Errors)
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 2, len(errs), "they should be equal")
+	assert.Equal(t, "2 errors: even 2; even 4", err.Error(), "they should be equal")
+}
+
+func TestParallelForEachCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	err := ParallelForEach(ctx, make([]int, 50), func(ctx context.Context, item int) error {
+		if atomic.AddInt32(&calls, 1) == 5 {
+			cancel()
+		}
+		return nil
+	}, 1)
+	assert.Equal(t, context.Canceled, err, "they should be equal")
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls), "they should be equal")
+}
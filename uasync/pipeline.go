@@ -0,0 +1,272 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uasync
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// The pipeline helpers below close their output channels once their inputs
+// are closed or ctx is done, so their goroutines never leak as long as ctx
+// is eventually cancelled or the inputs closed.
+
+// send sends v on out unless ctx is done first.
+func send[T any](ctx context.Context, out chan<- T, v T) bool {
+	select {
+	case out <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Generator returns a channel yielding values, closed after the last one.
+func Generator[T any](ctx context.Context, values ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			if !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// GeneratorFunc returns a channel yielding the values returned by fn,
+// closed once fn returns false.
+func GeneratorFunc[T any](ctx context.Context, fn func(ctx context.Context) (T, bool)) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, ok := fn(ctx)
+			if !ok || !send(ctx, out, v) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+/*
+OrDone returns a channel yielding the values of ch until ch is closed or
+ctx is done, to range over a channel without leaking on cancellation:
+
+	for v := range uasync.OrDone(ctx, ch) {
+		...
+	}
+*/
+func OrDone[T any](ctx context.Context, ch <-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok || !send(ctx, out, v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// FanIn merges chs into one channel, closed once all of chs are closed.
+// The order of values across chs is not kept.
+func FanIn[T any](ctx context.Context, chs ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(chs))
+	for _, ch := range chs {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for v := range OrDone(ctx, ch) {
+				if !send(ctx, out, v) {
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+/*
+FanOut distributes the values of ch over n channels, each value going to
+exactly one of them, whichever consumer is ready first. n <= 0 means 1.
+
+	for _, ch := range uasync.FanOut(ctx, jobs, 4) {
+		go worker(ch)
+	}
+*/
+func FanOut[T any](ctx context.Context, ch <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]<-chan T, n)
+	// one send case per output and the last one for ctx, a value waits in
+	// a single select until any consumer takes it
+	cases := make([]reflect.SelectCase, n+1)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(out)}
+	}
+	cases[n] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	go func() {
+		defer func() {
+			for _, c := range cases[:n] {
+				c.Chan.Close()
+			}
+		}()
+		for v := range OrDone(ctx, ch) {
+			value := reflect.ValueOf(&v).Elem()
+			for i := range cases[:n] {
+				cases[i].Send = value
+			}
+			if chosen, _, _ := reflect.Select(cases); chosen == n {
+				return
+			}
+		}
+	}()
+	return outs
+}
+
+/*
+Batch groups the values of ch into slices of size values, yielding a
+smaller batch when maxWait elapsed since its first value, or ch is closed.
+maxWait <= 0 waits until the batch is full.
+
+	for rows := range uasync.Batch(ctx, rowCh, 500, time.Second) {
+		db.InsertMany(rows)
+	}
+*/
+func Batch[T any](ctx context.Context, ch <-chan T, size int, maxWait time.Duration) <-chan []T {
+	if size <= 0 {
+		size = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var (
+			batch   []T
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) == 0 {
+				return true
+			}
+			b := batch
+			batch = nil
+			return send(ctx, out, b)
+		}
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, v)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) >= size && !flush() {
+					return
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+	return out
+}
+
+/*
+Window yields sliding windows of size values of ch, moving by step values,
+step <= 0 means 1. With step == size the windows do not overlap. Values
+left over when ch is closed, less than a window, are dropped.
+
+	uasync.Window(ctx, Generator(ctx, 1, 2, 3, 4), 3, 1) // [1 2 3] [2 3 4]
+*/
+func Window[T any](ctx context.Context, ch <-chan T, size, step int) <-chan []T {
+	if size <= 0 {
+		size = 1
+	}
+	if step <= 0 {
+		step = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		var window []T
+		skip := 0
+		for v := range OrDone(ctx, ch) {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			window = append(window, v)
+			if len(window) < size {
+				continue
+			}
+			w := make([]T, size)
+			copy(w, window)
+			if !send(ctx, out, w) {
+				return
+			}
+			if step >= size {
+				window = window[:0]
+				skip = step - size
+			} else {
+				window = append(window[:0], window[step:]...)
+			}
+		}
+	}()
+	return out
+}
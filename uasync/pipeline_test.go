@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uasync
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collect[T any](ch <-chan T) []T {
+	var out []T
+	for v := range ch {
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestGenerator(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, []string{"huangjian", "MDGSF"}, collect(Generator(ctx, "huangjian", "MDGSF")), "they should be equal")
+
+	i := 0
+	got := collect(GeneratorFunc(ctx, func(context.Context) (int, bool) {
+		i++
+		return i, i <= 3
+	}))
+	assert.Equal(t, []int{1, 2, 3}, got, "they should be equal")
+}
+
+func TestOrDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	out := OrDone(ctx, ch)
+	go func() { ch <- 1 }()
+	assert.Equal(t, 1, <-out, "they should be equal")
+	cancel()
+	_, ok := <-out
+	assert.False(t, ok)
+}
+
+func TestFanInFanOut(t *testing.T) {
+	ctx := context.Background()
+	in := Generator(ctx, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	outs := FanOut(ctx, in, 3)
+	assert.Equal(t, 3, len(outs), "they should be equal")
+
+	got := collect(FanIn(ctx, outs...))
+	sort.Ints(got)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, got, "they should be equal")
+}
+
+func TestFanOutReadyConsumer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outs := FanOut(ctx, Generator(ctx, 1, 2, 3, 4, 5), 2)
+	// outs[0] is never read, no value may wait for it
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(outs[1]), "they should be equal")
+	_, ok := <-outs[0]
+	assert.False(t, ok)
+}
+
+func TestFanInCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := FanIn(ctx, make(chan int), make(chan int))
+	cancel()
+	assert.Equal(t, 0, len(collect(out)), "they should be equal")
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	got := collect(Batch(ctx, Generator(ctx, 1, 2, 3, 4, 5), 2, 0))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, got, "they should be equal")
+}
+
+func TestBatchMaxWait(t *testing.T) {
+	ctx := context.Background()
+	ch := make(chan int)
+	out := Batch(ctx, ch, 10, 20*time.Millisecond)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var got [][]int
+	go func() {
+		defer wg.Done()
+		got = collect(out)
+	}()
+	ch <- 1
+	ch <- 2
+	time.Sleep(100 * time.Millisecond)
+	ch <- 3
+	close(ch)
+	wg.Wait()
+	assert.Equal(t, [][]int{{1, 2}, {3}}, got, "they should be equal")
+}
+
+func TestWindow(t *testing.T) {
+	ctx := context.Background()
+	got := collect(Window(ctx, Generator(ctx, 1, 2, 3, 4, 5), 3, 1))
+	assert.Equal(t, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, got, "they should be equal")
+
+	got = collect(Window(ctx, Generator(ctx, 1, 2, 3, 4, 5), 2, 2))
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}}, got, "they should be equal")
+
+	got = collect(Window(ctx, Generator(ctx, 1, 2, 3, 4, 5, 6, 7), 2, 3))
+	assert.Equal(t, [][]int{{1, 2}, {4, 5}}, got, "they should be equal")
+}
@@ -0,0 +1,318 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/uerrors"
+)
+
+var (
+	// ErrClosed is returned when adding to a closed Batcher.
+	ErrClosed = errors.New("ubatch: batcher closed")
+	// ErrFull is returned by TryAdd when the queue is full.
+	ErrFull = errors.New("ubatch: queue full")
+)
+
+const (
+	// DefaultMaxSize is the default number of items of a batch.
+	DefaultMaxSize = 100
+	// DefaultMaxWait is the default time an item waits for its batch to
+	// fill up.
+	DefaultMaxWait = time.Second
+)
+
+// FlushFunc handles a batch of items. ctx is cancelled when Close gives
+// up waiting.
+type FlushFunc[T any] func(ctx context.Context, items []T) error
+
+type options struct {
+	maxSize   int
+	maxWait   time.Duration
+	queueSize int
+	onError   func(n int, err error)
+}
+
+// Option configures a Batcher.
+type Option func(*options)
+
+// WithMaxSize sets the number of items flushing a batch, default is
+// DefaultMaxSize.
+func WithMaxSize(n int) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// WithMaxWait sets the time after its first item a batch is flushed even
+// if not full, default is DefaultMaxWait. 0 waits until it is full.
+func WithMaxWait(d time.Duration) Option {
+	return func(o *options) {
+		o.maxWait = d
+	}
+}
+
+// WithQueueSize sets how many items can wait while a batch is flushed
+// before Add blocks, default is the max size.
+func WithQueueSize(n int) Option {
+	return func(o *options) {
+		o.queueSize = n
+	}
+}
+
+// WithErrorHandler sets the function called with the size of a batch and
+// the error of its flush, by default the error is logged to
+// log.DefaultLog().
+func WithErrorHandler(fn func(n int, err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+/*
+Batcher accumulates items and flushes them in batches, when MaxSize items
+are pending or MaxWait elapsed since the first one. Flushes run one at a
+time in a background goroutine; while one runs Add queues up to QueueSize
+items, then blocks, slowing producers down to the pace of the flushes.
+
+	b := ubatch.New(func(ctx context.Context, rows []Row) error {
+		return db.InsertMany(ctx, rows)
+	}, ubatch.WithMaxSize(500), ubatch.WithMaxWait(time.Second))
+	b.Add(ctx, row)
+	...
+	b.Close(ctx)
+*/
+type Batcher[T any] struct {
+	opts  *options
+	flush FlushFunc[T]
+
+	in       chan T
+	flushReq chan chan error
+	done     chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	// closing is closed by Close to wake up the blocked Add, in is closed
+	// once the running Add returned.
+	closing   chan struct{}
+	producers sync.WaitGroup
+
+	lock   sync.Mutex
+	closed bool
+}
+
+// New returns a Batcher calling flush with the batches.
+func New[T any](flush FlushFunc[T], opts ...Option) *Batcher[T] {
+	o := &options{maxSize: DefaultMaxSize, maxWait: DefaultMaxWait}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.maxSize <= 0 {
+		o.maxSize = DefaultMaxSize
+	}
+	if o.queueSize <= 0 {
+		o.queueSize = o.maxSize
+	}
+	if o.onError == nil {
+		o.onError = func(n int, err error) {
+			log.DefaultLog().Errorf("ubatch: flush %d items: %+v", n, err)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Batcher[T]{
+		opts:     o,
+		flush:    flush,
+		in:       make(chan T, o.queueSize),
+		flushReq: make(chan chan error),
+		done:     make(chan struct{}),
+		closing:  make(chan struct{}),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	go b.run()
+	return b
+}
+
+// enter registers a producer, it returns false once b is closed.
+func (b *Batcher[T]) enter() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.closed {
+		return false
+	}
+	b.producers.Add(1)
+	return true
+}
+
+// Add adds item, waiting for room in the queue until ctx is done or b is
+// closed.
+func (b *Batcher[T]) Add(ctx context.Context, item T) error {
+	if !b.enter() {
+		return ErrClosed
+	}
+	defer b.producers.Done()
+	select {
+	case b.in <- item:
+		return nil
+	case <-b.closing:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryAdd adds item, returning ErrFull instead of waiting when the queue is
+// full.
+func (b *Batcher[T]) TryAdd(item T) error {
+	if !b.enter() {
+		return ErrClosed
+	}
+	defer b.producers.Done()
+	select {
+	case b.in <- item:
+		return nil
+	default:
+		return ErrFull
+	}
+}
+
+// Flush flushes the pending items now and returns the error of the first
+// failed batch.
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	reply := make(chan error, 1)
+	select {
+	case b.flushReq <- reply:
+	case <-b.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting items, the blocked Add return ErrClosed, and
+// flushes the pending ones. If ctx is done first, the context of the
+// running flush is cancelled, the rest is dropped and ctx.Err() is
+// returned.
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	b.lock.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.closing)
+		go func() {
+			b.producers.Wait()
+			close(b.in)
+		}()
+	}
+	b.lock.Unlock()
+
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+	defer b.cancel()
+	var (
+		batch   []T
+		timer   *time.Timer
+		timeout <-chan time.Time
+	)
+	flush := func() error {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		var first error
+		for len(batch) > 0 {
+			n := len(batch)
+			if n > b.opts.maxSize {
+				n = b.opts.maxSize
+			}
+			if err := b.flushBatch(batch[:n]); err != nil && first == nil {
+				first = err
+			}
+			batch = batch[n:]
+		}
+		batch = nil
+		return first
+	}
+	for {
+		select {
+		case item, ok := <-b.in:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) == 1 && b.opts.maxWait > 0 {
+				timer = time.NewTimer(b.opts.maxWait)
+				timeout = timer.C
+			}
+			if len(batch) >= b.opts.maxSize {
+				flush()
+			}
+		case <-timeout:
+			timer, timeout = nil, nil
+			flush()
+		case reply := <-b.flushReq:
+			// take what was queued before the request
+			for n := len(b.in); n > 0; n-- {
+				item, ok := <-b.in
+				if !ok {
+					break
+				}
+				batch = append(batch, item)
+			}
+			reply <- flush()
+		}
+	}
+}
+
+func (b *Batcher[T]) flushBatch(items []T) error {
+	if b.ctx.Err() != nil {
+		// Close gave up
+		return b.ctx.Err()
+	}
+	err := uerrors.Safe(func() error {
+		return b.flush(b.ctx, items)
+	})
+	if err != nil {
+		b.opts.onError(len(items), err)
+	}
+	return err
+}
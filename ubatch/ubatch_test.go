@@ -0,0 +1,164 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubatch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	lock    sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) flush(ctx context.Context, items []int) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.batches = append(r.batches, items)
+	return nil
+}
+
+func (r *recorder) get() [][]int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.batches
+}
+
+func TestMaxSize(t *testing.T) {
+	var r recorder
+	b := New(r.flush, WithMaxSize(3), WithMaxWait(0))
+	ctx := context.Background()
+	for i := 0; i < 7; i++ {
+		assert.Nil(t, b.Add(ctx, i))
+	}
+	assert.Nil(t, b.Close(ctx))
+	assert.Equal(t, [][]int{{0, 1, 2}, {3, 4, 5}, {6}}, r.get(), "they should be equal")
+	assert.Equal(t, ErrClosed, b.Add(ctx, 7), "they should be equal")
+	assert.Equal(t, ErrClosed, b.Flush(ctx), "they should be equal")
+}
+
+func TestMaxWait(t *testing.T) {
+	var r recorder
+	b := New(r.flush, WithMaxSize(10), WithMaxWait(20*time.Millisecond))
+	ctx := context.Background()
+	b.Add(ctx, 1)
+	b.Add(ctx, 2)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, [][]int{{1, 2}}, r.get(), "they should be equal")
+	assert.Nil(t, b.Close(ctx))
+}
+
+func TestFlush(t *testing.T) {
+	var r recorder
+	b := New(r.flush, WithMaxWait(0))
+	ctx := context.Background()
+	b.Add(ctx, 1)
+	b.Add(ctx, 2)
+	assert.Nil(t, b.Flush(ctx))
+	assert.Equal(t, [][]int{{1, 2}}, r.get(), "they should be equal")
+	assert.Nil(t, b.Flush(ctx))
+	assert.Equal(t, 1, len(r.get()), "they should be equal")
+	b.Close(ctx)
+}
+
+func TestBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	b := New(func(ctx context.Context, items []int) error {
+		<-release
+		return nil
+	}, WithMaxSize(1), WithQueueSize(1))
+	ctx := context.Background()
+	assert.Nil(t, b.Add(ctx, 1)) // being flushed
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, b.TryAdd(2)) // queued
+	assert.Equal(t, ErrFull, b.TryAdd(3), "they should be equal")
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Add(timeout, 3), "they should be equal")
+	close(release)
+	assert.Nil(t, b.Close(ctx))
+}
+
+func TestErrorHandler(t *testing.T) {
+	errFlush := errors.New("huangjian")
+	var lock sync.Mutex
+	var sizes []int
+	b := New(func(ctx context.Context, items []int) error {
+		if items[0] == 2 {
+			panic("MDGSF")
+		}
+		return errFlush
+	}, WithMaxWait(0), WithErrorHandler(func(n int, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		sizes = append(sizes, n)
+	}))
+	ctx := context.Background()
+	b.Add(ctx, 1)
+	assert.Equal(t, errFlush, b.Flush(ctx), "they should be equal")
+	b.Add(ctx, 2)
+	assert.NotNil(t, b.Flush(ctx))
+	b.Close(ctx)
+	assert.Equal(t, []int{1, 1}, sizes, "they should be equal")
+}
+
+func TestCloseTimeout(t *testing.T) {
+	b := New(func(ctx context.Context, items []int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithErrorHandler(func(int, error) {}))
+	b.Add(context.Background(), 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Close(ctx), "they should be equal")
+	<-b.done
+}
+
+func TestCloseWithBlockedAdd(t *testing.T) {
+	b := New(func(ctx context.Context, items []int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithMaxSize(1), WithQueueSize(1), WithErrorHandler(func(int, error) {}))
+	ctx := context.Background()
+	b.Add(ctx, 1) // hangs in flush
+	time.Sleep(10 * time.Millisecond)
+	b.Add(ctx, 2) // queued
+	added := make(chan error, 1)
+	go func() {
+		added <- b.Add(ctx, 3) // blocked
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Close(timeout), "they should be equal")
+	assert.Equal(t, ErrClosed, <-added, "they should be equal")
+	<-b.done
+}
@@ -0,0 +1,285 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubreaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State is the state of a Breaker.
+type State int
+
+const (
+	// StateClosed lets every request through and counts failures.
+	StateClosed State = iota
+	// StateOpen rejects every request until OpenTimeout elapsed.
+	StateOpen
+	// StateHalfOpen lets a few probe requests through to test recovery.
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("unknown state %d", int(s))
+}
+
+var (
+	// ErrOpen is returned while the breaker is open.
+	ErrOpen = errors.New("ubreaker: circuit breaker is open")
+	// ErrTooManyRequests is returned in half-open state when all probe
+	// slots are taken.
+	ErrTooManyRequests = errors.New("ubreaker: too many requests")
+)
+
+// Default settings.
+const (
+	DefaultOpenTimeout         = 60 * time.Second
+	DefaultHalfOpenMaxRequests = 1
+	DefaultConsecutiveFailures = 5
+)
+
+// Settings configures a Breaker.
+type Settings struct {
+	// Name is passed to OnStateChange.
+	Name string
+	// ConsecutiveFailures trips the breaker after that many failures in a
+	// row. If both thresholds are 0 DefaultConsecutiveFailures is used.
+	ConsecutiveFailures uint32
+	// FailureRate trips the breaker when failures/requests >= FailureRate,
+	// once MinRequests requests were counted. 0 disables it.
+	FailureRate float64
+	MinRequests uint32
+	// Interval is the period after which the closed state counts are
+	// cleared, 0 means never.
+	Interval time.Duration
+	// OpenTimeout is how long the breaker stays open before half-open.
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of probe requests in half-open
+	// state, the breaker closes once they all succeeded.
+	HalfOpenMaxRequests uint32
+	// IsFailure reports whether err counts as failure, default err != nil.
+	IsFailure func(err error) bool
+	// OnStateChange is called on every state transition, with the breaker
+	// locked, so it must not call methods of the breaker.
+	OnStateChange func(name string, from, to State)
+}
+
+// Counts holds the requests counted in the current state.
+type Counts struct {
+	Requests             uint32
+	Successes            uint32
+	Failures             uint32
+	ConsecutiveSuccesses uint32
+	ConsecutiveFailures  uint32
+}
+
+/*
+Breaker is a circuit breaker. It starts closed; when the failure thresholds
+are reached it opens and rejects requests with ErrOpen; after OpenTimeout it
+goes half-open and lets HalfOpenMaxRequests probes through, which close it
+again on success or reopen it on the first failure.
+*/
+type Breaker struct {
+	settings Settings
+
+	lock       sync.Mutex
+	state      State
+	generation uint64
+	counts     Counts
+	expiry     time.Time // end of the current interval or open timeout
+}
+
+// NewBreaker create a closed Breaker.
+func NewBreaker(settings Settings) *Breaker {
+	if settings.ConsecutiveFailures == 0 && settings.FailureRate <= 0 {
+		settings.ConsecutiveFailures = DefaultConsecutiveFailures
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = DefaultOpenTimeout
+	}
+	if settings.HalfOpenMaxRequests == 0 {
+		settings.HalfOpenMaxRequests = DefaultHalfOpenMaxRequests
+	}
+	if settings.IsFailure == nil {
+		settings.IsFailure = func(err error) bool { return err != nil }
+	}
+	b := &Breaker{settings: settings}
+	b.toState(StateClosed, time.Now())
+	return b
+}
+
+// Name returns the name of the breaker.
+func (b *Breaker) Name() string {
+	return b.settings.Name
+}
+
+// State returns the current state.
+func (b *Breaker) State() State {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	state, _ := b.currentState(time.Now())
+	return state
+}
+
+// Counts returns the counts of the current state.
+func (b *Breaker) Counts() Counts {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.currentState(time.Now())
+	return b.counts
+}
+
+/*
+Execute runs fn if the breaker allows it and records the result. It returns
+ErrOpen or ErrTooManyRequests without calling fn when rejected. A panic in
+fn counts as failure and is re-raised.
+*/
+func (b *Breaker) Execute(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			done(false)
+			panic(v)
+		}
+	}()
+	err = fn()
+	done(!b.settings.IsFailure(err))
+	return err
+}
+
+// Allow checks whether a request may proceed, the caller must report its
+// outcome by calling done exactly once.
+func (b *Breaker) Allow() (done func(success bool), err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	state, generation := b.currentState(now)
+	if state == StateOpen {
+		return nil, ErrOpen
+	}
+	if state == StateHalfOpen && b.counts.Requests >= b.settings.HalfOpenMaxRequests {
+		return nil, ErrTooManyRequests
+	}
+	b.counts.Requests++
+
+	var once sync.Once
+	return func(success bool) {
+		once.Do(func() {
+			b.record(generation, success)
+		})
+	}, nil
+}
+
+func (b *Breaker) record(generation uint64, success bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	state, current := b.currentState(now)
+	if generation != current {
+		// the request started in a previous state, ignore it
+		return
+	}
+	if success {
+		b.counts.Successes++
+		b.counts.ConsecutiveSuccesses++
+		b.counts.ConsecutiveFailures = 0
+		if state == StateHalfOpen && b.counts.ConsecutiveSuccesses >= b.settings.HalfOpenMaxRequests {
+			b.toState(StateClosed, now)
+		}
+		return
+	}
+
+	b.counts.Failures++
+	b.counts.ConsecutiveFailures++
+	b.counts.ConsecutiveSuccesses = 0
+	if state == StateHalfOpen || b.shouldTrip() {
+		b.toState(StateOpen, now)
+	}
+}
+
+func (b *Breaker) shouldTrip() bool {
+	s := b.settings
+	if s.ConsecutiveFailures > 0 && b.counts.ConsecutiveFailures >= s.ConsecutiveFailures {
+		return true
+	}
+	if s.FailureRate > 0 && b.counts.Requests >= s.MinRequests && b.counts.Requests > 0 {
+		return float64(b.counts.Failures)/float64(b.counts.Requests) >= s.FailureRate
+	}
+	return false
+}
+
+// currentState applies time based transitions, must hold lock.
+func (b *Breaker) currentState(now time.Time) (State, uint64) {
+	switch b.state {
+	case StateClosed:
+		if !b.expiry.IsZero() && !now.Before(b.expiry) {
+			b.newGeneration(now)
+		}
+	case StateOpen:
+		if !now.Before(b.expiry) {
+			b.toState(StateHalfOpen, now)
+		}
+	}
+	return b.state, b.generation
+}
+
+func (b *Breaker) toState(state State, now time.Time) {
+	prev := b.state
+	b.state = state
+	b.newGeneration(now)
+	if prev != state && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.settings.Name, prev, state)
+	}
+}
+
+func (b *Breaker) newGeneration(now time.Time) {
+	b.generation++
+	b.counts = Counts{}
+	switch b.state {
+	case StateClosed:
+		if b.settings.Interval > 0 {
+			b.expiry = now.Add(b.settings.Interval)
+		} else {
+			b.expiry = time.Time{}
+		}
+	case StateOpen:
+		b.expiry = now.Add(b.settings.OpenTimeout)
+	default:
+		b.expiry = time.Time{}
+	}
+}
@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errTest = errors.New("huangjian")
+
+func fail() error    { return errTest }
+func succeed() error { return nil }
+
+func TestBreakerConsecutiveFailures(t *testing.T) {
+	var changes []string
+	b := NewBreaker(Settings{
+		Name:                "MDGSF",
+		ConsecutiveFailures: 3,
+		OpenTimeout:         20 * time.Millisecond,
+		HalfOpenMaxRequests: 2,
+		OnStateChange: func(name string, from, to State) {
+			changes = append(changes, name+":"+from.String()+"->"+to.String())
+		},
+	})
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+
+	b.Execute(fail)
+	b.Execute(fail)
+	b.Execute(succeed)
+	b.Execute(fail)
+	b.Execute(fail)
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+	assert.Equal(t, errTest, b.Execute(fail), "they should be equal")
+	assert.Equal(t, StateOpen, b.State(), "they should be equal")
+	assert.Equal(t, ErrOpen, b.Execute(succeed), "they should be equal")
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, StateHalfOpen, b.State(), "they should be equal")
+
+	done1, err := b.Allow()
+	assert.Equal(t, nil, err, "they should be equal")
+	done2, err := b.Allow()
+	assert.Equal(t, nil, err, "they should be equal")
+	_, err = b.Allow()
+	assert.Equal(t, ErrTooManyRequests, err, "they should be equal")
+	done1(true)
+	assert.Equal(t, StateHalfOpen, b.State(), "they should be equal")
+	done2(true)
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+
+	assert.Equal(t, []string{
+		"MDGSF:closed->open",
+		"MDGSF:open->half-open",
+		"MDGSF:half-open->closed",
+	}, changes, "they should be equal")
+}
+
+func TestBreakerHalfOpenFailure(t *testing.T) {
+	b := NewBreaker(Settings{ConsecutiveFailures: 1, OpenTimeout: 10 * time.Millisecond})
+	b.Execute(fail)
+	assert.Equal(t, StateOpen, b.State(), "they should be equal")
+	time.Sleep(15 * time.Millisecond)
+	b.Execute(fail)
+	assert.Equal(t, StateOpen, b.State(), "they should be equal")
+}
+
+func TestBreakerFailureRate(t *testing.T) {
+	b := NewBreaker(Settings{FailureRate: 0.5, MinRequests: 4})
+	b.Execute(fail)
+	b.Execute(fail)
+	b.Execute(fail)
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+	assert.Equal(t, uint32(3), b.Counts().Failures, "they should be equal")
+	b.Execute(succeed)
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+	b.Execute(fail)
+	assert.Equal(t, StateOpen, b.State(), "they should be equal")
+}
+
+func TestBreakerInterval(t *testing.T) {
+	b := NewBreaker(Settings{ConsecutiveFailures: 2, Interval: 10 * time.Millisecond})
+	b.Execute(fail)
+	time.Sleep(15 * time.Millisecond)
+	assert.Equal(t, uint32(0), b.Counts().Requests, "they should be equal")
+	b.Execute(fail)
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+}
+
+func TestBreakerIsFailure(t *testing.T) {
+	b := NewBreaker(Settings{
+		ConsecutiveFailures: 1,
+		IsFailure: func(err error) bool {
+			return err != nil && err != errTest
+		},
+	})
+	b.Execute(fail)
+	assert.Equal(t, StateClosed, b.State(), "they should be equal")
+
+	func() {
+		defer func() { recover() }()
+		b.Execute(func() error { panic("boom") })
+	}()
+	assert.Equal(t, StateOpen, b.State(), "they should be equal")
+}
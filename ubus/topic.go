@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubus
+
+/*
+Topic is a topic whose payloads are of type T, catching mismatched
+payloads at compile time:
+
+	var userCreated = ubus.NewTopic[User](bus, "user.created")
+
+	userCreated.Subscribe(func(u User) { ... })
+	userCreated.Publish(User{Name: "huangjian"})
+*/
+type Topic[T any] struct {
+	bus  *Bus
+	name string
+}
+
+// NewTopic returns the topic name of bus.
+func NewTopic[T any](bus *Bus, name string) *Topic[T] {
+	return &Topic[T]{bus: bus, name: name}
+}
+
+// Name returns the name of t.
+func (t *Topic[T]) Name() string {
+	return t.name
+}
+
+// Publish publishes v on t.
+func (t *Topic[T]) Publish(v T) error {
+	return t.bus.Publish(t.name, v)
+}
+
+// Subscribe calls fn with the payloads published on t, ignoring the ones
+// not of type T published through Bus.Publish.
+func (t *Topic[T]) Subscribe(fn func(T), opts ...SubscribeOption) (*Subscription, error) {
+	return t.bus.Subscribe(t.name, func(e Event) {
+		if v, ok := e.Payload.(T); ok {
+			fn(v)
+		}
+	}, opts...)
+}
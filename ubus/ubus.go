@@ -0,0 +1,377 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/uerrors"
+)
+
+var (
+	// ErrClosed is returned when publishing or subscribing on a closed Bus.
+	ErrClosed = errors.New("ubus: bus closed")
+	// ErrInvalidTopic is returned for empty topics, topics with empty
+	// segments, wildcards in published topics, or ">" not at the end of a
+	// pattern.
+	ErrInvalidTopic = errors.New("ubus: invalid topic")
+)
+
+// Event is a message published on a topic.
+type Event struct {
+	Topic   string
+	Payload interface{}
+}
+
+// Handler handles the events of a subscription.
+type Handler func(Event)
+
+// DropPolicy tells what an asynchronous subscriber does when its queue is
+// full.
+type DropPolicy int
+
+const (
+	// Block blocks the publisher until the queue has room.
+	Block DropPolicy = iota
+	// DropNewest drops the event being published.
+	DropNewest
+	// DropOldest drops the oldest queued event to make room.
+	DropOldest
+)
+
+type options struct {
+	logger *log.Logger
+}
+
+// Option configures a Bus.
+type Option func(*options)
+
+// WithLogger logs the panics of handlers to logger, default is
+// log.DefaultLog().
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+type subOptions struct {
+	async  bool
+	size   int
+	policy DropPolicy
+}
+
+// SubscribeOption configures a subscription.
+type SubscribeOption func(*subOptions)
+
+// WithAsync runs the handler in its own goroutine, fed by a queue of size
+// events, instead of in the goroutine of the publisher.
+func WithAsync(size int) SubscribeOption {
+	return func(o *subOptions) {
+		o.async = true
+		o.size = size
+	}
+}
+
+// WithDropPolicy sets what happens when the queue of an asynchronous
+// subscriber is full, default is Block. DropOldest queues at least one
+// event.
+func WithDropPolicy(policy DropPolicy) SubscribeOption {
+	return func(o *subOptions) {
+		o.policy = policy
+	}
+}
+
+// Subscription is the subscription of a handler to a pattern.
+type Subscription struct {
+	bus     *Bus
+	pattern []string
+	handler Handler
+	opts    subOptions
+	dropped int64
+
+	queue chan Event
+	// closing is closed by close to wake up the blocked deliver, queue is
+	// closed once the running deliver returned.
+	closing chan struct{}
+	senders sync.WaitGroup
+
+	lock   sync.Mutex
+	closed bool
+}
+
+// Pattern returns the pattern of s.
+func (s *Subscription) Pattern() string {
+	return strings.Join(s.pattern, ".")
+}
+
+// Dropped returns the number of events dropped because the queue of s
+// was full.
+func (s *Subscription) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// Unsubscribe stops the delivery of events to s, the publishers blocked on
+// its queue give up. Events already queued are still handled.
+func (s *Subscription) Unsubscribe() {
+	s.bus.remove(s)
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.closing)
+	if s.queue != nil {
+		go func() {
+			s.senders.Wait()
+			close(s.queue)
+		}()
+	}
+}
+
+func (s *Subscription) deliver(e Event) {
+	if !s.opts.async {
+		s.bus.handle(s, e)
+		return
+	}
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return
+	}
+	s.senders.Add(1)
+	s.lock.Unlock()
+	defer s.senders.Done()
+
+	switch s.opts.policy {
+	case DropNewest:
+		select {
+		case s.queue <- e:
+		default:
+			atomic.AddInt64(&s.dropped, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case s.queue <- e:
+				return
+			default:
+			}
+			select {
+			case <-s.queue:
+				atomic.AddInt64(&s.dropped, 1)
+			default:
+			}
+		}
+	default:
+		select {
+		case s.queue <- e:
+		case <-s.closing:
+		}
+	}
+}
+
+/*
+Bus dispatches the events published on topics to the subscribers of
+matching patterns. Topics are dot separated like "user.created", in
+patterns "*" matches one segment and a trailing ">" one or more:
+
+	bus := ubus.New()
+	bus.Subscribe("user.*", func(e ubus.Event) {
+		fmt.Println(e.Topic, e.Payload)
+	})
+	bus.Publish("user.created", user)
+	bus.Close(ctx)
+*/
+type Bus struct {
+	opts *options
+	wg   sync.WaitGroup
+
+	lock   sync.RWMutex
+	subs   []*Subscription
+	closed bool
+}
+
+// New returns a Bus.
+func New(opts ...Option) *Bus {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.logger == nil {
+		o.logger = log.DefaultLog()
+	}
+	return &Bus{opts: o}
+}
+
+func splitTopic(topic string, pattern bool) ([]string, error) {
+	segments := strings.Split(topic, ".")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTopic, topic)
+		case !pattern && (segment == "*" || segment == ">"):
+			return nil, fmt.Errorf("%w: %q has wildcards", ErrInvalidTopic, topic)
+		case segment == ">" && i != len(segments)-1:
+			return nil, fmt.Errorf("%w: %q has > not at the end", ErrInvalidTopic, topic)
+		}
+	}
+	return segments, nil
+}
+
+func match(pattern, topic []string) bool {
+	for i, p := range pattern {
+		if p == ">" {
+			return len(topic) > i
+		}
+		if i >= len(topic) || p != "*" && p != topic[i] {
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
+
+// Subscribe calls handler with the events published on the topics
+// matching pattern, synchronously in the goroutine of Publish unless
+// WithAsync is given.
+func (b *Bus) Subscribe(pattern string, handler Handler, opts ...SubscribeOption) (*Subscription, error) {
+	segments, err := splitTopic(pattern, true)
+	if err != nil {
+		return nil, err
+	}
+	s := &Subscription{bus: b, pattern: segments, handler: handler, closing: make(chan struct{})}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	if s.opts.policy == DropOldest && s.opts.size < 1 {
+		// there must be an oldest event to drop
+		s.opts.size = 1
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.closed {
+		return nil, ErrClosed
+	}
+	if s.opts.async {
+		s.queue = make(chan Event, s.opts.size)
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for e := range s.queue {
+				b.handle(s, e)
+			}
+		}()
+	}
+	b.subs = append(b.subs, s)
+	return s, nil
+}
+
+func (b *Bus) remove(s *Subscription) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for i, sub := range b.subs {
+		if sub == s {
+			b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+			return
+		}
+	}
+}
+
+// handle calls the handler of s, logging its panic.
+func (b *Bus) handle(s *Subscription, e Event) {
+	err := uerrors.Safe(func() error {
+		s.handler(e)
+		return nil
+	})
+	if err != nil {
+		b.opts.logger.Errorf("ubus: handler of %q on %q: %+v", s.Pattern(), e.Topic, err)
+	}
+}
+
+// Publish sends payload to the subscribers of topic, in the order they
+// subscribed. It returns once synchronous subscribers handled it and it is
+// queued for asynchronous ones.
+func (b *Bus) Publish(topic string, payload interface{}) error {
+	segments, err := splitTopic(topic, false)
+	if err != nil {
+		return err
+	}
+	b.lock.RLock()
+	if b.closed {
+		b.lock.RUnlock()
+		return ErrClosed
+	}
+	var subs []*Subscription
+	for _, s := range b.subs {
+		if match(s.pattern, segments) {
+			subs = append(subs, s)
+		}
+	}
+	b.lock.RUnlock()
+
+	e := Event{Topic: topic, Payload: payload}
+	for _, s := range subs {
+		s.deliver(e)
+	}
+	return nil
+}
+
+// Close stops accepting events and subscriptions, then waits until the
+// asynchronous subscribers handled their queued events or ctx is done.
+func (b *Bus) Close(ctx context.Context) error {
+	b.lock.Lock()
+	if b.closed {
+		b.lock.Unlock()
+		return nil
+	}
+	b.closed = true
+	subs := b.subs
+	b.subs = nil
+	b.lock.Unlock()
+
+	for _, s := range subs {
+		s.close()
+	}
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
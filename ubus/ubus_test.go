@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		topic   string
+		want    bool
+	}{
+		{"user.created", "user.created", true},
+		{"user.created", "user.deleted", false},
+		{"user.*", "user.created", true},
+		{"user.*", "user.created.now", false},
+		{"*.created", "user.created", true},
+		{"user.>", "user.created.now", true},
+		{"user.>", "user", false},
+		{">", "user", true},
+	}
+	for _, tt := range tests {
+		p, err := splitTopic(tt.pattern, true)
+		assert.Nil(t, err)
+		topic, err := splitTopic(tt.topic, false)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.want, match(p, topic), tt.pattern+" "+tt.topic)
+	}
+}
+
+func TestInvalidTopic(t *testing.T) {
+	b := New()
+	_, err := b.Subscribe("user..created", func(Event) {})
+	assert.True(t, errors.Is(err, ErrInvalidTopic))
+	_, err = b.Subscribe("user.>.created", func(Event) {})
+	assert.True(t, errors.Is(err, ErrInvalidTopic))
+	assert.True(t, errors.Is(b.Publish("user.*", nil), ErrInvalidTopic))
+}
+
+func TestPublishSync(t *testing.T) {
+	b := New()
+	var got []string
+	b.Subscribe("user.*", func(e Event) {
+		got = append(got, "a "+e.Topic+" "+e.Payload.(string))
+	})
+	sub, _ := b.Subscribe("user.created", func(e Event) {
+		got = append(got, "b "+e.Topic)
+	})
+	assert.Nil(t, b.Publish("user.created", "huangjian"))
+	sub.Unsubscribe()
+	assert.Nil(t, b.Publish("user.created", "MDGSF"))
+	assert.Nil(t, b.Publish("order.created", "MDGSF"))
+	assert.Equal(t, []string{"a user.created huangjian", "b user.created", "a user.created MDGSF"}, got, "they should be equal")
+}
+
+func TestPublishAsync(t *testing.T) {
+	b := New()
+	var lock sync.Mutex
+	var got []interface{}
+	b.Subscribe("user.>", func(e Event) {
+		lock.Lock()
+		defer lock.Unlock()
+		got = append(got, e.Payload)
+	}, WithAsync(10))
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, b.Publish("user.created", i))
+	}
+	assert.Nil(t, b.Close(context.Background()))
+	assert.Equal(t, 100, len(got), "they should be equal")
+	assert.Equal(t, 99, got[99], "they should be equal")
+
+	assert.Equal(t, ErrClosed, b.Publish("user.created", 100), "they should be equal")
+	_, err := b.Subscribe("user.>", func(Event) {})
+	assert.Equal(t, ErrClosed, err, "they should be equal")
+}
+
+func TestDropPolicy(t *testing.T) {
+	for _, policy := range []DropPolicy{DropNewest, DropOldest} {
+		b := New()
+		release := make(chan struct{})
+		var got []interface{}
+		sub, _ := b.Subscribe("tick", func(e Event) {
+			<-release
+			got = append(got, e.Payload)
+		}, WithAsync(2), WithDropPolicy(policy))
+		b.Publish("tick", 0)
+		time.Sleep(10 * time.Millisecond) // the worker is blocked on 0
+		for i := 1; i <= 4; i++ {
+			b.Publish("tick", i)
+		}
+		close(release)
+		assert.Nil(t, b.Close(context.Background()))
+		assert.Equal(t, int64(2), sub.Dropped(), "they should be equal")
+		if policy == DropNewest {
+			assert.Equal(t, []interface{}{0, 1, 2}, got, "they should be equal")
+		} else {
+			assert.Equal(t, []interface{}{0, 3, 4}, got, "they should be equal")
+		}
+	}
+}
+
+func TestCloseTimeout(t *testing.T) {
+	b := New()
+	release := make(chan struct{})
+	defer close(release)
+	b.Subscribe("tick", func(Event) { <-release }, WithAsync(1))
+	b.Publish("tick", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Close(ctx), "they should be equal")
+}
+
+func TestHandlerPanic(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", "", 0, log.InfoLevel, log.NotTerminal)
+	b := New(WithLogger(logger))
+	called := false
+	b.Subscribe("user.created", func(Event) { panic("huangjian") })
+	b.Subscribe("user.created", func(Event) { called = true })
+	assert.Nil(t, b.Publish("user.created", nil))
+	assert.True(t, called)
+	assert.Contains(t, buf.String(), "huangjian")
+}
+
+func TestCloseBlockedPublisher(t *testing.T) {
+	b := New()
+	release := make(chan struct{})
+	defer close(release)
+	b.Subscribe("tick", func(Event) { <-release }, WithAsync(1))
+	b.Publish("tick", 0) // stuck in the handler
+	time.Sleep(10 * time.Millisecond)
+	b.Publish("tick", 1) // queued
+	published := make(chan error, 1)
+	go func() {
+		published <- b.Publish("tick", 2) // blocked
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.Close(ctx), "they should be equal")
+	assert.Nil(t, <-published)
+}
+
+func TestDropOldestUnbuffered(t *testing.T) {
+	b := New()
+	var lock sync.Mutex
+	var got []interface{}
+	sub, _ := b.Subscribe("tick", func(e Event) {
+		lock.Lock()
+		defer lock.Unlock()
+		got = append(got, e.Payload)
+	}, WithAsync(0), WithDropPolicy(DropOldest))
+	for i := 0; i < 100; i++ {
+		b.Publish("tick", i)
+	}
+	assert.Nil(t, b.Close(context.Background()))
+	assert.Equal(t, int64(100), int64(len(got))+sub.Dropped(), "they should be equal")
+	assert.Equal(t, 99, got[len(got)-1], "they should be equal")
+}
@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubytes
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Byte size units.
+const (
+	B int64 = 1
+
+	KB int64 = 1000
+	MB       = KB * 1000
+	GB       = MB * 1000
+	TB       = GB * 1000
+	PB       = TB * 1000
+	EB       = PB * 1000
+
+	KiB int64 = 1 << 10
+	MiB       = KiB << 10
+	GiB       = MiB << 10
+	TiB       = GiB << 10
+	PiB       = TiB << 10
+	EiB       = PiB << 10
+)
+
+var (
+	iecUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits  = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+)
+
+// units maps lower case unit names to their size. A single letter is SI,
+// like Kubernetes quantities; "Ki", "KiB" and friends are IEC.
+var units = map[string]int64{
+	"": B, "b": B,
+	"k": KB, "kb": KB, "ki": KiB, "kib": KiB,
+	"m": MB, "mb": MB, "mi": MiB, "mib": MiB,
+	"g": GB, "gb": GB, "gi": GiB, "gib": GiB,
+	"t": TB, "tb": TB, "ti": TiB, "tib": TiB,
+	"p": PB, "pb": PB, "pi": PiB, "pib": PiB,
+	"e": EB, "eb": EB, "ei": EiB, "eib": EiB,
+}
+
+/*
+Humanize formats n bytes with IEC units (powers of 1024).
+
+	Humanize(123456789) == "117.7 MiB"
+	Humanize(1000) == "1000 B"
+*/
+func Humanize(n int64) string {
+	return humanize(n, 1024, iecUnits)
+}
+
+/*
+HumanizeSI formats n bytes with SI units (powers of 1000).
+
+	HumanizeSI(123456789) == "123.5 MB"
+*/
+func HumanizeSI(n int64) string {
+	return humanize(n, 1000, siUnits)
+}
+
+func humanize(n int64, base float64, names []string) string {
+	sign := ""
+	value := float64(n)
+	if n < 0 {
+		sign = "-"
+		value = -value
+	}
+	if value < base {
+		return fmt.Sprintf("%s%d %s", sign, int64(value), names[0])
+	}
+
+	exp := 0
+	for value >= base && exp < len(names)-1 {
+		value /= base
+		exp++
+	}
+	// 1023.97 KiB would print as "1024.0 KiB"
+	if value >= base-0.05 && exp < len(names)-1 {
+		value /= base
+		exp++
+	}
+	return fmt.Sprintf("%s%.1f %s", sign, value, names[exp])
+}
+
+/*
+Parse parses a human readable byte size, the unit is case insensitive.
+
+	Parse("1.5GB") == 1500000000
+	Parse("1.5GiB") == 1610612736
+	Parse("512 k") == 512000
+	Parse("42") == 42
+
+A single letter unit is SI, "Ki", "KiB" and friends are IEC. Fractions
+are rounded down to whole bytes.
+*/
+func Parse(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	number, unit := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	if number == "" {
+		return 0, fmt.Errorf("ubytes: invalid size %q", s)
+	}
+	mult, ok := units[unit]
+	if !ok {
+		return 0, fmt.Errorf("ubytes: unknown unit %q in size %q", s[i:], s)
+	}
+
+	if !strings.Contains(number, ".") {
+		n, err := strconv.ParseInt(number, 10, 64)
+		if err != nil || n > math.MaxInt64/mult {
+			return 0, fmt.Errorf("ubytes: size %q out of range", s)
+		}
+		return n * mult, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ubytes: invalid size %q", s)
+	}
+	f *= float64(mult)
+	if f >= math.MaxInt64 {
+		return 0, fmt.Errorf("ubytes: size %q out of range", s)
+	}
+	return int64(f), nil
+}
+
+// MustParse is like Parse but panics on error, for constants.
+func MustParse(s string) int64 {
+	n, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
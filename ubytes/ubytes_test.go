@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ubytes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanize(t *testing.T) {
+	tests := []struct {
+		n   int64
+		iec string
+		si  string
+	}{
+		{0, "0 B", "0 B"},
+		{1000, "1000 B", "1.0 kB"},
+		{1024, "1.0 KiB", "1.0 kB"},
+		{1536, "1.5 KiB", "1.5 kB"},
+		{123456789, "117.7 MiB", "123.5 MB"},
+		{1048575, "1.0 MiB", "1.0 MB"},
+		{-2048, "-2.0 KiB", "-2.0 kB"},
+		{math.MaxInt64, "8.0 EiB", "9.2 EB"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.iec, Humanize(tt.n), "they should be equal")
+		assert.Equal(t, tt.si, HumanizeSI(tt.n), "they should be equal")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int64
+	}{
+		{"42", 42},
+		{"42B", 42},
+		{"1.5GB", 1500000000},
+		{"1.5GiB", 1610612736},
+		{"1.5 gib", 1610612736},
+		{"512 k", 512000},
+		{"512Ki", 512 * 1024},
+		{" 10 MB ", 10 * MB},
+		{"0.5KiB", 512},
+		{"7EiB", 7 * EiB},
+	}
+	for _, tt := range tests {
+		n, err := Parse(tt.s)
+		assert.Equal(t, nil, err, tt.s)
+		assert.Equal(t, tt.want, n, tt.s)
+	}
+	assert.Equal(t, 7*EiB, MustParse("7EiB"), "they should be equal")
+
+	for _, s := range []string{"", "MB", "huangjian", "1.5XB", "-1", "1..5MB", "8EiB", "10EB"} {
+		_, err := Parse(s)
+		assert.NotEqual(t, nil, err, s)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, n := range []int64{0, 1, 999, 1024, 5 * MiB, 3 * GiB} {
+		parsed, err := Parse(Humanize(n))
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, n, parsed, "they should be equal")
+	}
+}
@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFU(t *testing.T) {
+	var evicted []string
+	c := NewLFU[string, int](Config[string, int]{
+		Capacity: 3,
+		OnEvict: func(key string, value int) {
+			evicted = append(evicted, key)
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Get("c")
+
+	// b and c have the same frequency, b was used less recently
+	c.Set("d", 4)
+	assert.Equal(t, []string{"b"}, evicted, "they should be equal")
+
+	// d is the least frequently used now
+	c.Set("e", 5)
+	assert.Equal(t, []string{"b", "d"}, evicted, "they should be equal")
+
+	_, ok := c.Get("a")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 3, c.Len(), "they should be equal")
+
+	c.Purge()
+	assert.Equal(t, 0, c.Len(), "they should be equal")
+	assert.Equal(t, 2, len(evicted), "they should be equal")
+}
@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucache
+
+// NewLRU create a cache which evicts the least recently used entry first.
+func NewLRU[K comparable, V any](conf Config[K, V]) *Cache[K, V] {
+	return newCache[K, V](conf, &lruPolicy[K, V]{})
+}
+
+// lruPolicy keeps entries in a doubly linked list, most recent at head.
+type lruPolicy[K comparable, V any] struct {
+	head, tail *entry[K, V]
+}
+
+func (p *lruPolicy[K, V]) add(e *entry[K, V]) {
+	e.prev = nil
+	e.next = p.head
+	if p.head != nil {
+		p.head.prev = e
+	} else {
+		p.tail = e
+	}
+	p.head = e
+}
+
+func (p *lruPolicy[K, V]) access(e *entry[K, V]) {
+	if p.head == e {
+		return
+	}
+	p.remove(e)
+	p.add(e)
+}
+
+func (p *lruPolicy[K, V]) remove(e *entry[K, V]) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		p.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		p.tail = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+func (p *lruPolicy[K, V]) victim() *entry[K, V] {
+	return p.tail
+}
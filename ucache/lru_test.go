@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	var evicted []string
+	c := NewLRU[string, int](Config[string, int]{
+		Capacity: 2,
+		OnEvict: func(key string, value int) {
+			evicted = append(evicted, key)
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")
+	c.Set("c", 3)
+
+	assert.Equal(t, []string{"b"}, evicted, "they should be equal")
+	_, ok := c.Get("b")
+	assert.Equal(t, false, ok, "they should be equal")
+	v, ok := c.Get("a")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 1, v, "they should be equal")
+
+	// Peek does not refresh "c"
+	c.Peek("c")
+	c.Get("a")
+	c.Set("d", 4)
+	assert.Equal(t, []string{"b", "c"}, evicted, "they should be equal")
+	assert.Equal(t, 2, c.Len(), "they should be equal")
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(3), stats.Hits, "they should be equal")
+	assert.Equal(t, uint64(1), stats.Misses, "they should be equal")
+	assert.Equal(t, uint64(2), stats.Evictions, "they should be equal")
+	assert.Equal(t, 0.75, stats.HitRate(), "they should be equal")
+}
+
+func TestLRUSize(t *testing.T) {
+	c := NewLRU[string, string](Config[string, string]{
+		MaxSize: 10,
+		SizeFunc: func(key string, value string) int64 {
+			return int64(len(value))
+		},
+	})
+	c.Set("a", "12345")
+	c.Set("b", "1234")
+	assert.Equal(t, int64(9), c.Size(), "they should be equal")
+
+	c.Set("c", "123")
+	assert.Equal(t, int64(7), c.Size(), "they should be equal")
+	_, ok := c.Get("a")
+	assert.Equal(t, false, ok, "they should be equal")
+
+	c.Set("big", "12345678901")
+	_, ok = c.Get("big")
+	assert.Equal(t, false, ok, "they should be equal")
+	assert.Equal(t, int64(7), c.Size(), "they should be equal")
+
+	c.Set("b", "1")
+	assert.Equal(t, int64(4), c.Size(), "they should be equal")
+	assert.Equal(t, true, c.Delete("b"), "they should be equal")
+	assert.Equal(t, int64(3), c.Size(), "they should be equal")
+}
@@ -0,0 +1,367 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/uerrors"
+)
+
+// Config configures a Cache. The zero value is an unbounded cache.
+type Config[K comparable, V any] struct {
+	// Capacity is the max number of entries, 0 means unlimited.
+	Capacity int
+	// MaxSize is the max total size of entries as reported by SizeFunc,
+	// 0 disables size based eviction.
+	MaxSize int64
+	// SizeFunc returns the size of an entry, default every entry is 1.
+	SizeFunc func(key K, value V) int64
+	// TTL is the default time to live used by Set and GetOrLoad,
+	// 0 means never expire.
+	TTL time.Duration
+	// OnEvict is called after an entry is evicted by the policy or
+	// expires. It is not called for Delete or Purge.
+	OnEvict func(key K, value V)
+}
+
+// Stats holds cache metrics.
+type Stats struct {
+	Hits       uint64
+	Misses     uint64
+	Evictions  uint64
+	Expired    uint64
+	Loads      uint64
+	LoadErrors uint64
+}
+
+// HitRate returns Hits / (Hits + Misses), 0 if there was no lookup.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	size     int64
+	expireAt time.Time
+
+	// policy bookkeeping
+	prev, next *entry[K, V] // lru list
+	index      int          // lfu heap index
+	freq       uint64
+	tick       uint64
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// policy decides which entry is evicted next.
+type policy[K comparable, V any] interface {
+	add(e *entry[K, V])
+	access(e *entry[K, V])
+	remove(e *entry[K, V])
+	victim() *entry[K, V]
+}
+
+/*
+Cache is a concurrent-safe cache whose eviction order is decided by its
+policy, create it with NewLRU or NewLFU.
+*/
+type Cache[K comparable, V any] struct {
+	lock    sync.Mutex
+	conf    Config[K, V]
+	items   map[K]*entry[K, V]
+	policy  policy[K, V]
+	size    int64
+	stats   Stats
+	loading map[K]*call[V]
+}
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+func newCache[K comparable, V any](conf Config[K, V], p policy[K, V]) *Cache[K, V] {
+	if conf.SizeFunc == nil {
+		conf.SizeFunc = func(K, V) int64 { return 1 }
+	}
+	return &Cache[K, V]{
+		conf:    conf,
+		items:   make(map[K]*entry[K, V]),
+		policy:  p,
+		loading: make(map[K]*call[V]),
+	}
+}
+
+// Get returns the value of key, ok is false if absent or expired.
+func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
+	c.lock.Lock()
+	e, evicted := c.get(key)
+	if e != nil {
+		value, ok = e.value, true
+	}
+	c.lock.Unlock()
+	c.notify(evicted)
+	return
+}
+
+// Peek is like Get but neither updates the eviction order nor the stats.
+func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, found := c.items[key]; found && !e.expired(time.Now()) {
+		return e.value, true
+	}
+	return value, false
+}
+
+func (c *Cache[K, V]) get(key K) (*entry[K, V], []*entry[K, V]) {
+	e, found := c.items[key]
+	if !found {
+		c.stats.Misses++
+		return nil, nil
+	}
+	if e.expired(time.Now()) {
+		c.removeEntry(e)
+		c.stats.Misses++
+		c.stats.Expired++
+		return nil, []*entry[K, V]{e}
+	}
+	c.policy.access(e)
+	c.stats.Hits++
+	return e, nil
+}
+
+// Set stores value with the default TTL.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.conf.TTL)
+}
+
+// SetWithTTL stores value which expires after ttl, ttl <= 0 means never.
+// An entry larger than MaxSize is not stored.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.lock.Lock()
+	evicted := c.set(key, value, ttl)
+	c.lock.Unlock()
+	c.notify(evicted)
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) []*entry[K, V] {
+	size := c.conf.SizeFunc(key, value)
+	if old, found := c.items[key]; found {
+		c.removeEntry(old)
+	}
+	if c.conf.MaxSize > 0 && size > c.conf.MaxSize {
+		return nil
+	}
+
+	// make room before inserting, so that a fresh LFU entry is not its
+	// own victim
+	var evicted []*entry[K, V]
+	for c.overflow(1, size) {
+		victim := c.policy.victim()
+		c.removeEntry(victim)
+		c.stats.Evictions++
+		evicted = append(evicted, victim)
+	}
+
+	e := &entry[K, V]{key: key, value: value, size: size}
+	if ttl > 0 {
+		e.expireAt = time.Now().Add(ttl)
+	}
+	c.items[key] = e
+	c.size += size
+	c.policy.add(e)
+	return evicted
+}
+
+// overflow reports whether adding count entries of size would exceed limits.
+func (c *Cache[K, V]) overflow(count int, size int64) bool {
+	if len(c.items) == 0 {
+		return false
+	}
+	if c.conf.Capacity > 0 && len(c.items)+count > c.conf.Capacity {
+		return true
+	}
+	return c.conf.MaxSize > 0 && c.size+size > c.conf.MaxSize
+}
+
+func (c *Cache[K, V]) removeEntry(e *entry[K, V]) {
+	c.policy.remove(e)
+	delete(c.items, e.key)
+	c.size -= e.size
+}
+
+func (c *Cache[K, V]) notify(evicted []*entry[K, V]) {
+	if c.conf.OnEvict == nil {
+		return
+	}
+	for _, e := range evicted {
+		c.conf.OnEvict(e.key, e.value)
+	}
+}
+
+/*
+GetOrLoad returns the cached value of key, or calls loader to produce it and
+caches the result with the default TTL. Concurrent callers for the same
+missing key share a single loader call. Errors are returned to every waiting
+caller and are not cached. If loader panics, the waiting callers get a
+*uerrors.PanicError and the panic goes on in the caller which ran it.
+*/
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	c.lock.Lock()
+	e, evicted := c.get(key)
+	if e != nil {
+		value := e.value
+		c.lock.Unlock()
+		return value, nil
+	}
+	if cl, found := c.loading[key]; found {
+		c.lock.Unlock()
+		c.notify(evicted)
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+	cl := &call[V]{}
+	cl.wg.Add(1)
+	c.loading[key] = cl
+	c.lock.Unlock()
+	c.notify(evicted)
+
+	defer func() {
+		r := recover()
+		if r != nil {
+			cl.err = uerrors.NewPanicError(r)
+		}
+		c.lock.Lock()
+		delete(c.loading, key)
+		c.lock.Unlock()
+		cl.wg.Done()
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	cl.value, cl.err = loader(key)
+
+	c.lock.Lock()
+	c.stats.Loads++
+	if cl.err != nil {
+		c.stats.LoadErrors++
+		evicted = nil
+	} else {
+		evicted = c.set(key, cl.value, c.conf.TTL)
+	}
+	c.lock.Unlock()
+	c.notify(evicted)
+	return cl.value, cl.err
+}
+
+// Delete removes key, returns whether it was present.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, found := c.items[key]
+	if !found {
+		return false
+	}
+	c.removeEntry(e)
+	return true
+}
+
+// Len returns the number of entries, including expired ones not yet removed.
+func (c *Cache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.items)
+}
+
+// Size returns the total size of entries as reported by SizeFunc.
+func (c *Cache[K, V]) Size() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.size
+}
+
+// Keys returns the keys of live entries, in no particular order.
+func (c *Cache[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	keys := make([]K, 0, len(c.items))
+	for k, e := range c.items {
+		if !e.expired(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// DeleteExpired removes all expired entries, returns how many were removed.
+func (c *Cache[K, V]) DeleteExpired() int {
+	c.lock.Lock()
+	now := time.Now()
+	var evicted []*entry[K, V]
+	for _, e := range c.items {
+		if e.expired(now) {
+			c.removeEntry(e)
+			c.stats.Expired++
+			evicted = append(evicted, e)
+		}
+	}
+	c.lock.Unlock()
+	c.notify(evicted)
+	return len(evicted)
+}
+
+// Purge removes all entries, stats are kept.
+func (c *Cache[K, V]) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, e := range c.items {
+		c.removeEntry(e)
+	}
+}
+
+// Stats returns a snapshot of the cache metrics.
+func (c *Cache[K, V]) Stats() Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stats
+}
+
+// ResetStats clears the cache metrics.
+func (c *Cache[K, V]) ResetStats() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.stats = Stats{}
+}
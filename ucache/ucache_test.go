@@ -0,0 +1,174 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucache
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/uerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheTTL(t *testing.T) {
+	expired := 0
+	c := NewLRU[string, int](Config[string, int]{
+		TTL: 20 * time.Millisecond,
+		OnEvict: func(key string, value int) {
+			expired++
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.SetWithTTL("c", 3, 0)
+	assert.Equal(t, []string{"a", "b", "c"}, sortedKeys(c), "they should be equal")
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok := c.Get("a")
+	assert.Equal(t, false, ok, "they should be equal")
+	assert.Equal(t, 1, expired, "they should be equal")
+	assert.Equal(t, []string{"c"}, sortedKeys(c), "they should be equal")
+
+	assert.Equal(t, 1, c.DeleteExpired(), "they should be equal")
+	assert.Equal(t, 2, expired, "they should be equal")
+	assert.Equal(t, uint64(2), c.Stats().Expired, "they should be equal")
+	assert.Equal(t, 1, c.Len(), "they should be equal")
+}
+
+func sortedKeys(c *Cache[string, int]) []string {
+	keys := c.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+func TestCacheGetOrLoadPanic(t *testing.T) {
+	c := NewLRU[string, int](Config[string, int]{})
+	release := make(chan struct{})
+	loader := func(key string) (int, error) {
+		<-release
+		panic("boom")
+	}
+
+	var wg sync.WaitGroup
+	var panics, panicErrors int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					assert.Equal(t, "boom", r, "they should be equal")
+					atomic.AddInt32(&panics, 1)
+				}
+			}()
+			_, err := c.GetOrLoad("huangjian", loader)
+			var perr *uerrors.PanicError
+			if errors.As(err, &perr) {
+				assert.Equal(t, "boom", perr.Value, "they should be equal")
+				atomic.AddInt32(&panicErrors, 1)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), panics, "they should be equal")
+	assert.Equal(t, int32(9), panicErrors, "they should be equal")
+	_, ok := c.Peek("huangjian")
+	assert.Equal(t, false, ok, "they should be equal")
+}
+
+func TestCacheGetOrLoad(t *testing.T) {
+	c := NewLFU[string, int](Config[string, int]{Capacity: 10})
+
+	var calls int32
+	release := make(chan struct{})
+	loader := func(key string) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return len(key), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad("huangjian", loader)
+			assert.Equal(t, nil, err, "they should be equal")
+			results[i] = v
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "they should be equal")
+	for _, v := range results {
+		assert.Equal(t, 9, v, "they should be equal")
+	}
+	v, ok := c.Get("huangjian")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 9, v, "they should be equal")
+	assert.Equal(t, uint64(1), c.Stats().Loads, "they should be equal")
+
+	errLoad := errors.New("load failed")
+	_, err := c.GetOrLoad("MDGSF", func(key string) (int, error) {
+		return 0, errLoad
+	})
+	assert.Equal(t, errLoad, err, "they should be equal")
+	_, ok = c.Peek("MDGSF")
+	assert.Equal(t, false, ok, "they should be equal")
+	assert.Equal(t, uint64(1), c.Stats().LoadErrors, "they should be equal")
+
+	c.ResetStats()
+	assert.Equal(t, Stats{}, c.Stats(), "they should be equal")
+}
+
+func BenchmarkLRUGet(b *testing.B) {
+	c := NewLRU[int, int](Config[int, int]{Capacity: 1024})
+	for i := 0; i < 1024; i++ {
+		c.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(i & 1023)
+	}
+}
+
+func BenchmarkLFUGet(b *testing.B) {
+	c := NewLFU[int, int](Config[int, int]{Capacity: 1024})
+	for i := 0; i < 1024; i++ {
+		c.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(i & 1023)
+	}
+}
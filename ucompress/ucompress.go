@@ -0,0 +1,224 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucompress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Format is a compression format.
+type Format int
+
+const (
+	// Gzip is the gzip format, RFC 1952.
+	Gzip Format = iota
+	// Zlib is the zlib format, RFC 1950.
+	Zlib
+)
+
+func (f Format) String() string {
+	switch f {
+	case Gzip:
+		return "gzip"
+	case Zlib:
+		return "zlib"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// Compression levels, shared by all formats.
+const (
+	NoCompression      = flate.NoCompression
+	BestSpeed          = flate.BestSpeed
+	BestCompression    = flate.BestCompression
+	DefaultCompression = flate.DefaultCompression
+	HuffmanOnly        = flate.HuffmanOnly
+)
+
+var (
+	// ErrUnknownFormat is returned when the data is neither gzip nor zlib.
+	ErrUnknownFormat = errors.New("ucompress: unknown format")
+	// ErrTooLarge is returned when decompressed data exceeds the limit set
+	// with WithMaxSize.
+	ErrTooLarge = errors.New("ucompress: decompressed data too large")
+)
+
+type options struct {
+	format  Format
+	level   int
+	maxSize int64
+}
+
+// Option configures compression and decompression.
+type Option func(*options)
+
+// WithFormat set the format to write, default Gzip. Readers detect the
+// format by themselves.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithLevel set the compression level, default DefaultCompression.
+func WithLevel(level int) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithMaxSize limits the decompressed size to guard against compression
+// bombs, 0 means no limit.
+func WithMaxSize(n int64) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{format: Gzip, level: DefaultCompression}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// NewCompressingWriter returns a writer compressing into w. Close must be
+// called to flush the remaining data, it does not close w.
+func NewCompressingWriter(w io.Writer, opts ...Option) (io.WriteCloser, error) {
+	o := newOptions(opts)
+	switch o.format {
+	case Gzip:
+		return gzip.NewWriterLevel(w, o.level)
+	case Zlib:
+		return zlib.NewWriterLevel(w, o.level)
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnknownFormat, o.format)
+}
+
+// NewDecompressingReader returns a reader decompressing r, the format is
+// detected from the header.
+func NewDecompressingReader(r io.Reader, opts ...Option) (io.ReadCloser, error) {
+	o := newOptions(opts)
+	br := bufio.NewReader(r)
+	format, err := detect(br)
+	if err != nil {
+		return nil, err
+	}
+
+	var rc io.ReadCloser
+	switch format {
+	case Gzip:
+		rc, err = gzip.NewReader(br)
+	case Zlib:
+		rc, err = zlib.NewReader(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.maxSize > 0 {
+		rc = &limitReader{rc: rc, left: o.maxSize}
+	}
+	return rc, nil
+}
+
+// Detect reports the format of compressed data.
+func Detect(data []byte) (Format, error) {
+	return detect(bufio.NewReader(bytes.NewReader(data)))
+}
+
+func detect(br *bufio.Reader) (Format, error) {
+	header, err := br.Peek(2)
+	if err != nil {
+		if err == io.EOF {
+			err = ErrUnknownFormat
+		}
+		return 0, err
+	}
+	if header[0] == 0x1f && header[1] == 0x8b {
+		return Gzip, nil
+	}
+	// CM must be deflate and CMF*256+FLG a multiple of 31
+	if header[0]&0x0f == 8 && (uint(header[0])<<8|uint(header[1]))%31 == 0 {
+		return Zlib, nil
+	}
+	return 0, ErrUnknownFormat
+}
+
+// Compress compresses data in one go.
+func Compress(data []byte, opts ...Option) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := NewCompressingWriter(&buf, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses gzip or zlib data in one go.
+func Decompress(data []byte, opts ...Option) ([]byte, error) {
+	r, err := NewDecompressingReader(bytes.NewReader(data), opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+type limitReader struct {
+	rc   io.ReadCloser
+	left int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	// read one byte past the limit to tell "exactly the limit" from "more"
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+	n, err := l.rc.Read(p)
+	if int64(n) > l.left {
+		n = int(l.left)
+		l.left = 0
+		return n, ErrTooLarge
+	}
+	l.left -= int64(n)
+	return n, err
+}
+
+func (l *limitReader) Close() error {
+	return l.rc.Close()
+}
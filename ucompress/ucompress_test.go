@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucompress
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testData = []byte(strings.Repeat("huangjian MDGSF ", 1000))
+
+func TestCompressDecompress(t *testing.T) {
+	for _, format := range []Format{Gzip, Zlib} {
+		for _, level := range []int{NoCompression, BestSpeed, DefaultCompression, BestCompression, HuffmanOnly} {
+			compressed, err := Compress(testData, WithFormat(format), WithLevel(level))
+			assert.Equal(t, nil, err, format.String())
+
+			detected, err := Detect(compressed)
+			assert.Equal(t, nil, err, format.String())
+			assert.Equal(t, format, detected, "they should be equal")
+
+			data, err := Decompress(compressed)
+			assert.Equal(t, nil, err, format.String())
+			assert.Equal(t, testData, data, "they should be equal")
+		}
+	}
+
+	compressed, err := Compress(testData)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, len(compressed) < len(testData)/10, "they should be equal")
+}
+
+func TestInvalid(t *testing.T) {
+	_, err := Compress(testData, WithLevel(42))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	_, err = Compress(testData, WithFormat(Format(7)))
+	assert.Equal(t, true, errors.Is(err, ErrUnknownFormat), "they should be equal")
+
+	for _, data := range [][]byte{nil, []byte("h"), []byte("huangjian")} {
+		_, err = Decompress(data)
+		assert.Equal(t, true, errors.Is(err, ErrUnknownFormat), string(data))
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	compressed, _ := Compress(testData)
+
+	data, err := Decompress(compressed, WithMaxSize(int64(len(testData))))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, testData, data, "they should be equal")
+
+	_, err = Decompress(compressed, WithMaxSize(int64(len(testData)-1)))
+	assert.Equal(t, true, errors.Is(err, ErrTooLarge), "they should be equal")
+}
+
+func TestStreaming(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewCompressingWriter(&buf, WithFormat(Zlib), WithLevel(BestSpeed))
+	assert.Equal(t, nil, err, "they should be equal")
+	for i := 0; i < 1000; i++ {
+		w.Write([]byte("huangjian MDGSF "))
+	}
+	assert.Equal(t, nil, w.Close(), "they should be equal")
+
+	r, err := NewDecompressingReader(&buf)
+	assert.Equal(t, nil, err, "they should be equal")
+	data, err := ioutil.ReadAll(r)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, testData, data, "they should be equal")
+	assert.Equal(t, nil, r.Close(), "they should be equal")
+}
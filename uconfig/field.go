@@ -0,0 +1,345 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// field is one settable leaf of the config struct.
+type field struct {
+	path  []string // config names from the root
+	name  string   // dotted path, used in messages
+	value reflect.Value
+	tag   reflect.StructTag
+}
+
+func (f *field) error(source, value string, err error) error {
+	return &FieldError{Field: f.name, Source: source, Value: value, Err: err}
+}
+
+// envName returns the environment variable of the field, "" if none is read.
+func (f *field) envName(o *loadOptions) string {
+	if name, ok := f.tag.Lookup("env"); ok {
+		if name == "-" {
+			return ""
+		}
+		return name
+	}
+	if !o.env {
+		return ""
+	}
+
+	name := strings.ToUpper(strings.Join(f.path, "_"))
+	name = strings.NewReplacer("-", "_", ".", "_").Replace(name)
+	if o.envPrefix != "" {
+		name = o.envPrefix + "_" + name
+	}
+	return name
+}
+
+// flagName returns the flag of the field, "" if it has none.
+func (f *field) flagName() string {
+	if name, ok := f.tag.Lookup("flag"); ok {
+		if name == "-" {
+			return ""
+		}
+		return name
+	}
+	return strings.ToLower(f.name)
+}
+
+// configName returns the name of a struct field, "-" to skip it.
+func configName(sf reflect.StructField) string {
+	if name := sf.Tag.Get("config"); name != "" {
+		return name
+	}
+	if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return sf.Name
+}
+
+// isLeaf reports whether values of t are set as a whole.
+func isLeaf(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return true
+	}
+	return reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func collectFields(v reflect.Value, path []string, fields []*field) []*field {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := configName(sf)
+		if name == "-" {
+			continue
+		}
+
+		fv := v.Field(i)
+		if !isLeaf(sf.Type) {
+			if sf.Anonymous && sf.Tag.Get("config") == "" {
+				fields = collectFields(fv, path, fields)
+			} else if sf.PkgPath == "" {
+				fields = collectFields(fv, appendPath(path, name), fields)
+			}
+			continue
+		}
+		if !fv.CanSet() {
+			continue
+		}
+
+		fp := appendPath(path, name)
+		fields = append(fields, &field{
+			path:  fp,
+			name:  strings.Join(fp, "."),
+			value: fv,
+			tag:   sf.Tag,
+		})
+	}
+	return fields
+}
+
+func appendPath(path []string, name string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, name)
+}
+
+// setValue assigns a decoded file value: lists and maps element by
+// element, everything else through its string form.
+func setValue(v reflect.Value, raw interface{}) error {
+	switch r := raw.(type) {
+	case string:
+		return setString(v, r)
+	case nil:
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	case []interface{}:
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("cannot assign a list to %s", v.Type())
+		}
+		s := reflect.MakeSlice(v.Type(), len(r), len(r))
+		for i, elem := range r {
+			if err := setValue(s.Index(i), elem); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		v.Set(s)
+		return nil
+	case map[string]interface{}:
+		if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("cannot assign a map to %s", v.Type())
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(r))
+		for key, elem := range r {
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := setValue(ev, elem); err != nil {
+				return fmt.Errorf("key %q: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), ev)
+		}
+		v.Set(m)
+		return nil
+	case float64:
+		return setString(v, strconv.FormatFloat(r, 'f', -1, 64))
+	default:
+		return setString(v, fmt.Sprint(r))
+	}
+}
+
+// setString parses s into v. Lists are comma separated, maps are
+// "key=value" pairs separated by commas.
+func setString(v reflect.Value, s string) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("expected a duration like \"1m30s\"")
+		}
+		v.SetInt(int64(d))
+		return nil
+	case timeType:
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return errors.New("expected an RFC 3339 time")
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errors.New("expected true or false")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return numberError(v, err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return numberError(v, err)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return numberError(v, err)
+		}
+		v.SetFloat(n)
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		if err := setString(p.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.Slice:
+		parts := splitList(s)
+		sl := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setString(sl.Index(i), part); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		v.Set(sl)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		parts := splitList(s)
+		m := reflect.MakeMapWithSize(v.Type(), len(parts))
+		for _, part := range parts {
+			kv := strings.SplitN(part, "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("expected key=value, got %q", part)
+			}
+			ev := reflect.New(v.Type().Elem()).Elem()
+			if err := setString(ev, strings.TrimSpace(kv[1])); err != nil {
+				return fmt.Errorf("key %q: %w", kv[0], err)
+			}
+			key := strings.TrimSpace(kv[0])
+			m.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), ev)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func numberError(v reflect.Value, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("out of range for %s", v.Type())
+	}
+	return fmt.Errorf("expected %s", v.Type())
+}
+
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+// fieldFlag collects the command line value of one field.
+type fieldFlag struct {
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (f *fieldFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *fieldFlag) Set(s string) error {
+	f.value, f.set = s, true
+	return nil
+}
+
+func (f *fieldFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// parseFlags defines a flag for every field on fs and parses args.
+func parseFlags(fs *flag.FlagSet, args []string, fields []*field) (map[string]*fieldFlag, error) {
+	if fs == nil {
+		fs = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	}
+
+	flags := make(map[string]*fieldFlag)
+	for _, f := range fields {
+		name := f.flagName()
+		if name == "" {
+			continue
+		}
+		if fs.Lookup(name) != nil {
+			return nil, fmt.Errorf("uconfig: flag -%s of %s is already defined", name, f.name)
+		}
+		fl := &fieldFlag{
+			value:  f.tag.Get("default"),
+			isBool: f.value.Kind() == reflect.Bool,
+		}
+		fs.Var(fl, name, f.tag.Get("usage"))
+		flags[name] = fl
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("uconfig: %w", err)
+	}
+	return flags, nil
+}
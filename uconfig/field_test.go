@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Embedded struct {
+	Level string
+}
+
+type fieldConfig struct {
+	Embedded
+	Name    string `json:"name"`
+	Skip    string `config:"-"`
+	private string
+	Server  struct {
+		Addr string `config:"addr" flag:"listen" env:"ADDR"`
+	} `config:"server"`
+}
+
+func TestCollectFields(t *testing.T) {
+	var cfg fieldConfig
+	fields := collectFields(reflect.ValueOf(&cfg).Elem(), nil, nil)
+
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.name)
+	}
+	assert.Equal(t, []string{"Level", "name", "server.addr"}, names, "they should be equal")
+
+	o := &loadOptions{env: true, envPrefix: "APP"}
+	assert.Equal(t, "APP_LEVEL", fields[0].envName(o), "they should be equal")
+	assert.Equal(t, "level", fields[0].flagName(), "they should be equal")
+	assert.Equal(t, "ADDR", fields[2].envName(o), "they should be equal")
+	assert.Equal(t, "listen", fields[2].flagName(), "they should be equal")
+	assert.Equal(t, "", fields[1].envName(&loadOptions{}), "they should be equal")
+}
+
+func TestSetString(t *testing.T) {
+	var v struct {
+		I   int8
+		U   uint
+		F   float64
+		D   time.Duration
+		T   time.Time
+		P   *int
+		IP  net.IP
+		S   []int
+		M   map[string]bool
+		Bad chan int
+	}
+	rv := reflect.ValueOf(&v).Elem()
+
+	assert.Equal(t, nil, setString(rv.Field(0), "-12"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(1), "0x10"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(2), "1.5"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(3), "1m30s"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(4), "2019-08-13T14:10:00Z"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(5), "7"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(6), "10.0.0.1"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(7), "1, 2,3"), "they should be equal")
+	assert.Equal(t, nil, setString(rv.Field(8), "a=true,b=false"), "they should be equal")
+
+	assert.Equal(t, int8(-12), v.I, "they should be equal")
+	assert.Equal(t, uint(16), v.U, "they should be equal")
+	assert.Equal(t, 1.5, v.F, "they should be equal")
+	assert.Equal(t, 90*time.Second, v.D, "they should be equal")
+	assert.Equal(t, time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC), v.T, "they should be equal")
+	assert.Equal(t, 7, *v.P, "they should be equal")
+	assert.Equal(t, "10.0.0.1", v.IP.String(), "they should be equal")
+	assert.Equal(t, []int{1, 2, 3}, v.S, "they should be equal")
+	assert.Equal(t, map[string]bool{"a": true, "b": false}, v.M, "they should be equal")
+
+	assert.Equal(t, "out of range for int8", setString(rv.Field(0), "300").Error(), "they should be equal")
+	assert.Equal(t, "index 1: expected int", setString(rv.Field(7), "1,x").Error(), "they should be equal")
+	assert.Equal(t, "unsupported type chan int", setString(rv.Field(9), "1").Error(), "they should be equal")
+}
@@ -0,0 +1,154 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/MDGSF/utils/ini"
+	"gopkg.in/yaml.v2"
+)
+
+// source is a parsed config file.
+type source interface {
+	lookup(f *field) (interface{}, bool)
+}
+
+func formatOf(path string) string {
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+}
+
+func parseSource(format string, data []byte) (source, error) {
+	switch strings.ToLower(format) {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var m map[string]interface{}
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("uconfig: parse json: %w", err)
+		}
+		return mapSource(m), nil
+	case "yaml", "yml":
+		var m map[interface{}]interface{}
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("uconfig: parse yaml: %w", err)
+		}
+		return mapSource(normalizeYAML(m).(map[string]interface{})), nil
+	case "ini":
+		conf, err := ini.NewConfigFromData(data)
+		if err != nil {
+			return nil, fmt.Errorf("uconfig: parse ini: %w", err)
+		}
+		return iniSource{conf}, nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// mapSource is a decoded JSON or YAML document.
+type mapSource map[string]interface{}
+
+func (m mapSource) lookup(f *field) (interface{}, bool) {
+	var cur interface{} = map[string]interface{}(m)
+	for _, name := range f.path {
+		node, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = lookupFold(node, name); !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// lookupFold prefers an exact key and falls back to a case-insensitive one.
+func lookupFold(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for key, v := range m {
+		if strings.EqualFold(key, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// normalizeYAML turns the map[interface{}]interface{} of yaml.v2 into
+// map[string]interface{}.
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, elem := range t {
+			m[fmt.Sprint(key)] = normalizeYAML(elem)
+		}
+		return m
+	case []interface{}:
+		for i := range t {
+			t[i] = normalizeYAML(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+/*
+iniSource reads fields from an ini file: top level fields from the default
+section, nested ones from the section named after the parent path.
+
+	name = app
+
+	[db]
+	host = localhost
+*/
+type iniSource struct {
+	conf *ini.Config
+}
+
+func (s iniSource) lookup(f *field) (interface{}, bool) {
+	key := f.path[len(f.path)-1]
+	if len(f.path) == 1 {
+		// The ini package does not tell empty and missing keys apart in
+		// the default section.
+		value := s.conf.String(key)
+		return value, value != ""
+	}
+
+	section, err := s.conf.GetSection(strings.Join(f.path[:len(f.path)-1], "."))
+	if err != nil {
+		return nil, false
+	}
+	for k, v := range section {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fileConfig struct {
+	Name  string         `config:"name"`
+	Ports []int          `config:"ports"`
+	Big   int64          `config:"big"`
+	Rate  float64        `config:"rate"`
+	Label map[string]int `config:"labels"`
+	DB    struct {
+		Host string `config:"host"`
+	} `config:"db"`
+}
+
+func TestParseFormats(t *testing.T) {
+	tests := map[string]string{
+		"json": `{"NAME": "huangjian", "ports": [80, 443], "big": 10000000000, "rate": 0.5,
+			"labels": {"a": 1}, "db": {"host": "MDGSF"}}`,
+		"yaml": "name: huangjian\nports: [80, 443]\nbig: 10000000000\nrate: 0.5\nlabels:\n  a: 1\ndb:\n  host: MDGSF\n",
+		"ini":  "name = huangjian\nports = 80,443\nbig = 10000000000\nrate = 0.5\nlabels = a=1\n\n[db]\nhost = MDGSF\n",
+	}
+	for format, data := range tests {
+		var cfg fileConfig
+		err := Load(&cfg, WithData(format, []byte(data)))
+		assert.Equal(t, nil, err, format)
+		assert.Equal(t, "huangjian", cfg.Name, format)
+		assert.Equal(t, []int{80, 443}, cfg.Ports, format)
+		assert.Equal(t, int64(10000000000), cfg.Big, format)
+		assert.Equal(t, 0.5, cfg.Rate, format)
+		assert.Equal(t, map[string]int{"a": 1}, cfg.Label, format)
+		assert.Equal(t, "MDGSF", cfg.DB.Host, format)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	assert.Equal(t, "yaml", formatOf("/etc/app/config.YAML"), "they should be equal")
+	assert.Equal(t, "json", formatOf("config.json"), "they should be equal")
+	assert.Equal(t, "", formatOf("config"), "they should be equal")
+}
+
+func TestParseSourceErrors(t *testing.T) {
+	var cfg fileConfig
+	err := Load(&cfg, WithData("json", []byte(`{"ports": "x"}`)))
+	assert.Equal(t, `uconfig: ports: invalid value "x" from json data: index 0: expected int`,
+		err.Error(), "they should be equal")
+
+	err = Load(&cfg, WithData("json", []byte(`{"ports": {"a": 1}}`)))
+	assert.Equal(t, `uconfig: ports: invalid value "map[a:1]" from json data: cannot assign a map to []int`,
+		err.Error(), "they should be equal")
+
+	err = Load(&cfg, WithData("yaml", []byte("name: [")))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,283 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidTarget is returned when Load is not given a struct pointer.
+	ErrInvalidTarget = errors.New("uconfig: target must be a non-nil pointer to a struct")
+
+	// ErrMissingRequired is returned when required fields are still empty
+	// after all sources have been applied.
+	ErrMissingRequired = errors.New("uconfig: missing required fields")
+
+	// ErrUnsupportedFormat is returned for config files which are not JSON,
+	// YAML or INI.
+	ErrUnsupportedFormat = errors.New("uconfig: unsupported config format")
+)
+
+// Validator is implemented by configs which check themselves after loading.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError reports a value which could not be assigned to a field.
+type FieldError struct {
+	Field  string // dotted field path, "db.port"
+	Source string // where the value came from, "env APP_DB_PORT"
+	Value  string
+	Err    error
+}
+
+// Error implements error.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("uconfig: %s: invalid value %q from %s: %v",
+		e.Field, e.Value, e.Source, e.Err)
+}
+
+// Unwrap returns the conversion error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+type loadOptions struct {
+	file      string
+	format    string
+	data      []byte
+	env       bool
+	envPrefix string
+	flags     bool
+	flagSet   *flag.FlagSet
+	args      []string
+
+	// parsed keeps the flag values so reloads do not parse args again.
+	parsed map[string]*fieldFlag
+
+	interval time.Duration
+	onError  func(error)
+}
+
+// Option configures Load.
+type Option func(*loadOptions)
+
+// WithFile reads the config file at path. The format is taken from the
+// extension: .json, .yaml, .yml or .ini.
+func WithFile(path string) Option {
+	return func(o *loadOptions) {
+		o.file = path
+	}
+}
+
+// WithData reads config data of the given format, "json", "yaml" or "ini",
+// instead of a file.
+func WithData(format string, data []byte) Option {
+	return func(o *loadOptions) {
+		o.format = format
+		o.data = data
+	}
+}
+
+// WithEnv reads environment variables named after the field path, upper
+// cased and joined by "_": with prefix "APP" field db.host is read from
+// APP_DB_HOST. Fields with an `env` tag are always read from that variable.
+func WithEnv(prefix string) Option {
+	return func(o *loadOptions) {
+		o.env = true
+		o.envPrefix = prefix
+	}
+}
+
+// WithFlags defines a flag for every field on fs, named after the dotted
+// field path or the `flag` tag, and parses args. A nil fs uses a new
+// flag.ContinueOnError set.
+func WithFlags(fs *flag.FlagSet, args []string) Option {
+	return func(o *loadOptions) {
+		o.flags = true
+		o.flagSet = fs
+		o.args = args
+	}
+}
+
+/*
+Load fills the struct pointed to by cfg. Every field is looked up in the
+following sources, later ones win:
+
+ 1. the `default:"..."` tag, applied to fields which are still zero
+ 2. the config file, see WithFile and WithData
+ 3. environment variables, see WithEnv
+ 4. command line flags, see WithFlags
+
+Field names come from the `config` tag, then the `json` tag, then the Go
+name, file keys are matched case-insensitively. Nested structs add a path
+segment, embedded structs are flattened. Fields tagged `required:"true"`
+must be non-zero afterwards, and a cfg implementing Validator is validated
+last. cfg is only modified when loading succeeds.
+
+	type Config struct {
+		Name string `config:"name" required:"true"`
+		Port int    `config:"port" default:"8080" env:"PORT" usage:"listen port"`
+		DB   struct {
+			Host    string        `config:"host" default:"localhost"`
+			Timeout time.Duration `config:"timeout" default:"5s"`
+		} `config:"db"`
+	}
+
+	var cfg Config
+	err := uconfig.Load(&cfg,
+		uconfig.WithFile("app.yaml"),
+		uconfig.WithEnv("APP"),              // APP_NAME, APP_DB_HOST, ...
+		uconfig.WithFlags(nil, os.Args[1:]), // -name, -db.host, ...
+	)
+*/
+func Load(cfg interface{}, opts ...Option) error {
+	var o loadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return load(cfg, &o)
+}
+
+func load(cfg interface{}, o *loadOptions) error {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	// Work on a copy so a failed load leaves cfg untouched.
+	target := reflect.New(rv.Elem().Type()).Elem()
+	target.Set(rv.Elem())
+	fields := collectFields(target, nil, nil)
+
+	file, fileName, err := readSource(o)
+	if err != nil {
+		return err
+	}
+
+	if o.flags && o.parsed == nil {
+		if o.parsed, err = parseFlags(o.flagSet, o.args, fields); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range fields {
+		if def, ok := f.tag.Lookup("default"); ok && f.value.IsZero() {
+			if err := setString(f.value, def); err != nil {
+				return f.error("default tag", def, err)
+			}
+		}
+
+		if file != nil {
+			if raw, ok := file.lookup(f); ok {
+				if err := setValue(f.value, raw); err != nil {
+					return f.error(fileName, fmt.Sprint(raw), err)
+				}
+			}
+		}
+
+		if name := f.envName(o); name != "" {
+			if s, ok := os.LookupEnv(name); ok {
+				if err := setString(f.value, s); err != nil {
+					return f.error("env "+name, s, err)
+				}
+			}
+		}
+
+		if fl := o.parsed[f.flagName()]; fl != nil && fl.set {
+			if err := setString(f.value, fl.value); err != nil {
+				return f.error("flag -"+f.flagName(), fl.value, err)
+			}
+		}
+	}
+
+	if err := checkRequired(fields, o); err != nil {
+		return err
+	}
+
+	if v, ok := target.Addr().Interface().(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("uconfig: invalid config: %w", err)
+		}
+	}
+
+	rv.Elem().Set(target)
+	return nil
+}
+
+// readSource parses the config file or data, nil when there is none. The
+// name describes the source in errors.
+func readSource(o *loadOptions) (source, string, error) {
+	if o.file != "" {
+		data, err := ioutil.ReadFile(o.file)
+		if err != nil {
+			return nil, "", fmt.Errorf("uconfig: %w", err)
+		}
+		src, err := parseSource(formatOf(o.file), data)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w (%s)", err, o.file)
+		}
+		return src, "file " + o.file, nil
+	}
+	if o.data != nil {
+		src, err := parseSource(o.format, o.data)
+		return src, o.format + " data", err
+	}
+	return nil, "", nil
+}
+
+// checkRequired lists every required field which is still zero together
+// with the places it can be set.
+func checkRequired(fields []*field, o *loadOptions) error {
+	var missing []string
+	for _, f := range fields {
+		if f.tag.Get("required") != "true" || !f.value.IsZero() {
+			continue
+		}
+
+		var hints []string
+		if name := f.envName(o); name != "" {
+			hints = append(hints, "env "+name)
+		}
+		if o.flags && f.flagName() != "" {
+			hints = append(hints, "flag -"+f.flagName())
+		}
+		if len(hints) > 0 {
+			missing = append(missing, f.name+" ("+strings.Join(hints, ", ")+")")
+		} else {
+			missing = append(missing, f.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
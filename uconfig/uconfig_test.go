@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testDB struct {
+	Host    string        `config:"host" default:"localhost"`
+	Port    int           `config:"port" default:"5432"`
+	Timeout time.Duration `config:"timeout" default:"5s"`
+}
+
+type testConfig struct {
+	Name  string   `config:"name" required:"true"`
+	Debug bool     `config:"debug"`
+	Tags  []string `config:"tags"`
+	Token string   `config:"token" env:"TEST_UCONFIG_TOKEN"`
+	DB    testDB   `config:"db"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "uconfig")
+	assert.Equal(t, nil, err, "they should be equal")
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	assert.Equal(t, nil, ioutil.WriteFile(path, []byte(content), 0644), "they should be equal")
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	var cfg testConfig
+	cfg.Name = "huangjian"
+	assert.Equal(t, nil, Load(&cfg), "they should be equal")
+	assert.Equal(t, "huangjian", cfg.Name, "they should be equal")
+	assert.Equal(t, "localhost", cfg.DB.Host, "they should be equal")
+	assert.Equal(t, 5432, cfg.DB.Port, "they should be equal")
+	assert.Equal(t, 5*time.Second, cfg.DB.Timeout, "they should be equal")
+}
+
+func TestLoadPriority(t *testing.T) {
+	path := writeFile(t, "app.yaml", `
+name: huangjian
+tags: [a, b]
+db:
+  host: db.local
+  port: 3306
+`)
+	t.Setenv("APP_DB_PORT", "3307")
+	t.Setenv("APP_NAME", "MDGSF")
+	t.Setenv("TEST_UCONFIG_TOKEN", "secret")
+
+	var cfg testConfig
+	err := Load(&cfg,
+		WithFile(path),
+		WithEnv("APP"),
+		WithFlags(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-debug", "-db.port", "3308"}),
+	)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "MDGSF", cfg.Name, "they should be equal")
+	assert.Equal(t, true, cfg.Debug, "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, cfg.Tags, "they should be equal")
+	assert.Equal(t, "secret", cfg.Token, "they should be equal")
+	assert.Equal(t, "db.local", cfg.DB.Host, "they should be equal")
+	assert.Equal(t, 3308, cfg.DB.Port, "they should be equal")
+	assert.Equal(t, 5*time.Second, cfg.DB.Timeout, "they should be equal")
+}
+
+func TestLoadRequired(t *testing.T) {
+	var cfg testConfig
+	err := Load(&cfg, WithEnv("APP"))
+	assert.Equal(t, true, errors.Is(err, ErrMissingRequired), "they should be equal")
+	assert.Equal(t, "uconfig: missing required fields: name (env APP_NAME)", err.Error(), "they should be equal")
+}
+
+func TestLoadFieldError(t *testing.T) {
+	t.Setenv("APP_DB_PORT", "abc")
+
+	cfg := testConfig{Name: "huangjian"}
+	err := Load(&cfg, WithEnv("APP"))
+	var fe *FieldError
+	assert.Equal(t, true, errors.As(err, &fe), "they should be equal")
+	assert.Equal(t, "db.port", fe.Field, "they should be equal")
+	assert.Equal(t, `uconfig: db.port: invalid value "abc" from env APP_DB_PORT: expected int`,
+		err.Error(), "they should be equal")
+
+	// cfg is untouched when loading fails.
+	assert.Equal(t, "", cfg.DB.Host, "they should be equal")
+}
+
+type validatedConfig struct {
+	Min int `config:"min"`
+	Max int `config:"max"`
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.Min > c.Max {
+		return errors.New("min is greater than max")
+	}
+	return nil
+}
+
+func TestLoadValidate(t *testing.T) {
+	var cfg validatedConfig
+	err := Load(&cfg, WithData("json", []byte(`{"min": 2, "max": 1}`)))
+	assert.Equal(t, "uconfig: invalid config: min is greater than max", err.Error(), "they should be equal")
+
+	err = Load(&cfg, WithData("json", []byte(`{"min": 1, "max": 2}`)))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, validatedConfig{Min: 1, Max: 2}, cfg, "they should be equal")
+}
+
+func TestLoadErrors(t *testing.T) {
+	var cfg testConfig
+	assert.Equal(t, ErrInvalidTarget, Load(cfg), "they should be equal")
+
+	err := Load(&cfg, WithData("toml", []byte(`name = "huangjian"`)))
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedFormat), "they should be equal")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	err = Load(&cfg, WithFlags(fs, []string{"-unknown"}))
+	assert.Equal(t, true, strings.Contains(err.Error(), "flag provided but not defined"), "they should be equal")
+}
@@ -0,0 +1,252 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/uwatch"
+)
+
+// WithWatchInterval sets how often Watch checks the file, default
+// uwatch.DefaultInterval.
+func WithWatchInterval(d time.Duration) Option {
+	return func(o *loadOptions) {
+		o.interval = d
+	}
+}
+
+// WithReloadError is called by Watch when a changed file fails to load or
+// validate; the previous config stays in place.
+func WithReloadError(fn func(err error)) Option {
+	return func(o *loadOptions) {
+		o.onError = fn
+	}
+}
+
+// FieldChange is one field which differs between two configs.
+type FieldChange struct {
+	Field string // dotted field path, "db.port"
+	Old   interface{}
+	New   interface{}
+}
+
+// Change is passed to the onChange callback of Watch.
+type Change[T any] struct {
+	Old    T
+	New    T
+	Fields []FieldChange
+}
+
+// Changed reports whether field, a dotted field path, is among the changes.
+func (c Change[T]) Changed(field string) bool {
+	for _, fc := range c.Fields {
+		if fc.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Watcher reloads a config file when it changes, see Watch.
+*/
+type Watcher[T any] struct {
+	path     string
+	cfg      *T
+	base     T
+	onChange func(Change[T])
+	watcher  *uwatch.Watcher
+
+	reloadLock sync.Mutex
+	opts       loadOptions
+
+	lock  sync.RWMutex
+	hooks []func(FieldChange)
+
+	done chan struct{}
+}
+
+/*
+Watch loads the config file at path into cfg like Load and reloads it
+whenever the file changes. A reload starts again from the value cfg had
+when Watch was called, so removed keys fall back to their defaults. The new
+config is only swapped in after it loaded and validated; failures go to
+WithReloadError and keep the old config. After a swap onChange, which may
+be nil, is called with the changed fields.
+
+cfg is written under the watcher's lock, goroutines other than the
+callbacks should read it through Get.
+
+	w, err := uconfig.Watch("app.yaml", &cfg, func(c uconfig.Change[Config]) {
+		if c.Changed("log.level") {
+			logger.SetLevel(c.New.Log.Level)
+		}
+	}, uconfig.WithEnv("APP"))
+	defer w.Close()
+*/
+func Watch[T any](path string, cfg *T, onChange func(Change[T]), opts ...Option) (*Watcher[T], error) {
+	w := &Watcher[T]{
+		path:     path,
+		cfg:      cfg,
+		base:     *cfg,
+		onChange: onChange,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	w.opts.file = path
+	if w.opts.interval <= 0 {
+		w.opts.interval = uwatch.DefaultInterval
+	}
+
+	if err := load(cfg, &w.opts); err != nil {
+		return nil, err
+	}
+
+	// Watch the directory, editors often replace the file instead of
+	// writing it in place.
+	w.watcher = uwatch.New(uwatch.Options{
+		Interval: w.opts.interval,
+		Debounce: uwatch.DefaultDebounce,
+		Include:  []string{filepath.Base(path)},
+	})
+	if err := w.watcher.Add(filepath.Dir(path)); err != nil {
+		w.watcher.Close()
+		return nil, fmt.Errorf("uconfig: %w", err)
+	}
+	go w.run()
+	return w, nil
+}
+
+/*
+OnField registers fn to be called after a reload changed field, a dotted
+field path. V must be the type of the field.
+
+	uconfig.OnField(w, "db.timeout", func(old, new time.Duration) {
+		pool.SetTimeout(new)
+	})
+*/
+func OnField[T, V any](w *Watcher[T], field string, fn func(old, new V)) error {
+	var zero T
+	vt := reflect.TypeOf((*V)(nil)).Elem()
+	for _, f := range collectFields(reflect.ValueOf(&zero).Elem(), nil, nil) {
+		if f.name != field {
+			continue
+		}
+		ft := f.value.Type()
+		if ft != vt && !(vt.Kind() == reflect.Interface && ft.Implements(vt)) {
+			return fmt.Errorf("uconfig: field %s is %s, not %s", field, ft, vt)
+		}
+
+		w.lock.Lock()
+		w.hooks = append(w.hooks, func(fc FieldChange) {
+			if fc.Field == field {
+				fn(fc.Old.(V), fc.New.(V))
+			}
+		})
+		w.lock.Unlock()
+		return nil
+	}
+	return fmt.Errorf("uconfig: unknown field %s", field)
+}
+
+// Get returns a copy of the current config.
+func (w *Watcher[T]) Get() T {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return *w.cfg
+}
+
+// Reload loads the file now, for example on SIGHUP. Errors are returned
+// and not passed to WithReloadError.
+func (w *Watcher[T]) Reload() error {
+	w.reloadLock.Lock()
+	defer w.reloadLock.Unlock()
+
+	next := w.base
+	if err := load(&next, &w.opts); err != nil {
+		return err
+	}
+
+	w.lock.Lock()
+	old := *w.cfg
+	changes := diffFields(&old, &next)
+	if len(changes) == 0 {
+		w.lock.Unlock()
+		return nil
+	}
+	*w.cfg = next
+	hooks := w.hooks
+	w.lock.Unlock()
+
+	if w.onChange != nil {
+		w.onChange(Change[T]{Old: old, New: next, Fields: changes})
+	}
+	for _, fc := range changes {
+		for _, hook := range hooks {
+			hook(fc)
+		}
+	}
+	return nil
+}
+
+// Close stops watching the file.
+func (w *Watcher[T]) Close() {
+	w.watcher.Close()
+	<-w.done
+}
+
+func (w *Watcher[T]) run() {
+	defer close(w.done)
+
+	target := filepath.Clean(w.path)
+	for e := range w.watcher.Events() {
+		if e.Op == uwatch.Delete || filepath.Clean(e.Path) != target {
+			continue
+		}
+		if err := w.Reload(); err != nil && w.opts.onError != nil {
+			w.opts.onError(err)
+		}
+	}
+}
+
+// diffFields compares the leaf fields of two configs of the same type.
+func diffFields(a, b interface{}) []FieldChange {
+	af := collectFields(reflect.ValueOf(a).Elem(), nil, nil)
+	bf := collectFields(reflect.ValueOf(b).Elem(), nil, nil)
+
+	var changes []FieldChange
+	for i := range af {
+		old, new := af[i].value.Interface(), bf[i].value.Interface()
+		if !reflect.DeepEqual(old, new) {
+			changes = append(changes, FieldChange{Field: af[i].name, Old: old, New: new})
+		}
+	}
+	return changes
+}
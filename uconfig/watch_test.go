@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconfig
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type watchConfig struct {
+	Name    string        `config:"name" required:"true"`
+	Level   string        `config:"level" default:"info"`
+	Timeout time.Duration `config:"timeout" default:"1s"`
+}
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "app.json", `{"name": "huangjian", "level": "debug"}`)
+
+	var (
+		lock    sync.Mutex
+		changes []Change[watchConfig]
+		errs    []error
+		levels  []string
+	)
+	var cfg watchConfig
+	w, err := Watch(path, &cfg, func(c Change[watchConfig]) {
+		lock.Lock()
+		changes = append(changes, c)
+		lock.Unlock()
+	}, WithWatchInterval(10*time.Millisecond), WithReloadError(func(err error) {
+		lock.Lock()
+		errs = append(errs, err)
+		lock.Unlock()
+	}))
+	assert.Equal(t, nil, err, "they should be equal")
+	defer w.Close()
+	assert.Equal(t, watchConfig{Name: "huangjian", Level: "debug", Timeout: time.Second}, w.Get(), "they should be equal")
+
+	err = OnField(w, "level", func(old, new string) {
+		lock.Lock()
+		levels = append(levels, old+"->"+new)
+		lock.Unlock()
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+
+	// A removed key falls back to its default.
+	assert.Equal(t, nil, ioutil.WriteFile(path, []byte(`{"name": "huangjian", "timeout": "2s"}`), 0644), "they should be equal")
+	waitFor(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(changes) == 1
+	})
+	lock.Lock()
+	c := changes[0]
+	assert.Equal(t, "debug", c.Old.Level, "they should be equal")
+	assert.Equal(t, "info", c.New.Level, "they should be equal")
+	assert.Equal(t, true, c.Changed("timeout"), "they should be equal")
+	assert.Equal(t, false, c.Changed("name"), "they should be equal")
+	assert.Equal(t, []FieldChange{
+		{Field: "level", Old: "debug", New: "info"},
+		{Field: "timeout", Old: time.Second, New: 2 * time.Second},
+	}, c.Fields, "they should be equal")
+	assert.Equal(t, []string{"debug->info"}, levels, "they should be equal")
+	lock.Unlock()
+
+	// An invalid file keeps the old config.
+	assert.Equal(t, nil, ioutil.WriteFile(path, []byte(`{"level": "warn"}`), 0644), "they should be equal")
+	waitFor(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(errs) == 1
+	})
+	lock.Lock()
+	assert.Equal(t, true, errors.Is(errs[0], ErrMissingRequired), "they should be equal")
+	lock.Unlock()
+	assert.Equal(t, "info", w.Get().Level, "they should be equal")
+	assert.Equal(t, 2*time.Second, w.Get().Timeout, "they should be equal")
+}
+
+func TestWatchReplace(t *testing.T) {
+	path := writeFile(t, "app.yaml", "name: huangjian\n")
+
+	var cfg watchConfig
+	w, err := Watch(path, &cfg, nil, WithWatchInterval(10*time.Millisecond))
+	assert.Equal(t, nil, err, "they should be equal")
+	defer w.Close()
+
+	tmp := filepath.Join(filepath.Dir(path), "app.yaml.tmp")
+	assert.Equal(t, nil, ioutil.WriteFile(tmp, []byte("name: MDGSF\nlevel: error\n"), 0644), "they should be equal")
+	assert.Equal(t, nil, os.Rename(tmp, path), "they should be equal")
+	waitFor(t, func() bool { return w.Get().Name == "MDGSF" })
+	assert.Equal(t, "error", w.Get().Level, "they should be equal")
+}
+
+func TestWatchErrors(t *testing.T) {
+	path := writeFile(t, "app.json", `{}`)
+	var cfg watchConfig
+	_, err := Watch(path, &cfg, nil)
+	assert.Equal(t, true, errors.Is(err, ErrMissingRequired), "they should be equal")
+
+	cfg.Name = "huangjian"
+	w, err := Watch(path, &cfg, nil)
+	assert.Equal(t, nil, err, "they should be equal")
+	defer w.Close()
+
+	err = OnField(w, "level", func(old, new int) {})
+	assert.Equal(t, "uconfig: field level is string, not int", err.Error(), "they should be equal")
+	err = OnField(w, "unknown", func(old, new int) {})
+	assert.Equal(t, "uconfig: unknown field unknown", err.Error(), "they should be equal")
+
+	assert.Equal(t, nil, w.Reload(), "they should be equal")
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
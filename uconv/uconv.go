@@ -0,0 +1,404 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/utime"
+)
+
+// ErrConvert is wrapped by all conversion errors.
+var ErrConvert = errors.New("uconv: cannot convert")
+
+func convertError(value interface{}, to string) error {
+	if s, ok := value.(string); ok {
+		return fmt.Errorf("%w %q to %s", ErrConvert, s, to)
+	}
+	return fmt.Errorf("%w %v (%T) to %s", ErrConvert, value, value, to)
+}
+
+// text returns the string form of strings, []byte and json.Number.
+func text(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case json.Number:
+		return string(v), true
+	}
+	return "", false
+}
+
+// indirect follows pointers, nil for nil pointers.
+func indirect(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr {
+		return value
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+/*
+ToInt64 converts value to an int64:
+
+  - nil and "" are 0, bools are 0 or 1
+  - integers must fit, floats are truncated toward zero
+  - strings and json.Number may be integers or floats, "42", " 3.9 "
+  - pointers are followed
+*/
+func ToInt64(value interface{}) (int64, error) {
+	value = indirect(value)
+	if value == nil {
+		return 0, nil
+	}
+	if s, ok := text(value); ok {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return 0, nil
+		}
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || !floatFits(f, math.MinInt64, math.MaxInt64) {
+			return 0, convertError(value, "int64")
+		}
+		return int64(f), nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, convertError(value, "int64")
+		}
+		return int64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		if !floatFits(v.Float(), math.MinInt64, math.MaxInt64) {
+			return 0, convertError(value, "int64")
+		}
+		return int64(v.Float()), nil
+	}
+	return 0, convertError(value, "int64")
+}
+
+// floatFits reports whether f truncated lies in [min, max].
+func floatFits(f, min, max float64) bool {
+	return !math.IsNaN(f) && f >= min && f < max+1
+}
+
+// ToInt converts value to an int like ToInt64.
+func ToInt(value interface{}) (int, error) {
+	n, err := ToInt64(value)
+	if err != nil {
+		return 0, convertError(value, "int")
+	}
+	if int64(int(n)) != n {
+		return 0, convertError(value, "int")
+	}
+	return int(n), nil
+}
+
+/*
+ToFloat converts value to a float64. nil and "" are 0, bools are 0 or 1,
+strings and json.Number are parsed and pointers are followed.
+*/
+func ToFloat(value interface{}) (float64, error) {
+	value = indirect(value)
+	if value == nil {
+		return 0, nil
+	}
+	if s, ok := text(value); ok {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return 0, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, convertError(value, "float64")
+		}
+		return f, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+	return 0, convertError(value, "float64")
+}
+
+/*
+ToBool converts value to a bool. nil and "" are false, numbers are true
+when not zero, strings accept the strconv.ParseBool forms and "yes", "no",
+"y", "n", "on" and "off" in any case.
+*/
+func ToBool(value interface{}) (bool, error) {
+	value = indirect(value)
+	if value == nil {
+		return false, nil
+	}
+	if s, ok := text(value); ok {
+		switch strings.ToLower(strings.TrimSpace(s)) {
+		case "":
+			return false, nil
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off":
+			return false, nil
+		}
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return b, nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return f != 0, nil
+		}
+		return false, convertError(value, "bool")
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() != 0, nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float() != 0, nil
+	}
+	return false, convertError(value, "bool")
+}
+
+/*
+ToString converts value to a string. nil is "", numbers and bools use
+strconv without exponents, times are formatted as RFC 3339 and errors and
+fmt.Stringers use their methods. Slices, maps, structs and funcs fail.
+*/
+func ToString(value interface{}) (string, error) {
+	// Check the methods before following pointers, they are often
+	// implemented on the pointer.
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return "", nil
+	}
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case *time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case error:
+		return v.Error(), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+
+	value = indirect(value)
+	if value == nil {
+		return "", nil
+	}
+	if s, ok := text(value); ok {
+		return s, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return "", convertError(value, "string")
+}
+
+// ToTime converts value to a time.Time like ToTimeIn with UTC.
+func ToTime(value interface{}) (time.Time, error) {
+	return ToTimeIn(value, time.UTC)
+}
+
+/*
+ToTimeIn converts value to a time.Time. Strings and json.Number are parsed
+by utime.ParseAny, taking values without offset in loc. Integers are Unix
+timestamps with their unit told apart by the number of digits, floats are
+Unix seconds. nil and "" are the zero time.
+*/
+func ToTimeIn(value interface{}, loc *time.Location) (time.Time, error) {
+	value = indirect(value)
+	if value == nil {
+		return time.Time{}, nil
+	}
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+	if s, ok := text(value); ok {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return time.Time{}, nil
+		}
+		t, err := utime.ParseAny(s, loc)
+		if err != nil {
+			return time.Time{}, convertError(value, "time.Time")
+		}
+		return t, nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, _ := ToString(value)
+		if t, err := utime.ParseAny(s, loc); err == nil {
+			return t, nil
+		}
+	case reflect.Float32, reflect.Float64:
+		sec, frac := math.Modf(v.Float())
+		if !math.IsNaN(sec) && !math.IsInf(sec, 0) {
+			return time.Unix(int64(sec), int64(frac*1e9)).In(loc), nil
+		}
+	}
+	return time.Time{}, convertError(value, "time.Time")
+}
+
+/*
+ToDuration converts value to a time.Duration. Strings are parsed by
+utime.ParseDuration, so "1d12h" works, and plain numbers in strings or
+numbers are nanoseconds like time.Duration itself.
+*/
+func ToDuration(value interface{}) (time.Duration, error) {
+	value = indirect(value)
+	if d, ok := value.(time.Duration); ok {
+		return d, nil
+	}
+	if s, ok := text(value); ok {
+		s = strings.TrimSpace(s)
+		if d, err := utime.ParseDuration(s); err == nil {
+			return d, nil
+		}
+	}
+	n, err := ToInt64(value)
+	if err != nil {
+		return 0, convertError(value, "time.Duration")
+	}
+	return time.Duration(n), nil
+}
+
+// MustInt is like ToInt but panics on error.
+func MustInt(value interface{}) int {
+	n, err := ToInt(value)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// MustInt64 is like ToInt64 but panics on error.
+func MustInt64(value interface{}) int64 {
+	n, err := ToInt64(value)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+// MustFloat is like ToFloat but panics on error.
+func MustFloat(value interface{}) float64 {
+	f, err := ToFloat(value)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// MustBool is like ToBool but panics on error.
+func MustBool(value interface{}) bool {
+	b, err := ToBool(value)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// MustString is like ToString but panics on error.
+func MustString(value interface{}) string {
+	s, err := ToString(value)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// MustTime is like ToTime but panics on error.
+func MustTime(value interface{}) time.Time {
+	t, err := ToTime(value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// MustDuration is like ToDuration but panics on error.
+func MustDuration(value interface{}) time.Duration {
+	d, err := ToDuration(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
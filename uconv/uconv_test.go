@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uconv
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToInt(t *testing.T) {
+	n := 7
+	tests := []struct {
+		in  interface{}
+		out int64
+	}{
+		{nil, 0},
+		{"", 0},
+		{" 42 ", 42},
+		{"-3.9", -3},
+		{json.Number("12"), 12},
+		{[]byte("5"), 5},
+		{true, 1},
+		{uint8(200), 200},
+		{3.99, 3},
+		{&n, 7},
+		{time.Second, int64(time.Second)},
+	}
+	for _, test := range tests {
+		got, err := ToInt64(test.in)
+		assert.Equal(t, nil, err, "%v", test.in)
+		assert.Equal(t, test.out, got, "%v", test.in)
+	}
+
+	for _, in := range []interface{}{"abc", uint64(math.MaxUint64), math.NaN(), 1e20, []int{1}} {
+		_, err := ToInt64(in)
+		assert.Equal(t, true, errors.Is(err, ErrConvert), "%v", in)
+	}
+
+	_, err := ToInt("abc")
+	assert.Equal(t, `uconv: cannot convert "abc" to int`, err.Error(), "they should be equal")
+	assert.Equal(t, 42, MustInt("42"), "they should be equal")
+	assert.Panics(t, func() { MustInt(struct{}{}) })
+}
+
+func TestToFloat(t *testing.T) {
+	assert.Equal(t, 1.5, MustFloat("1.5"), "they should be equal")
+	assert.Equal(t, 3.0, MustFloat(3), "they should be equal")
+	assert.Equal(t, 0.25, MustFloat(json.Number("0.25")), "they should be equal")
+	assert.Equal(t, 1.0, MustFloat(true), "they should be equal")
+	assert.Equal(t, 0.0, MustFloat(nil), "they should be equal")
+
+	_, err := ToFloat("1,5")
+	assert.Equal(t, `uconv: cannot convert "1,5" to float64`, err.Error(), "they should be equal")
+}
+
+func TestToBool(t *testing.T) {
+	for _, in := range []interface{}{true, "true", "YES", "on", "1", 2, 0.5} {
+		assert.Equal(t, true, MustBool(in), "%v", in)
+	}
+	for _, in := range []interface{}{nil, false, "", "off", "N", "0", 0, 0.0} {
+		assert.Equal(t, false, MustBool(in), "%v", in)
+	}
+	_, err := ToBool("maybe")
+	assert.Equal(t, true, errors.Is(err, ErrConvert), "they should be equal")
+}
+
+func TestToString(t *testing.T) {
+	tests := []struct {
+		in  interface{}
+		out string
+	}{
+		{nil, ""},
+		{"huangjian", "huangjian"},
+		{[]byte("MDGSF"), "MDGSF"},
+		{42, "42"},
+		{uint(42), "42"},
+		{1e21, "1000000000000000000000"},
+		{float32(0.1), "0.1"},
+		{true, "true"},
+		{time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC), "2019-08-13T14:10:00Z"},
+		{time.Minute, "1m0s"},
+		{errors.New("boom"), "boom"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.out, MustString(test.in), "%v", test.in)
+	}
+
+	_, err := ToString(map[string]int{"a": 1})
+	assert.Equal(t, "uconv: cannot convert map[a:1] (map[string]int) to string", err.Error(), "they should be equal")
+}
+
+func TestToTime(t *testing.T) {
+	want := time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC)
+	for _, in := range []interface{}{
+		want,
+		"2019-08-13T14:10:00Z",
+		"2019-08-13 14:10:00",
+		want.Unix(),
+		json.Number("1565705400000"),
+		float64(want.Unix()),
+	} {
+		got, err := ToTime(in)
+		assert.Equal(t, nil, err, "%v", in)
+		assert.Equal(t, true, want.Equal(got), "%v", in)
+	}
+
+	loc := time.FixedZone("CST", 8*3600)
+	got, err := ToTimeIn("2019-08-13 22:10:00", loc)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, want.Equal(got), "they should be equal")
+
+	assert.Equal(t, time.Time{}, MustTime(nil), "they should be equal")
+	_, err = ToTime("yesterday")
+	assert.Equal(t, true, errors.Is(err, ErrConvert), "they should be equal")
+}
+
+func TestToDuration(t *testing.T) {
+	assert.Equal(t, 36*time.Hour, MustDuration("1d12h"), "they should be equal")
+	assert.Equal(t, time.Duration(1000), MustDuration("1000"), "they should be equal")
+	assert.Equal(t, time.Second, MustDuration(int64(time.Second)), "they should be equal")
+	assert.Equal(t, time.Minute, MustDuration(time.Minute), "they should be equal")
+
+	_, err := ToDuration("soon")
+	assert.Equal(t, `uconv: cannot convert "soon" to time.Duration`, err.Error(), "they should be equal")
+}
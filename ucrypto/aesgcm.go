@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// KeySize AES-256 key size in bytes.
+const KeySize = 32
+
+var (
+	// ErrInvalidKeySize key is not KeySize bytes.
+	ErrInvalidKeySize = errors.New("ucrypto: key must be 32 bytes")
+
+	// ErrCiphertextTooShort ciphertext is shorter than nonce and tag.
+	ErrCiphertextTooShort = errors.New("ucrypto: ciphertext too short")
+)
+
+// GenerateKey generate a random AES-256 key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt encrypt plaintext with AES-256-GCM, see EncryptWithAD.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	return EncryptWithAD(key, plaintext, nil)
+}
+
+// Decrypt decrypt ciphertext returned by Encrypt.
+func Decrypt(key, ciphertext []byte) ([]byte, error) {
+	return DecryptWithAD(key, ciphertext, nil)
+}
+
+/*
+EncryptWithAD encrypt plaintext with AES-256-GCM and a random nonce.
+@param key: 32 bytes key, see GenerateKey.
+@param additionalData: not encrypted, but authenticated, Decrypt fails if it
+is different, for example a user id which binds the ciphertext to one user.
+Returns nonce + ciphertext + tag.
+*/
+func EncryptWithAD(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// DecryptWithAD decrypt ciphertext returned by EncryptWithAD, with the same additionalData.
+func DecryptWithAD(key, ciphertext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, ErrCiphertextTooShort
+	}
+	nonce := ciphertext[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, ciphertext[gcm.NonceSize():], additionalData)
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucrypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncrypt(t *testing.T) {
+	key, err := GenerateKey()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, KeySize, len(key), "they should be equal")
+
+	plaintext := []byte("huangjian")
+	a, err := Encrypt(key, plaintext)
+	assert.Equal(t, nil, err, "they should be equal")
+	b, err := Encrypt(key, plaintext)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.NotEqual(t, a, b, "random nonce, they should not be equal")
+
+	decrypted, err := Decrypt(key, a)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, plaintext, decrypted, "they should be equal")
+
+	// tampered
+	a[len(a)-1] ^= 1
+	_, err = Decrypt(key, a)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestEncryptWithAD(t *testing.T) {
+	key, _ := GenerateKey()
+	ciphertext, err := EncryptWithAD(key, []byte("huangjian"), []byte("user1"))
+	assert.Equal(t, nil, err, "they should be equal")
+
+	plaintext, err := DecryptWithAD(key, ciphertext, []byte("user1"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", string(plaintext), "they should be equal")
+
+	_, err = DecryptWithAD(key, ciphertext, []byte("user2"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestEncryptInvalid(t *testing.T) {
+	_, err := Encrypt([]byte("short"), []byte("huangjian"))
+	assert.Equal(t, ErrInvalidKeySize, err, "they should be equal")
+
+	key, _ := GenerateKey()
+	_, err = Decrypt(key, []byte("short"))
+	assert.Equal(t, ErrCiphertextTooShort, err, "they should be equal")
+
+	other, _ := GenerateKey()
+	ciphertext, _ := Encrypt(key, []byte("huangjian"))
+	_, err = Decrypt(other, ciphertext)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucrypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+)
+
+// ErrUnsupportedKey key is neither RSA nor ECDSA.
+var ErrUnsupportedKey = errors.New("ucrypto: unsupported key type")
+
+// ErrInvalidSignature signature does not match.
+var ErrInvalidSignature = errors.New("ucrypto: invalid signature")
+
+// GenerateRSAKey generate a RSA private key, bits should be at least 2048.
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, bits)
+}
+
+// GenerateECDSAKey generate an ECDSA P-256 private key.
+func GenerateECDSAKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+/*
+ParsePrivateKeyPEM parse the first private key in PEM data.
+Supported blocks: "PRIVATE KEY" (PKCS #8), "RSA PRIVATE KEY" (PKCS #1),
+"EC PRIVATE KEY" (SEC 1).
+Returns *rsa.PrivateKey or *ecdsa.PrivateKey.
+*/
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("ucrypto: no private key found in pem")
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k, nil
+		case *ecdsa.PrivateKey:
+			return k, nil
+		}
+		return nil, ErrUnsupportedKey
+	}
+}
+
+/*
+ParsePublicKeyPEM parse the first public key in PEM data.
+Supported blocks: "PUBLIC KEY" (PKIX), "RSA PUBLIC KEY" (PKCS #1),
+"CERTIFICATE" (public key of the certificate).
+Returns *rsa.PublicKey or *ecdsa.PublicKey.
+*/
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("ucrypto: no public key found in pem")
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			return k, nil
+		case *ecdsa.PublicKey:
+			return k, nil
+		}
+		return nil, ErrUnsupportedKey
+	}
+}
+
+// LoadPrivateKey load private key from a PEM file, see ParsePrivateKeyPEM.
+func LoadPrivateKey(filename string) (crypto.Signer, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKeyPEM(data)
+}
+
+// LoadPublicKey load public key from a PEM file, see ParsePublicKeyPEM.
+func LoadPublicKey(filename string) (crypto.PublicKey, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeyPEM(data)
+}
+
+// MarshalPrivateKeyPEM encode private key as PKCS #8 "PRIVATE KEY" PEM.
+func MarshalPrivateKeyPEM(key crypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM encode public key as PKIX "PUBLIC KEY" PEM.
+func MarshalPublicKeyPEM(key crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+/*
+Sign sign the sha256 digest of data.
+RSA keys use RSA-PSS, ECDSA keys return an ASN.1 DER signature.
+*/
+func Sign(priv crypto.Signer, data []byte) ([]byte, error) {
+	digest := sha256.Sum256(data)
+	switch priv.(type) {
+	case *rsa.PrivateKey:
+		return priv.Sign(rand.Reader, digest[:], &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       crypto.SHA256,
+		})
+	case *ecdsa.PrivateKey:
+		return priv.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// Verify verify signature returned by Sign, returns nil if sig is valid.
+func Verify(pub crypto.PublicKey, data, sig []byte) error {
+	digest := sha256.Sum256(data)
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		err := rsa.VerifyPSS(k, crypto.SHA256, digest[:], sig, &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+		})
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		var esig struct {
+			R, S *big.Int
+		}
+		if rest, err := asn1.Unmarshal(sig, &esig); err != nil || len(rest) != 0 {
+			return ErrInvalidSignature
+		}
+		if !ecdsa.Verify(k, digest[:], esig.R, esig.S) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %T", ErrUnsupportedKey, pub)
+}
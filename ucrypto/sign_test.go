@@ -0,0 +1,120 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucrypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignRSA(t *testing.T) {
+	priv, err := GenerateRSAKey(2048)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	sig, err := Sign(priv, []byte("huangjian"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, nil, Verify(&priv.PublicKey, []byte("huangjian"), sig), "they should be equal")
+	assert.Equal(t, ErrInvalidSignature, Verify(&priv.PublicKey, []byte("MDGSF"), sig), "they should be equal")
+}
+
+func TestSignECDSA(t *testing.T) {
+	priv, err := GenerateECDSAKey()
+	assert.Equal(t, nil, err, "they should be equal")
+
+	sig, err := Sign(priv, []byte("huangjian"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, nil, Verify(&priv.PublicKey, []byte("huangjian"), sig), "they should be equal")
+	assert.Equal(t, ErrInvalidSignature, Verify(&priv.PublicKey, []byte("MDGSF"), sig), "they should be equal")
+	assert.Equal(t, ErrInvalidSignature, Verify(&priv.PublicKey, []byte("huangjian"), []byte("invalid")), "they should be equal")
+
+	other, _ := GenerateECDSAKey()
+	assert.Equal(t, ErrInvalidSignature, Verify(&other.PublicKey, []byte("huangjian"), sig), "they should be equal")
+
+	err = Verify("MDGSF", []byte("huangjian"), sig)
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedKey), "they should be equal")
+}
+
+func TestPEMRoundTrip(t *testing.T) {
+	ecKey, _ := GenerateECDSAKey()
+	rsaKey, _ := GenerateRSAKey(2048)
+
+	for _, priv := range []interface{}{ecKey, rsaKey} {
+		privPEM, err := MarshalPrivateKeyPEM(priv)
+		assert.Equal(t, nil, err, "they should be equal")
+
+		var pubPEM []byte
+		switch k := priv.(type) {
+		case *ecdsa.PrivateKey:
+			pubPEM, err = MarshalPublicKeyPEM(&k.PublicKey)
+		case *rsa.PrivateKey:
+			pubPEM, err = MarshalPublicKeyPEM(&k.PublicKey)
+		}
+		assert.Equal(t, nil, err, "they should be equal")
+
+		dir, _ := ioutil.TempDir("", "ucrypto")
+		defer os.RemoveAll(dir)
+		ioutil.WriteFile(dir+"/priv.pem", privPEM, 0600)
+		ioutil.WriteFile(dir+"/pub.pem", pubPEM, 0644)
+
+		signer, err := LoadPrivateKey(dir + "/priv.pem")
+		assert.Equal(t, nil, err, "they should be equal")
+		pub, err := LoadPublicKey(dir + "/pub.pem")
+		assert.Equal(t, nil, err, "they should be equal")
+
+		sig, err := Sign(signer, []byte("huangjian"))
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, nil, Verify(pub, []byte("huangjian"), sig), "they should be equal")
+	}
+}
+
+func TestParseLegacyPEM(t *testing.T) {
+	rsaKey, _ := GenerateRSAKey(2048)
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})
+
+	priv, err := ParsePrivateKeyPEM(append([]byte("comment\n"), privPEM...))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, rsaKey.D, priv.(*rsa.PrivateKey).D, "they should be equal")
+
+	pub, err := ParsePublicKeyPEM(pubPEM)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, rsaKey.N, pub.(*rsa.PublicKey).N, "they should be equal")
+
+	ecKey, _ := GenerateECDSAKey()
+	der, _ := x509.MarshalECPrivateKey(ecKey)
+	_, err = ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	assert.Equal(t, nil, err, "they should be equal")
+
+	_, err = ParsePrivateKeyPEM([]byte("invalid"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = ParsePublicKeyPEM(privPEM)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,183 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucsv
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Reader decodes CSV rows into values of type T, a struct or a pointer to
+// a struct, one row at a time.
+type Reader[T any] struct {
+	r       *csv.Reader
+	rowType reflect.Type
+	// cols maps the position of a cell to its column, nil for cells
+	// without a field.
+	cols []*column
+}
+
+/*
+NewReader returns a Reader of the rows of r. The header line is read
+right away, its names match the columns case-insensitively and in any
+order; cells of unknown columns are ignored and fields without a cell keep
+their zero value.
+*/
+func NewReader[T any](r io.Reader, opts ...Option) (*Reader[T], error) {
+	o := newOptions(opts)
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+	st, err := structType(rowType)
+	if err != nil {
+		return nil, err
+	}
+
+	cr := csv.NewReader(r)
+	cr.Comma = o.comma
+	cr.Comment = o.comment
+	cr.LazyQuotes = o.lazyQuotes
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	all := columnsOf(st, o.tag, nil)
+	reader := &Reader[T]{r: cr, rowType: rowType}
+	if o.noHeader {
+		for i := range all {
+			reader.cols = append(reader.cols, &all[i])
+		}
+		return reader, nil
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	reader.cols = make([]*column, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.TrimSpace(name)
+		for j := range all {
+			if strings.EqualFold(all[j].name, name) {
+				reader.cols[i] = &all[j]
+				break
+			}
+		}
+	}
+	return reader, nil
+}
+
+// Read returns the next row, or io.EOF after the last one.
+func (r *Reader[T]) Read() (T, error) {
+	var row T
+	record, err := r.r.Read()
+	if err != nil {
+		return row, err
+	}
+
+	rv := reflect.ValueOf(&row).Elem()
+	if r.rowType.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(r.rowType.Elem()))
+		rv = rv.Elem()
+	}
+	for i, cell := range record {
+		if i >= len(r.cols) || r.cols[i] == nil {
+			continue
+		}
+		col := r.cols[i]
+		if err := parseCell(fieldByIndex(rv, col.index, true), cell); err != nil {
+			line, _ := r.r.FieldPos(i)
+			return row, &ParseError{Line: line, Column: col.name, Value: cell, Err: err}
+		}
+	}
+	return row, nil
+}
+
+// ReadAll reads all the rows of r.
+func ReadAll[T any](r io.Reader, opts ...Option) ([]T, error) {
+	var rows []T
+	err := Each(r, func(row T) error {
+		rows = append(rows, row)
+		return nil
+	}, opts...)
+	return rows, err
+}
+
+// Each calls fn for every row of r without keeping them in memory,
+// stopping at the first error.
+func Each[T any](r io.Reader, fn func(row T) error, opts ...Option) error {
+	reader, err := NewReader[T](r, opts...)
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+/*
+Read reads all the rows of the CSV file path into out.
+
+	var users []User
+	err := ucsv.Read("users.csv", &users)
+*/
+func Read[T any](path string, out *[]T, opts ...Option) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rows, err := ReadAll[T](bufio.NewReader(f), opts...)
+	if err != nil {
+		return err
+	}
+	*out = rows
+	return nil
+}
+
+// ReadEach calls fn for every row of the CSV file path, streaming files
+// too large to be read at once.
+func ReadEach[T any](path string, fn func(row T) error, opts ...Option) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return Each(bufio.NewReader(f), fn, opts...)
+}
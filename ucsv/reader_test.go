@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucsv
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAll(t *testing.T) {
+	data := "\ufeffName, ID ,age,unknown,created\n" +
+		"huangjian,1,18,x,2019-01-02\n" +
+		"\"MDGSF, Inc\",2,,y,\n"
+	users, err := ReadAll[User](strings.NewReader(data))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 2, len(users), "they should be equal")
+	assert.Equal(t, "huangjian", users[0].Name, "they should be equal")
+	assert.Equal(t, 1, users[0].ID, "they should be equal")
+	assert.Equal(t, 18, users[0].Age, "they should be equal")
+	assert.Equal(t, time.Date(2019, 1, 2, 0, 0, 0, 0, time.Local).Unix(), users[0].Created.Unix(), "they should be equal")
+	assert.Equal(t, "MDGSF, Inc", users[1].Name, "they should be equal")
+	assert.Equal(t, 0, users[1].Age, "they should be equal")
+	assert.Equal(t, true, users[1].Created.IsZero(), "they should be equal")
+
+	ptrs, err := ReadAll[*User](strings.NewReader(data))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "MDGSF, Inc", ptrs[1].Name, "they should be equal")
+
+	empty, err := ReadAll[User](strings.NewReader(""))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 0, len(empty), "they should be equal")
+
+	_, err = ReadAll[int](strings.NewReader(data))
+	assert.Equal(t, ErrNotStruct, err, "they should be equal")
+}
+
+func TestReadOptions(t *testing.T) {
+	data := "# users\n1;huangjian;18\n2;MDGSF;20\n"
+	users, err := ReadAll[User](strings.NewReader(data), WithComma(';'), WithComment('#'), WithoutHeader())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 2, len(users), "they should be equal")
+	assert.Equal(t, "MDGSF", users[1].Name, "they should be equal")
+	assert.Equal(t, 20, users[1].Age, "they should be equal")
+}
+
+func TestReadParseError(t *testing.T) {
+	data := "name,age\nhuangjian,18\nMDGSF,old\n"
+	_, err := ReadAll[User](strings.NewReader(data))
+	var perr *ParseError
+	assert.Equal(t, true, errors.As(err, &perr), "they should be equal")
+	assert.Equal(t, 3, perr.Line, "they should be equal")
+	assert.Equal(t, "age", perr.Column, "they should be equal")
+	assert.Equal(t, `ucsv: line 3, column age: cannot parse "old": strconv.ParseInt: parsing "old": invalid syntax`, err.Error(), "they should be equal")
+}
+
+func TestReaderStream(t *testing.T) {
+	r, err := NewReader[User](strings.NewReader("name\nhuangjian\nMDGSF\n"))
+	assert.Equal(t, nil, err, "they should be equal")
+	u, err := r.Read()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", u.Name, "they should be equal")
+	u, err = r.Read()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "MDGSF", u.Name, "they should be equal")
+	_, err = r.Read()
+	assert.Equal(t, io.EOF, err, "they should be equal")
+
+	stop := errors.New("stop")
+	count := 0
+	err = Each(strings.NewReader("name\na\nb\nc\n"), func(u User) error {
+		count++
+		if u.Name == "b" {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err, "they should be equal")
+	assert.Equal(t, 2, count, "they should be equal")
+}
+
+func TestReadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ucsv")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "users.csv")
+	assert.Equal(t, nil, ioutil.WriteFile(path, []byte("name,age\nhuangjian,18\n"), 0644), "they should be equal")
+
+	var users []User
+	assert.Equal(t, nil, Read(path, &users), "they should be equal")
+	assert.Equal(t, []User{{Name: "huangjian", Age: 18}}, users, "they should be equal")
+
+	var names []string
+	err = ReadEach(path, func(u User) error {
+		names = append(names, u.Name)
+		return nil
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []string{"huangjian"}, names, "they should be equal")
+
+	assert.NotEqual(t, nil, Read(filepath.Join(dir, "missing.csv"), &users), "they should not be equal")
+}
@@ -0,0 +1,332 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucsv
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/uconv"
+)
+
+// ErrNotStruct is returned when rows are not structs or pointers to structs.
+var ErrNotStruct = errors.New("ucsv: rows must be structs")
+
+// DefaultTag names the columns of struct fields.
+const DefaultTag = "csv"
+
+// ParseError is returned when a cell can not be converted to its field.
+type ParseError struct {
+	Line   int
+	Column string
+	Value  string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("ucsv: line %d, column %s: cannot parse %q: %v", e.Line, e.Column, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+type options struct {
+	comma      rune
+	comment    rune
+	lazyQuotes bool
+	quoteAll   bool
+	useCRLF    bool
+	noHeader   bool
+	tag        string
+	timeLayout string
+}
+
+// Option configures readers and writers.
+type Option func(*options)
+
+// WithComma sets the field delimiter, default is ','.
+func WithComma(comma rune) Option {
+	return func(o *options) {
+		o.comma = comma
+	}
+}
+
+// WithComment sets the character starting comment lines when reading.
+func WithComment(comment rune) Option {
+	return func(o *options) {
+		o.comment = comment
+	}
+}
+
+// WithLazyQuotes accepts quotes in unquoted fields when reading.
+func WithLazyQuotes() Option {
+	return func(o *options) {
+		o.lazyQuotes = true
+	}
+}
+
+// WithQuoteAll quotes every field when writing, by default fields are
+// only quoted when needed.
+func WithQuoteAll() Option {
+	return func(o *options) {
+		o.quoteAll = true
+	}
+}
+
+// WithCRLF ends written lines with \r\n instead of \n.
+func WithCRLF() Option {
+	return func(o *options) {
+		o.useCRLF = true
+	}
+}
+
+// WithoutHeader maps columns to fields by position, no header line is
+// read or written.
+func WithoutHeader() Option {
+	return func(o *options) {
+		o.noHeader = true
+	}
+}
+
+// WithTag sets the struct tag naming the columns, default is "csv".
+func WithTag(tag string) Option {
+	return func(o *options) {
+		o.tag = tag
+	}
+}
+
+// WithTimeLayout sets the layout of written times, default is
+// time.RFC3339. Times are read in any format known to uconv.ToTime.
+func WithTimeLayout(layout string) Option {
+	return func(o *options) {
+		o.timeLayout = layout
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		comma:      ',',
+		tag:        DefaultTag,
+		timeLayout: time.RFC3339,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// column is a struct field mapped to a CSV column.
+type column struct {
+	name  string
+	index []int
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// structType returns the struct type of rows of type t, which is a
+// struct or a pointer to one.
+func structType(t reflect.Type) (reflect.Type, error) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, ErrNotStruct
+	}
+	return t, nil
+}
+
+/*
+columnsOf lists the columns of struct type t: the tag names the column,
+"-" skips the field and untagged fields use their Go name. Embedded
+structs are inlined.
+*/
+func columnsOf(t reflect.Type, tag string, parent []int) []column {
+	var cols []column
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := sf.Tag.Get(tag)
+		if idx := strings.IndexByte(name, ','); idx >= 0 {
+			name = name[:idx]
+		}
+		if name == "-" {
+			continue
+		}
+		index := append(append([]int{}, parent...), i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+			cols = append(cols, columnsOf(ft, tag, index)...)
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		cols = append(cols, column{name: name, index: index})
+	}
+	return cols
+}
+
+// fieldByIndex returns the field of v at index, allocating nil embedded
+// pointers when alloc is true. It returns an invalid value for fields
+// behind nil pointers otherwise.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// parseCell sets v from the cell s, an empty cell leaves the zero value.
+func parseCell(v reflect.Value, s string) error {
+	if s == "" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return parseCell(v.Elem(), s)
+	}
+
+	switch v.Type() {
+	case timeType:
+		t, err := uconv.ToTime(s)
+		if err == nil {
+			v.Set(reflect.ValueOf(t))
+		}
+		return err
+	case durationType:
+		d, err := uconv.ToDuration(s)
+		if err == nil {
+			v.SetInt(int64(d))
+		}
+		return err
+	}
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, err := uconv.ToBool(s)
+		if err == nil {
+			v.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err == nil {
+			v.SetInt(n)
+		}
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err == nil {
+			v.SetUint(n)
+		}
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err == nil {
+			v.SetFloat(f)
+		}
+		return err
+	}
+	return fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// formatCell returns the cell of v, nil pointers give an empty cell.
+func formatCell(v reflect.Value, timeLayout string) (string, error) {
+	if !v.IsValid() {
+		return "", nil
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(timeLayout), nil
+	case durationType:
+		return time.Duration(v.Int()).String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("ucsv: unsupported type %s", v.Type())
+}
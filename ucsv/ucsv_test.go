@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucsv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Base struct {
+	ID int `csv:"id"`
+}
+
+type User struct {
+	Base
+	Name     string        `csv:"name"`
+	Age      int           `csv:"age"`
+	Score    float64       `csv:"score"`
+	Active   bool          `csv:"active"`
+	Created  time.Time     `csv:"created"`
+	Timeout  time.Duration `csv:"timeout"`
+	Nickname *string       `csv:"nickname"`
+	Secret   string        `csv:"-"`
+	Comment  string
+	private  string
+}
+
+func TestColumnsOf(t *testing.T) {
+	cols := columnsOf(reflect.TypeOf(User{}), DefaultTag, nil)
+	var names []string
+	for _, col := range cols {
+		names = append(names, col.name)
+	}
+	want := []string{"id", "name", "age", "score", "active", "created", "timeout", "nickname", "Comment"}
+	assert.Equal(t, want, names, "they should be equal")
+	assert.Equal(t, []int{0, 0}, cols[0].index, "they should be equal")
+
+	_, err := structType(reflect.TypeOf(1))
+	assert.Equal(t, ErrNotStruct, err, "they should be equal")
+}
+
+func TestParseFormatCell(t *testing.T) {
+	var u User
+	v := reflect.ValueOf(&u).Elem()
+	assert.Equal(t, nil, parseCell(v.FieldByName("Age"), " 18 "), "they should be equal")
+	assert.Equal(t, nil, parseCell(v.FieldByName("Active"), "yes"), "they should be equal")
+	assert.Equal(t, nil, parseCell(v.FieldByName("Created"), "2019-01-02 03:04:05"), "they should be equal")
+	assert.Equal(t, nil, parseCell(v.FieldByName("Timeout"), "1m30s"), "they should be equal")
+	assert.Equal(t, nil, parseCell(v.FieldByName("Nickname"), "MDGSF"), "they should be equal")
+	assert.Equal(t, 18, u.Age, "they should be equal")
+	assert.Equal(t, true, u.Active, "they should be equal")
+	assert.Equal(t, 2019, u.Created.Year(), "they should be equal")
+	assert.Equal(t, 90*time.Second, u.Timeout, "they should be equal")
+	assert.Equal(t, "MDGSF", *u.Nickname, "they should be equal")
+
+	assert.Equal(t, nil, parseCell(v.FieldByName("Nickname"), ""), "they should be equal")
+	assert.Equal(t, (*string)(nil), u.Nickname, "they should be equal")
+
+	err := parseCell(v.FieldByName("Age"), "abc")
+	assert.Equal(t, true, errors.Is(err, strconv.ErrSyntax), "they should be equal")
+
+	cell, err := formatCell(v.FieldByName("Timeout"), time.RFC3339)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "1m30s", cell, "they should be equal")
+	cell, err = formatCell(v.FieldByName("Nickname"), time.RFC3339)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "", cell, "they should be equal")
+	cell, err = formatCell(v.FieldByName("Created"), "2006-01-02")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "2019-01-02", cell, "they should be equal")
+}
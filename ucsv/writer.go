@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucsv
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Writer encodes values of type T, a struct or a pointer to a struct, as
+// CSV rows.
+type Writer[T any] struct {
+	w    *bufio.Writer
+	o    *options
+	cols []column
+	// record is reused between rows.
+	record []string
+}
+
+// NewWriter returns a Writer to w and writes the header line unless
+// WithoutHeader is given. Call Flush when done.
+func NewWriter[T any](w io.Writer, opts ...Option) (*Writer[T], error) {
+	o := newOptions(opts)
+	st, err := structType(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return nil, err
+	}
+	cols := columnsOf(st, o.tag, nil)
+	writer := &Writer[T]{
+		w:      bufio.NewWriter(w),
+		o:      o,
+		cols:   cols,
+		record: make([]string, len(cols)),
+	}
+	if !o.noHeader {
+		for i, col := range cols {
+			writer.record[i] = col.name
+		}
+		if err := writer.writeRecord(); err != nil {
+			return nil, err
+		}
+	}
+	return writer, nil
+}
+
+// Write writes row, a nil pointer writes a line of empty cells.
+func (w *Writer[T]) Write(row T) error {
+	rv := reflect.ValueOf(&row).Elem()
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			for i := range w.record {
+				w.record[i] = ""
+			}
+			return w.writeRecord()
+		}
+		rv = rv.Elem()
+	}
+	for i, col := range w.cols {
+		cell, err := formatCell(fieldByIndex(rv, col.index, false), w.o.timeLayout)
+		if err != nil {
+			return err
+		}
+		w.record[i] = cell
+	}
+	return w.writeRecord()
+}
+
+// Flush writes buffered rows to the underlying writer.
+func (w *Writer[T]) Flush() error {
+	return w.w.Flush()
+}
+
+func (w *Writer[T]) writeRecord() error {
+	for i, field := range w.record {
+		if i > 0 {
+			w.w.WriteRune(w.o.comma)
+		}
+		if !w.o.quoteAll && !w.needsQuotes(field) {
+			w.w.WriteString(field)
+			continue
+		}
+		w.w.WriteByte('"')
+		w.w.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		w.w.WriteByte('"')
+	}
+	var err error
+	if w.o.useCRLF {
+		_, err = w.w.WriteString("\r\n")
+	} else {
+		err = w.w.WriteByte('\n')
+	}
+	return err
+}
+
+// needsQuotes follows encoding/csv: fields holding the delimiter, quotes,
+// line breaks or starting with a space are quoted.
+func (w *Writer[T]) needsQuotes(field string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.ContainsRune(field, w.o.comma) || strings.ContainsAny(field, "\"\r\n") {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
+
+// WriteAll writes the header and rows to w.
+func WriteAll[T any](w io.Writer, rows []T, opts ...Option) error {
+	writer, err := NewWriter[T](w, opts...)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+/*
+Write writes rows to the CSV file path, creating or truncating it.
+
+	err := ucsv.Write("users.csv", users, ucsv.WithComma(';'))
+*/
+func Write[T any](path string, rows []T, opts ...Option) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	return WriteAll(f, rows, opts...)
+}
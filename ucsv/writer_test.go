@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ucsv
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteAll(t *testing.T) {
+	nickname := "MDGSF"
+	users := []User{
+		{Base: Base{ID: 1}, Name: "huangjian", Age: 18, Score: 99.5, Active: true,
+			Created: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC), Timeout: time.Second, Nickname: &nickname},
+		{Base: Base{ID: 2}, Name: "MDGSF, \"Inc\"", Comment: " spaced"},
+	}
+	var buf bytes.Buffer
+	assert.Equal(t, nil, WriteAll(&buf, users), "they should be equal")
+	want := "id,name,age,score,active,created,timeout,nickname,Comment\n" +
+		"1,huangjian,18,99.5,true,2019-01-02T03:04:05Z,1s,MDGSF,\n" +
+		"2,\"MDGSF, \"\"Inc\"\"\",0,0,false,,0s,,\" spaced\"\n"
+	assert.Equal(t, want, buf.String(), "they should be equal")
+
+	// Round trip.
+	back, err := ReadAll[User](&buf)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, users[1], back[1], "they should be equal")
+	assert.Equal(t, users[0].Created.Unix(), back[0].Created.Unix(), "they should be equal")
+}
+
+func TestWriteOptions(t *testing.T) {
+	type row struct {
+		A string `col:"a"`
+		B int    `col:"b"`
+	}
+	var buf bytes.Buffer
+	err := WriteAll(&buf, []*row{{A: "x", B: 1}, nil}, WithTag("col"), WithComma('\t'), WithQuoteAll(), WithCRLF())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "\"a\"\t\"b\"\r\n\"x\"\t\"1\"\r\n\"\"\t\"\"\r\n", buf.String(), "they should be equal")
+
+	buf.Reset()
+	err = WriteAll(&buf, []row{{A: "x", B: 1}}, WithTag("col"), WithoutHeader())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "x,1\n", buf.String(), "they should be equal")
+
+	assert.Equal(t, ErrNotStruct, WriteAll(&buf, []string{"x"}), "they should be equal")
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ucsv")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "users.csv")
+
+	assert.Equal(t, nil, Write(path, []User{{Name: "huangjian"}}), "they should be equal")
+	var users []User
+	assert.Equal(t, nil, Read(path, &users), "they should be equal")
+	assert.Equal(t, []User{{Name: "huangjian"}}, users, "they should be equal")
+}
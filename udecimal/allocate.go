@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package udecimal
+
+import "math/big"
+
+/*
+Allocate splits d rounded half up to places decimals into parts
+proportional to ratios without losing a cent: the units left over by the
+truncated shares go one by one to the first parts with a non-zero ratio.
+It returns nil when the ratios do not sum to a positive number, negative
+places count as 0.
+
+	MustParse("100").Allocate(2, 1, 1, 1) // 33.34, 33.33, 33.33
+	MustParse("0.05").Allocate(2, 3, 7)   // 0.02, 0.03
+*/
+func (d Decimal) Allocate(places int32, ratios ...int64) []Decimal {
+	if places < 0 {
+		places = 0
+	}
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil
+		}
+		total += r
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	units := d.Round(places, RoundHalfUp).bigInt()
+	bigTotal := big.NewInt(total)
+	shares := make([]*big.Int, len(ratios))
+	remainder := new(big.Int).Set(units)
+	for i, r := range ratios {
+		shares[i] = new(big.Int).Mul(units, big.NewInt(r))
+		shares[i].Quo(shares[i], bigTotal)
+		remainder.Sub(remainder, shares[i])
+	}
+
+	// The remainder is smaller than the number of parts.
+	step := big.NewInt(int64(remainder.Sign()))
+	for i := 0; remainder.Sign() != 0; i++ {
+		if ratios[i%len(ratios)] == 0 {
+			continue
+		}
+		shares[i%len(ratios)].Add(shares[i%len(ratios)], step)
+		remainder.Sub(remainder, step)
+	}
+
+	parts := make([]Decimal, len(shares))
+	for i, share := range shares {
+		parts[i] = Decimal{value: share, scale: places}
+	}
+	return parts
+}
+
+// Split divides d rounded to places decimals into n parts which differ by
+// at most one unit and sum to the rounded d.
+func (d Decimal) Split(places int32, n int) []Decimal {
+	if n <= 0 {
+		return nil
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return d.Allocate(places, ratios...)
+}
@@ -0,0 +1,51 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package udecimal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func texts(ds []Decimal) []string {
+	var result []string
+	for _, d := range ds {
+		result = append(result, d.String())
+	}
+	return result
+}
+
+func TestAllocate(t *testing.T) {
+	assert.Equal(t, []string{"33.34", "33.33", "33.33"}, texts(MustParse("100").Allocate(2, 1, 1, 1)), "they should be equal")
+	assert.Equal(t, []string{"0.02", "0.03"}, texts(MustParse("0.05").Allocate(2, 3, 7)), "they should be equal")
+	assert.Equal(t, []string{"0.00", "0.06", "0.04"}, texts(MustParse("0.10").Allocate(2, 0, 3, 2)), "they should be equal")
+	assert.Equal(t, []string{"-33.34", "-33.33", "-33.33"}, texts(MustParse("-100").Allocate(2, 1, 1, 1)), "they should be equal")
+	assert.Equal(t, []string(nil), texts(MustParse("1").Allocate(2, 0, 0)), "they should be equal")
+	assert.Equal(t, []string(nil), texts(MustParse("1").Allocate(2, 1, -1)), "they should be equal")
+
+	parts := MustParse("10.005").Split(2, 3)
+	assert.Equal(t, []string{"3.34", "3.34", "3.33"}, texts(parts), "they should be equal")
+	assert.Equal(t, "10.01", Sum(parts...).String(), "they should be equal")
+	assert.Equal(t, []string(nil), texts(MustParse("1").Split(2, 0)), "they should be equal")
+}
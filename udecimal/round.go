@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package udecimal
+
+import "math/big"
+
+// RoundingMode tells how to round away dropped digits.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to nearest, ties away from zero: 2.5 -> 3,
+	// -2.5 -> -3.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to nearest, ties to even, also called banker's
+	// rounding: 2.5 -> 2, 3.5 -> 4.
+	RoundHalfEven
+	// RoundHalfDown rounds to nearest, ties toward zero: 2.5 -> 2.
+	RoundHalfDown
+	// RoundDown truncates toward zero: 2.9 -> 2, -2.9 -> -2.
+	RoundDown
+	// RoundUp rounds away from zero: 2.1 -> 3, -2.1 -> -3.
+	RoundUp
+	// RoundFloor rounds toward negative infinity: -2.1 -> -3.
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity: 2.1 -> 3.
+	RoundCeiling
+)
+
+// roundQuo returns n / m rounded to an integer by mode.
+func roundQuo(n, m *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, m, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	// sign of the exact quotient and |r| compared to |m|/2.
+	sign := n.Sign() * m.Sign()
+	half := new(big.Int).Abs(r)
+	half.Lsh(half, 1)
+	cmpHalf := half.Cmp(new(big.Int).Abs(m))
+
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = cmpHalf >= 0
+	case RoundHalfEven:
+		away = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+	case RoundHalfDown:
+		away = cmpHalf > 0
+	case RoundUp:
+		away = true
+	case RoundFloor:
+		away = sign < 0
+	case RoundCeiling:
+		away = sign > 0
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+/*
+Round returns d rounded by mode to places digits after the decimal point,
+the result has exactly that scale: MustParse("1.5").Round(2, RoundHalfUp)
+is "1.50". Negative places round to tens, hundreds and so on.
+*/
+func (d Decimal) Round(places int32, mode RoundingMode) Decimal {
+	if places >= d.scale {
+		return Decimal{value: d.rescale(places), scale: places}
+	}
+
+	q := roundQuo(d.bigInt(), pow10(d.scale-places), mode)
+	if places < 0 {
+		return Decimal{value: q.Mul(q, pow10(-places))}
+	}
+	return Decimal{value: q, scale: places}
+}
+
+// Truncate returns d with the digits after places dropped.
+func (d Decimal) Truncate(places int32) Decimal {
+	return d.Round(places, RoundDown)
+}
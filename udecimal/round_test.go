@@ -0,0 +1,53 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package udecimal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	inputs := []string{"2.5", "-2.5", "3.5", "2.4", "-2.6", "2.1", "-2.1"}
+	want := map[RoundingMode][]string{
+		RoundHalfUp:   {"3", "-3", "4", "2", "-3", "2", "-2"},
+		RoundHalfEven: {"2", "-2", "4", "2", "-3", "2", "-2"},
+		RoundHalfDown: {"2", "-2", "3", "2", "-3", "2", "-2"},
+		RoundDown:     {"2", "-2", "3", "2", "-2", "2", "-2"},
+		RoundUp:       {"3", "-3", "4", "3", "-3", "3", "-3"},
+		RoundFloor:    {"2", "-3", "3", "2", "-3", "2", "-3"},
+		RoundCeiling:  {"3", "-2", "4", "3", "-2", "3", "-2"},
+	}
+	for mode, outputs := range want {
+		for i, in := range inputs {
+			assert.Equal(t, outputs[i], MustParse(in).Round(0, mode).String(), "mode %d: %s", mode, in)
+		}
+	}
+
+	assert.Equal(t, "1.01", MustParse("1.005").Round(2, RoundHalfUp).String(), "they should be equal")
+	assert.Equal(t, "1.50", MustParse("1.5").Round(2, RoundHalfUp).String(), "they should be equal")
+	assert.Equal(t, "1200", MustParse("1250").Round(-2, RoundHalfEven).String(), "they should be equal")
+	assert.Equal(t, "1.50", MustParse("1.5").StringFixed(2), "they should be equal")
+	assert.Equal(t, "9.99", MustParse("9.999").Truncate(2).String(), "they should be equal")
+}
@@ -0,0 +1,367 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package udecimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidDecimal is returned when parsing fails.
+	ErrInvalidDecimal = errors.New("udecimal: invalid decimal")
+
+	// ErrDivisionByZero is the panic value of a division by zero.
+	ErrDivisionByZero = errors.New("udecimal: division by zero")
+)
+
+// MaxParseDigits bounds the digits Parse produces on either side of the
+// point, so an exponent like "1e100000000" in untrusted input can not make
+// it allocate and compute for minutes.
+const MaxParseDigits = 10000
+
+var (
+	bigZero = big.NewInt(0)
+	bigTen  = big.NewInt(10)
+)
+
+// pow10 returns 10^n for n >= 0.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+/*
+Decimal is an exact decimal number, value * 10^-scale, for money and other
+amounts where float64 rounding is unacceptable. The zero value is 0.
+Decimals are immutable, every operation returns a new one.
+
+	price := udecimal.MustParse("19.99")
+	total := price.Mul(udecimal.NewFromInt(3)) // 59.97
+	tax := total.Mul(udecimal.MustParse("0.0825")).Round(2, udecimal.RoundHalfEven)
+*/
+type Decimal struct {
+	value *big.Int
+	scale int32
+}
+
+// New returns value * 10^exp.
+func New(value int64, exp int32) Decimal {
+	v := big.NewInt(value)
+	if exp >= 0 {
+		return Decimal{value: v.Mul(v, pow10(exp))}
+	}
+	return Decimal{value: v, scale: -exp}
+}
+
+// NewFromInt returns i as a Decimal.
+func NewFromInt(i int64) Decimal {
+	return New(i, 0)
+}
+
+// NewFromFloat returns the shortest decimal which reads back as f, so
+// NewFromFloat(0.1) is exactly 0.1. It panics for NaN and infinities.
+func NewFromFloat(f float64) Decimal {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		panic(fmt.Sprintf("udecimal: cannot convert %v to Decimal", f))
+	}
+	d, _ := Parse(strconv.FormatFloat(f, 'g', -1, 64))
+	return d
+}
+
+/*
+Parse parses a decimal like "-12.345", "+1", ".5" or "1.5e-3". Exponents
+are applied so the result has no negative scale: "1e3" is 1000. Numbers
+with more than MaxParseDigits digits before or after the point are
+rejected.
+*/
+func Parse(s string) (Decimal, error) {
+	text := strings.TrimSpace(s)
+	invalid := fmt.Errorf("%w: %q", ErrInvalidDecimal, s)
+
+	var exp int64
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(text[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, invalid
+		}
+		exp = e
+		text = text[:i]
+	}
+
+	negative := false
+	if text != "" && (text[0] == '-' || text[0] == '+') {
+		negative = text[0] == '-'
+		text = text[1:]
+	}
+
+	intPart, fracPart := text, ""
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		intPart, fracPart = text[:i], text[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return Decimal{}, invalid
+	}
+	digits := intPart + fracPart
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return Decimal{}, invalid
+		}
+	}
+
+	v, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, invalid
+	}
+	if negative {
+		v.Neg(v)
+	}
+
+	scale := int64(len(fracPart)) - exp
+	if scale > MaxParseDigits || int64(len(intPart))-scale > MaxParseDigits {
+		return Decimal{}, fmt.Errorf("%w: more than %d digits", invalid, MaxParseDigits)
+	}
+	if scale < 0 {
+		return Decimal{value: v.Mul(v, pow10(int32(-scale)))}, nil
+	}
+	return Decimal{value: v, scale: int32(scale)}, nil
+}
+
+// MustParse is like Parse but panics on error.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// bigInt returns the coefficient, 0 for the zero Decimal.
+func (d Decimal) bigInt() *big.Int {
+	if d.value == nil {
+		return bigZero
+	}
+	return d.value
+}
+
+// rescale returns the coefficient of d at scale, which must be >= d.scale.
+func (d Decimal) rescale(scale int32) *big.Int {
+	v := new(big.Int).Set(d.bigInt())
+	if scale > d.scale {
+		v.Mul(v, pow10(scale-d.scale))
+	}
+	return v
+}
+
+// align returns the coefficients of a and b at their common scale.
+func align(a, b Decimal) (*big.Int, *big.Int, int32) {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	return a.rescale(scale), b.rescale(scale), scale
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Add returns d + d2.
+func (d Decimal) Add(d2 Decimal) Decimal {
+	a, b, scale := align(d, d2)
+	return Decimal{value: a.Add(a, b), scale: scale}
+}
+
+// Sub returns d - d2.
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	a, b, scale := align(d, d2)
+	return Decimal{value: a.Sub(a, b), scale: scale}
+}
+
+// Mul returns d * d2 exactly, with the scales added.
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	v := new(big.Int).Mul(d.bigInt(), d2.bigInt())
+	return Decimal{value: v, scale: d.scale + d2.scale}
+}
+
+// Div returns d / d2 rounded to places digits after the decimal point by
+// mode, negative places count as 0. It panics with ErrDivisionByZero when
+// d2 is 0.
+func (d Decimal) Div(d2 Decimal, places int32, mode RoundingMode) Decimal {
+	if d2.IsZero() {
+		panic(ErrDivisionByZero)
+	}
+	if places < 0 {
+		places = 0
+	}
+	// d / d2 at scale places is (d.v * 10^(places+d2.scale)) / (d2.v * 10^d.scale).
+	n := new(big.Int).Mul(d.bigInt(), pow10(places+d2.scale))
+	m := new(big.Int).Mul(d2.bigInt(), pow10(d.scale))
+	return Decimal{value: roundQuo(n, m, mode), scale: places}
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.bigInt()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.bigInt()), scale: d.scale}
+}
+
+// Sign returns -1, 0 or 1.
+func (d Decimal) Sign() int {
+	return d.bigInt().Sign()
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1, 0 or 1 as d is less than, equal to or greater than d2.
+func (d Decimal) Cmp(d2 Decimal) int {
+	a, b, _ := align(d, d2)
+	return a.Cmp(b)
+}
+
+// Equal reports whether d and d2 are the same number, 1.5 equals 1.50.
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+// LessThan reports whether d < d2.
+func (d Decimal) LessThan(d2 Decimal) bool {
+	return d.Cmp(d2) < 0
+}
+
+// GreaterThan reports whether d > d2.
+func (d Decimal) GreaterThan(d2 Decimal) bool {
+	return d.Cmp(d2) > 0
+}
+
+// Int64 returns the integer part of d, truncated toward zero.
+func (d Decimal) Int64() int64 {
+	return new(big.Int).Quo(d.bigInt(), pow10(d.scale)).Int64()
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d without exponent, keeping its scale: "1.50".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.bigInt()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		point := len(digits) - int(d.scale)
+		digits = digits[:point] + "." + digits[point:]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// StringFixed formats d rounded half up to exactly places decimals:
+// MustParse("1.5").StringFixed(2) == "1.50".
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places, RoundHalfUp).String()
+}
+
+// Sum returns the sum of ds, 0 for none.
+func Sum(ds ...Decimal) Decimal {
+	var sum Decimal
+	for _, d := range ds {
+		sum = sum.Add(d)
+	}
+	return sum
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON encodes d as a JSON string, "12.30", so no precision is lost
+// in clients which read numbers as float64.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts JSON strings and numbers, null leaves d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// Value implements driver.Valuer, decimals are stored as strings.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan implements sql.Scanner for strings, bytes, integers and floats.
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	case int64:
+		*d = NewFromInt(v)
+		return nil
+	case float64:
+		*d = NewFromFloat(v)
+		return nil
+	}
+	return fmt.Errorf("udecimal: cannot scan %T into Decimal", src)
+}
@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package udecimal
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"0":                                 "0",
+		"-12.345":                           "-12.345",
+		"+1":                                "1",
+		".5":                                "0.5",
+		"5.":                                "5",
+		"1.50":                              "1.50",
+		"1.5e-3":                            "0.0015",
+		"1e3":                               "1000",
+		"-0.001":                            "-0.001",
+		" 42 ":                              "42",
+		"123456789012345678901234567890.12": "123456789012345678901234567890.12",
+	}
+	for in, want := range tests {
+		d, err := Parse(in)
+		assert.Equal(t, nil, err, in)
+		assert.Equal(t, want, d.String(), in)
+	}
+
+	for _, in := range []string{"", "-", ".", "1.2.3", "abc", "1e", "1e1.5", "--1"} {
+		_, err := Parse(in)
+		assert.Equal(t, true, errors.Is(err, ErrInvalidDecimal), in)
+	}
+	assert.Panics(t, func() { MustParse("huangjian") })
+}
+
+func TestParseHugeExponent(t *testing.T) {
+	start := time.Now()
+	for _, in := range []string{"1e100000000", "1e-100000000", "1e2147483647", "0.1e-2147483647"} {
+		_, err := Parse(in)
+		assert.Equal(t, true, errors.Is(err, ErrInvalidDecimal), in)
+	}
+	var d Decimal
+	assert.NotEqual(t, nil, json.Unmarshal([]byte(`"1e10000000"`), &d), "they should not be equal")
+	assert.Equal(t, true, time.Since(start) < time.Second, "they should be equal")
+
+	d, err := Parse("1e9999")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 10000, len(d.String()), "they should be equal")
+}
+
+func TestConstructors(t *testing.T) {
+	assert.Equal(t, "12.34", New(1234, -2).String(), "they should be equal")
+	assert.Equal(t, "1200", New(12, 2).String(), "they should be equal")
+	assert.Equal(t, "7", NewFromInt(7).String(), "they should be equal")
+	assert.Equal(t, "0.1", NewFromFloat(0.1).String(), "they should be equal")
+	assert.Equal(t, "1000000000000000000000", NewFromFloat(1e21).String(), "they should be equal")
+	assert.Equal(t, "0", Decimal{}.String(), "they should be equal")
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustParse("0.1"), MustParse("0.2")
+	assert.Equal(t, "0.3", a.Add(b).String(), "they should be equal")
+	assert.Equal(t, "-0.1", a.Sub(b).String(), "they should be equal")
+	assert.Equal(t, "0.02", a.Mul(b).String(), "they should be equal")
+	assert.Equal(t, "59.97", MustParse("19.99").Mul(NewFromInt(3)).String(), "they should be equal")
+
+	assert.Equal(t, "0.33", NewFromInt(1).Div(NewFromInt(3), 2, RoundHalfUp).String(), "they should be equal")
+	assert.Equal(t, "0.67", NewFromInt(2).Div(NewFromInt(3), 2, RoundHalfUp).String(), "they should be equal")
+	assert.Equal(t, "-0.67", NewFromInt(-2).Div(NewFromInt(3), 2, RoundHalfUp).String(), "they should be equal")
+	assert.Equal(t, "40", MustParse("10").Div(MustParse("0.25"), 0, RoundHalfUp).String(), "they should be equal")
+	assert.Panics(t, func() { a.Div(Decimal{}, 2, RoundHalfUp) })
+
+	// Operations do not change their operands.
+	var zero Decimal
+	assert.Equal(t, "0.1", zero.Add(a).String(), "they should be equal")
+	assert.Equal(t, "0", zero.String(), "they should be equal")
+	assert.Equal(t, "0.1", a.Neg().Abs().String(), "they should be equal")
+	assert.Equal(t, "0.1", a.String(), "they should be equal")
+	assert.Equal(t, "0.6", Sum(a, b, MustParse("0.3")).String(), "they should be equal")
+}
+
+func TestCompare(t *testing.T) {
+	assert.Equal(t, true, MustParse("1.5").Equal(MustParse("1.50")), "they should be equal")
+	assert.Equal(t, true, MustParse("-2").LessThan(MustParse("1.5")), "they should be equal")
+	assert.Equal(t, true, MustParse("2.01").GreaterThan(MustParse("2")), "they should be equal")
+	assert.Equal(t, -1, MustParse("-0.5").Sign(), "they should be equal")
+	assert.Equal(t, true, MustParse("0.00").IsZero(), "they should be equal")
+	assert.Equal(t, int64(-12), MustParse("-12.9").Int64(), "they should be equal")
+	assert.Equal(t, 12.5, MustParse("12.5").Float64(), "they should be equal")
+	assert.Equal(t, int32(2), MustParse("1.50").Scale(), "they should be equal")
+}
+
+func TestEncoding(t *testing.T) {
+	type order struct {
+		Price Decimal  `json:"price"`
+		Tax   *Decimal `json:"tax"`
+	}
+	var o order
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"price": 19.99, "tax": "1.65"}`), &o), "they should be equal")
+	assert.Equal(t, "19.99", o.Price.String(), "they should be equal")
+	assert.Equal(t, "1.65", o.Tax.String(), "they should be equal")
+
+	data, err := json.Marshal(o)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"price":"19.99","tax":"1.65"}`, string(data), "they should be equal")
+
+	assert.NotEqual(t, nil, json.Unmarshal([]byte(`{"price": "abc"}`), &o), "they should not be equal")
+
+	var d Decimal
+	for _, src := range []interface{}{"1.25", []byte("1.25"), 1.25} {
+		assert.Equal(t, nil, d.Scan(src), "they should be equal")
+		assert.Equal(t, "1.25", d.String(), "they should be equal")
+	}
+	assert.Equal(t, nil, d.Scan(int64(3)), "they should be equal")
+	assert.Equal(t, "3", d.String(), "they should be equal")
+	assert.NotEqual(t, nil, d.Scan(true), "they should not be equal")
+
+	v, err := MustParse("1.50").Value()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "1.50", v, "they should be equal")
+}
@@ -0,0 +1,238 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uencode
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/MDGSF/utils/ucompress"
+)
+
+/*
+Envelopes written by Marshal are laid out as:
+
+	magic "UE" | envelope version 1 | codec | flags | version uint32 | payload
+
+all integers big endian. The version is the one given with WithVersion,
+so readers can tell outdated cached entries from current ones.
+*/
+const (
+	envelopeVersion = 1
+	headerSize      = 9
+
+	flagCompressed = 1 << 0
+)
+
+var envelopeMagic = [2]byte{'U', 'E'}
+
+// CodecGob is the id of the encoding/gob codec, the default.
+const CodecGob byte = 1
+
+var (
+	// ErrInvalidEnvelope is returned when decoding data not written by
+	// Marshal.
+	ErrInvalidEnvelope = errors.New("uencode: invalid envelope")
+	// ErrVersionMismatch is returned by Unmarshal when the version of the
+	// envelope is not the expected one.
+	ErrVersionMismatch = errors.New("uencode: version mismatch")
+	// ErrUnknownCodec is returned for codec ids not registered.
+	ErrUnknownCodec = errors.New("uencode: unknown codec")
+)
+
+// Codec serializes values for Marshal and Unmarshal.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+var (
+	codecLock sync.RWMutex
+	codecs    = map[byte]Codec{CodecGob: gobCodec{}}
+)
+
+/*
+RegisterCodec registers c under id for use with WithCodec, for example a
+msgpack codec wrapping github.com/vmihailenco/msgpack:
+
+	uencode.RegisterCodec(2, msgpackCodec{})
+	data, err := uencode.Marshal(v, uencode.WithCodec(2))
+
+The id is stored in the envelopes, so it must not change once data is
+written.
+*/
+func RegisterCodec(id byte, c Codec) {
+	codecLock.Lock()
+	defer codecLock.Unlock()
+	codecs[id] = c
+}
+
+func getCodec(id byte) (Codec, error) {
+	codecLock.RLock()
+	defer codecLock.RUnlock()
+	c, ok := codecs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCodec, id)
+	}
+	return c, nil
+}
+
+type envelopeOptions struct {
+	codec         byte
+	version       uint32
+	checkVersion  bool
+	compress      bool
+	compressAbove int
+}
+
+// EnvelopeOption configures Marshal and Unmarshal.
+type EnvelopeOption func(*envelopeOptions)
+
+// WithCodec sets the codec of Marshal, default is CodecGob. Unmarshal
+// reads the codec from the envelope.
+func WithCodec(id byte) EnvelopeOption {
+	return func(o *envelopeOptions) {
+		o.codec = id
+	}
+}
+
+// WithVersion sets the version written by Marshal, default 0. Given to
+// Unmarshal, envelopes of another version fail with ErrVersionMismatch.
+func WithVersion(version uint32) EnvelopeOption {
+	return func(o *envelopeOptions) {
+		o.version = version
+		o.checkVersion = true
+	}
+}
+
+// WithCompression compresses payloads of at least minSize bytes with
+// ucompress, small payloads are not worth it.
+func WithCompression(minSize int) EnvelopeOption {
+	return func(o *envelopeOptions) {
+		o.compress = true
+		o.compressAbove = minSize
+	}
+}
+
+func newEnvelopeOptions(opts []EnvelopeOption) *envelopeOptions {
+	o := &envelopeOptions{codec: CodecGob}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+/*
+Marshal serializes v in a versioned envelope, for caching structs to disk
+or Redis.
+
+	data, err := uencode.Marshal(user, uencode.WithVersion(2), uencode.WithCompression(1024))
+	...
+	err = uencode.Unmarshal(data, &user, uencode.WithVersion(2))
+	if errors.Is(err, uencode.ErrVersionMismatch) {
+		// outdated entry, treat as a cache miss
+	}
+*/
+func Marshal(v interface{}, opts ...EnvelopeOption) ([]byte, error) {
+	o := newEnvelopeOptions(opts)
+	codec, err := getCodec(o.codec)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags byte
+	if o.compress && len(payload) >= o.compressAbove {
+		payload, err = ucompress.Compress(payload, ucompress.WithFormat(ucompress.Zlib))
+		if err != nil {
+			return nil, err
+		}
+		flags |= flagCompressed
+	}
+
+	data := make([]byte, headerSize, headerSize+len(payload))
+	data[0], data[1] = envelopeMagic[0], envelopeMagic[1]
+	data[2] = envelopeVersion
+	data[3] = o.codec
+	data[4] = flags
+	binary.BigEndian.PutUint32(data[5:], o.version)
+	return append(data, payload...), nil
+}
+
+// Unmarshal decodes data written by Marshal into v.
+func Unmarshal(data []byte, v interface{}, opts ...EnvelopeOption) error {
+	o := newEnvelopeOptions(opts)
+	version, err := Version(data)
+	if err != nil {
+		return err
+	}
+	if o.checkVersion && version != o.version {
+		return fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, version, o.version)
+	}
+	codec, err := getCodec(data[3])
+	if err != nil {
+		return err
+	}
+
+	payload := data[headerSize:]
+	if data[4]&flagCompressed != 0 {
+		payload, err = ucompress.Decompress(payload)
+		if err != nil {
+			return err
+		}
+	}
+	return codec.Unmarshal(payload, v)
+}
+
+// Version returns the version of an envelope written by Marshal without
+// decoding it.
+func Version(data []byte) (uint32, error) {
+	if len(data) < headerSize || data[0] != envelopeMagic[0] || data[1] != envelopeMagic[1] {
+		return 0, ErrInvalidEnvelope
+	}
+	if data[2] != envelopeVersion {
+		return 0, fmt.Errorf("%w: envelope version %d", ErrInvalidEnvelope, data[2])
+	}
+	return binary.BigEndian.Uint32(data[5:]), nil
+}
@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uencode
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type envelopeUser struct {
+	Name string
+	Age  int
+	Tags []string
+}
+
+func TestMarshal(t *testing.T) {
+	u := envelopeUser{Name: "huangjian", Age: 18, Tags: []string{"MDGSF"}}
+	data, err := Marshal(u)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "UE", string(data[:2]), "they should be equal")
+
+	var got envelopeUser
+	assert.Equal(t, nil, Unmarshal(data, &got), "they should be equal")
+	assert.Equal(t, u, got, "they should be equal")
+
+	version, err := Version(data)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, uint32(0), version, "they should be equal")
+}
+
+func TestMarshalVersion(t *testing.T) {
+	u := envelopeUser{Name: "huangjian"}
+	data, err := Marshal(u, WithVersion(2))
+	assert.Equal(t, nil, err, "they should be equal")
+
+	version, err := Version(data)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, uint32(2), version, "they should be equal")
+
+	var got envelopeUser
+	assert.Equal(t, nil, Unmarshal(data, &got, WithVersion(2)), "they should be equal")
+	err = Unmarshal(data, &got, WithVersion(3))
+	assert.Equal(t, true, errors.Is(err, ErrVersionMismatch), "they should be equal")
+	assert.Equal(t, "uencode: version mismatch: got 2, want 3", err.Error(), "they should be equal")
+}
+
+func TestMarshalCompression(t *testing.T) {
+	u := envelopeUser{Name: strings.Repeat("huangjian", 1000)}
+	plain, err := Marshal(u)
+	assert.Equal(t, nil, err, "they should be equal")
+	compressed, err := Marshal(u, WithCompression(1024))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, len(compressed) < len(plain)/10, "they should be equal")
+
+	var got envelopeUser
+	assert.Equal(t, nil, Unmarshal(compressed, &got), "they should be equal")
+	assert.Equal(t, u, got, "they should be equal")
+
+	small, err := Marshal(envelopeUser{Name: "MDGSF"}, WithCompression(1024))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, byte(0), small[4]&flagCompressed, "they should be equal")
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+func TestCodec(t *testing.T) {
+	_, err := Marshal(1, WithCodec(200))
+	assert.Equal(t, true, errors.Is(err, ErrUnknownCodec), "they should be equal")
+
+	RegisterCodec(200, jsonCodec{})
+	data, err := Marshal(envelopeUser{Name: "MDGSF"}, WithCodec(200))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"Name":"MDGSF","Age":0,"Tags":null}`, string(data[headerSize:]), "they should be equal")
+	var got envelopeUser
+	assert.Equal(t, nil, Unmarshal(data, &got), "they should be equal")
+	assert.Equal(t, "MDGSF", got.Name, "they should be equal")
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var got envelopeUser
+	assert.Equal(t, ErrInvalidEnvelope, Unmarshal([]byte("huangjian"), &got), "they should be equal")
+	assert.Equal(t, ErrInvalidEnvelope, Unmarshal(nil, &got), "they should be equal")
+	err := Unmarshal([]byte("UE\x09\x01\x00\x00\x00\x00\x00"), &got)
+	assert.Equal(t, true, errors.Is(err, ErrInvalidEnvelope), "they should be equal")
+}
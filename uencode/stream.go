@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uencode
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+)
+
+/*
+NewBase64Encoder returns a writer encoding to w in standard base64, padded
+by default. Close must be called to flush the last partial block.
+
+	enc := uencode.NewBase64Encoder(f)
+	io.Copy(enc, src)
+	enc.Close()
+*/
+func NewBase64Encoder(w io.Writer, opts ...Option) io.WriteCloser {
+	return base64.NewEncoder(base64Encoding(false, padded(opts, true)), w)
+}
+
+// NewBase64Decoder returns a reader decoding standard base64 from r, with
+// or without padding. Line breaks are ignored.
+func NewBase64Decoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.RawStdEncoding, &unpadReader{r: r})
+}
+
+// NewBase64URLEncoder returns a writer encoding to w in url safe base64,
+// without padding by default. Close must be called.
+func NewBase64URLEncoder(w io.Writer, opts ...Option) io.WriteCloser {
+	return base64.NewEncoder(base64Encoding(true, padded(opts, false)), w)
+}
+
+// NewBase64URLDecoder returns a reader decoding url safe base64 from r,
+// with or without padding.
+func NewBase64URLDecoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.RawURLEncoding, &unpadReader{r: r})
+}
+
+// NewBase32Encoder returns a writer encoding to w in standard base32,
+// padded by default. Close must be called.
+func NewBase32Encoder(w io.Writer, opts ...Option) io.WriteCloser {
+	return base32.NewEncoder(base32Encoding(padded(opts, true)), w)
+}
+
+// NewBase32Decoder returns a reader decoding standard base32 from r, with
+// or without padding.
+func NewBase32Decoder(r io.Reader) io.Reader {
+	return base32.NewDecoder(base32Encoding(false), &unpadReader{r: r})
+}
+
+// NewHexEncoder returns a writer encoding to w in lower case hex.
+func NewHexEncoder(w io.Writer) io.Writer {
+	return hex.NewEncoder(w)
+}
+
+// NewHexDecoder returns a reader decoding hex from r.
+func NewHexDecoder(r io.Reader) io.Reader {
+	return hex.NewDecoder(r)
+}
+
+// unpadReader drops the "=" padding from r, so the raw encodings decode
+// padded input too.
+type unpadReader struct {
+	r io.Reader
+}
+
+func (u *unpadReader) Read(p []byte) (int, error) {
+	for {
+		n, err := u.r.Read(p)
+		j := 0
+		for _, b := range p[:n] {
+			if b != '=' {
+				p[j] = b
+				j++
+			}
+		}
+		if j > 0 || err != nil {
+			return j, err
+		}
+	}
+}
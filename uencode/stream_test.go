@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uencode
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamBase64(t *testing.T) {
+	data := bytes.Repeat([]byte("huangjian?>"), 100)
+
+	var buf bytes.Buffer
+	enc := NewBase64Encoder(&buf)
+	enc.Write(data[:7])
+	enc.Write(data[7:])
+	assert.Equal(t, nil, enc.Close(), "they should be equal")
+	assert.Equal(t, Base64Encode(data), buf.String(), "they should be equal")
+	got, err := ioutil.ReadAll(NewBase64Decoder(&buf))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, data, got, "they should be equal")
+
+	buf.Reset()
+	enc = NewBase64URLEncoder(&buf, WithPadding(true))
+	enc.Write(data)
+	enc.Close()
+	assert.Equal(t, Base64URLEncode(data, WithPadding(true)), buf.String(), "they should be equal")
+	got, err = ioutil.ReadAll(NewBase64URLDecoder(&buf))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, data, got, "they should be equal")
+
+	got, err = ioutil.ReadAll(NewBase64Decoder(strings.NewReader("aHVhbmdq\naWFuPz4=\n")))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []byte("huangjian?>"), got, "they should be equal")
+}
+
+func TestStreamBase32Hex(t *testing.T) {
+	data := []byte("MDGS")
+
+	var buf bytes.Buffer
+	enc := NewBase32Encoder(&buf)
+	enc.Write(data)
+	enc.Close()
+	assert.Equal(t, "JVCEOUY=", buf.String(), "they should be equal")
+	got, err := ioutil.ReadAll(NewBase32Decoder(&buf))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, data, got, "they should be equal")
+
+	buf.Reset()
+	NewHexEncoder(&buf).Write(data)
+	assert.Equal(t, "4d444753", buf.String(), "they should be equal")
+	got, err = ioutil.ReadAll(NewHexDecoder(&buf))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, data, got, "they should be equal")
+}
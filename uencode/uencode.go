@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uencode
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+type options struct {
+	padding *bool
+}
+
+// Option configures an encoding.
+type Option func(*options)
+
+// WithPadding sets whether encoded output ends with "=" padding. By
+// default base64 and base32 are padded, base64url and tokens are not.
+// Decoding accepts both.
+func WithPadding(padding bool) Option {
+	return func(o *options) {
+		o.padding = &padding
+	}
+}
+
+func padded(opts []Option, def bool) bool {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.padding == nil {
+		return def
+	}
+	return *o.padding
+}
+
+func base64Encoding(url, padding bool) *base64.Encoding {
+	switch {
+	case url && padding:
+		return base64.URLEncoding
+	case url:
+		return base64.RawURLEncoding
+	case padding:
+		return base64.StdEncoding
+	}
+	return base64.RawStdEncoding
+}
+
+// Base64Encode returns the standard base64 encoding of data, padded by
+// default.
+func Base64Encode(data []byte, opts ...Option) string {
+	return base64Encoding(false, padded(opts, true)).EncodeToString(data)
+}
+
+// Base64Decode decodes standard base64, with or without padding.
+func Base64Decode(s string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// Base64URLEncode returns the url safe base64 encoding of data, without
+// padding by default.
+func Base64URLEncode(data []byte, opts ...Option) string {
+	return base64Encoding(true, padded(opts, false)).EncodeToString(data)
+}
+
+// Base64URLDecode decodes url safe base64, with or without padding.
+func Base64URLDecode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// HexEncode returns the lower case hex encoding of data.
+func HexEncode(data []byte) string {
+	return hex.EncodeToString(data)
+}
+
+// HexDecode decodes hex in upper or lower case.
+func HexDecode(s string) ([]byte, error) {
+	return hex.DecodeString(s)
+}
+
+func base32Encoding(padding bool) *base32.Encoding {
+	if padding {
+		return base32.StdEncoding
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding)
+}
+
+// Base32Encode returns the standard base32 encoding of data, padded by
+// default.
+func Base32Encode(data []byte, opts ...Option) string {
+	return base32Encoding(padded(opts, true)).EncodeToString(data)
+}
+
+// Base32Decode decodes standard base32 in upper or lower case, with or
+// without padding, like the secrets of authenticator apps.
+func Base32Decode(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.TrimRight(s, "="))
+	return base32Encoding(false).DecodeString(s)
+}
+
+// EncodeToken encodes data to a token safe in urls, file names and
+// cookies: url safe base64 without padding. See urand.Token to generate
+// random ones.
+func EncodeToken(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeToken decodes a token of EncodeToken. Padded tokens and tokens in
+// standard base64 are accepted too.
+func DecodeToken(token string) ([]byte, error) {
+	token = strings.TrimRight(token, "=")
+	token = strings.NewReplacer("+", "-", "/", "_").Replace(token)
+	return base64.RawURLEncoding.DecodeString(token)
+}
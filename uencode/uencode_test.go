@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uencode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBase64(t *testing.T) {
+	data := []byte("huangjian?>")
+	assert.Equal(t, "aHVhbmdqaWFuPz4=", Base64Encode(data), "they should be equal")
+	assert.Equal(t, "aHVhbmdqaWFuPz4", Base64Encode(data, WithPadding(false)), "they should be equal")
+	assert.Equal(t, "aHVhbmdqaWFuPz4", Base64URLEncode(data), "they should be equal")
+	assert.Equal(t, "aHVhbmdqaWFuPz4=", Base64URLEncode(data, WithPadding(true)), "they should be equal")
+
+	for _, s := range []string{"aHVhbmdqaWFuPz4=", "aHVhbmdqaWFuPz4"} {
+		got, err := Base64Decode(s)
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, data, got, "they should be equal")
+	}
+	got, err := Base64URLDecode(Base64URLEncode([]byte{0xfb, 0xff}))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []byte{0xfb, 0xff}, got, "they should be equal")
+
+	_, err = Base64Decode("a$")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestHex(t *testing.T) {
+	assert.Equal(t, "4d444753", HexEncode([]byte("MDGS")), "they should be equal")
+	got, err := HexDecode("4D444753")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []byte("MDGS"), got, "they should be equal")
+	_, err = HexDecode("4")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestBase32(t *testing.T) {
+	assert.Equal(t, "JVCEOU2G", Base32Encode([]byte("MDGSF")), "they should be equal")
+	assert.Equal(t, "JVCEOUY=", Base32Encode([]byte("MDGS")), "they should be equal")
+	assert.Equal(t, "JVCEOUY", Base32Encode([]byte("MDGS"), WithPadding(false)), "they should be equal")
+	for _, s := range []string{"JVCEOUY=", "JVCEOUY", "jvceouy"} {
+		got, err := Base32Decode(s)
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, []byte("MDGS"), got, "they should be equal")
+	}
+}
+
+func TestToken(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0x01}
+	token := EncodeToken(data)
+	assert.Equal(t, "-_8B", token, "they should be equal")
+	for _, s := range []string{"-_8B", "+/8B", "-_8B=="} {
+		got, err := DecodeToken(s)
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, data, got, "they should be equal")
+	}
+}
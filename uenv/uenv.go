@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uenv
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/utime"
+)
+
+// lookup returns the value of key, ok is false when it is unset or empty.
+func lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	return value, ok && value != ""
+}
+
+// Get returns the environment variable key, def when it is unset or empty.
+func Get(key, def string) string {
+	if value, ok := lookup(key); ok {
+		return value
+	}
+	return def
+}
+
+// MustGet returns the environment variable key and panics when it is unset
+// or empty.
+func MustGet(key string) string {
+	value, ok := lookup(key)
+	if !ok {
+		panic(fmt.Sprintf("uenv: environment variable %s is not set", key))
+	}
+	return value
+}
+
+// GetInt returns key parsed as an int, def when it is unset or invalid.
+func GetInt(key string, def int) int {
+	value, ok := lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// GetInt64 returns key parsed as an int64, def when it is unset or invalid.
+func GetInt64(key string, def int64) int64 {
+	value, ok := lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// GetFloat returns key parsed as a float64, def when it is unset or
+// invalid.
+func GetFloat(key string, def float64) float64 {
+	value, ok := lookup(key)
+	if !ok {
+		return def
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+// GetBool returns key parsed as a bool, def when it is unset or invalid.
+// Besides the strconv.ParseBool forms "yes", "no", "on" and "off" are
+// accepted.
+func GetBool(key string, def bool) bool {
+	value, ok := lookup(key)
+	if !ok {
+		return def
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "on":
+		return true
+	case "no", "n", "off":
+		return false
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// GetDuration returns key parsed by utime.ParseDuration, so "1d12h" works,
+// def when it is unset or invalid.
+func GetDuration(key string, def time.Duration) time.Duration {
+	value, ok := lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := utime.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+/*
+GetSlice splits key by sep and trims the parts, empty parts are dropped.
+It returns nil when key is unset or empty.
+
+	HOSTS="a, b,,c"
+	GetSlice("HOSTS", ",") == []string{"a", "b", "c"}
+*/
+func GetSlice(key, sep string) []string {
+	value, ok := lookup(key)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, part := range strings.Split(value, sep) {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+/*
+Setenv sets key to value and returns a func restoring the previous state,
+meant for tests:
+
+	defer uenv.Setenv("PORT", "8080")()
+*/
+func Setenv(key, value string) (restore func()) {
+	return Setenvs(map[string]string{key: value})
+}
+
+// Setenvs sets all vars and returns a func restoring the previous state.
+func Setenvs(vars map[string]string) (restore func()) {
+	saved := save(vars)
+	for key, value := range vars {
+		os.Setenv(key, value)
+	}
+	return saved
+}
+
+// Unsetenv unsets keys and returns a func restoring the previous state.
+func Unsetenv(keys ...string) (restore func()) {
+	vars := make(map[string]string, len(keys))
+	for _, key := range keys {
+		vars[key] = ""
+	}
+	saved := save(vars)
+	for _, key := range keys {
+		os.Unsetenv(key)
+	}
+	return saved
+}
+
+func save(vars map[string]string) func() {
+	type entry struct {
+		value string
+		ok    bool
+	}
+	old := make(map[string]entry, len(vars))
+	for key := range vars {
+		value, ok := os.LookupEnv(key)
+		old[key] = entry{value, ok}
+	}
+	return func() {
+		for key, e := range old {
+			if e.ok {
+				os.Setenv(key, e.value)
+			} else {
+				os.Unsetenv(key)
+			}
+		}
+	}
+}
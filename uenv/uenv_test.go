@@ -0,0 +1,93 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uenv
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	defer Setenvs(map[string]string{
+		"UENV_NAME":  "huangjian",
+		"UENV_EMPTY": "",
+	})()
+
+	assert.Equal(t, "huangjian", Get("UENV_NAME", "MDGSF"), "they should be equal")
+	assert.Equal(t, "MDGSF", Get("UENV_EMPTY", "MDGSF"), "they should be equal")
+	assert.Equal(t, "MDGSF", Get("UENV_MISSING", "MDGSF"), "they should be equal")
+
+	assert.Equal(t, "huangjian", MustGet("UENV_NAME"), "they should be equal")
+	assert.Panics(t, func() { MustGet("UENV_MISSING") })
+}
+
+func TestGetTyped(t *testing.T) {
+	defer Setenvs(map[string]string{
+		"UENV_INT":      " 42",
+		"UENV_BAD":      "abc",
+		"UENV_FLOAT":    "1.5",
+		"UENV_BOOL":     "yes",
+		"UENV_BOOL2":    "false",
+		"UENV_DURATION": "1d12h",
+	})()
+
+	assert.Equal(t, 42, GetInt("UENV_INT", 1), "they should be equal")
+	assert.Equal(t, 1, GetInt("UENV_BAD", 1), "they should be equal")
+	assert.Equal(t, int64(42), GetInt64("UENV_INT", 1), "they should be equal")
+	assert.Equal(t, 1.5, GetFloat("UENV_FLOAT", 0), "they should be equal")
+	assert.Equal(t, 0.5, GetFloat("UENV_BAD", 0.5), "they should be equal")
+	assert.Equal(t, true, GetBool("UENV_BOOL", false), "they should be equal")
+	assert.Equal(t, false, GetBool("UENV_BOOL2", true), "they should be equal")
+	assert.Equal(t, true, GetBool("UENV_BAD", true), "they should be equal")
+	assert.Equal(t, 36*time.Hour, GetDuration("UENV_DURATION", 0), "they should be equal")
+	assert.Equal(t, time.Second, GetDuration("UENV_BAD", time.Second), "they should be equal")
+}
+
+func TestGetSlice(t *testing.T) {
+	defer Setenv("UENV_HOSTS", "a, b,,c")()
+
+	assert.Equal(t, []string{"a", "b", "c"}, GetSlice("UENV_HOSTS", ","), "they should be equal")
+	assert.Equal(t, []string{"a, b,,c"}, GetSlice("UENV_HOSTS", ";"), "they should be equal")
+	assert.Equal(t, []string(nil), GetSlice("UENV_MISSING", ","), "they should be equal")
+}
+
+func TestRestore(t *testing.T) {
+	os.Setenv("UENV_KEEP", "huangjian")
+	defer os.Unsetenv("UENV_KEEP")
+
+	restore := Setenvs(map[string]string{"UENV_KEEP": "MDGSF", "UENV_NEW": "1"})
+	assert.Equal(t, "MDGSF", os.Getenv("UENV_KEEP"), "they should be equal")
+	restore()
+	assert.Equal(t, "huangjian", os.Getenv("UENV_KEEP"), "they should be equal")
+	_, ok := os.LookupEnv("UENV_NEW")
+	assert.Equal(t, false, ok, "they should be equal")
+
+	restore = Unsetenv("UENV_KEEP")
+	_, ok = os.LookupEnv("UENV_KEEP")
+	assert.Equal(t, false, ok, "they should be equal")
+	restore()
+	assert.Equal(t, "huangjian", os.Getenv("UENV_KEEP"), "they should be equal")
+}
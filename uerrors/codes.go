@@ -0,0 +1,264 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrorCode classifies errors. The values are the ones of gRPC status
+// codes, google.golang.org/grpc/codes.
+type ErrorCode int
+
+const (
+	CodeOK                 ErrorCode = 0
+	CodeCanceled           ErrorCode = 1
+	CodeUnknown            ErrorCode = 2
+	CodeInvalidArgument    ErrorCode = 3
+	CodeDeadlineExceeded   ErrorCode = 4
+	CodeNotFound           ErrorCode = 5
+	CodeAlreadyExists      ErrorCode = 6
+	CodePermissionDenied   ErrorCode = 7
+	CodeResourceExhausted  ErrorCode = 8
+	CodeFailedPrecondition ErrorCode = 9
+	CodeAborted            ErrorCode = 10
+	CodeOutOfRange         ErrorCode = 11
+	CodeUnimplemented      ErrorCode = 12
+	CodeInternal           ErrorCode = 13
+	CodeUnavailable        ErrorCode = 14
+	CodeDataLoss           ErrorCode = 15
+	CodeUnauthenticated    ErrorCode = 16
+)
+
+var codeNames = [...]string{
+	CodeOK:                 "OK",
+	CodeCanceled:           "Canceled",
+	CodeUnknown:            "Unknown",
+	CodeInvalidArgument:    "InvalidArgument",
+	CodeDeadlineExceeded:   "DeadlineExceeded",
+	CodeNotFound:           "NotFound",
+	CodeAlreadyExists:      "AlreadyExists",
+	CodePermissionDenied:   "PermissionDenied",
+	CodeResourceExhausted:  "ResourceExhausted",
+	CodeFailedPrecondition: "FailedPrecondition",
+	CodeAborted:            "Aborted",
+	CodeOutOfRange:         "OutOfRange",
+	CodeUnimplemented:      "Unimplemented",
+	CodeInternal:           "Internal",
+	CodeUnavailable:        "Unavailable",
+	CodeDataLoss:           "DataLoss",
+	CodeUnauthenticated:    "Unauthenticated",
+}
+
+func (c ErrorCode) String() string {
+	if c >= 0 && int(c) < len(codeNames) {
+		return codeNames[c]
+	}
+	return fmt.Sprintf("ErrorCode(%d)", int(c))
+}
+
+var httpStatuses = [...]int{
+	CodeOK:                 http.StatusOK,
+	CodeCanceled:           499, // client closed request, nginx
+	CodeUnknown:            http.StatusInternalServerError,
+	CodeInvalidArgument:    http.StatusBadRequest,
+	CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	CodeNotFound:           http.StatusNotFound,
+	CodeAlreadyExists:      http.StatusConflict,
+	CodePermissionDenied:   http.StatusForbidden,
+	CodeResourceExhausted:  http.StatusTooManyRequests,
+	CodeFailedPrecondition: http.StatusBadRequest,
+	CodeAborted:            http.StatusConflict,
+	CodeOutOfRange:         http.StatusBadRequest,
+	CodeUnimplemented:      http.StatusNotImplemented,
+	CodeInternal:           http.StatusInternalServerError,
+	CodeUnavailable:        http.StatusServiceUnavailable,
+	CodeDataLoss:           http.StatusInternalServerError,
+	CodeUnauthenticated:    http.StatusUnauthorized,
+}
+
+// HTTPStatus returns the HTTP status of c, following the mapping of
+// grpc-gateway. Unknown codes give 500.
+func (c ErrorCode) HTTPStatus() int {
+	if c >= 0 && int(c) < len(httpStatuses) {
+		return httpStatuses[c]
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns c as a gRPC status code, convert it with codes.Code(c).
+func (c ErrorCode) GRPCCode() uint32 {
+	if c >= 0 && int(c) < len(codeNames) {
+		return uint32(c)
+	}
+	return uint32(CodeUnknown)
+}
+
+// CodeFromHTTPStatus returns the code of an HTTP status, for errors
+// returned by other services.
+func CodeFromHTTPStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodePermissionDenied
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeAlreadyExists
+	case http.StatusPreconditionFailed:
+		return CodeFailedPrecondition
+	case http.StatusTooManyRequests:
+		return CodeResourceExhausted
+	case 499:
+		return CodeCanceled
+	case http.StatusNotImplemented:
+		return CodeUnimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout, http.StatusRequestTimeout:
+		return CodeDeadlineExceeded
+	}
+	switch {
+	case status >= 200 && status < 300:
+		return CodeOK
+	case status >= 400 && status < 500:
+		return CodeFailedPrecondition
+	}
+	return CodeUnknown
+}
+
+// CodeFromGRPCCode returns the code of a gRPC status code.
+func CodeFromGRPCCode(code uint32) ErrorCode {
+	if int(code) < len(codeNames) {
+		return ErrorCode(code)
+	}
+	return CodeUnknown
+}
+
+/*
+CodeOf returns the code of err: CodeOK for nil, the code set with WithCode
+or one of the constructors, CodeCanceled and CodeDeadlineExceeded for the
+context errors, CodeUnknown otherwise.
+
+	func writeError(w http.ResponseWriter, err error) {
+		http.Error(w, err.Error(), uerrors.CodeOf(err).HTTPStatus())
+	}
+*/
+func CodeOf(err error) ErrorCode {
+	if err == nil {
+		return CodeOK
+	}
+	if code, ok := Code(err); ok {
+		return ErrorCode(code)
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeDeadlineExceeded
+	}
+	return CodeUnknown
+}
+
+// IsCode reports whether the code of err is code.
+func IsCode(err error, code ErrorCode) bool {
+	return CodeOf(err) == code
+}
+
+// HTTPStatus returns the HTTP status of err, see CodeOf.
+func HTTPStatus(err error) int {
+	return CodeOf(err).HTTPStatus()
+}
+
+// GRPCCode returns the gRPC status code of err, see CodeOf.
+func GRPCCode(err error) uint32 {
+	return CodeOf(err).GRPCCode()
+}
+
+func newCode(code ErrorCode, format string, args []interface{}) error {
+	return &codeError{
+		err:  &stackError{err: fmt.Errorf(format, args...), stack: callers(1)},
+		code: int(code),
+	}
+}
+
+// NewCode returns an error with code, formatted like fmt.Errorf.
+func NewCode(code ErrorCode, format string, args ...interface{}) error {
+	return newCode(code, format, args)
+}
+
+// InvalidArgument returns an error with CodeInvalidArgument.
+func InvalidArgument(format string, args ...interface{}) error {
+	return newCode(CodeInvalidArgument, format, args)
+}
+
+// NotFound returns an error with CodeNotFound.
+func NotFound(format string, args ...interface{}) error {
+	return newCode(CodeNotFound, format, args)
+}
+
+// AlreadyExists returns an error with CodeAlreadyExists.
+func AlreadyExists(format string, args ...interface{}) error {
+	return newCode(CodeAlreadyExists, format, args)
+}
+
+// PermissionDenied returns an error with CodePermissionDenied.
+func PermissionDenied(format string, args ...interface{}) error {
+	return newCode(CodePermissionDenied, format, args)
+}
+
+// Unauthenticated returns an error with CodeUnauthenticated.
+func Unauthenticated(format string, args ...interface{}) error {
+	return newCode(CodeUnauthenticated, format, args)
+}
+
+// ResourceExhausted returns an error with CodeResourceExhausted.
+func ResourceExhausted(format string, args ...interface{}) error {
+	return newCode(CodeResourceExhausted, format, args)
+}
+
+// FailedPrecondition returns an error with CodeFailedPrecondition.
+func FailedPrecondition(format string, args ...interface{}) error {
+	return newCode(CodeFailedPrecondition, format, args)
+}
+
+// Unimplemented returns an error with CodeUnimplemented.
+func Unimplemented(format string, args ...interface{}) error {
+	return newCode(CodeUnimplemented, format, args)
+}
+
+// Unavailable returns an error with CodeUnavailable.
+func Unavailable(format string, args ...interface{}) error {
+	return newCode(CodeUnavailable, format, args)
+}
+
+// Internal returns an error with CodeInternal.
+func Internal(format string, args ...interface{}) error {
+	return newCode(CodeInternal, format, args)
+}
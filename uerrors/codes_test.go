@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "NotFound", CodeNotFound.String(), "they should be equal")
+	assert.Equal(t, "ErrorCode(99)", ErrorCode(99).String(), "they should be equal")
+	assert.Equal(t, http.StatusNotFound, CodeNotFound.HTTPStatus(), "they should be equal")
+	assert.Equal(t, http.StatusServiceUnavailable, CodeUnavailable.HTTPStatus(), "they should be equal")
+	assert.Equal(t, http.StatusInternalServerError, ErrorCode(99).HTTPStatus(), "they should be equal")
+	assert.Equal(t, uint32(5), CodeNotFound.GRPCCode(), "they should be equal")
+	assert.Equal(t, uint32(2), ErrorCode(-1).GRPCCode(), "they should be equal")
+
+	assert.Equal(t, CodeNotFound, CodeFromHTTPStatus(404), "they should be equal")
+	assert.Equal(t, CodeOK, CodeFromHTTPStatus(204), "they should be equal")
+	assert.Equal(t, CodeFailedPrecondition, CodeFromHTTPStatus(418), "they should be equal")
+	assert.Equal(t, CodeUnknown, CodeFromHTTPStatus(500), "they should be equal")
+	assert.Equal(t, CodeUnauthenticated, CodeFromGRPCCode(16), "they should be equal")
+	assert.Equal(t, CodeUnknown, CodeFromGRPCCode(17), "they should be equal")
+}
+
+func TestCodeOf(t *testing.T) {
+	assert.Equal(t, CodeOK, CodeOf(nil), "they should be equal")
+	assert.Equal(t, CodeUnknown, CodeOf(io.EOF), "they should be equal")
+	assert.Equal(t, CodeCanceled, CodeOf(fmt.Errorf("query: %w", context.Canceled)), "they should be equal")
+	assert.Equal(t, CodeDeadlineExceeded, CodeOf(context.DeadlineExceeded), "they should be equal")
+	assert.Equal(t, CodeNotFound, CodeOf(WithCode(io.EOF, int(CodeNotFound))), "they should be equal")
+
+	err := Wrap(NotFound("user %s: %w", "huangjian", os.ErrNotExist), "get user")
+	assert.Equal(t, "get user: user huangjian: file does not exist", err.Error(), "they should be equal")
+	assert.Equal(t, CodeNotFound, CodeOf(err), "they should be equal")
+	assert.Equal(t, true, IsCode(err, CodeNotFound), "they should be equal")
+	assert.Equal(t, true, Is(err, os.ErrNotExist), "they should be equal")
+	assert.Equal(t, http.StatusNotFound, HTTPStatus(err), "they should be equal")
+	assert.Equal(t, uint32(5), GRPCCode(err), "they should be equal")
+
+	// The stack starts at the caller of the constructor.
+	assert.Equal(t, true, strings.HasSuffix(StackOf(err)[0].Func(), "uerrors.TestCodeOf"), "they should be equal")
+}
+
+func TestConstructors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code ErrorCode
+	}{
+		{NewCode(CodeAborted, "x"), CodeAborted},
+		{InvalidArgument("x"), CodeInvalidArgument},
+		{NotFound("x"), CodeNotFound},
+		{AlreadyExists("x"), CodeAlreadyExists},
+		{PermissionDenied("x"), CodePermissionDenied},
+		{Unauthenticated("x"), CodeUnauthenticated},
+		{ResourceExhausted("x"), CodeResourceExhausted},
+		{FailedPrecondition("x"), CodeFailedPrecondition},
+		{Unimplemented("x"), CodeUnimplemented},
+		{Unavailable("x"), CodeUnavailable},
+		{Internal("x"), CodeInternal},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.code, CodeOf(test.err), "they should be equal")
+		assert.Equal(t, "x", test.err.Error(), "they should be equal")
+	}
+}
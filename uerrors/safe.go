@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MDGSF/utils/log"
+)
+
+// PanicError is a recovered panic. It is the panic error of Safe and SafeGo,
+// and of the uasync, upool, usingle and ucache packages.
+type PanicError struct {
+	// Value is the value given to panic.
+	Value interface{}
+	stack StackTrace
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns Value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// StackTrace returns the stack of the panic.
+func (e *PanicError) StackTrace() StackTrace {
+	return e.stack
+}
+
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// NewPanicError returns the *PanicError of r, with the stack trace of the
+// panic. It must be called by the deferred function which recovered r.
+func NewPanicError(r interface{}) *PanicError {
+	st := callers(0)
+	// Start at the function which panicked: skip the deferred function,
+	// then the runtime frames of the panic.
+	i := 0
+	for i < len(st) && !strings.HasPrefix(st[i].Func(), "runtime.") {
+		i++
+	}
+	for i < len(st) && strings.HasPrefix(st[i].Func(), "runtime.") {
+		i++
+	}
+	if i < len(st) {
+		st = st[i:]
+	}
+	return &PanicError{Value: r, stack: st}
+}
+
+/*
+Safe calls fn and returns its error, or a *PanicError if fn panics. Use it
+around plugin callbacks and other code which must not crash the process.
+
+	err := uerrors.Safe(func() error {
+		return plugin.Handle(event)
+	})
+*/
+func Safe(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewPanicError(r)
+		}
+	}()
+	return fn()
+}
+
+/*
+SafeGo runs fn in a new goroutine, logging the error it returns or its
+panic with the stack trace to logger, the default logger of the log
+package if nil. The returned channel receives the error, nil included,
+when fn is done.
+
+	uerrors.SafeGo(func() error {
+		return consume(queue)
+	}, nil)
+*/
+func SafeGo(fn func() error, logger *log.Logger) <-chan error {
+	if logger == nil {
+		logger = log.DefaultLog()
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := Safe(fn)
+		if err != nil {
+			logger.Errorf("%+v", err)
+		}
+		done <- err
+		close(done)
+	}()
+	return done
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func panicky() error {
+	panic("huangjian")
+}
+
+func TestSafe(t *testing.T) {
+	assert.Equal(t, nil, Safe(func() error { return nil }), "they should be equal")
+	assert.Equal(t, io.EOF, Safe(func() error { return io.EOF }), "they should be equal")
+
+	err := Safe(panicky)
+	var perr *PanicError
+	assert.Equal(t, true, errors.As(err, &perr), "they should be equal")
+	assert.Equal(t, "huangjian", perr.Value, "they should be equal")
+	assert.Equal(t, "panic: huangjian", err.Error(), "they should be equal")
+	assert.Equal(t, true, strings.HasSuffix(perr.StackTrace()[0].Func(), "uerrors.panicky"), "they should be equal")
+	assert.Equal(t, true, strings.Contains(fmt.Sprintf("%+v", err), "safe_test.go"), "they should be equal")
+
+	err = Safe(func() error { panic(io.EOF) })
+	assert.Equal(t, true, errors.Is(err, io.EOF), "they should be equal")
+
+	err = Safe(func() error {
+		var m map[string]int
+		m["MDGSF"] = 1
+		return nil
+	})
+	assert.Equal(t, true, strings.HasPrefix(err.Error(), "panic: assignment to entry in nil map"), "they should be equal")
+}
+
+func TestSafeGo(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", "", 0, log.ErrorLevel, log.NotTerminal)
+
+	err := <-SafeGo(panicky, logger)
+	assert.Equal(t, "panic: huangjian", err.Error(), "they should be equal")
+	assert.Equal(t, true, strings.HasPrefix(buf.String(), "panic: huangjian\n"), "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "uerrors.panicky"), "they should be equal")
+
+	buf.Reset()
+	assert.Equal(t, nil, <-SafeGo(func() error { return nil }, logger), "they should be equal")
+	assert.Equal(t, "", buf.String(), "they should be equal")
+}
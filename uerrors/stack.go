@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the number of frames captured.
+const maxStackDepth = 32
+
+// Frame is a program counter of a stack trace.
+type Frame uintptr
+
+func (f Frame) frame() runtime.Frame {
+	frames := runtime.CallersFrames([]uintptr{uintptr(f)})
+	frame, _ := frames.Next()
+	return frame
+}
+
+// Func returns the full name of the function of f, like
+// "github.com/MDGSF/utils/uerrors.New".
+func (f Frame) Func() string {
+	if name := f.frame().Function; name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// File returns the full path of the file of f.
+func (f Frame) File() string {
+	if file := f.frame().File; file != "" {
+		return file
+	}
+	return "unknown"
+}
+
+// Line returns the line number of f.
+func (f Frame) Line() int {
+	return f.frame().Line
+}
+
+/*
+Format formats f:
+
+	%s    file base name
+	%d    line number
+	%n    function name without its package path
+	%v    file:line
+	%+v   function name and path of file, on two lines
+*/
+func (f Frame) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's':
+		file := f.File()
+		if i := strings.LastIndexByte(file, '/'); i >= 0 {
+			file = file[i+1:]
+		}
+		io.WriteString(s, file)
+	case 'd':
+		fmt.Fprintf(s, "%d", f.Line())
+	case 'n':
+		name := f.Func()
+		if i := strings.LastIndexByte(name, '/'); i >= 0 {
+			name = name[i+1:]
+		}
+		io.WriteString(s, name)
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%s\n\t%s:%d", f.Func(), f.File(), f.Line())
+			return
+		}
+		fmt.Fprintf(s, "%s:%d", f, f)
+	}
+}
+
+// StackTrace is a stack of frames, innermost first.
+type StackTrace []Frame
+
+// Format formats the frames of st with the verb of Frame.Format, one per
+// line for %+v.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		for _, f := range st {
+			io.WriteString(s, "\n")
+			f.Format(s, verb)
+		}
+		return
+	}
+	io.WriteString(s, "[")
+	for i, f := range st {
+		if i > 0 {
+			io.WriteString(s, " ")
+		}
+		f.Format(s, verb)
+	}
+	io.WriteString(s, "]")
+}
+
+// callers returns the stack of the caller of the function calling it,
+// skipping skip more frames.
+func callers(skip int) StackTrace {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3+skip, pcs[:])
+	st := make(StackTrace, n)
+	for i, pc := range pcs[:n] {
+		st[i] = Frame(pc)
+	}
+	return st
+}
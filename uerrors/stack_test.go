@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func here() StackTrace {
+	return callers(0)
+}
+
+func TestFrame(t *testing.T) {
+	st := func() StackTrace { return here() }()
+	f := st[0]
+	assert.Equal(t, true, strings.HasSuffix(f.Func(), "uerrors.TestFrame.func1"), "they should be equal")
+	assert.Equal(t, true, strings.HasSuffix(f.File(), "/uerrors/stack_test.go"), "they should be equal")
+	assert.Equal(t, "stack_test.go", fmt.Sprintf("%s", f), "they should be equal")
+	assert.Equal(t, "uerrors.TestFrame.func1", fmt.Sprintf("%n", f), "they should be equal")
+	assert.Equal(t, fmt.Sprintf("stack_test.go:%d", f.Line()), fmt.Sprintf("%v", f), "they should be equal")
+	assert.Equal(t, fmt.Sprintf("%s\n\t%s:%d", f.Func(), f.File(), f.Line()), fmt.Sprintf("%+v", f), "they should be equal")
+
+	assert.Equal(t, "unknown", Frame(0).Func(), "they should be equal")
+	assert.Equal(t, "unknown", Frame(0).File(), "they should be equal")
+}
+
+func TestStackTraceFormat(t *testing.T) {
+	st := here()
+	assert.Equal(t, true, strings.HasPrefix(fmt.Sprintf("%v", st), "[stack_test.go:"), "they should be equal")
+	assert.Equal(t, true, strings.HasPrefix(fmt.Sprintf("%+v", st), "\n"+st[0].Func()), "they should be equal")
+	assert.Equal(t, len(st), strings.Count(fmt.Sprintf("%+v", st), "\n\t"), "they should be equal")
+}
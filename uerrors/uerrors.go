@@ -0,0 +1,213 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// stackError is an error with the stack trace of where it was created.
+type stackError struct {
+	msg   string
+	err   error
+	stack StackTrace
+}
+
+func (e *stackError) Error() string {
+	switch {
+	case e.err == nil:
+		return e.msg
+	case e.msg == "":
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+func (e *stackError) StackTrace() StackTrace {
+	return e.stack
+}
+
+func (e *stackError) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// format prints err for %s, %v and %q, and adds the stack trace of the
+// chain for %+v.
+func format(s fmt.State, verb rune, err error) {
+	switch verb {
+	case 'v':
+		io.WriteString(s, err.Error())
+		if s.Flag('+') {
+			if st := StackOf(err); st != nil {
+				st.Format(s, verb)
+			}
+		}
+	case 's':
+		io.WriteString(s, err.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	}
+}
+
+// New returns an error with message msg and the stack trace of the caller.
+func New(msg string) error {
+	return &stackError{msg: msg, stack: callers(0)}
+}
+
+// Errorf returns an error formatted like fmt.Errorf, %w included, with the
+// stack trace of the caller.
+func Errorf(format string, args ...interface{}) error {
+	return &stackError{err: fmt.Errorf(format, args...), stack: callers(0)}
+}
+
+/*
+Wrap returns err annotated with msg, "msg: err", or nil if err is nil. The
+stack trace of the caller is captured unless err already carries one.
+
+	if err := db.Ping(); err != nil {
+		return uerrors.Wrap(err, "ping database")
+	}
+	...
+	log.Errorf("%+v", err) // message and stack trace
+*/
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e := &stackError{msg: msg, err: err}
+	if StackOf(err) == nil {
+		e.stack = callers(0)
+	}
+	return e
+}
+
+// Wrapf is Wrap with a formatted message.
+func Wrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	e := &stackError{msg: fmt.Sprintf(format, args...), err: err}
+	if StackOf(err) == nil {
+		e.stack = callers(0)
+	}
+	return e
+}
+
+// WithStack returns err with the stack trace of the caller, or nil if err
+// is nil. Errors already carrying a stack trace are returned unchanged.
+func WithStack(err error) error {
+	if err == nil || StackOf(err) != nil {
+		return err
+	}
+	return &stackError{err: err, stack: callers(0)}
+}
+
+type stackTracer interface {
+	StackTrace() StackTrace
+}
+
+// StackOf returns the innermost stack trace of the chain of err, nil if
+// there is none.
+func StackOf(err error) StackTrace {
+	var st StackTrace
+	for err != nil {
+		if e, ok := err.(stackTracer); ok && e.StackTrace() != nil {
+			st = e.StackTrace()
+		}
+		err = errors.Unwrap(err)
+	}
+	return st
+}
+
+// codeError is an error with a code.
+type codeError struct {
+	err  error
+	code int
+}
+
+func (e *codeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codeError) Unwrap() error {
+	return e.err
+}
+
+func (e *codeError) Code() int {
+	return e.code
+}
+
+func (e *codeError) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// WithCode returns err with code, or nil if err is nil. The message of
+// err is unchanged, see Code.
+func WithCode(err error, code int) error {
+	if err == nil {
+		return nil
+	}
+	return &codeError{err: err, code: code}
+}
+
+// Code returns the outermost code of the chain of err, set with WithCode
+// or by any error with a Code() int method.
+func Code(err error) (int, bool) {
+	var c interface{ Code() int }
+	if errors.As(err, &c) {
+		return c.Code(), true
+	}
+	return 0, false
+}
+
+// Cause returns the innermost error of the chain of err.
+func Cause(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// Is is errors.Is, for convenience.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As is errors.As, for convenience.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+// Unwrap is errors.Unwrap, for convenience.
+func Unwrap(err error) error {
+	return errors.Unwrap(err)
+}
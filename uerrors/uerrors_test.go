@@ -0,0 +1,95 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uerrors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	err := New("huangjian")
+	assert.Equal(t, "huangjian", err.Error(), "they should be equal")
+	assert.Equal(t, "huangjian", fmt.Sprintf("%v", err), "they should be equal")
+	assert.Equal(t, `"huangjian"`, fmt.Sprintf("%q", err), "they should be equal")
+
+	verbose := fmt.Sprintf("%+v", err)
+	assert.Equal(t, true, strings.HasPrefix(verbose, "huangjian\ngithub.com/MDGSF/utils/uerrors.TestNew\n\t"), "they should be equal")
+	assert.Equal(t, true, strings.Contains(verbose, "uerrors_test.go:"), "they should be equal")
+}
+
+func TestErrorf(t *testing.T) {
+	err := Errorf("open %s: %w", "MDGSF", os.ErrNotExist)
+	assert.Equal(t, "open MDGSF: file does not exist", err.Error(), "they should be equal")
+	assert.Equal(t, true, Is(err, os.ErrNotExist), "they should be equal")
+	assert.NotEqual(t, StackTrace(nil), StackOf(err), "they should not be equal")
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, nil, Wrap(nil, "x"), "they should be equal")
+	assert.Equal(t, nil, Wrapf(nil, "x %d", 1), "they should be equal")
+	assert.Equal(t, nil, WithStack(nil), "they should be equal")
+
+	err := Wrap(io.EOF, "read config")
+	assert.Equal(t, "read config: EOF", err.Error(), "they should be equal")
+	assert.Equal(t, true, errors.Is(err, io.EOF), "they should be equal")
+	assert.Equal(t, io.EOF, Cause(err), "they should be equal")
+	assert.Equal(t, io.EOF, Unwrap(err), "they should be equal")
+
+	// The stack of the innermost error is kept.
+	inner := New("inner")
+	outer := Wrapf(inner, "outer %d", 1)
+	assert.Equal(t, "outer 1: inner", outer.Error(), "they should be equal")
+	assert.Equal(t, StackOf(inner), StackOf(outer), "they should be equal")
+	assert.Equal(t, StackTrace(nil), outer.(*stackError).stack, "they should be equal")
+	assert.Equal(t, inner, WithStack(inner), "they should be equal")
+
+	ws := WithStack(io.EOF)
+	assert.Equal(t, "EOF", ws.Error(), "they should be equal")
+	assert.NotEqual(t, StackTrace(nil), StackOf(ws), "they should not be equal")
+
+	var se *stackError
+	assert.Equal(t, true, As(fmt.Errorf("wrapped: %w", outer), &se), "they should be equal")
+	assert.Equal(t, StackTrace(nil), StackOf(io.EOF), "they should be equal")
+}
+
+func TestWithCode(t *testing.T) {
+	assert.Equal(t, nil, WithCode(nil, 404), "they should be equal")
+
+	err := WithCode(Wrap(io.EOF, "read"), 404)
+	assert.Equal(t, "read: EOF", err.Error(), "they should be equal")
+	code, ok := Code(fmt.Errorf("handler: %w", err))
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 404, code, "they should be equal")
+	assert.Equal(t, true, errors.Is(err, io.EOF), "they should be equal")
+	assert.Equal(t, true, strings.Contains(fmt.Sprintf("%+v", err), "uerrors.TestWithCode"), "they should be equal")
+
+	_, ok = Code(io.EOF)
+	assert.Equal(t, false, ok, "they should be equal")
+}
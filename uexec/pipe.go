@@ -0,0 +1,203 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrEmptyPipeline is returned when running a pipeline without commands.
+var ErrEmptyPipeline = errors.New("uexec: empty pipeline")
+
+// StageError is the error of one command of a pipeline.
+type StageError struct {
+	// Stage is the index of the command, from 0.
+	Stage int
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %d: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// PipeError lists the failed commands of a pipeline.
+type PipeError struct {
+	Stages []*StageError
+}
+
+func (e *PipeError) Error() string {
+	msgs := make([]string, len(e.Stages))
+	for i, s := range e.Stages {
+		msgs[i] = s.Error()
+	}
+	return "uexec: pipeline failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the error of the first failed command.
+func (e *PipeError) Unwrap() error {
+	return e.Stages[0]
+}
+
+// PipeResult is the outcome of a pipeline.
+type PipeResult struct {
+	// Stdout is the output of the last command.
+	Stdout []byte
+	// Stages holds the error output and exit code of every command.
+	Stages   []*Result
+	Duration time.Duration
+}
+
+// Pipeline is a sequence of commands, the output of each one being the
+// input of the next, like a shell pipeline.
+type Pipeline struct {
+	cmds []*Cmd
+}
+
+/*
+Pipe returns the pipeline of cmds. The processes are connected directly,
+no shell is involved, so arguments need no quoting and are never
+interpreted.
+
+	// ps aux | grep -F "$name" | wc -l
+	res, err := uexec.Pipe(
+		uexec.Command("ps", "aux"),
+		uexec.Command("grep", "-F", name),
+		uexec.Command("wc", "-l"),
+	).Run(ctx)
+*/
+func Pipe(cmds ...*Cmd) *Pipeline {
+	return &Pipeline{cmds: cmds}
+}
+
+// String returns the pipeline as a shell command line.
+func (p *Pipeline) String() string {
+	parts := make([]string, len(p.cmds))
+	for i, c := range p.cmds {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " | ")
+}
+
+/*
+Run runs the commands of p concurrently and waits for all of them. Like
+"set -o pipefail", the pipeline fails when any command fails, the
+*PipeError tells which ones. A command killed because the next one exited
+without reading all its input, like "yes | head -1", is not a failure.
+The stdin of the first command is the input of the pipeline, the stdin
+of the others is ignored.
+*/
+func (p *Pipeline) Run(ctx context.Context) (*PipeResult, error) {
+	n := len(p.cmds)
+	if n == 0 {
+		return nil, ErrEmptyPipeline
+	}
+
+	cmds := make([]*exec.Cmd, n)
+	ctxs := make([]context.Context, n)
+	stderrs := make([]bytes.Buffer, n)
+	var stdout bytes.Buffer
+	for i, c := range p.cmds {
+		var cancel context.CancelFunc
+		cmds[i], ctxs[i], cancel = c.build(ctx)
+		defer cancel()
+		cmds[i].Stderr = &stderrs[i]
+		if c.logger != nil {
+			c := c
+			errLog := &lineWriter{fn: func(line string) { c.logger.Warnf("%s: %s", c.name, line) }}
+			defer errLog.Flush()
+			cmds[i].Stderr = io.MultiWriter(&stderrs[i], errLog)
+		}
+	}
+	cmds[n-1].Stdout = &stdout
+
+	// The parent closes its copies of the pipes once the children have
+	// them, so readers see EOF when writers exit.
+	var parentEnds []*os.File
+	closeEnds := func() {
+		for _, f := range parentEnds {
+			f.Close()
+		}
+		parentEnds = nil
+	}
+	defer closeEnds()
+	for i := 0; i < n-1; i++ {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return nil, err
+		}
+		cmds[i].Stdout = w
+		cmds[i+1].Stdin = r
+		parentEnds = append(parentEnds, r, w)
+	}
+
+	start := time.Now()
+	for i, cmd := range cmds {
+		if err := cmd.Start(); err != nil {
+			for _, started := range cmds[:i] {
+				started.Process.Kill()
+				started.Wait()
+			}
+			return nil, &PipeError{Stages: []*StageError{{Stage: i, Err: p.cmds[i].runErr(ctx, ctxs[i], err, nil)}}}
+		}
+	}
+	closeEnds()
+
+	res := &PipeResult{Stages: make([]*Result, n)}
+	var failed []*StageError
+	for i, cmd := range cmds {
+		err := cmd.Wait()
+		stage := &Result{Stderr: stderrs[i].Bytes()}
+		if cmd.ProcessState != nil {
+			stage.ExitCode = cmd.ProcessState.ExitCode()
+			if i < n-1 && cmd.ProcessState.String() == "signal: broken pipe" {
+				err = nil
+			}
+		}
+		res.Stages[i] = stage
+		if err = p.cmds[i].runErr(ctx, ctxs[i], err, stage.Stderr); err != nil {
+			failed = append(failed, &StageError{Stage: i, Err: err})
+		}
+	}
+	res.Duration = time.Since(start)
+	res.Stdout = stdout.Bytes()
+	res.Stages[n-1].Stdout = res.Stdout
+	for _, stage := range res.Stages {
+		stage.Duration = res.Duration
+	}
+	if len(failed) > 0 {
+		return res, &PipeError{Stages: failed}
+	}
+	return res, nil
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uexec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipe(t *testing.T) {
+	res, err := Pipe(
+		Command("printf", "huangjian\nMDGSF\nhuangjian; rm -rf /\n").Stdin(strings.NewReader("ignored")),
+		Command("grep", "-F", "huangjian; rm"),
+		Command("wc", "-l"),
+	).Run(context.Background())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "1", strings.TrimSpace(string(res.Stdout)), "they should be equal")
+	assert.Equal(t, 3, len(res.Stages), "they should be equal")
+
+	res, err = Pipe(Command("tr", "a-z", "A-Z").Stdin(strings.NewReader("mdgsf"))).Run(context.Background())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "MDGSF", string(res.Stdout), "they should be equal")
+
+	_, err = Pipe().Run(context.Background())
+	assert.Equal(t, ErrEmptyPipeline, err, "they should be equal")
+}
+
+func TestPipeStageError(t *testing.T) {
+	res, err := Pipe(
+		Command("sh", "-c", "echo huangjian; echo oops >&2; exit 2"),
+		Command("cat"),
+		Command("grep", "MDGSF"),
+	).Run(context.Background())
+
+	var pipeErr *PipeError
+	assert.Equal(t, true, errors.As(err, &pipeErr), "they should be equal")
+	assert.Equal(t, 2, len(pipeErr.Stages), "they should be equal")
+	assert.Equal(t, 0, pipeErr.Stages[0].Stage, "they should be equal")
+	assert.Equal(t, 2, pipeErr.Stages[1].Stage, "they should be equal")
+	assert.Equal(t, "oops\n", string(res.Stages[0].Stderr), "they should be equal")
+	assert.Equal(t, 2, res.Stages[0].ExitCode, "they should be equal")
+	assert.Equal(t, 1, res.Stages[2].ExitCode, "they should be equal")
+
+	var exitErr *ExitError
+	assert.Equal(t, true, errors.As(err, &exitErr), "they should be equal")
+	assert.Equal(t, 2, exitErr.ExitCode, "they should be equal")
+	assert.Equal(t, true, strings.HasPrefix(err.Error(), "uexec: pipeline failed: stage 0: uexec: sh -c"), "they should be equal")
+
+	_, err = Pipe(Command("echo"), Command("uexec-no-such-program")).Run(context.Background())
+	assert.Equal(t, true, errors.As(err, &pipeErr), "they should be equal")
+	assert.Equal(t, 1, pipeErr.Stages[0].Stage, "they should be equal")
+}
+
+func TestPipeBrokenPipe(t *testing.T) {
+	res, err := Pipe(Command("yes"), Command("head", "-n", "1")).Run(context.Background())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "y\n", string(res.Stdout), "they should be equal")
+}
+
+func TestPipeString(t *testing.T) {
+	p := Pipe(Command("ps", "aux"), Command("grep", "-F", "my app"))
+	assert.Equal(t, `ps aux | grep -F "my app"`, p.String(), "they should be equal")
+}
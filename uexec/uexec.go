@@ -0,0 +1,293 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+)
+
+// ErrTimeout is returned when a command runs longer than its timeout.
+var ErrTimeout = errors.New("uexec: timeout")
+
+// ExitError is returned when a command exits with a non zero code.
+type ExitError struct {
+	// Cmd is the command line.
+	Cmd      string
+	ExitCode int
+	// Stderr is the trimmed end of the error output.
+	Stderr string
+	Err    error
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("uexec: %s: exit code %d", e.Cmd, e.ExitCode)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// maxStderrInError bounds the error output kept in ExitError.
+const maxStderrInError = 1024
+
+// Result is the outcome of a command.
+type Result struct {
+	// Stdout holds stdout and stderr interleaved for Combined commands.
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+	Duration time.Duration
+}
+
+// Cmd is a command to run, configured by its methods.
+type Cmd struct {
+	name     string
+	args     []string
+	dir      string
+	env      []string
+	stdin    io.Reader
+	timeout  time.Duration
+	combined bool
+	logger   *log.Logger
+}
+
+// Command returns the command running the program name with args,
+// without a shell: args are passed as they are, no quoting is needed.
+func Command(name string, args ...string) *Cmd {
+	return &Cmd{name: name, args: args}
+}
+
+// Dir sets the working directory, the current one by default.
+func (c *Cmd) Dir(dir string) *Cmd {
+	c.dir = dir
+	return c
+}
+
+// Env adds "KEY=value" pairs to the environment inherited from the
+// current process.
+func (c *Cmd) Env(kv ...string) *Cmd {
+	c.env = append(c.env, kv...)
+	return c
+}
+
+// Stdin sets the standard input, empty by default.
+func (c *Cmd) Stdin(r io.Reader) *Cmd {
+	c.stdin = r
+	return c
+}
+
+// Timeout kills the command after d, Run then returns ErrTimeout.
+func (c *Cmd) Timeout(d time.Duration) *Cmd {
+	c.timeout = d
+	return c
+}
+
+// Combined captures stdout and stderr interleaved in Result.Stdout, like
+// a terminal shows them.
+func (c *Cmd) Combined() *Cmd {
+	c.combined = true
+	return c
+}
+
+// Log writes every output line to logger as it comes, stdout at info
+// level and stderr at warn level, for long running commands.
+func (c *Cmd) Log(logger *log.Logger) *Cmd {
+	c.logger = logger
+	return c
+}
+
+// String returns the command line, arguments quoted when needed.
+func (c *Cmd) String() string {
+	parts := make([]string, 0, len(c.args)+1)
+	for _, s := range append([]string{c.name}, c.args...) {
+		if s == "" || strings.ContainsAny(s, " \t\n\"'\\$`|&;<>()*?[]#~") {
+			s = strconv.Quote(s)
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " ")
+}
+
+// build returns the exec.Cmd of c, its context must be cancelled when
+// done.
+func (c *Cmd) build(ctx context.Context) (*exec.Cmd, context.Context, context.CancelFunc) {
+	cancel := func() {}
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+	cmd := exec.CommandContext(ctx, c.name, c.args...)
+	cmd.Dir = c.dir
+	if len(c.env) > 0 {
+		cmd.Env = append(os.Environ(), c.env...)
+	}
+	cmd.Stdin = c.stdin
+	return cmd, ctx, cancel
+}
+
+// runErr translates the error of a finished command.
+func (c *Cmd) runErr(parent, ctx context.Context, err error, stderr []byte) error {
+	if err == nil {
+		return nil
+	}
+	if parent.Err() != nil {
+		return fmt.Errorf("uexec: %s: %w", c, parent.Err())
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w after %v: %s", ErrTimeout, c.timeout, c)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &ExitError{
+			Cmd:      c.String(),
+			ExitCode: exitErr.ExitCode(),
+			Stderr:   tail(stderr, maxStderrInError),
+			Err:      err,
+		}
+	}
+	return fmt.Errorf("uexec: %s: %w", c, err)
+}
+
+/*
+Run runs c and waits for it. A non zero exit gives an *ExitError along
+with the Result.
+
+	res, err := uexec.Command("git", "status", "--short").Dir(repo).Timeout(time.Minute).Run(ctx)
+	var exitErr *uexec.ExitError
+	if errors.As(err, &exitErr) {
+		... exitErr.ExitCode, exitErr.Stderr ...
+	}
+*/
+func (c *Cmd) Run(ctx context.Context) (*Result, error) {
+	cmd, runCtx, cancel := c.build(ctx)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	var outW, errW io.Writer = &stdout, &stderr
+	if c.combined {
+		combined := &lockedWriter{w: &stdout}
+		outW, errW = combined, combined
+	}
+	if c.logger != nil {
+		outLog := &lineWriter{fn: func(line string) { c.logger.Infof("%s: %s", c.name, line) }}
+		errLog := &lineWriter{fn: func(line string) { c.logger.Warnf("%s: %s", c.name, line) }}
+		defer outLog.Flush()
+		defer errLog.Flush()
+		outW, errW = io.MultiWriter(outW, outLog), io.MultiWriter(errW, errLog)
+	}
+	cmd.Stdout, cmd.Stderr = outW, errW
+
+	start := time.Now()
+	err := cmd.Run()
+	res := &Result{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		Duration: time.Since(start),
+	}
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	errOutput := res.Stderr
+	if c.combined {
+		errOutput = res.Stdout
+	}
+	return res, c.runErr(ctx, runCtx, err, errOutput)
+}
+
+// Run runs the program name with args, see Cmd.Run.
+func Run(ctx context.Context, name string, args ...string) (*Result, error) {
+	return Command(name, args...).Run(ctx)
+}
+
+// Output runs the program name with args and returns its stdout with
+// surrounding white space trimmed.
+func Output(ctx context.Context, name string, args ...string) (string, error) {
+	res, err := Run(ctx, name, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(res.Stdout)), nil
+}
+
+// tail returns the last n bytes of data, trimmed.
+func tail(data []byte, n int) string {
+	data = bytes.TrimSpace(data)
+	if len(data) > n {
+		data = data[len(data)-n:]
+	}
+	return string(data)
+}
+
+// lockedWriter serializes the writes of stdout and stderr.
+type lockedWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return l.w.Write(p)
+}
+
+// lineWriter calls fn for every complete line written.
+type lineWriter struct {
+	buf []byte
+	fn  func(line string)
+}
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		l.fn(strings.TrimRight(string(l.buf[:i]), "\r"))
+		l.buf = l.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush calls fn for the last line if it has no line feed.
+func (l *lineWriter) Flush() {
+	if len(l.buf) > 0 {
+		l.fn(string(l.buf))
+		l.buf = nil
+	}
+}
@@ -0,0 +1,109 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	res, err := Run(context.Background(), "sh", "-c", "echo huangjian; echo MDGSF >&2")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian\n", string(res.Stdout), "they should be equal")
+	assert.Equal(t, "MDGSF\n", string(res.Stderr), "they should be equal")
+	assert.Equal(t, 0, res.ExitCode, "they should be equal")
+
+	out, err := Output(context.Background(), "echo", "  huangjian  ")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", out, "they should be equal")
+}
+
+func TestRunExitError(t *testing.T) {
+	res, err := Run(context.Background(), "sh", "-c", "echo bad input >&2; exit 3")
+	var exitErr *ExitError
+	assert.Equal(t, true, errors.As(err, &exitErr), "they should be equal")
+	assert.Equal(t, 3, exitErr.ExitCode, "they should be equal")
+	assert.Equal(t, 3, res.ExitCode, "they should be equal")
+	assert.Equal(t, `uexec: sh -c "echo bad input >&2; exit 3": exit code 3: bad input`, err.Error(), "they should be equal")
+
+	_, err = Run(context.Background(), "uexec-no-such-program")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	assert.Equal(t, false, errors.As(err, &exitErr), "they should be equal")
+}
+
+func TestCmdOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uexec")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+
+	res, err := Command("sh", "-c", `pwd; echo "$UEXEC_NAME"; cat`).
+		Dir(dir).
+		Env("UEXEC_NAME=huangjian").
+		Stdin(strings.NewReader("MDGSF")).
+		Run(context.Background())
+	assert.Equal(t, nil, err, "they should be equal")
+	lines := strings.Split(string(res.Stdout), "\n")
+	assert.Equal(t, true, strings.HasSuffix(lines[0], strings.TrimPrefix(dir, "/private")), "they should be equal")
+	assert.Equal(t, "huangjian", lines[1], "they should be equal")
+	assert.Equal(t, "MDGSF", lines[2], "they should be equal")
+
+	res, err = Command("sh", "-c", "echo a; echo b >&2; echo c").Combined().Run(context.Background())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "a\nb\nc\n", string(res.Stdout), "they should be equal")
+	assert.Equal(t, 0, len(res.Stderr), "they should be equal")
+}
+
+func TestCmdTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := Command("sleep", "5").Timeout(50 * time.Millisecond).Run(context.Background())
+	assert.Equal(t, true, errors.Is(err, ErrTimeout), "they should be equal")
+	assert.Equal(t, true, time.Since(start) < 2*time.Second, "they should be equal")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Run(ctx, "sleep", "5")
+	assert.Equal(t, true, errors.Is(err, context.Canceled), "they should be equal")
+}
+
+func TestCmdLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", "", 0, log.InfoLevel, log.NotTerminal)
+	_, err := Command("sh", "-c", "echo huangjian; printf MDGSF >&2").Log(logger).Run(context.Background())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "sh: huangjian\n"), "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "sh: MDGSF\n"), "they should be equal")
+}
+
+func TestCmdString(t *testing.T) {
+	assert.Equal(t, `ls -l "my file" ""`, Command("ls", "-l", "my file", "").String(), "they should be equal")
+}
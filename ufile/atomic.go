@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// BackupSuffix is appended to the path of the backup kept by WithBackup.
+const BackupSuffix = ".bak"
+
+type atomicOptions struct {
+	backup bool
+}
+
+// AtomicOption configures WriteFileAtomic.
+type AtomicOption func(*atomicOptions)
+
+// WithBackup keeps the previous content of the file in path + BackupSuffix.
+func WithBackup() AtomicOption {
+	return func(o *atomicOptions) {
+		o.backup = true
+	}
+}
+
+/*
+WriteFileAtomic writes data to path so that readers, and the file after a
+crash, see either the old or the new content but never a partial write. It
+writes a temp file in the same directory, fsyncs it, renames it over path
+and fsyncs the directory.
+*/
+func WriteFileAtomic(path string, data []byte, perm os.FileMode, opts ...AtomicOption) (err error) {
+	var o atomicOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := ioutil.TempFile(dir, "."+base+".tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpName)
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if o.backup && IsFile(path) {
+		if err = CopyFile(path, path+BackupSuffix); err != nil {
+			return err
+		}
+	}
+	if err = os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir makes a rename durable, it is a no-op where directories can not
+// be synced.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+
+	assert.Equal(t, nil, WriteFileAtomic(path, []byte("huangjian"), 0600), "they should be equal")
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "huangjian", string(data), "they should be equal")
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "they should be equal")
+	}
+	assert.Equal(t, false, Exists(path+BackupSuffix), "they should be equal")
+
+	assert.Equal(t, nil, WriteFileAtomic(path, []byte("MDGSF"), 0600, WithBackup()), "they should be equal")
+	data, _ = ioutil.ReadFile(path)
+	assert.Equal(t, "MDGSF", string(data), "they should be equal")
+	data, _ = ioutil.ReadFile(path + BackupSuffix)
+	assert.Equal(t, "huangjian", string(data), "they should be equal")
+
+	// no temp files are left behind
+	entries, _ := ioutil.ReadDir(dir)
+	assert.Equal(t, 2, len(entries), "they should be equal")
+
+	err := WriteFileAtomic(filepath.Join(dir, "none", "file"), []byte("x"), 0644)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
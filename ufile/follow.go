@@ -0,0 +1,207 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often Follower checks for new data.
+const DefaultPollInterval = 250 * time.Millisecond
+
+type followOptions struct {
+	pollInterval time.Duration
+	fromStart    bool
+}
+
+// FollowOption configures Follow.
+type FollowOption func(*followOptions)
+
+// WithPollInterval set how often the file is checked, default
+// DefaultPollInterval.
+func WithPollInterval(d time.Duration) FollowOption {
+	return func(o *followOptions) {
+		o.pollInterval = d
+	}
+}
+
+// FromStart emits the existing content of the file too, by default only
+// lines appended after Follow was called are emitted.
+func FromStart() FollowOption {
+	return func(o *followOptions) {
+		o.fromStart = true
+	}
+}
+
+/*
+Follower streams the lines appended to a file, like tail -F. It keeps
+following the path when the file is truncated, or rotated (renamed or
+removed and recreated), and waits for the file if it does not exist yet.
+A partial last line is held back until its newline is written.
+*/
+type Follower struct {
+	path    string
+	options followOptions
+	lines   chan string
+	err     error
+
+	file   *os.File
+	info   os.FileInfo
+	reader *bufio.Reader
+	offset int64
+	buffer strings.Builder
+}
+
+// Follow starts following path until ctx is done.
+func Follow(ctx context.Context, path string, opts ...FollowOption) *Follower {
+	f := &Follower{
+		path:    path,
+		options: followOptions{pollInterval: DefaultPollInterval},
+		lines:   make(chan string),
+	}
+	for _, opt := range opts {
+		opt(&f.options)
+	}
+	// open synchronously so that lines written after Follow returns are seen
+	f.open(!f.options.fromStart)
+	go f.run(ctx)
+	return f
+}
+
+// Lines returns the channel of lines, it is closed when following stops.
+func (f *Follower) Lines() <-chan string {
+	return f.lines
+}
+
+// Err returns the error which stopped the follower, valid once Lines is
+// closed. It is the context error after a cancellation.
+func (f *Follower) Err() error {
+	return f.err
+}
+
+func (f *Follower) open(seekEnd bool) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return
+	}
+	f.offset = 0
+	if seekEnd {
+		f.offset = info.Size()
+		file.Seek(f.offset, io.SeekStart)
+	}
+	f.file, f.info = file, info
+	f.reader = bufio.NewReader(file)
+	f.buffer.Reset()
+}
+
+func (f *Follower) close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file, f.info, f.reader = nil, nil, nil
+	}
+}
+
+func (f *Follower) run(ctx context.Context) {
+	defer close(f.lines)
+	defer f.close()
+
+	ticker := time.NewTicker(f.options.pollInterval)
+	defer ticker.Stop()
+	for {
+		if f.file == nil {
+			// missing at start or after rotation, read new files from start
+			f.open(false)
+		}
+		if f.file != nil {
+			if err := f.drain(ctx); err != nil {
+				f.err = err
+				return
+			}
+			if f.rotated() {
+				// pick up what was written just before the rotation
+				if err := f.drain(ctx); err != nil {
+					f.err = err
+					return
+				}
+				f.close()
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			f.err = ctx.Err()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain emits every complete line currently available.
+func (f *Follower) drain(ctx context.Context) error {
+	for {
+		chunk, err := f.reader.ReadString('\n')
+		f.offset += int64(len(chunk))
+		f.buffer.WriteString(chunk)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line := strings.TrimSuffix(strings.TrimSuffix(f.buffer.String(), "\n"), "\r")
+		f.buffer.Reset()
+		select {
+		case f.lines <- line:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// rotated reports whether path now names another file, and rewinds the
+// current file if it was truncated.
+func (f *Follower) rotated() bool {
+	info, err := os.Stat(f.path)
+	if err != nil || !os.SameFile(f.info, info) {
+		return true
+	}
+	if info.Size() < f.offset {
+		// truncated in place
+		f.file.Seek(0, io.SeekStart)
+		f.reader.Reset(f.file)
+		f.offset = 0
+		f.buffer.Reset()
+	}
+	return false
+}
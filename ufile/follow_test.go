@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func appendFile(t *testing.T, path, content string) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(content)
+	f.Close()
+}
+
+func nextLine(t *testing.T, f *Follower) string {
+	select {
+	case line := <-f.Lines():
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for line")
+	}
+	return ""
+}
+
+func TestFollower(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "old\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := Follow(ctx, path, WithPollInterval(5*time.Millisecond))
+
+	appendFile(t, path, "huangjian\nMD")
+	assert.Equal(t, "huangjian", nextLine(t, f), "they should be equal")
+	appendFile(t, path, "GSF\r\n")
+	assert.Equal(t, "MDGSF", nextLine(t, f), "they should be equal")
+
+	// truncate
+	os.Truncate(path, 0)
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "after truncate\n")
+	assert.Equal(t, "after truncate", nextLine(t, f), "they should be equal")
+
+	// rotate
+	appendFile(t, path, "last old line\n")
+	os.Rename(path, path+".1")
+	appendFile(t, path, "new file\n")
+	assert.Equal(t, "last old line", nextLine(t, f), "they should be equal")
+	assert.Equal(t, "new file", nextLine(t, f), "they should be equal")
+
+	cancel()
+	for range f.Lines() {
+	}
+	assert.Equal(t, context.Canceled, f.Err(), "they should be equal")
+}
+
+func TestFollowerFromStart(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f := Follow(ctx, path, WithPollInterval(5*time.Millisecond), FromStart())
+
+	// the file does not exist yet
+	time.Sleep(20 * time.Millisecond)
+	appendFile(t, path, "first\nsecond\n")
+	assert.Equal(t, "first", nextLine(t, f), "they should be equal")
+	assert.Equal(t, "second", nextLine(t, f), "they should be equal")
+}
@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "src/main.go", false},
+		{"src/*.go", "src/main.go", true},
+		{"src/**", "src/a/b/c.go", true},
+		{"src/**", "src", true},
+		{"**/*.go", "main.go", true},
+		{"**/*.go", "a/b/main.go", true},
+		{"**/*.go", "a/b/main.c", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"file?.txt", "file1.txt", true},
+		{"[a-c].txt", "d.txt", false},
+		{"[", "[", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.match, MatchGlob(tc.pattern, tc.name), tc.pattern+" "+tc.name)
+	}
+}
+
+func TestMatchAny(t *testing.T) {
+	patterns := []string{"*.go", "/docs/*.md", "vendor/**"}
+	assert.Equal(t, true, MatchAny(patterns, "src/main.go"), "they should be equal")
+	assert.Equal(t, true, MatchAny(patterns, "docs/huangjian.md"), "they should be equal")
+	assert.Equal(t, false, MatchAny(patterns, "src/docs/huangjian.md"), "they should be equal")
+	assert.Equal(t, true, MatchAny(patterns, "vendor/MDGSF/utils"), "they should be equal")
+	assert.Equal(t, false, MatchAny(patterns, "main.c"), "they should be equal")
+	assert.Equal(t, false, MatchAny(nil, "main.go"), "they should be equal")
+}
@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules := parseIgnore(`# comment
+*.log
+!keep.log
+build/
+/root.txt
+docs/*.md
+\#hash
+`, ".")
+	rules = append(rules, parseIgnore("*.tmp\n", "sub")...)
+
+	testCases := []struct {
+		rel     string
+		isDir   bool
+		ignored bool
+	}{
+		{"a.log", false, true},
+		{"x/y/a.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"root.txt", false, true},
+		{"x/root.txt", false, false},
+		{"docs/a.md", false, true},
+		{"docs/x/a.md", false, false},
+		{"#hash", false, true},
+		{"sub/a.tmp", false, true},
+		{"a.tmp", false, false},
+		{"main.go", false, false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.ignored, ignored(rules, tc.rel, tc.isDir), tc.rel)
+	}
+}
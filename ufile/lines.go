@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	// ErrStop may be returned by a ForEachLine callback to stop reading
+	// early, ForEachLine then returns nil.
+	ErrStop = errors.New("ufile: stop iteration")
+	// ErrLineTooLong is returned when a line exceeds the max line size.
+	ErrLineTooLong = errors.New("ufile: line too long")
+)
+
+// DefaultLineBufferSize is the read buffer size of ForEachLine.
+const DefaultLineBufferSize = 64 * 1024
+
+type lineOptions struct {
+	bufferSize  int
+	maxLineSize int
+}
+
+// LineOption configures ForEachLine and ReadLines.
+type LineOption func(*lineOptions)
+
+// WithBufferSize set the read buffer size, default DefaultLineBufferSize.
+func WithBufferSize(n int) LineOption {
+	return func(o *lineOptions) {
+		o.bufferSize = n
+	}
+}
+
+// WithMaxLineSize fails with ErrLineTooLong on lines longer than n bytes,
+// default 0 means lines of any length are accepted.
+func WithMaxLineSize(n int) LineOption {
+	return func(o *lineOptions) {
+		o.maxLineSize = n
+	}
+}
+
+/*
+ForEachLine calls fn for every line of the file, without the trailing "\n"
+or "\r\n". Unlike bufio.Scanner lines are not limited to the buffer size.
+Reading stops at the first error of fn, which is returned unless it is
+ErrStop.
+*/
+func ForEachLine(path string, fn func(line string) error, opts ...LineOption) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return forEachLine(f, fn, opts...)
+}
+
+func forEachLine(r io.Reader, fn func(line string) error, opts ...LineOption) error {
+	o := lineOptions{bufferSize: DefaultLineBufferSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	reader := bufio.NewReaderSize(r, o.bufferSize)
+
+	var long []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			long = append(long, chunk...)
+			if o.maxLineSize > 0 && len(long) > o.maxLineSize {
+				return ErrLineTooLong
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		line := chunk
+		if long != nil {
+			line = append(long, chunk...)
+			long = nil
+		}
+		if len(line) > 0 || err == nil {
+			line = bytes.TrimSuffix(line, []byte("\n"))
+			line = bytes.TrimSuffix(line, []byte("\r"))
+			if o.maxLineSize > 0 && len(line) > o.maxLineSize {
+				return ErrLineTooLong
+			}
+			if ferr := fn(string(line)); ferr != nil {
+				if ferr == ErrStop {
+					return nil
+				}
+				return ferr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// ReadLines returns all lines of the file, see ForEachLine.
+func ReadLines(path string, opts ...LineOption) ([]string, error) {
+	var lines []string
+	err := ForEachLine(path, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	}, opts...)
+	return lines, err
+}
+
+// tailBlockSize is the size of the blocks Tail reads backwards.
+const tailBlockSize = 4096
+
+/*
+Tail returns the last n lines of the file. It reads the file backwards in
+blocks, so its cost depends on the size of the last lines rather than on
+the size of the file. A final "\n" does not start an empty line.
+*/
+func Tail(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := info.Size()
+	offset := size
+	var data []byte
+	newlines := 0
+	for offset > 0 && newlines <= n {
+		blockSize := int64(tailBlockSize)
+		if offset < blockSize {
+			blockSize = offset
+		}
+		offset -= blockSize
+		block := make([]byte, blockSize)
+		if _, err := f.ReadAt(block, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		newlines += bytes.Count(block, []byte("\n"))
+		data = append(block, data...)
+	}
+
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	if len(data) == 0 && size == 0 {
+		return nil, nil
+	}
+	lines := bytes.Split(data, []byte("\n"))
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = string(bytes.TrimSuffix(line, []byte("\r")))
+	}
+	return result, nil
+}
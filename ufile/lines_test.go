@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTemp(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "lines.txt")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadLines(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		content string
+		lines   []string
+	}{
+		{"", nil},
+		{"huangjian", []string{"huangjian"}},
+		{"huangjian\n", []string{"huangjian"}},
+		{"a\r\nb\n\nc", []string{"a", "b", "", "c"}},
+		{"\n", []string{""}},
+	}
+	for _, tc := range testCases {
+		path := writeTemp(t, dir, tc.content)
+		lines, err := ReadLines(path)
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, tc.lines, lines, "they should be equal")
+	}
+
+	_, err := ReadLines(filepath.Join(dir, "none"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestForEachLineLong(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	long := strings.Repeat("MDGSF", 1000)
+	path := writeTemp(t, dir, "short\n"+long+"\nend")
+
+	lines, err := ReadLines(path, WithBufferSize(16))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []string{"short", long, "end"}, lines, "they should be equal")
+
+	_, err = ReadLines(path, WithBufferSize(16), WithMaxLineSize(100))
+	assert.Equal(t, ErrLineTooLong, err, "they should be equal")
+}
+
+func TestForEachLineStop(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeTemp(t, dir, "a\nb\nc\n")
+
+	var seen []string
+	err := ForEachLine(path, func(line string) error {
+		seen = append(seen, line)
+		if line == "b" {
+			return ErrStop
+		}
+		return nil
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, seen, "they should be equal")
+
+	errTest := errors.New("huangjian")
+	err = ForEachLine(path, func(line string) error { return errTest })
+	assert.Equal(t, errTest, err, "they should be equal")
+}
+
+func TestTail(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	var b strings.Builder
+	for i := 1; i <= 2000; i++ {
+		fmt.Fprintf(&b, "line %d\r\n", i)
+	}
+	path := writeTemp(t, dir, b.String())
+	lines, err := Tail(path, 3)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []string{"line 1998", "line 1999", "line 2000"}, lines, "they should be equal")
+
+	lines, _ = Tail(path, 1000)
+	assert.Equal(t, 1000, len(lines), "they should be equal")
+	assert.Equal(t, "line 1001", lines[0], "they should be equal")
+
+	lines, _ = Tail(path, 5000)
+	assert.Equal(t, 2000, len(lines), "they should be equal")
+
+	path = writeTemp(t, dir, "a\nb")
+	lines, _ = Tail(path, 1)
+	assert.Equal(t, []string{"b"}, lines, "they should be equal")
+
+	path = writeTemp(t, dir, "")
+	lines, _ = Tail(path, 1)
+	assert.Equal(t, 0, len(lines), "they should be equal")
+}
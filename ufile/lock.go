@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLockNotSupported is returned on platforms without file locking.
+	ErrLockNotSupported = errors.New("ufile: file locking not supported on this platform")
+	// ErrNotLocked is returned by Unlock when the lock is not held.
+	ErrNotLocked = errors.New("ufile: file lock not held")
+)
+
+// lockPollInterval is how often Lock retries while the file is locked.
+const lockPollInterval = 50 * time.Millisecond
+
+/*
+FileLock is an exclusive advisory lock on a file shared between processes,
+using flock on unix and LockFileEx on windows. The lock file is created if
+needed and is not removed on Unlock. The lock is released by the OS when
+the process exits, so a crashed process never leaves a stale lock.
+*/
+type FileLock struct {
+	path string
+
+	lock sync.Mutex
+	file *os.File
+}
+
+// NewFileLock create an unlocked FileLock for path.
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+// Path returns the path of the lock file.
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// TryLock takes the lock without blocking, returns false if another
+// process, or another FileLock, holds it.
+func (l *FileLock) TryLock() (bool, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file != nil {
+		return true, nil
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return false, err
+	}
+	ok, err := tryLockFile(f)
+	if err != nil || !ok {
+		f.Close()
+		return false, err
+	}
+	l.file = f
+	return true, nil
+}
+
+// Lock takes the lock, waiting until it is free or ctx is done.
+func (l *FileLock) Lock(ctx context.Context) error {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := l.TryLock()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.file == nil {
+		return ErrNotLocked
+	}
+	err := unlockFile(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
+
+// Lock takes the lock on path, waiting until it is free or ctx is done.
+//
+//	lock, err := ufile.Lock(ctx, "/var/run/myjob.lock")
+//	if err != nil {
+//		return err
+//	}
+//	defer lock.Unlock()
+func Lock(ctx context.Context, path string) (*FileLock, error) {
+	l := NewFileLock(path)
+	if err := l.Lock(ctx); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// TryLock takes the lock on path without blocking, the FileLock is nil if
+// the lock is held elsewhere.
+func TryLock(path string) (*FileLock, error) {
+	l := NewFileLock(path)
+	ok, err := l.TryLock()
+	if err != nil || !ok {
+		return nil, err
+	}
+	return l, nil
+}
@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLock(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "huangjian.lock")
+
+	l1, err := TryLock(path)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.NotEqual(t, (*FileLock)(nil), l1, "they should not be equal")
+	assert.Equal(t, path, l1.Path(), "they should be equal")
+
+	l2 := NewFileLock(path)
+	ok, err := l2.TryLock()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, false, ok, "they should be equal")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 80*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l2.Lock(ctx), "they should be equal")
+
+	locked := make(chan error)
+	go func() {
+		l, err := Lock(context.Background(), path)
+		if err == nil {
+			err = l.Unlock()
+		}
+		locked <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, nil, l1.Unlock(), "they should be equal")
+	assert.Equal(t, nil, <-locked, "they should be equal")
+
+	assert.Equal(t, ErrNotLocked, l1.Unlock(), "they should be equal")
+	ok, _ = l2.TryLock()
+	assert.Equal(t, true, ok, "they should be equal")
+	ok, _ = l2.TryLock()
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, nil, l2.Unlock(), "they should be equal")
+}
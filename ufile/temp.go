@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+/*
+WithTempDir creates a temp directory, calls fn with its path and removes it
+with all its content afterwards, also when fn panics. The error of fn is
+returned, or the removal error if fn succeeded.
+*/
+func WithTempDir(fn func(dir string) error) (err error) {
+	dir, err := ioutil.TempDir("", "ufile")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rerr := os.RemoveAll(dir); err == nil {
+			err = rerr
+		}
+	}()
+	return fn(dir)
+}
+
+/*
+WithTempFile creates a temp file named after pattern (see ioutil.TempFile),
+calls fn with it and closes and removes it afterwards, also when fn panics.
+fn may close the file itself.
+*/
+func WithTempFile(pattern string, fn func(f *os.File) error) (err error) {
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		f.Close()
+		if rerr := os.Remove(f.Name()); err == nil && !os.IsNotExist(rerr) {
+			err = rerr
+		}
+	}()
+	return fn(f)
+}
+
+var (
+	cleanupLock  sync.Mutex
+	cleanupPaths = make(map[string]bool)
+)
+
+// RegisterCleanup remembers path to be removed by Cleanup.
+func RegisterCleanup(path string) {
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+	cleanupPaths[path] = true
+}
+
+// UnregisterCleanup forgets path, e.g. after it was moved to its final place.
+func UnregisterCleanup(path string) {
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+	delete(cleanupPaths, path)
+}
+
+/*
+Cleanup removes every registered path with its content and returns the
+first error. Go runs nothing when the process exits, so call it on the way
+out, e.g. deferred in main or as a ushutdown hook.
+*/
+func Cleanup() error {
+	cleanupLock.Lock()
+	paths := cleanupPaths
+	cleanupPaths = make(map[string]bool)
+	cleanupLock.Unlock()
+
+	var first error
+	for path := range paths {
+		if err := os.RemoveAll(path); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// TempDir creates a temp directory registered for Cleanup.
+func TempDir(pattern string) (string, error) {
+	dir, err := ioutil.TempDir("", pattern)
+	if err != nil {
+		return "", err
+	}
+	RegisterCleanup(dir)
+	return dir, nil
+}
+
+// TempFile creates a temp file registered for Cleanup.
+func TempFile(pattern string) (*os.File, error) {
+	f, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return nil, err
+	}
+	RegisterCleanup(f.Name())
+	return f, nil
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTempDir(t *testing.T) {
+	var saved string
+	err := WithTempDir(func(dir string) error {
+		saved = dir
+		return ioutil.WriteFile(filepath.Join(dir, "huangjian"), []byte("MDGSF"), 0644)
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, false, Exists(saved), "they should be equal")
+
+	errTest := errors.New("huangjian")
+	err = WithTempDir(func(dir string) error {
+		saved = dir
+		return errTest
+	})
+	assert.Equal(t, errTest, err, "they should be equal")
+	assert.Equal(t, false, Exists(saved), "they should be equal")
+
+	func() {
+		defer func() { recover() }()
+		WithTempDir(func(dir string) error {
+			saved = dir
+			panic("boom")
+		})
+	}()
+	assert.Equal(t, false, Exists(saved), "they should be equal")
+}
+
+func TestWithTempFile(t *testing.T) {
+	var saved string
+	err := WithTempFile("huangjian-*.txt", func(f *os.File) error {
+		saved = f.Name()
+		_, err := f.WriteString("MDGSF")
+		return err
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, strings.HasSuffix(saved, ".txt"), "they should be equal")
+	assert.Equal(t, false, Exists(saved), "they should be equal")
+
+	err = WithTempFile("", func(f *os.File) error {
+		saved = f.Name()
+		f.Close()
+		return os.Remove(f.Name())
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+}
+
+func TestCleanup(t *testing.T) {
+	dir, err := TempDir("ufile")
+	assert.Equal(t, nil, err, "they should be equal")
+	f, err := TempFile("ufile")
+	assert.Equal(t, nil, err, "they should be equal")
+	f.Close()
+	kept, _ := TempFile("ufile")
+	kept.Close()
+	defer os.Remove(kept.Name())
+	UnregisterCleanup(kept.Name())
+
+	assert.Equal(t, nil, Cleanup(), "they should be equal")
+	assert.Equal(t, false, Exists(dir), "they should be equal")
+	assert.Equal(t, false, Exists(f.Name()), "they should be equal")
+	assert.Equal(t, true, Exists(kept.Name()), "they should be equal")
+}
@@ -0,0 +1,195 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ufile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestExists(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "huangjian.txt")
+	ioutil.WriteFile(file, []byte("MDGSF"), 0644)
+
+	assert.Equal(t, true, Exists(dir), "they should be equal")
+	assert.Equal(t, true, Exists(file), "they should be equal")
+	assert.Equal(t, false, Exists(filepath.Join(dir, "none")), "they should be equal")
+	assert.Equal(t, true, IsDir(dir), "they should be equal")
+	assert.Equal(t, false, IsDir(file), "they should be equal")
+	assert.Equal(t, true, IsFile(file), "they should be equal")
+	assert.Equal(t, false, IsFile(dir), "they should be equal")
+
+	size, err := FileSize(file)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, int64(5), size, "they should be equal")
+	_, err = FileSize(filepath.Join(dir, "none"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestEnsureDir(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	sub := filepath.Join(dir, "a", "b", "c")
+	assert.Equal(t, nil, EnsureDir(sub, 0755), "they should be equal")
+	assert.Equal(t, true, IsDir(sub), "they should be equal")
+	assert.Equal(t, nil, EnsureDir(sub, 0755), "they should be equal")
+
+	file := filepath.Join(dir, "file")
+	ioutil.WriteFile(file, nil, 0644)
+	assert.NotEqual(t, nil, EnsureDir(file, 0755), "they should not be equal")
+}
+
+func TestTouch(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "touch")
+	assert.Equal(t, nil, Touch(file), "they should be equal")
+	assert.Equal(t, true, IsFile(file), "they should be equal")
+
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(file, old, old)
+	ioutil.WriteFile(file, []byte("huangjian"), 0644)
+	os.Chtimes(file, old, old)
+	assert.Equal(t, nil, Touch(file), "they should be equal")
+	info, _ := os.Stat(file)
+	assert.Equal(t, true, info.ModTime().After(old.Add(time.Minute)), "they should be equal")
+	assert.Equal(t, int64(9), info.Size(), "they should be equal")
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	ioutil.WriteFile(src, []byte("huangjian"), 0600)
+	os.Chmod(src, 0751)
+
+	assert.Equal(t, nil, CopyFile(src, dst), "they should be equal")
+	data, _ := ioutil.ReadFile(dst)
+	assert.Equal(t, "huangjian", string(data), "they should be equal")
+	srcInfo, _ := os.Stat(src)
+	dstInfo, _ := os.Stat(dst)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0751), dstInfo.Mode().Perm(), "they should be equal")
+	}
+	assert.Equal(t, srcInfo.ModTime().Unix(), dstInfo.ModTime().Unix(), "they should be equal")
+
+	ioutil.WriteFile(src, []byte("MDGSF"), 0644)
+	assert.Equal(t, nil, CopyFile(src, dst), "they should be equal")
+	data, _ = ioutil.ReadFile(dst)
+	assert.Equal(t, "MDGSF", string(data), "they should be equal")
+
+	assert.NotEqual(t, nil, CopyFile(src, src), "they should not be equal")
+	assert.NotEqual(t, nil, CopyFile(dir, dst), "they should not be equal")
+}
+
+func TestCopyDir(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	os.MkdirAll(filepath.Join(src, "sub", "deep"), 0755)
+	ioutil.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0644)
+	ioutil.WriteFile(filepath.Join(src, "sub", "deep", "b.txt"), []byte("b"), 0600)
+	if runtime.GOOS != "windows" {
+		os.Symlink("a.txt", filepath.Join(src, "link"))
+	}
+
+	dst := filepath.Join(dir, "dst")
+	assert.Equal(t, nil, CopyDir(src, dst), "they should be equal")
+	data, _ := ioutil.ReadFile(filepath.Join(dst, "sub", "deep", "b.txt"))
+	assert.Equal(t, "b", string(data), "they should be equal")
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(filepath.Join(dst, "sub", "deep", "b.txt"))
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "they should be equal")
+		link, err := os.Readlink(filepath.Join(dst, "link"))
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, "a.txt", link, "they should be equal")
+	}
+
+	assert.NotEqual(t, nil, CopyDir(src, filepath.Join(src, "sub", "copy")), "they should not be equal")
+	assert.NotEqual(t, nil, CopyDir(filepath.Join(src, "a.txt"), dst), "they should not be equal")
+}
+
+func TestCopyDirReadOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no unix permissions")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	os.MkdirAll(filepath.Join(src, "ro", "deep"), 0755)
+	ioutil.WriteFile(filepath.Join(src, "ro", "deep", "a.txt"), []byte("huangjian"), 0644)
+	ioutil.WriteFile(filepath.Join(src, "ro", "b.txt"), []byte("MDGSF"), 0644)
+	os.Chmod(filepath.Join(src, "ro", "deep"), 0555)
+	os.Chmod(filepath.Join(src, "ro"), 0555)
+
+	dst := filepath.Join(dir, "dst")
+	assert.Equal(t, nil, CopyDir(src, dst), "they should be equal")
+	defer func() {
+		// let RemoveAll clean up
+		os.Chmod(filepath.Join(src, "ro"), 0755)
+		os.Chmod(filepath.Join(src, "ro", "deep"), 0755)
+		os.Chmod(filepath.Join(dst, "ro"), 0755)
+		os.Chmod(filepath.Join(dst, "ro", "deep"), 0755)
+	}()
+
+	data, _ := ioutil.ReadFile(filepath.Join(dst, "ro", "deep", "a.txt"))
+	assert.Equal(t, "huangjian", string(data), "they should be equal")
+	data, _ = ioutil.ReadFile(filepath.Join(dst, "ro", "b.txt"))
+	assert.Equal(t, "MDGSF", string(data), "they should be equal")
+	info, _ := os.Stat(filepath.Join(dst, "ro"))
+	assert.Equal(t, os.FileMode(0555), info.Mode().Perm(), "they should be equal")
+	info, _ = os.Stat(filepath.Join(dst, "ro", "deep"))
+	assert.Equal(t, os.FileMode(0555), info.Mode().Perm(), "they should be equal")
+}
+
+func TestMoveFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	ioutil.WriteFile(src, []byte("huangjian"), 0644)
+
+	assert.Equal(t, nil, MoveFile(src, dst), "they should be equal")
+	assert.Equal(t, false, Exists(src), "they should be equal")
+	data, _ := ioutil.ReadFile(dst)
+	assert.Equal(t, "huangjian", string(data), "they should be equal")
+
+	assert.NotEqual(t, nil, MoveFile(src, dst), "they should not be equal")
+}
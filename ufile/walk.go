@@ -0,0 +1,221 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// WalkOptions configures Walk. Patterns are slash separated; a pattern
+// without "/" matches the base name, otherwise the path relative to root.
+type WalkOptions struct {
+	// Include only emits files matching one of the globs, empty means all.
+	Include []string
+	// Exclude skips files and whole directories matching one of the globs.
+	Exclude []string
+	// IgnoreFiles are names of .gitignore style files, e.g. ".gitignore",
+	// whose rules apply to the directory they are in and below.
+	IgnoreFiles []string
+	// MaxDepth limits recursion, entries of root have depth 1. 0 means no
+	// limit.
+	MaxDepth int
+	// FollowSymlinks descends into symlinked directories, loops are
+	// detected. By default symlinks are emitted but not followed.
+	FollowSymlinks bool
+	// IncludeDirs emits directories too.
+	IncludeDirs bool
+	// Workers is the number of directories read in parallel, default
+	// runtime.NumCPU().
+	Workers int
+}
+
+// WalkEntry is a file found by Walk. Err is set, and Info nil, if a
+// directory could not be read.
+type WalkEntry struct {
+	Path    string // root joined with RelPath
+	RelPath string // slash separated path relative to root
+	Info    os.FileInfo
+	Err     error
+}
+
+type walker struct {
+	ctx     context.Context
+	root    string
+	options WalkOptions
+	out     chan WalkEntry
+	sem     chan struct{}
+	wg      sync.WaitGroup
+
+	lock    sync.Mutex
+	visited map[string]bool // real paths of followed directories
+}
+
+/*
+Walk traverses root in parallel and sends the entries found on the
+returned channel, in no particular order. The channel is closed when the
+walk is done or ctx is cancelled; the caller must drain it or cancel ctx.
+*/
+func Walk(ctx context.Context, root string, options WalkOptions) <-chan WalkEntry {
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+	w := &walker{
+		ctx:     ctx,
+		root:    root,
+		options: options,
+		out:     make(chan WalkEntry),
+		sem:     make(chan struct{}, options.Workers),
+		visited: make(map[string]bool),
+	}
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		w.visited[real] = true
+	}
+
+	w.wg.Add(1)
+	go w.walkDir("", 0, nil)
+	go func() {
+		w.wg.Wait()
+		close(w.out)
+	}()
+	return w.out
+}
+
+func (w *walker) send(e WalkEntry) bool {
+	select {
+	case w.out <- e:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+func (w *walker) walkDir(rel string, depth int, rules []ignoreRule) {
+	defer w.wg.Done()
+
+	select {
+	case w.sem <- struct{}{}:
+	case <-w.ctx.Done():
+		return
+	}
+	dir := filepath.Join(w.root, filepath.FromSlash(rel))
+	entries, err := ioutil.ReadDir(dir)
+	if err == nil {
+		rules = w.loadIgnore(dir, rel, rules)
+	}
+	<-w.sem
+	if err != nil {
+		w.send(WalkEntry{Path: dir, RelPath: rel, Err: err})
+		return
+	}
+
+	for _, info := range entries {
+		childRel := info.Name()
+		if rel != "" {
+			childRel = rel + "/" + info.Name()
+		}
+		childPath := filepath.Join(dir, info.Name())
+
+		isDir := info.IsDir()
+		if info.Mode()&os.ModeSymlink != 0 && w.options.FollowSymlinks {
+			if target, err := os.Stat(childPath); err == nil && target.IsDir() {
+				isDir = true
+			}
+		}
+		if w.skip(childRel, isDir, rules) {
+			continue
+		}
+
+		if isDir {
+			if w.options.IncludeDirs {
+				if !w.send(WalkEntry{Path: childPath, RelPath: childRel, Info: info}) {
+					return
+				}
+			}
+			if w.options.MaxDepth > 0 && depth+1 >= w.options.MaxDepth {
+				continue
+			}
+			// with symlinks followed a directory may be reachable twice
+			if w.options.FollowSymlinks && !w.firstVisit(childPath) {
+				continue
+			}
+			w.wg.Add(1)
+			go w.walkDir(childRel, depth+1, rules)
+			continue
+		}
+
+		if !w.included(childRel) {
+			continue
+		}
+		if !w.send(WalkEntry{Path: childPath, RelPath: childRel, Info: info}) {
+			return
+		}
+	}
+}
+
+func (w *walker) skip(rel string, isDir bool, rules []ignoreRule) bool {
+	if MatchAny(w.options.Exclude, rel) {
+		return true
+	}
+	return ignored(rules, rel, isDir)
+}
+
+func (w *walker) included(rel string) bool {
+	return len(w.options.Include) == 0 || MatchAny(w.options.Include, rel)
+}
+
+// firstVisit records the real path of a directory, false if it was seen
+// already.
+func (w *walker) firstVisit(path string) bool {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+func (w *walker) loadIgnore(dir, rel string, rules []ignoreRule) []ignoreRule {
+	for _, name := range w.options.IgnoreFiles {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		base := rel
+		if base == "" {
+			base = "."
+		}
+		// copy so sibling directories do not share the appended rules
+		rules = append(append([]ignoreRule(nil), rules...), parseIgnore(string(data), base)...)
+	}
+	return rules
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ufile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeTree(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func collect(t *testing.T, root string, options WalkOptions) []string {
+	var rels []string
+	for e := range Walk(context.Background(), root, options) {
+		assert.Equal(t, nil, e.Err, "they should be equal")
+		rels = append(rels, e.RelPath)
+	}
+	sort.Strings(rels)
+	return rels
+}
+
+func TestWalk(t *testing.T) {
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+	makeTree(t, root, map[string]string{
+		"main.go":             "",
+		"README.md":           "",
+		".gitignore":          "*.log\nvendor/\n",
+		"app.log":             "",
+		"src/a.go":            "",
+		"src/a_test.go":       "",
+		"src/deep/b.go":       "",
+		"src/deep/.gitignore": "!debug.log\n",
+		"src/deep/debug.log":  "",
+		"vendor/lib/c.go":     "",
+		"node_modules/x.js":   "",
+	})
+
+	assert.Equal(t, []string{
+		".gitignore", "README.md", "app.log", "main.go", "node_modules/x.js",
+		"src/a.go", "src/a_test.go", "src/deep/.gitignore", "src/deep/b.go",
+		"src/deep/debug.log", "vendor/lib/c.go",
+	}, collect(t, root, WalkOptions{}), "they should be equal")
+
+	assert.Equal(t, []string{
+		"main.go", "src/a.go", "src/deep/b.go",
+	}, collect(t, root, WalkOptions{
+		Include:     []string{"*.go"},
+		Exclude:     []string{"*_test.go", "node_modules"},
+		IgnoreFiles: []string{".gitignore"},
+	}), "they should be equal")
+
+	assert.Equal(t, []string{
+		"src/deep/debug.log",
+	}, collect(t, root, WalkOptions{
+		Include:     []string{"**/*.log"},
+		IgnoreFiles: []string{".gitignore"},
+	}), "they should be equal")
+
+	assert.Equal(t, []string{
+		"main.go", "node_modules", "src", "src/a.go", "src/a_test.go",
+		"src/deep", "vendor", "vendor/lib",
+	}, collect(t, root, WalkOptions{
+		Include:     []string{"*.go"},
+		MaxDepth:    2,
+		IncludeDirs: true,
+		Workers:     1,
+	}), "they should be equal")
+}
+
+func TestWalkSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+	makeTree(t, root, map[string]string{"real/a.txt": ""})
+	os.Symlink(filepath.Join(root, "real"), filepath.Join(root, "link"))
+	os.Symlink(root, filepath.Join(root, "real", "loop"))
+
+	assert.Equal(t, []string{
+		"link", "real/a.txt", "real/loop",
+	}, collect(t, root, WalkOptions{}), "they should be equal")
+
+	rels := collect(t, root, WalkOptions{FollowSymlinks: true})
+	assert.Equal(t, 1, len(rels), "they should be equal")
+}
+
+func TestWalkCancel(t *testing.T) {
+	root := tempDir(t)
+	defer os.RemoveAll(root)
+	files := make(map[string]string)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		files[name+"/1"] = ""
+		files[name+"/2"] = ""
+	}
+	makeTree(t, root, files)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := Walk(ctx, root, WalkOptions{})
+	<-ch
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,229 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sync"
+)
+
+/*
+BloomFilter probabilistic set, Test never returns false for added data, but
+may return true for data never added, with the configured false positive rate.
+BloomFilter is not safe for concurrent use, see SafeBloomFilter.
+*/
+type BloomFilter struct {
+	m    uint64 // number of bits
+	k    uint64 // number of hash functions
+	bits []uint64
+}
+
+/*
+NewBloomFilter create a bloom filter.
+@param n: expected number of elements.
+@param fpRate: wanted false positive rate when n elements are added, like 0.01.
+*/
+func NewBloomFilter(n uint, fpRate float64) *BloomFilter {
+	if n == 0 {
+		n = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k == 0 {
+		k = 1
+	}
+	return newBloomFilter(m, k)
+}
+
+func newBloomFilter(m, k uint64) *BloomFilter {
+	return &BloomFilter{
+		m:    m,
+		k:    k,
+		bits: make([]uint64, (m+63)/64),
+	}
+}
+
+// locations use double hashing on the two halves of sha256, g(i) = h1 + i*h2.
+func (bf *BloomFilter) locations(data []byte) []uint64 {
+	sum := sha256.Sum256(data)
+	h1 := binary.BigEndian.Uint64(sum[0:8])
+	h2 := binary.BigEndian.Uint64(sum[8:16]) | 1 // never 0, or all locations are the same
+	locs := make([]uint64, bf.k)
+	for i := uint64(0); i < bf.k; i++ {
+		locs[i] = (h1 + i*h2) % bf.m
+	}
+	return locs
+}
+
+// Add adds data to the filter.
+func (bf *BloomFilter) Add(data []byte) {
+	for _, loc := range bf.locations(data) {
+		bf.bits[loc/64] |= 1 << (loc % 64)
+	}
+}
+
+// AddString adds str to the filter.
+func (bf *BloomFilter) AddString(str string) {
+	bf.Add([]byte(str))
+}
+
+// Test reports whether data may be in the filter.
+func (bf *BloomFilter) Test(data []byte) bool {
+	for _, loc := range bf.locations(data) {
+		if bf.bits[loc/64]&(1<<(loc%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString reports whether str may be in the filter.
+func (bf *BloomFilter) TestString(str string) bool {
+	return bf.Test([]byte(str))
+}
+
+// Cap returns the number of bits of the filter.
+func (bf *BloomFilter) Cap() uint64 {
+	return bf.m
+}
+
+// K returns the number of hash functions of the filter.
+func (bf *BloomFilter) K() uint64 {
+	return bf.k
+}
+
+// Clear removes all data from the filter.
+func (bf *BloomFilter) Clear() {
+	for i := range bf.bits {
+		bf.bits[i] = 0
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 16+8*len(bf.bits))
+	binary.BigEndian.PutUint64(data[0:8], bf.m)
+	binary.BigEndian.PutUint64(data[8:16], bf.k)
+	for i, word := range bf.bits {
+		binary.BigEndian.PutUint64(data[16+8*i:], word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 16 {
+		return errors.New("bloom filter: data too short")
+	}
+	m := binary.BigEndian.Uint64(data[0:8])
+	k := binary.BigEndian.Uint64(data[8:16])
+	// check m against the data first, (m+63)/64 overflows for huge m, and
+	// k against m, NewBloomFilter never makes more hashes than bits
+	if m == 0 || k == 0 || m > uint64(len(data)-16)*8 || k > m ||
+		uint64(len(data)-16) != (m+63)/64*8 {
+		return errors.New("bloom filter: invalid data")
+	}
+	filter := newBloomFilter(m, k)
+	for i := range filter.bits {
+		filter.bits[i] = binary.BigEndian.Uint64(data[16+8*i:])
+	}
+	*bf = *filter
+	return nil
+}
+
+// BloomFilterFromBytes create a bloom filter from data returned by MarshalBinary.
+func BloomFilterFromBytes(data []byte) (*BloomFilter, error) {
+	bf := &BloomFilter{}
+	if err := bf.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return bf, nil
+}
+
+// SafeBloomFilter is a BloomFilter safe for concurrent use.
+type SafeBloomFilter struct {
+	lock sync.RWMutex
+	bf   *BloomFilter
+}
+
+// NewSafeBloomFilter create a bloom filter safe for concurrent use,
+// parameters are the same as NewBloomFilter.
+func NewSafeBloomFilter(n uint, fpRate float64) *SafeBloomFilter {
+	return &SafeBloomFilter{bf: NewBloomFilter(n, fpRate)}
+}
+
+// Add adds data to the filter.
+func (s *SafeBloomFilter) Add(data []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.bf.Add(data)
+}
+
+// AddString adds str to the filter.
+func (s *SafeBloomFilter) AddString(str string) {
+	s.Add([]byte(str))
+}
+
+// Test reports whether data may be in the filter.
+func (s *SafeBloomFilter) Test(data []byte) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.bf.Test(data)
+}
+
+// TestString reports whether str may be in the filter.
+func (s *SafeBloomFilter) TestString(str string) bool {
+	return s.Test([]byte(str))
+}
+
+// Clear removes all data from the filter.
+func (s *SafeBloomFilter) Clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.bf.Clear()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s *SafeBloomFilter) MarshalBinary() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.bf.MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *SafeBloomFilter) UnmarshalBinary(data []byte) error {
+	bf, err := BloomFilterFromBytes(data)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.bf = bf
+	return nil
+}
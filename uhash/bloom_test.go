@@ -0,0 +1,127 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBloomFilter(t *testing.T) {
+	bf := NewBloomFilter(1000, 0.01)
+	assert.Equal(t, uint64(9586), bf.Cap(), "they should be equal")
+	assert.Equal(t, uint64(7), bf.K(), "they should be equal")
+
+	for i := 0; i < 1000; i++ {
+		bf.AddString("key" + strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, true, bf.TestString("key"+strconv.Itoa(i)), "they should be equal")
+	}
+
+	falsePositive := 0
+	for i := 0; i < 10000; i++ {
+		if bf.TestString("other" + strconv.Itoa(i)) {
+			falsePositive++
+		}
+	}
+	assert.Equal(t, true, falsePositive < 200, "false positive %v", falsePositive)
+
+	bf.Clear()
+	assert.Equal(t, false, bf.TestString("key1"), "they should be equal")
+}
+
+func TestBloomFilterMarshal(t *testing.T) {
+	bf := NewBloomFilter(100, 0.001)
+	bf.AddString("huangjian")
+
+	data, err := bf.MarshalBinary()
+	assert.Equal(t, nil, err, "they should be equal")
+
+	bf2, err := BloomFilterFromBytes(data)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, bf, bf2, "they should be equal")
+	assert.Equal(t, true, bf2.TestString("huangjian"), "they should be equal")
+
+	_, err = BloomFilterFromBytes(data[:20])
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestBloomFilterUnmarshalInvalid(t *testing.T) {
+	data := make([]byte, 16)
+	binary.BigEndian.PutUint64(data[0:8], math.MaxUint64-10)
+	binary.BigEndian.PutUint64(data[8:16], 3)
+	_, err := BloomFilterFromBytes(data)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	data = make([]byte, 24)
+	binary.BigEndian.PutUint64(data[0:8], 64)
+	binary.BigEndian.PutUint64(data[8:16], math.MaxUint64)
+	_, err = BloomFilterFromBytes(data)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func FuzzBloomFilterUnmarshal(f *testing.F) {
+	bf := NewBloomFilter(10, 0.01)
+	data, _ := bf.MarshalBinary()
+	f.Add(data)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bf, err := BloomFilterFromBytes(data)
+		if err != nil {
+			return
+		}
+		bf.AddString("huangjian")
+		bf.TestString("MDGSF")
+	})
+}
+
+func TestSafeBloomFilter(t *testing.T) {
+	bf := NewSafeBloomFilter(1000, 0.01)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bf.AddString(strconv.Itoa(i*100 + j))
+				bf.TestString(strconv.Itoa(j))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, true, bf.TestString(strconv.Itoa(i)), "they should be equal")
+	}
+
+	data, _ := bf.MarshalBinary()
+	bf2 := NewSafeBloomFilter(1, 0.5)
+	assert.Equal(t, nil, bf2.UnmarshalBinary(data), "they should be equal")
+	assert.Equal(t, true, bf2.TestString("999"), "they should be equal")
+}
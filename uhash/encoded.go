@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+// Encoding text encoding of a digest.
+type Encoding string
+
+// Supported encodings for SumEncoded.
+const (
+	HexEncoding       Encoding = "hex"
+	Base64Encoding    Encoding = "base64"    // standard base64, with padding
+	Base64URLEncoding Encoding = "base64url" // url safe base64, without padding
+)
+
+// GetDataSum Returns the raw digest of data, algorithms are the same as GetDataDigest.
+func GetDataSum(data []byte, algo string) ([]byte, error) {
+	hasher, err := NewHasher(algo)
+	if err != nil {
+		return nil, err
+	}
+	hasher.Write(data)
+	return hasher.Sum(nil), nil
+}
+
+/*
+SumEncoded Returns the digest of data in the given encoding.
+@param algo: algorithms of GetDataDigest.
+@param encoding: HexEncoding, Base64Encoding, Base64URLEncoding
+*/
+func SumEncoded(algo string, data []byte, encoding Encoding) (string, error) {
+	sum, err := GetDataSum(data, algo)
+	if err != nil {
+		return "", err
+	}
+	return EncodeSum(sum, encoding)
+}
+
+// EncodeSum encode a raw digest in the given encoding.
+func EncodeSum(sum []byte, encoding Encoding) (string, error) {
+	switch encoding {
+	case HexEncoding:
+		return hex.EncodeToString(sum), nil
+	case Base64Encoding:
+		return base64.StdEncoding.EncodeToString(sum), nil
+	case Base64URLEncoding:
+		return base64.RawURLEncoding.EncodeToString(sum), nil
+	}
+	return "", errors.New("invalid digest encoding")
+}
+
+// ContentMD5 Returns the value of Content-MD5 header for data, base64 of md5.
+func ContentMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumEncoded(t *testing.T) {
+	digest, err := SumEncoded("sha256", []byte("huangjian"), HexEncoding)
+	assert.Equal(t, nil, err, "they should be equal")
+	expected, _ := SHA256([]byte("huangjian"))
+	assert.Equal(t, expected, digest, "they should be equal")
+
+	digest, err = SumEncoded("sha256", []byte("huangjian"), Base64Encoding)
+	assert.Equal(t, nil, err, "they should be equal")
+	cmd := exec.Command("/bin/sh", "-c", `echo -n huangjian|openssl dgst -sha256 -binary|openssl base64 -A`)
+	output, err := cmd.Output()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, strings.TrimSpace(string(output)), digest, "they should be equal")
+
+	urlDigest, err := SumEncoded("sha256", []byte("huangjian"), Base64URLEncoding)
+	assert.Equal(t, nil, err, "they should be equal")
+	r := strings.NewReplacer("+", "-", "/", "_", "=", "")
+	assert.Equal(t, r.Replace(digest), urlDigest, "they should be equal")
+}
+
+func TestSumEncodedInvalid(t *testing.T) {
+	_, err := SumEncoded("invalid", []byte("huangjian"), HexEncoding)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = SumEncoded("md5", []byte("huangjian"), "invalid")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestGetDataSum(t *testing.T) {
+	sum, err := GetDataSum([]byte("huangjian"), "sha1")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 20, len(sum), "they should be equal")
+}
+
+func TestContentMD5(t *testing.T) {
+	expected, _ := SumEncoded("md5", []byte("huangjian"), Base64Encoding)
+	assert.Equal(t, expected, ContentMD5([]byte("huangjian")), "they should be equal")
+}
@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// etagLen number of sha256 bytes used in an ETag.
+const etagLen = 16
+
+func makeETag(sum []byte, weak bool) string {
+	etag := `"` + hex.EncodeToString(sum[:etagLen]) + `"`
+	if weak {
+		etag = "W/" + etag
+	}
+	return etag
+}
+
+// ETag Returns a strong ETag of data, like "\"<32 hex chars>\"".
+func ETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return makeETag(sum[:], false)
+}
+
+// WeakETag Returns a weak ETag of data, like "W/\"<32 hex chars>\"".
+func WeakETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return makeETag(sum[:], true)
+}
+
+/*
+ETagReader Returns a strong ETag of the content of r, from the current
+offset to the end. r is seeked back to its original offset after reading, so
+it can be served afterwards, for example by http.ServeContent.
+*/
+func ETagReader(r io.ReadSeeker) (string, error) {
+	return etagReader(r, false)
+}
+
+// WeakETagReader is the same as ETagReader, but returns a weak ETag.
+func WeakETagReader(r io.ReadSeeker) (string, error) {
+	return etagReader(r, true)
+}
+
+func etagReader(r io.ReadSeeker, weak bool) (string, error) {
+	offset, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return "", err
+	}
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+	return makeETag(hasher.Sum(nil), weak), nil
+}
+
+/*
+ETagMatch reports whether etag matches the value of an If-None-Match header.
+ifNoneMatch can be "*" or a comma separated list of ETags. As RFC 7232
+requires for If-None-Match, weak comparison is used, so W/"x" matches "x".
+If it returns true, the handler should reply 304 Not Modified.
+*/
+func ETagMatch(ifNoneMatch, etag string) bool {
+	ifNoneMatch = strings.TrimSpace(ifNoneMatch)
+	if len(ifNoneMatch) == 0 || len(etag) == 0 {
+		return false
+	}
+	if ifNoneMatch == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
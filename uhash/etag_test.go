@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	etag := ETag([]byte("huangjian"))
+	digest, _ := SHA256([]byte("huangjian"))
+	assert.Equal(t, `"`+digest[:32]+`"`, etag, "they should be equal")
+	assert.Equal(t, "W/"+etag, WeakETag([]byte("huangjian")), "they should be equal")
+	assert.NotEqual(t, etag, ETag([]byte("MDGSF")), "they should not be equal")
+}
+
+func TestETagReader(t *testing.T) {
+	r := bytes.NewReader([]byte("huangjian"))
+	etag, err := ETagReader(r)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, ETag([]byte("huangjian")), etag, "they should be equal")
+
+	// reader is rewound.
+	content, _ := ioutil.ReadAll(r)
+	assert.Equal(t, "huangjian", string(content), "they should be equal")
+
+	r = bytes.NewReader([]byte("huangjian"))
+	r.Seek(5, 0)
+	etag, err = WeakETagReader(r)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, WeakETag([]byte("jian")), etag, "they should be equal")
+	content, _ = ioutil.ReadAll(r)
+	assert.Equal(t, "jian", string(content), "they should be equal")
+}
+
+func TestETagMatch(t *testing.T) {
+	etag := ETag([]byte("huangjian"))
+	weak := WeakETag([]byte("huangjian"))
+
+	assert.Equal(t, true, ETagMatch(etag, etag), "they should be equal")
+	assert.Equal(t, true, ETagMatch(weak, etag), "they should be equal")
+	assert.Equal(t, true, ETagMatch(etag, weak), "they should be equal")
+	assert.Equal(t, true, ETagMatch("*", etag), "they should be equal")
+	assert.Equal(t, true, ETagMatch(`"abc", `+etag, etag), "they should be equal")
+	assert.Equal(t, false, ETagMatch(`"abc", "def"`, etag), "they should be equal")
+	assert.Equal(t, false, ETagMatch("", etag), "they should be equal")
+}
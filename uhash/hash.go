@@ -33,17 +33,28 @@ import (
 	"io/ioutil"
 	"strings"
 
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/crypto/sha3"
 )
 
 /*
 GetDataDigest Returns the hex digest of some data.
 @param data: calculate hash for data.
-@param algo: md5, sha1, sha256, sha512
+@param algo: md5, sha1, sha256, sha512, sha3-512, blake2b-256, blake2b-512
 */
 func GetDataDigest(data []byte, algo string) (digest string, err error) {
-	var hasher hash.Hash
+	hasher, err := NewHasher(algo)
+	if err != nil {
+		return
+	}
+
+	hasher.Write(data)
+	digest = hex.EncodeToString(hasher.Sum(nil))
+	return
+}
 
+// NewHasher Returns a new hash.Hash of algo, algorithms are the same as GetDataDigest.
+func NewHasher(algo string) (hasher hash.Hash, err error) {
 	switch strings.ToLower(algo) {
 	case "md5":
 		hasher = md5.New()
@@ -55,13 +66,13 @@ func GetDataDigest(data []byte, algo string) (digest string, err error) {
 		hasher = sha512.New()
 	case "sha3-512":
 		hasher = sha3.New512()
+	case "blake2b-256":
+		hasher, _ = blake2b.New256(nil)
+	case "blake2b-512":
+		hasher, _ = blake2b.New512(nil)
 	default:
 		err = errors.New("invalid hash algorithm")
-		return
 	}
-
-	hasher.Write(data)
-	digest = hex.EncodeToString(hasher.Sum(nil))
 	return
 }
 
@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"encoding/hex"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// BLAKE2b256 calculate blake2b-256 for data
+func BLAKE2b256(data []byte) (digest string, err error) {
+	return BLAKE2b(data, nil, blake2b.Size256)
+}
+
+// BLAKE2b512 calculate blake2b-512 for data
+func BLAKE2b512(data []byte) (digest string, err error) {
+	return BLAKE2b(data, nil, blake2b.Size)
+}
+
+/*
+BLAKE2b calculate blake2b for data.
+@param key: nil for plain hash, or at most 64 bytes for keyed hash (MAC).
+@param size: digest size in bytes, between 1 and 64.
+*/
+func BLAKE2b(data, key []byte, size int) (digest string, err error) {
+	hasher, err := blake2b.New(size, key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := hasher.Write(data); err != nil {
+		return "", err
+	}
+	digest = hex.EncodeToString(hasher.Sum(nil))
+	return
+}
+
+// BLAKE2bFile calculate blake2b for a file, the file is read as a stream,
+// so it works for large files. key and size are the same as BLAKE2b.
+func BLAKE2bFile(filename string, key []byte, size int) (digest string, err error) {
+	hasher, err := blake2b.New(size, key)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", err
+	}
+	digest = hex.EncodeToString(hasher.Sum(nil))
+	return
+}
@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBLAKE2b512(t *testing.T) {
+	digest, err := BLAKE2b512([]byte("huangjian"))
+	assert.Equal(t, nil, err, "they should be equal")
+
+	cmd := exec.Command("/bin/sh", "-c", `echo -n huangjian|openssl dgst -blake2b512`)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Skip("openssl blake2b512 not supported")
+	}
+	assert.Equal(t, true, strings.Contains(string(output), digest), "they should be equal")
+}
+
+func TestBLAKE2b256(t *testing.T) {
+	digest, err := BLAKE2b256([]byte("huangjian"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 64, len(digest), "they should be equal")
+
+	digest2, err := GetDataDigest([]byte("huangjian"), "blake2b-256")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, digest, digest2, "they should be equal")
+}
+
+func TestBLAKE2bKeyed(t *testing.T) {
+	plain, err := BLAKE2b([]byte("huangjian"), nil, 32)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	keyed, err := BLAKE2b([]byte("huangjian"), []byte("secret"), 32)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.NotEqual(t, plain, keyed, "they should not be equal")
+
+	_, err = BLAKE2b([]byte("huangjian"), make([]byte, 65), 32)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	_, err = BLAKE2b([]byte("huangjian"), nil, 65)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestBLAKE2bFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "uhash_blake2b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write([]byte("huangjian"))
+	f.Close()
+
+	digest, err := BLAKE2bFile(f.Name(), []byte("secret"), 32)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	expected, _ := BLAKE2b([]byte("huangjian"), []byte("secret"), 32)
+	assert.Equal(t, expected, digest, "they should be equal")
+}
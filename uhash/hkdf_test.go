@@ -0,0 +1,55 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHKDF(t *testing.T) {
+	// RFC 5869 A.1
+	ikm, _ := hex.DecodeString("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+
+	key, err := HKDF(ikm, salt, info, 42)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865", hex.EncodeToString(key), "they should be equal")
+}
+
+func TestHKDFInfo(t *testing.T) {
+	secret := []byte("huangjian")
+	a, _ := HKDF(secret, nil, []byte("encryption"), 32)
+	b, _ := HKDF(secret, nil, []byte("signing"), 32)
+	assert.NotEqual(t, a, b, "they should not be equal")
+}
+
+func TestHKDFInvalid(t *testing.T) {
+	_, err := HKDF([]byte("huangjian"), nil, nil, 0)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = HKDF([]byte("huangjian"), nil, nil, 255*32+1)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"strings"
+)
+
+/*
+NewHMAC Returns a new HMAC hash.Hash of algo with key.
+@param algo: md5, sha1, sha256, sha512
+*/
+func NewHMAC(algo string, key []byte) (hash.Hash, error) {
+	var fn func() hash.Hash
+	switch strings.ToLower(algo) {
+	case "md5":
+		fn = md5.New
+	case "sha1":
+		fn = sha1.New
+	case "sha256":
+		fn = sha256.New
+	case "sha512":
+		fn = sha512.New
+	default:
+		return nil, errors.New("invalid hmac algorithm")
+	}
+	return hmac.New(fn, key), nil
+}
+
+// HMAC Returns the hex HMAC of data with key, algorithms are the same as NewHMAC.
+func HMAC(data, key []byte, algo string) (digest string, err error) {
+	mac, err := NewHMAC(algo, key)
+	if err != nil {
+		return "", err
+	}
+	mac.Write(data)
+	digest = hex.EncodeToString(mac.Sum(nil))
+	return
+}
+
+// VerifyHMAC reports whether digest is the hex HMAC of data with key, in constant time.
+func VerifyHMAC(data, key []byte, algo, digest string) bool {
+	expected, err := HMAC(data, key, algo)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(digest)))
+}
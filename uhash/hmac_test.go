@@ -0,0 +1,50 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMAC(t *testing.T) {
+	digest, err := HMAC([]byte("huangjian"), []byte("secret"), "sha256")
+	assert.Equal(t, nil, err, "they should be equal")
+
+	cmd := exec.Command("/bin/sh", "-c", `echo -n huangjian|openssl dgst -sha256 -hmac secret`)
+	output, err := cmd.Output()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, strings.Contains(string(output), digest), "they should be equal")
+
+	assert.Equal(t, true, VerifyHMAC([]byte("huangjian"), []byte("secret"), "sha256", strings.ToUpper(digest)), "they should be equal")
+	assert.Equal(t, false, VerifyHMAC([]byte("huangjian"), []byte("other"), "sha256", digest), "they should be equal")
+}
+
+func TestHMACInvalid(t *testing.T) {
+	_, err := HMAC([]byte("huangjian"), []byte("secret"), "invalid")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	assert.Equal(t, false, VerifyHMAC([]byte("huangjian"), []byte("secret"), "invalid", ""), "they should be equal")
+}
@@ -0,0 +1,197 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestReport is the result of VerifyManifest.
+type ManifestReport struct {
+	Missing    []string // listed in manifest, but not found on disk
+	Extra      []string // found on disk, but not listed in manifest
+	Mismatched []string // digest on disk differs from manifest
+}
+
+// OK reports whether the manifest matches the directory exactly.
+func (r *ManifestReport) OK() bool {
+	return len(r.Missing) == 0 && len(r.Extra) == 0 && len(r.Mismatched) == 0
+}
+
+/*
+GenerateManifest Returns a sha256sum compatible manifest of all regular
+files under dir. Each line looks like "<hex digest>  <relative path>", paths
+use forward slashes and are sorted, so `sha256sum -c` can check it when run
+inside dir.
+*/
+func GenerateManifest(dir string) ([]byte, error) {
+	return generateManifest(dir, "")
+}
+
+// WriteManifest generate manifest of dir and write it to filename.
+// If filename is inside dir, it is not listed in the manifest.
+func WriteManifest(dir, filename string) error {
+	data, err := generateManifest(dir, filename)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+/*
+VerifyManifest check the files listed in a sha256sum manifest.
+Paths in the manifest are relative to the directory of the manifest file,
+files in that directory which are not listed are reported as extra.
+*/
+func VerifyManifest(filename string) (*ManifestReport, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parseManifest(content)
+	if err != nil {
+		return nil, fmt.Errorf("parse manifest %v failed, err = %v", filename, err)
+	}
+
+	dir := filepath.Dir(filename)
+	files, err := listFiles(dir, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ManifestReport{}
+	for _, name := range files {
+		if _, ok := entries[name]; !ok {
+			report.Extra = append(report.Extra, name)
+		}
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		digest, err := GetFileDigest(filepath.Join(dir, filepath.FromSlash(name)), "sha256")
+		if err != nil {
+			if os.IsNotExist(err) {
+				report.Missing = append(report.Missing, name)
+				continue
+			}
+			return nil, err
+		}
+		if digest != entries[name] {
+			report.Mismatched = append(report.Mismatched, name)
+		}
+	}
+	return report, nil
+}
+
+func generateManifest(dir, exclude string) ([]byte, error) {
+	files, err := listFiles(dir, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, name := range files {
+		digest, err := GetFileDigest(filepath.Join(dir, filepath.FromSlash(name)), "sha256")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%s  %s\n", digest, name)
+	}
+	return buf.Bytes(), nil
+}
+
+// listFiles returns sorted slash separated paths of regular files under dir.
+func listFiles(dir, exclude string) ([]string, error) {
+	var excludeAbs string
+	if len(exclude) > 0 {
+		excludeAbs, _ = filepath.Abs(exclude)
+	}
+
+	files := make([]string, 0)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		if len(excludeAbs) > 0 {
+			if abs, _ := filepath.Abs(path); abs == excludeAbs {
+				return nil
+			}
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseManifest parse lines like "<digest>  <path>" or "<digest> *<path>".
+func parseManifest(content []byte) (map[string]string, error) {
+	entries := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineno := 0
+	for scanner.Scan() {
+		lineno++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+		idx := strings.Index(line, " ")
+		if idx <= 0 || idx+2 > len(line) {
+			return nil, fmt.Errorf("invalid line %v: %q", lineno, line)
+		}
+		digest := strings.ToLower(line[:idx])
+		mode := line[idx+1]
+		if mode != ' ' && mode != '*' {
+			return nil, fmt.Errorf("invalid line %v: %q", lineno, line)
+		}
+		name := strings.TrimPrefix(line[idx+2:], "./")
+		entries[name] = digest
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
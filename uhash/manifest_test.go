@@ -0,0 +1,105 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeManifestTestFiles(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "uhash_manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("huangjian"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("MDGSF"), 0644)
+	return dir
+}
+
+func TestGenerateManifest(t *testing.T) {
+	dir := writeManifestTestFiles(t)
+	defer os.RemoveAll(dir)
+
+	data, err := GenerateManifest(dir)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	a, _ := SHA256([]byte("huangjian"))
+	b, _ := SHA256([]byte("MDGSF"))
+	assert.Equal(t, a+"  a.txt\n"+b+"  sub/b.txt\n", string(data), "they should be equal")
+}
+
+func TestWriteManifestSha256sum(t *testing.T) {
+	dir := writeManifestTestFiles(t)
+	defer os.RemoveAll(dir)
+
+	manifest := filepath.Join(dir, "SHA256SUMS")
+	assert.Equal(t, nil, WriteManifest(dir, manifest), "they should be equal")
+
+	if _, err := exec.LookPath("sha256sum"); err != nil {
+		t.Skip("sha256sum not found")
+	}
+	cmd := exec.Command("sha256sum", "-c", "SHA256SUMS")
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	assert.Equal(t, nil, err, string(output))
+}
+
+func TestVerifyManifest(t *testing.T) {
+	dir := writeManifestTestFiles(t)
+	defer os.RemoveAll(dir)
+
+	manifest := filepath.Join(dir, "SHA256SUMS")
+	assert.Equal(t, nil, WriteManifest(dir, manifest), "they should be equal")
+
+	report, err := VerifyManifest(manifest)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, report.OK(), "they should be equal")
+
+	ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0644)
+	os.Remove(filepath.Join(dir, "sub", "b.txt"))
+	ioutil.WriteFile(filepath.Join(dir, "c.txt"), []byte("new"), 0644)
+
+	report, err = VerifyManifest(manifest)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, false, report.OK(), "they should be equal")
+	assert.Equal(t, []string{"a.txt"}, report.Mismatched, "they should be equal")
+	assert.Equal(t, []string{"sub/b.txt"}, report.Missing, "they should be equal")
+	assert.Equal(t, []string{"c.txt"}, report.Extra, "they should be equal")
+}
+
+func TestVerifyManifestInvalid(t *testing.T) {
+	dir := writeManifestTestFiles(t)
+	defer os.RemoveAll(dir)
+
+	manifest := filepath.Join(dir, "SHA256SUMS")
+	ioutil.WriteFile(manifest, []byte("invalid\n"), 0644)
+	_, err := VerifyManifest(manifest)
+	assert.NotEqual(t, nil, err, "they should be equal")
+}
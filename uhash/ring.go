@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// RingHashFunc hash function used by Ring.
+type RingHashFunc func(data []byte) uint32
+
+// DefaultRingReplicas default number of virtual nodes of each node.
+const DefaultRingReplicas = 100
+
+/*
+Ring consistent hashing ring with virtual nodes.
+Each node is put on the ring replicas times, a key belongs to the first
+virtual node clockwise from the hash of the key. It is safe for concurrent use.
+*/
+type Ring struct {
+	lock     sync.RWMutex
+	replicas int
+	hash     RingHashFunc
+	keys     []uint32          // sorted hashes of virtual nodes
+	hashMap  map[uint32]string // virtual node hash -> node
+	nodes    map[string]bool
+}
+
+/*
+NewRing create a consistent hashing ring.
+@param replicas: virtual nodes of each node, DefaultRingReplicas if <= 0.
+@param fn: hash function, crc32.ChecksumIEEE if nil.
+*/
+func NewRing(replicas int, fn RingHashFunc) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultRingReplicas
+	}
+	if fn == nil {
+		fn = crc32.ChecksumIEEE
+	}
+	return &Ring{
+		replicas: replicas,
+		hash:     fn,
+		hashMap:  make(map[uint32]string),
+		nodes:    make(map[string]bool),
+	}
+}
+
+// Add adds nodes to the ring, nodes already in the ring are ignored.
+func (r *Ring) Add(nodes ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, node := range nodes {
+		if r.nodes[node] {
+			continue
+		}
+		r.nodes[node] = true
+		for i := 0; i < r.replicas; i++ {
+			h := r.hash([]byte(strconv.Itoa(i) + node))
+			if _, ok := r.hashMap[h]; ok {
+				continue // hash collision, first node wins
+			}
+			r.hashMap[h] = node
+			r.keys = append(r.keys, h)
+		}
+	}
+	sort.Slice(r.keys, func(i, j int) bool { return r.keys[i] < r.keys[j] })
+}
+
+// Remove removes node from the ring.
+func (r *Ring) Remove(node string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !r.nodes[node] {
+		return
+	}
+	delete(r.nodes, node)
+
+	keys := r.keys[:0]
+	for _, h := range r.keys {
+		if r.hashMap[h] == node {
+			delete(r.hashMap, h)
+			continue
+		}
+		keys = append(keys, h)
+	}
+	r.keys = keys
+}
+
+// Get returns the node which key belongs to, or empty string if the ring is empty.
+func (r *Ring) Get(key string) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if len(r.keys) == 0 {
+		return ""
+	}
+	h := r.hash([]byte(key))
+	idx := sort.Search(len(r.keys), func(i int) bool { return r.keys[i] >= h })
+	if idx == len(r.keys) {
+		idx = 0
+	}
+	return r.hashMap[r.keys[idx]]
+}
+
+// Nodes returns all nodes in the ring, sorted.
+func (r *Ring) Nodes() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	nodes := make([]string, 0, len(r.nodes))
+	for node := range r.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Len returns the number of nodes in the ring.
+func (r *Ring) Len() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return len(r.nodes)
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingCustomHash(t *testing.T) {
+	// hash is the number itself, so the ring is predictable.
+	ring := NewRing(3, func(data []byte) uint32 {
+		n, _ := strconv.Atoi(string(data))
+		return uint32(n)
+	})
+
+	// virtual nodes: 2, 4, 6; 12, 14, 16; 22, 24, 26
+	ring.Add("6", "4", "2")
+
+	cases := map[string]string{
+		"2":  "2",
+		"11": "2",
+		"23": "4",
+		"27": "2",
+	}
+	for k, v := range cases {
+		assert.Equal(t, v, ring.Get(k), "key "+k)
+	}
+
+	// virtual nodes: 8, 18, 28
+	ring.Add("8")
+	cases["27"] = "8"
+	for k, v := range cases {
+		assert.Equal(t, v, ring.Get(k), "key "+k)
+	}
+
+	ring.Remove("8")
+	cases["27"] = "2"
+	for k, v := range cases {
+		assert.Equal(t, v, ring.Get(k), "key "+k)
+	}
+}
+
+func TestRingConsistency(t *testing.T) {
+	ring := NewRing(0, nil)
+	assert.Equal(t, "", ring.Get("huangjian"), "they should be equal")
+
+	ring.Add("node1", "node2", "node3")
+	ring.Add("node1")
+	assert.Equal(t, 3, ring.Len(), "they should be equal")
+	assert.Equal(t, []string{"node1", "node2", "node3"}, ring.Nodes(), "they should be equal")
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := "key" + strconv.Itoa(i)
+		before[key] = ring.Get(key)
+	}
+
+	// only keys of the removed node move.
+	ring.Remove("node2")
+	for key, node := range before {
+		if node != "node2" {
+			assert.Equal(t, node, ring.Get(key), "key "+key)
+		} else {
+			assert.NotEqual(t, "node2", ring.Get(key), "key "+key)
+		}
+	}
+}
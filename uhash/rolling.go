@@ -0,0 +1,184 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// rollingBase multiplier of the polynomial, arithmetic is mod 2^64.
+const rollingBase = 1099511628211
+
+/*
+RollingHash Rabin-Karp rolling hash over a fixed size window.
+After the window is full, each Roll adds one byte and removes the oldest one
+in O(1), so the hash of every window of a stream can be computed cheaply.
+*/
+type RollingHash struct {
+	window []byte
+	pos    int
+	full   bool
+	hash   uint64
+	outPow uint64 // rollingBase^len(window), weight of the byte leaving the window
+}
+
+// NewRollingHash create a rolling hash with window size bytes.
+func NewRollingHash(size int) *RollingHash {
+	if size <= 0 {
+		size = 1
+	}
+	outPow := uint64(1)
+	for i := 0; i < size; i++ {
+		outPow *= rollingBase
+	}
+	return &RollingHash{
+		window: make([]byte, size),
+		outPow: outPow,
+	}
+}
+
+// Roll adds b to the window, removes the oldest byte if the window is full,
+// and returns the new hash.
+func (rh *RollingHash) Roll(b byte) uint64 {
+	rh.hash = rh.hash*rollingBase + uint64(b) + 1
+	if rh.full {
+		rh.hash -= (uint64(rh.window[rh.pos]) + 1) * rh.outPow
+	}
+	rh.window[rh.pos] = b
+	rh.pos++
+	if rh.pos == len(rh.window) {
+		rh.pos = 0
+		rh.full = true
+	}
+	return rh.hash
+}
+
+// Write adds all bytes of p, it satisfies the io.Writer interface.
+func (rh *RollingHash) Write(p []byte) (int, error) {
+	for _, b := range p {
+		rh.Roll(b)
+	}
+	return len(p), nil
+}
+
+// Sum64 returns the hash of the current window.
+func (rh *RollingHash) Sum64() uint64 {
+	return rh.hash
+}
+
+// Reset clears the window.
+func (rh *RollingHash) Reset() {
+	for i := range rh.window {
+		rh.window[i] = 0
+	}
+	rh.pos = 0
+	rh.full = false
+	rh.hash = 0
+}
+
+// ChunkerConfig sizes used by Chunker, zero values use defaults.
+type ChunkerConfig struct {
+	MinSize int // minimum chunk size, default AvgSize/4
+	AvgSize int // expected chunk size, rounded up to power of 2, default 8KB
+	MaxSize int // maximum chunk size, default AvgSize*4
+	Window  int // rolling hash window, default 64
+}
+
+// Chunk one content defined chunk of a stream.
+type Chunk struct {
+	Offset int64
+	Data   []byte
+}
+
+/*
+Chunker splits a stream into content defined chunks. A chunk ends where the
+rolling hash of the last Window bytes matches a pattern, so an insertion or
+deletion only changes the chunks around it, and the other chunks (and their
+digests) stay the same. That is what dedup and incremental sync are built on.
+*/
+type Chunker struct {
+	r      *bufio.Reader
+	conf   ChunkerConfig
+	mask   uint64
+	hash   *RollingHash
+	offset int64
+}
+
+// NewChunker create a chunker reading from r.
+func NewChunker(r io.Reader, conf ChunkerConfig) (*Chunker, error) {
+	if conf.AvgSize <= 0 {
+		conf.AvgSize = 8 * 1024
+	}
+	shift := bits.Len(uint(conf.AvgSize - 1))
+	conf.AvgSize = 1 << uint(shift)
+	if conf.MinSize <= 0 {
+		conf.MinSize = conf.AvgSize / 4
+	}
+	if conf.MaxSize <= 0 {
+		conf.MaxSize = conf.AvgSize * 4
+	}
+	if conf.Window <= 0 {
+		conf.Window = 64
+	}
+	if conf.MinSize > conf.MaxSize {
+		return nil, errors.New("chunker: MinSize is bigger than MaxSize")
+	}
+
+	return &Chunker{
+		r:    bufio.NewReader(r),
+		conf: conf,
+		// use the high bits, they depend on every byte of the window.
+		mask: ^uint64(0) << uint(64-shift),
+		hash: NewRollingHash(conf.Window),
+	}, nil
+}
+
+// Next returns the next chunk, or io.EOF after the last chunk.
+func (c *Chunker) Next() (*Chunk, error) {
+	data := make([]byte, 0, c.conf.AvgSize)
+	c.hash.Reset()
+	for len(data) < c.conf.MaxSize {
+		b, err := c.r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+		h := c.hash.Roll(b)
+		if len(data) >= c.conf.MinSize && h&c.mask == 0 {
+			break
+		}
+	}
+	if len(data) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk := &Chunk{Offset: c.offset, Data: data}
+	c.offset += int64(len(data))
+	return chunk, nil
+}
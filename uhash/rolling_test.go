@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingHash(t *testing.T) {
+	data := []byte("huangjian MDGSF huangjian MDGSF")
+
+	rh := NewRollingHash(9)
+	hashes := make([]uint64, len(data))
+	for i, b := range data {
+		hashes[i] = rh.Roll(b)
+	}
+
+	// same window, same hash, whatever comes before it.
+	for i := 9; i <= len(data); i++ {
+		fresh := NewRollingHash(9)
+		fresh.Write(data[i-9 : i])
+		assert.Equal(t, fresh.Sum64(), hashes[i-1], "window %v", i)
+
+		// window shorter than size, hash of bytes so far.
+		longer := NewRollingHash(20)
+		longer.Write(data[i-9 : i])
+		assert.Equal(t, fresh.Sum64(), longer.Sum64(), "window %v", i)
+	}
+	assert.Equal(t, hashes[8], hashes[8+16], "they should be equal")
+	assert.NotEqual(t, hashes[8], hashes[9], "they should not be equal")
+
+	rh.Reset()
+	rh.Write(data[:9])
+	assert.Equal(t, hashes[8], rh.Sum64(), "they should be equal")
+}
+
+func readChunks(t *testing.T, data []byte, conf ChunkerConfig) []*Chunk {
+	chunker, err := NewChunker(bytes.NewReader(data), conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunks []*Chunk
+	for {
+		chunk, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestChunker(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	rand.New(rand.NewSource(1)).Read(data)
+
+	conf := ChunkerConfig{AvgSize: 4096}
+	chunks := readChunks(t, data, conf)
+
+	var joined []byte
+	for _, chunk := range chunks {
+		assert.Equal(t, int64(len(joined)), chunk.Offset, "they should be equal")
+		assert.Equal(t, true, len(chunk.Data) <= 4*4096, "they should be equal")
+		joined = append(joined, chunk.Data...)
+	}
+	assert.Equal(t, data, joined, "they should be equal")
+
+	// average should be about AvgSize + MinSize.
+	avg := len(data) / len(chunks)
+	assert.Equal(t, true, avg > 2048 && avg < 16384, "average chunk size %v", avg)
+
+	// insert some bytes at the beginning, most chunks stay the same.
+	modified := append([]byte("huangjian"), data...)
+	digests := make(map[string]bool)
+	for _, chunk := range chunks {
+		digest, _ := SHA256(chunk.Data)
+		digests[digest] = true
+	}
+	same := 0
+	modifiedChunks := readChunks(t, modified, conf)
+	for _, chunk := range modifiedChunks {
+		digest, _ := SHA256(chunk.Data)
+		if digests[digest] {
+			same++
+		}
+	}
+	assert.Equal(t, true, same >= len(modifiedChunks)-2, "same %v of %v", same, len(modifiedChunks))
+}
+
+func TestChunkerEmpty(t *testing.T) {
+	chunks := readChunks(t, nil, ChunkerConfig{})
+	assert.Equal(t, 0, len(chunks), "they should be equal")
+
+	_, err := NewChunker(bytes.NewReader(nil), ChunkerConfig{MinSize: 10, MaxSize: 5})
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
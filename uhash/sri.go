@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"hash"
+	"strings"
+)
+
+// sriAlgos supported Subresource Integrity algorithms, weakest first.
+var sriAlgos = []string{"sha256", "sha384", "sha512"}
+
+func newSRIHasher(algo string) hash.Hash {
+	switch algo {
+	case "sha256":
+		return sha256.New()
+	case "sha384":
+		return sha512.New384()
+	case "sha512":
+		return sha512.New()
+	}
+	return nil
+}
+
+/*
+SRI Returns Subresource Integrity metadata of data, like "sha384-<base64>".
+@param algo: sha256, sha384, sha512
+Returns empty string if algo is not supported.
+*/
+func SRI(algo string, data []byte) string {
+	algo = strings.ToLower(algo)
+	hasher := newSRIHasher(algo)
+	if hasher == nil {
+		return ""
+	}
+	hasher.Write(data)
+	return algo + "-" + base64.StdEncoding.EncodeToString(hasher.Sum(nil))
+}
+
+/*
+VerifySRI check data against integrity, the value of an html integrity
+attribute. integrity may contain several space separated hashes, like the
+browser only the strongest algorithm is used, and data matches if any hash
+of that algorithm matches. Unknown algorithms and options ("?...") are
+ignored.
+*/
+func VerifySRI(integrity string, data []byte) bool {
+	hashes := make(map[string][]string)
+	strongest := -1
+	for _, token := range strings.Fields(integrity) {
+		if idx := strings.Index(token, "?"); idx >= 0 {
+			token = token[:idx]
+		}
+		idx := strings.Index(token, "-")
+		if idx <= 0 {
+			continue
+		}
+		algo := strings.ToLower(token[:idx])
+		for i, name := range sriAlgos {
+			if name == algo {
+				hashes[algo] = append(hashes[algo], token[idx+1:])
+				if i > strongest {
+					strongest = i
+				}
+			}
+		}
+	}
+	if strongest < 0 {
+		return false
+	}
+
+	algo := sriAlgos[strongest]
+	expected := SRI(algo, data)[len(algo)+1:]
+	for _, digest := range hashes[algo] {
+		if subtle.ConstantTimeCompare([]byte(digest), []byte(expected)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSRI(t *testing.T) {
+	sri := SRI("sha384", []byte("alert('Hello, world.');"))
+	assert.Equal(t, "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO", sri, "they should be equal")
+
+	cmd := exec.Command("/bin/sh", "-c", `echo -n huangjian|openssl dgst -sha256 -binary|openssl base64 -A`)
+	output, err := cmd.Output()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "sha256-"+strings.TrimSpace(string(output)), SRI("SHA256", []byte("huangjian")), "they should be equal")
+}
+
+func TestSRIInvalid(t *testing.T) {
+	assert.Equal(t, "", SRI("md5", []byte("huangjian")), "they should be equal")
+}
+
+func TestVerifySRI(t *testing.T) {
+	data := []byte("huangjian")
+	sha256 := SRI("sha256", data)
+	sha512 := SRI("sha512", data)
+
+	assert.Equal(t, true, VerifySRI(sha256, data), "they should be equal")
+	assert.Equal(t, true, VerifySRI(sha512+"?foo", data), "they should be equal")
+	assert.Equal(t, false, VerifySRI(sha256, []byte("MDGSF")), "they should be equal")
+	assert.Equal(t, false, VerifySRI("", data), "they should be equal")
+	assert.Equal(t, false, VerifySRI("md5-abc", data), "they should be equal")
+
+	// only the strongest algorithm is used.
+	assert.Equal(t, false, VerifySRI(sha256+" sha512-invalid", data), "they should be equal")
+	assert.Equal(t, true, VerifySRI(sha256+" sha512-invalid "+sha512, data), "they should be equal")
+}
@@ -0,0 +1,118 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// HashingReader computes the digest of all data read through it.
+type HashingReader struct {
+	r      io.Reader
+	hasher hash.Hash
+	n      int64
+}
+
+/*
+NewHashingReader wrap r, data read from the returned reader is hashed with algo.
+@param algo: algorithms of GetDataDigest.
+*/
+func NewHashingReader(r io.Reader, algo string) (*HashingReader, error) {
+	hasher, err := NewHasher(algo)
+	if err != nil {
+		return nil, err
+	}
+	return &HashingReader{r: r, hasher: hasher}, nil
+}
+
+// Read satisfies the io.Reader interface.
+func (hr *HashingReader) Read(p []byte) (int, error) {
+	n, err := hr.r.Read(p)
+	if n > 0 {
+		hr.hasher.Write(p[:n])
+		hr.n += int64(n)
+	}
+	return n, err
+}
+
+// Sum returns the hex digest of data read so far.
+func (hr *HashingReader) Sum() string {
+	return hex.EncodeToString(hr.hasher.Sum(nil))
+}
+
+// SumBytes returns the raw digest of data read so far.
+func (hr *HashingReader) SumBytes() []byte {
+	return hr.hasher.Sum(nil)
+}
+
+// Count returns number of bytes read so far.
+func (hr *HashingReader) Count() int64 {
+	return hr.n
+}
+
+// HashingWriter computes the digest of all data written through it.
+type HashingWriter struct {
+	w      io.Writer
+	hasher hash.Hash
+	n      int64
+}
+
+/*
+NewHashingWriter wrap w, data written to the returned writer is hashed with algo.
+Only data accepted by w is hashed.
+@param algo: algorithms of GetDataDigest.
+*/
+func NewHashingWriter(w io.Writer, algo string) (*HashingWriter, error) {
+	hasher, err := NewHasher(algo)
+	if err != nil {
+		return nil, err
+	}
+	return &HashingWriter{w: w, hasher: hasher}, nil
+}
+
+// Write satisfies the io.Writer interface.
+func (hw *HashingWriter) Write(p []byte) (int, error) {
+	n, err := hw.w.Write(p)
+	if n > 0 {
+		hw.hasher.Write(p[:n])
+		hw.n += int64(n)
+	}
+	return n, err
+}
+
+// Sum returns the hex digest of data written so far.
+func (hw *HashingWriter) Sum() string {
+	return hex.EncodeToString(hw.hasher.Sum(nil))
+}
+
+// SumBytes returns the raw digest of data written so far.
+func (hw *HashingWriter) SumBytes() []byte {
+	return hw.hasher.Sum(nil)
+}
+
+// Count returns number of bytes written so far.
+func (hw *HashingWriter) Count() int64 {
+	return hw.n
+}
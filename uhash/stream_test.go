@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashingReader(t *testing.T) {
+	hr, err := NewHashingReader(strings.NewReader("huangjian"), "sha256")
+	assert.Equal(t, nil, err, "they should be equal")
+
+	content, err := ioutil.ReadAll(hr)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", string(content), "they should be equal")
+
+	expected, _ := SHA256([]byte("huangjian"))
+	assert.Equal(t, expected, hr.Sum(), "they should be equal")
+	assert.Equal(t, 32, len(hr.SumBytes()), "they should be equal")
+	assert.Equal(t, int64(9), hr.Count(), "they should be equal")
+}
+
+func TestHashingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	hw, err := NewHashingWriter(&buf, "md5")
+	assert.Equal(t, nil, err, "they should be equal")
+
+	io.Copy(hw, strings.NewReader("huangjian"))
+	assert.Equal(t, "huangjian", buf.String(), "they should be equal")
+
+	expected, _ := MD5([]byte("huangjian"))
+	assert.Equal(t, expected, hw.Sum(), "they should be equal")
+	assert.Equal(t, int64(9), hw.Count(), "they should be equal")
+}
+
+func TestHashingInvalid(t *testing.T) {
+	_, err := NewHashingReader(strings.NewReader(""), "invalid")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = NewHashingWriter(ioutil.Discard, "invalid")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
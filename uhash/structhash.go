@@ -0,0 +1,208 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const structHashMaxDepth = 64
+
+type structHashOptions struct {
+	algo string
+	tag  string
+}
+
+// StructHashOption configures StructHash.
+type StructHashOption func(*structHashOptions)
+
+// WithHashAlgo set the hash algorithm used by StructHash, default is sha256.
+// All algorithms of GetDataDigest are supported.
+func WithHashAlgo(algo string) StructHashOption {
+	return func(o *structHashOptions) {
+		o.algo = algo
+	}
+}
+
+// WithHashTag set the struct tag read by StructHash, default is "hash".
+// `hash:"-"` excludes a field, `hash:"name"` renames a field.
+func WithHashTag(tag string) StructHashOption {
+	return func(o *structHashOptions) {
+		o.tag = tag
+	}
+}
+
+/*
+StructHash Returns the hex digest of a canonical encoding of v.
+v can be any combination of structs, maps, slices, pointers and basic types.
+The encoding is deterministic: map keys and struct fields are sorted,
+numbers are formatted the same way whatever their type (int 1 and float 1.0
+are equal), unexported fields are ignored, and types which implement
+encoding.TextMarshaler (like time.Time) are encoded as their text.
+Channels and functions are not supported.
+*/
+func StructHash(v interface{}, opts ...StructHashOption) (digest string, err error) {
+	o := &structHashOptions{
+		algo: "sha256",
+		tag:  "hash",
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var buf bytes.Buffer
+	if err = canonicalEncode(&buf, reflect.ValueOf(v), o, 0); err != nil {
+		return
+	}
+	return GetDataDigest(buf.Bytes(), o.algo)
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func canonicalEncode(buf *bytes.Buffer, v reflect.Value, o *structHashOptions, depth int) error {
+	if depth > structHashMaxDepth {
+		return errors.New("structhash: exceeded max depth, maybe a cycle")
+	}
+
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.Quote(string(text)))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		buf.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.String:
+		buf.WriteString(strconv.Quote(v.String()))
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return canonicalEncode(buf, v.Elem(), o, depth+1)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := canonicalEncode(buf, v.Index(i), o, depth+1); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		fields := make([]canonicalField, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			var key bytes.Buffer
+			if err := canonicalEncode(&key, iter.Key(), o, depth+1); err != nil {
+				return err
+			}
+			fields = append(fields, canonicalField{key.String(), iter.Value()})
+		}
+		return encodeFields(buf, fields, o, depth)
+	case reflect.Struct:
+		t := v.Type()
+		fields := make([]canonicalField, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if len(sf.PkgPath) > 0 {
+				continue // unexported
+			}
+			name := sf.Name
+			if tag := sf.Tag.Get(o.tag); len(tag) > 0 {
+				tag = strings.Split(tag, ",")[0]
+				if tag == "-" {
+					continue
+				}
+				if len(tag) > 0 {
+					name = tag
+				}
+			}
+			fields = append(fields, canonicalField{strconv.Quote(name), v.Field(i)})
+		}
+		return encodeFields(buf, fields, o, depth)
+	default:
+		return fmt.Errorf("structhash: unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+type canonicalField struct {
+	key   string
+	value reflect.Value
+}
+
+func encodeFields(buf *bytes.Buffer, fields []canonicalField, o *structHashOptions, depth int) error {
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].key < fields[j].key
+	})
+	buf.WriteByte('{')
+	for i, field := range fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(field.key)
+		buf.WriteByte(':')
+		if err := canonicalEncode(buf, field.value, o, depth+1); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type structHashConfig struct {
+	Name     string
+	Port     int
+	Tags     []string
+	Labels   map[string]string
+	Updated  time.Time `hash:"-"`
+	Timeout  *time.Duration
+	internal int
+}
+
+func TestStructHash(t *testing.T) {
+	a := structHashConfig{
+		Name:     "huangjian",
+		Port:     8080,
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"x": "1", "y": "2", "z": "3"},
+		Updated:  time.Now(),
+		internal: 1,
+	}
+	b := a
+	b.Labels = map[string]string{"z": "3", "y": "2", "x": "1"}
+	b.Updated = time.Now().Add(time.Hour)
+	b.internal = 2
+
+	digestA, err := StructHash(a)
+	assert.Equal(t, nil, err, "they should be equal")
+	digestB, err := StructHash(&b)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, digestA, digestB, "they should be equal")
+	assert.Equal(t, 64, len(digestA), "they should be equal")
+
+	b.Port = 8081
+	digestB, _ = StructHash(b)
+	assert.NotEqual(t, digestA, digestB, "they should not be equal")
+}
+
+func TestStructHashNumbers(t *testing.T) {
+	a, _ := StructHash(map[string]interface{}{"n": 1})
+	b, _ := StructHash(map[string]interface{}{"n": 1.0})
+	c, _ := StructHash(map[string]interface{}{"n": uint8(1)})
+	assert.Equal(t, a, b, "they should be equal")
+	assert.Equal(t, a, c, "they should be equal")
+}
+
+func TestStructHashOptions(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Note string `json:"-"`
+	}
+
+	a, _ := StructHash(item{1, "a"}, WithHashTag("json"))
+	b, _ := StructHash(item{1, "b"}, WithHashTag("json"))
+	assert.Equal(t, a, b, "they should be equal")
+
+	c, _ := StructHash(map[string]int{"id": 1}, WithHashTag("json"))
+	assert.Equal(t, a, c, "they should be equal")
+
+	d, err := StructHash(item{1, "a"}, WithHashAlgo("md5"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 32, len(d), "they should be equal")
+}
+
+func TestStructHashInvalid(t *testing.T) {
+	_, err := StructHash(map[string]interface{}{"f": func() {}})
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	_, err = StructHash(1, WithHashAlgo("invalid"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+	_, err = StructHash(n)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhealth
+
+import (
+	"net/http"
+
+	"github.com/MDGSF/utils/uhttp"
+)
+
+/*
+Handler returns an http.Handler reporting the checks of kind as JSON,
+with status 200 when all are up and 503 otherwise:
+
+	{"status":"down","checks":{"db":{"status":"down","error":"context deadline exceeded","duration":"1s","time":"..."}}}
+
+Mount one per kind for orchestrators:
+
+	mux.Handle("/livez", reg.Handler(uhealth.Liveness))
+	mux.Handle("/readyz", reg.Handler(uhealth.Readiness))
+*/
+func (r *Registry) Handler(kind Kind) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var report *Report
+		if r.running() {
+			report = r.Last(kind)
+		} else {
+			report = r.Check(req.Context(), kind)
+		}
+
+		status := http.StatusOK
+		if report.Status != StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		uhttp.WriteJSON(w, status, report)
+	})
+}
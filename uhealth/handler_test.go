@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhealth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	reg := New()
+	reg.Register("db", Readiness, func(ctx context.Context) error { return errors.New("down") })
+	reg.Register("loop", Liveness, func(ctx context.Context) error { return nil })
+
+	rec := httptest.NewRecorder()
+	reg.Handler(Liveness).ServeHTTP(rec, httptest.NewRequest("GET", "/livez", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "they should be equal")
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"), "they should be equal")
+
+	rec = httptest.NewRecorder()
+	reg.Handler(Readiness).ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "they should be equal")
+	var report Report
+	assert.Equal(t, nil, json.Unmarshal(rec.Body.Bytes(), &report), "they should be equal")
+	assert.Equal(t, StatusDown, report.Status, "they should be equal")
+	assert.Equal(t, "down", report.Checks["db"].Error, "they should be equal")
+}
+
+func TestHandlerBackground(t *testing.T) {
+	reg := New()
+	var calls int32
+	reg.Register("db", Readiness, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	reg.Start(time.Hour)
+	defer reg.Stop()
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		reg.Handler(All).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+		assert.Equal(t, http.StatusOK, rec.Code, "they should be equal")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "they should be equal")
+}
@@ -0,0 +1,361 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhealth
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/uerrors"
+)
+
+// Status is the status of a check or a report.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Kind tells what a check is about.
+type Kind int
+
+const (
+	// Liveness checks fail when the process must be restarted.
+	Liveness Kind = 1 << iota
+	// Readiness checks fail when the process can not serve traffic for
+	// now, e.g. its database is unreachable.
+	Readiness
+	// All selects both kinds.
+	All = Liveness | Readiness
+)
+
+// DefaultTimeout is the timeout of checks.
+const DefaultTimeout = 5 * time.Second
+
+// DefaultInterval is used by Start when the interval is not positive.
+const DefaultInterval = 10 * time.Second
+
+// CheckFunc checks a component, returning nil when it is healthy. It
+// should give up when ctx is done.
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult is the outcome of a check.
+type CheckResult struct {
+	Status   Status
+	Error    string
+	Duration time.Duration
+	Time     time.Time
+}
+
+// checkResultJSON is the JSON form of CheckResult.
+type checkResultJSON struct {
+	Status   Status    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration"`
+	Time     time.Time `json:"time"`
+}
+
+// MarshalJSON writes the duration as a string like "1.5ms".
+func (r CheckResult) MarshalJSON() ([]byte, error) {
+	return json.Marshal(checkResultJSON{r.Status, r.Error, r.Duration.String(), r.Time})
+}
+
+// UnmarshalJSON reads results written by MarshalJSON, to aggregate the
+// reports of other services.
+func (r *CheckResult) UnmarshalJSON(data []byte) error {
+	var v checkResultJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	d, err := time.ParseDuration(v.Duration)
+	if err != nil && v.Duration != "" {
+		return err
+	}
+	*r = CheckResult{Status: v.Status, Error: v.Error, Duration: d, Time: v.Time}
+	return nil
+}
+
+// Report aggregates the results of checks, it is down when any is down.
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+type check struct {
+	name    string
+	fn      CheckFunc
+	kind    Kind
+	timeout time.Duration
+}
+
+type options struct {
+	timeout time.Duration
+	logger  *log.Logger
+}
+
+// Option configures a Registry.
+type Option func(*options)
+
+// WithDefaultTimeout sets the timeout of checks registered without
+// WithTimeout, default is DefaultTimeout.
+func WithDefaultTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithLogger logs the checks going down and up again to logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// CheckOption configures a check.
+type CheckOption func(*check)
+
+// WithTimeout sets the timeout of a check, it is down when exceeded.
+func WithTimeout(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// Registry holds the health checks of the components of a process.
+type Registry struct {
+	opts *options
+
+	lock    sync.Mutex
+	checks  map[string]*check
+	results map[string]CheckResult
+	// background is true while Start runs, the handlers then serve the
+	// last results instead of running the checks.
+	background bool
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// New returns an empty Registry.
+func New(opts ...Option) *Registry {
+	o := &options{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Registry{
+		opts:    o,
+		checks:  make(map[string]*check),
+		results: make(map[string]CheckResult),
+	}
+}
+
+/*
+Register adds the check name of kind, replacing the one of the same name.
+
+	reg.Register("db", uhealth.Readiness, func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}, uhealth.WithTimeout(time.Second))
+*/
+func (r *Registry) Register(name string, kind Kind, fn CheckFunc, opts ...CheckOption) {
+	c := &check{name: name, fn: fn, kind: kind, timeout: r.opts.timeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.checks[name] = c
+	delete(r.results, name)
+}
+
+// Unregister removes the check name.
+func (r *Registry) Unregister(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.checks, name)
+	delete(r.results, name)
+}
+
+func (r *Registry) selectChecks(kind Kind) []*check {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var checks []*check
+	for _, c := range r.checks {
+		if c.kind&kind != 0 {
+			checks = append(checks, c)
+		}
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].name < checks[j].name })
+	return checks
+}
+
+// runCheck runs c within its timeout, a panic counts as a failure.
+func runCheck(ctx context.Context, c *check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- uerrors.Safe(func() error { return c.fn(ctx) })
+	}()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := CheckResult{Status: StatusUp, Duration: time.Since(start), Time: start}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Check runs the checks of kind concurrently and returns their report.
+func (r *Registry) Check(ctx context.Context, kind Kind) *Report {
+	checks := r.selectChecks(kind)
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	r.lock.Lock()
+	for i, c := range checks {
+		if r.checks[c.name] != c {
+			// unregistered or replaced meanwhile
+			continue
+		}
+		r.logChange(c.name, r.results[c.name], results[i])
+		r.results[c.name] = results[i]
+	}
+	r.lock.Unlock()
+
+	report := &Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+// logChange logs the transitions of a check, r.lock is held.
+func (r *Registry) logChange(name string, prev, cur CheckResult) {
+	if r.opts.logger == nil {
+		return
+	}
+	switch {
+	case cur.Status == StatusDown && prev.Status != StatusDown:
+		r.opts.logger.Warnf("uhealth: check %s is down: %s", name, cur.Error)
+	case cur.Status == StatusUp && prev.Status == StatusDown:
+		r.opts.logger.Infof("uhealth: check %s is up again", name)
+	}
+}
+
+// Last returns the report of the last results of the checks of kind,
+// checks not run yet are down.
+func (r *Registry) Last(kind Kind) *Report {
+	checks := r.selectChecks(kind)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	report := &Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checks))}
+	for _, c := range checks {
+		res, ok := r.results[c.name]
+		if !ok {
+			res = CheckResult{Status: StatusDown, Error: "not checked yet"}
+		}
+		report.Checks[c.name] = res
+		if res.Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+/*
+Start runs all the checks every interval in the background until Stop,
+the first time right away. Meanwhile the handlers serve the last results,
+so probes are cheap and do not load the checked components. An interval
+<= 0 means DefaultInterval.
+*/
+func (r *Registry) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	r.lock.Lock()
+	if r.background {
+		r.lock.Unlock()
+		return
+	}
+	r.background = true
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	stop, done := r.stop, r.done
+	r.lock.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			r.Check(context.Background(), All)
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks started by Start and waits for them.
+func (r *Registry) Stop() {
+	r.lock.Lock()
+	if !r.background {
+		r.lock.Unlock()
+		return
+	}
+	r.background = false
+	close(r.stop)
+	done := r.done
+	r.lock.Unlock()
+	<-done
+}
+
+func (r *Registry) running() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.background
+}
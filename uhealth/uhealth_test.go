@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhealth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func up(ctx context.Context) error { return nil }
+
+func TestCheck(t *testing.T) {
+	reg := New(WithDefaultTimeout(50 * time.Millisecond))
+	reg.Register("db", Readiness, up)
+	reg.Register("deadlock", Liveness, up)
+	reg.Register("cache", Readiness, func(ctx context.Context) error { return errors.New("connection refused") })
+
+	report := reg.Check(context.Background(), Liveness)
+	assert.Equal(t, StatusUp, report.Status, "they should be equal")
+	assert.Equal(t, 1, len(report.Checks), "they should be equal")
+
+	report = reg.Check(context.Background(), Readiness)
+	assert.Equal(t, StatusDown, report.Status, "they should be equal")
+	assert.Equal(t, StatusUp, report.Checks["db"].Status, "they should be equal")
+	assert.Equal(t, "connection refused", report.Checks["cache"].Error, "they should be equal")
+
+	reg.Unregister("cache")
+	report = reg.Check(context.Background(), All)
+	assert.Equal(t, StatusUp, report.Status, "they should be equal")
+	assert.Equal(t, 2, len(report.Checks), "they should be equal")
+}
+
+func TestCheckTimeoutPanic(t *testing.T) {
+	reg := New()
+	reg.Register("slow", Readiness, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}, WithTimeout(20*time.Millisecond))
+	reg.Register("panic", Readiness, func(ctx context.Context) error {
+		panic("huangjian")
+	})
+
+	start := time.Now()
+	report := reg.Check(context.Background(), All)
+	assert.Equal(t, true, time.Since(start) < 500*time.Millisecond, "they should be equal")
+	assert.Equal(t, "context deadline exceeded", report.Checks["slow"].Error, "they should be equal")
+	assert.Equal(t, "panic: huangjian", report.Checks["panic"].Error, "they should be equal")
+}
+
+func TestLastAndLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", "", 0, log.InfoLevel, log.NotTerminal)
+	reg := New(WithLogger(logger))
+	var healthy int32
+	reg.Register("MDGSF", Readiness, func(ctx context.Context) error {
+		if atomic.LoadInt32(&healthy) == 0 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+
+	report := reg.Last(All)
+	assert.Equal(t, "not checked yet", report.Checks["MDGSF"].Error, "they should be equal")
+
+	reg.Check(context.Background(), All)
+	reg.Check(context.Background(), All)
+	atomic.StoreInt32(&healthy, 1)
+	reg.Check(context.Background(), All)
+	assert.Equal(t, StatusUp, reg.Last(All).Status, "they should be equal")
+	assert.Equal(t, "uhealth: check MDGSF is down: not ready\nuhealth: check MDGSF is up again\n", buf.String(), "they should be equal")
+}
+
+func TestStart(t *testing.T) {
+	reg := New()
+	var calls int32
+	reg.Register("db", Readiness, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	reg.Start(10 * time.Millisecond)
+	reg.Start(10 * time.Millisecond)
+	time.Sleep(55 * time.Millisecond)
+	reg.Stop()
+	reg.Stop()
+	n := atomic.LoadInt32(&calls)
+	assert.Equal(t, true, n >= 3, "they should be equal")
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, n, atomic.LoadInt32(&calls), "they should be equal")
+}
+
+func TestStartZeroInterval(t *testing.T) {
+	reg := New()
+	var calls int32
+	reg.Register("db", Readiness, func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+	// falls back to DefaultInterval instead of panicking in the ticker
+	reg.Start(0)
+	time.Sleep(20 * time.Millisecond)
+	reg.Stop()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "they should be equal")
+}
+
+func TestCheckResultJSON(t *testing.T) {
+	res := CheckResult{Status: StatusDown, Error: "x", Duration: 1500 * time.Microsecond, Time: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)}
+	data, err := json.Marshal(res)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"status":"down","error":"x","duration":"1.5ms","time":"2019-01-02T03:04:05Z"}`, string(data), "they should be equal")
+	var back CheckResult
+	assert.Equal(t, nil, json.Unmarshal(data, &back), "they should be equal")
+	assert.Equal(t, res, back, "they should be equal")
+	assert.NotEqual(t, nil, json.Unmarshal([]byte(`{"duration":"x"}`), &back), "they should not be equal")
+
+	data, _ = json.Marshal(CheckResult{Status: StatusUp})
+	assert.Equal(t, true, strings.HasPrefix(string(data), `{"status":"up","duration":"0s"`), "they should be equal")
+}
@@ -0,0 +1,314 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/uretry"
+)
+
+// DefaultMaxAttempts is the number of attempts when WithMaxAttempts is not
+// given.
+const DefaultMaxAttempts = 3
+
+// IdempotencyKeyHeader marks a request as safe to retry whatever its method.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type clientOptions struct {
+	client         *http.Client
+	maxAttempts    int
+	backoff        uretry.Backoff
+	attemptTimeout time.Duration
+	maxRetryAfter  time.Duration
+	retryAll       bool
+	retryIf        func(resp *http.Response, err error) bool
+	logger         *log.Logger
+}
+
+// ClientOption configures a Client.
+type ClientOption func(*clientOptions)
+
+// WithHTTPClient set the underlying client, default http.DefaultClient.
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.client = c
+	}
+}
+
+// WithMaxAttempts set the max number of attempts including the first one.
+func WithMaxAttempts(n int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// WithBackoff set the delay between attempts, default exponential from
+// 100ms to 10s with jitter. A Retry-After header takes precedence.
+func WithBackoff(b uretry.Backoff) ClientOption {
+	return func(o *clientOptions) {
+		o.backoff = b
+	}
+}
+
+// WithAttemptTimeout limits every attempt to d, including reading the
+// response body.
+func WithAttemptTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.attemptTimeout = d
+	}
+}
+
+// WithMaxRetryAfter caps the wait asked by a Retry-After header, default
+// one minute. A longer wait is not done and the response is returned.
+func WithMaxRetryAfter(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.maxRetryAfter = d
+	}
+}
+
+// WithRetryNonIdempotent also retries POST and PATCH requests which have no
+// Idempotency-Key header.
+func WithRetryNonIdempotent() ClientOption {
+	return func(o *clientOptions) {
+		o.retryAll = true
+	}
+}
+
+// WithRetryIf replaces DefaultRetryIf.
+func WithRetryIf(fn func(resp *http.Response, err error) bool) ClientOption {
+	return func(o *clientOptions) {
+		o.retryIf = fn
+	}
+}
+
+// WithLogger logs requests and responses at debug level and retries at warn
+// level to logger.
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = logger
+	}
+}
+
+// DefaultRetryIf retries transport errors, 429 Too Many Requests and 5xx
+// responses except 501 Not Implemented.
+func DefaultRetryIf(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+/*
+Client wraps http.Client with retries. Only idempotent requests are
+retried: GET, HEAD, OPTIONS, TRACE, PUT, DELETE, and requests carrying an
+Idempotency-Key header, unless WithRetryNonIdempotent is given.
+
+When the attempts are used up on a retryable status the last response is
+returned with a nil error, like http.Client does for any status.
+*/
+type Client struct {
+	opts clientOptions
+}
+
+// NewClient create a retrying client.
+func NewClient(opts ...ClientOption) *Client {
+	o := clientOptions{
+		client:        http.DefaultClient,
+		maxAttempts:   DefaultMaxAttempts,
+		backoff:       uretry.JitterBackoff(uretry.ExponentialBackoff(100*time.Millisecond, 10*time.Second)),
+		maxRetryAfter: time.Minute,
+		retryIf:       DefaultRetryIf,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Client{opts: o}
+}
+
+// Get issues a GET request.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post issues a POST request, it is only retried with WithRetryNonIdempotent.
+func (c *Client) Post(ctx context.Context, url, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	return c.Do(req)
+}
+
+func (c *Client) retryable(req *http.Request) bool {
+	if c.opts.retryAll || req.Header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// Do sends req, retrying as configured. The body of req is buffered in
+// memory unless req.GetBody is set.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	maxAttempts := c.opts.maxAttempts
+	if !c.retryable(req) || maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	if maxAttempts > 1 && req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(data)), nil
+		}
+		req.Body, _ = req.GetBody()
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(req, attempt)
+		if attempt >= maxAttempts || !c.opts.retryIf(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		delay := c.opts.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				if after > c.opts.maxRetryAfter {
+					return resp, nil
+				}
+				delay = after
+			}
+			// drain so the connection can be reused
+			io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if c.opts.logger != nil {
+			reason := fmt.Sprint(err)
+			if err == nil {
+				reason = resp.Status
+			}
+			c.opts.logger.Warn("uhttp: %s %s attempt %d failed: %s, retrying in %v",
+				req.Method, req.URL, attempt, reason, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *Client) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if c.opts.attemptTimeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), c.opts.attemptTimeout)
+		req = req.WithContext(ctx)
+	}
+
+	start := time.Now()
+	resp, err := c.opts.client.Do(req)
+	if c.opts.logger != nil {
+		if err != nil {
+			c.opts.logger.Debug("uhttp: %s %s failed after %v: %v", req.Method, req.URL, time.Since(start), err)
+		} else {
+			c.opts.logger.Debug("uhttp: %s %s -> %d in %v", req.Method, req.URL, resp.StatusCode, time.Since(start))
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the timeout also covers reading the body
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// retryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
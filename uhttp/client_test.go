@@ -0,0 +1,203 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/uretry"
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+// flakyServer fails the first failures requests with status.
+func flakyServer(failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write([]byte("huangjian " + string(body)))
+	}))
+	return server, &calls
+}
+
+var fastBackoff = WithBackoff(uretry.FixedBackoff(time.Millisecond))
+
+func TestClientRetry(t *testing.T) {
+	server, calls := flakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	logger := log.NewDefaultLog()
+	buf := &syncBuffer{}
+	logger.SetOutput(buf)
+	c := NewClient(fastBackoff, WithLogger(logger))
+	resp, err := c.Get(context.Background(), server.URL)
+	assert.Equal(t, nil, err, "they should be equal")
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "huangjian ", string(body), "they should be equal")
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls), "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "attempt 2 failed: 503"), "they should be equal")
+}
+
+func TestClientGivesUp(t *testing.T) {
+	server, calls := flakyServer(10, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	resp, err := NewClient(fastBackoff, WithMaxAttempts(4)).Get(context.Background(), server.URL)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode, "they should be equal")
+	resp.Body.Close()
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls), "they should be equal")
+
+	// not retryable
+	server2, calls2 := flakyServer(10, http.StatusNotFound, nil)
+	defer server2.Close()
+	resp, _ = NewClient(fastBackoff).Get(context.Background(), server2.URL)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "they should be equal")
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls2), "they should be equal")
+}
+
+func TestClientIdempotency(t *testing.T) {
+	server, calls := flakyServer(1, http.StatusInternalServerError, nil)
+	defer server.Close()
+
+	c := NewClient(fastBackoff)
+	resp, err := c.Post(context.Background(), server.URL, "text/plain", strings.NewReader("MDGSF"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode, "they should be equal")
+	resp.Body.Close()
+
+	// the body is replayed on retry
+	atomic.StoreInt32(calls, 0)
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("MDGSF"))
+	req.Header.Set(IdempotencyKeyHeader, "huangjian")
+	resp, err = c.Do(req)
+	assert.Equal(t, nil, err, "they should be equal")
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "huangjian MDGSF", string(body), "they should be equal")
+
+	atomic.StoreInt32(calls, 0)
+	resp, err = NewClient(fastBackoff, WithRetryNonIdempotent()).Post(context.Background(), server.URL, "text/plain", strings.NewReader("MDGSF"))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "they should be equal")
+	resp.Body.Close()
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	header := http.Header{"Retry-After": []string{"1"}}
+	server, calls := flakyServer(1, http.StatusTooManyRequests, header)
+	defer server.Close()
+
+	start := time.Now()
+	resp, err := NewClient(fastBackoff).Get(context.Background(), server.URL)
+	assert.Equal(t, nil, err, "they should be equal")
+	resp.Body.Close()
+	assert.Equal(t, true, time.Since(start) >= time.Second, "they should be equal")
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls), "they should be equal")
+
+	// waits longer than the cap are not done
+	atomic.StoreInt32(calls, 0)
+	resp, err = NewClient(fastBackoff, WithMaxRetryAfter(time.Millisecond)).Get(context.Background(), server.URL)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "they should be equal")
+	resp.Body.Close()
+}
+
+func TestClientTimeout(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte("huangjian"))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(fastBackoff, WithAttemptTimeout(50*time.Millisecond)).Get(context.Background(), server.URL)
+	assert.Equal(t, nil, err, "they should be equal")
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", string(body), "they should be equal")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "they should be equal")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewClient(fastBackoff).Get(ctx, server.URL)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter("120")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 2*time.Minute, d, "they should be equal")
+
+	d, ok = retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, true, d > 59*time.Minute, "they should be equal")
+
+	for _, value := range []string{"", "-1", "huangjian"} {
+		_, ok = retryAfter(value)
+		assert.Equal(t, false, ok, value)
+	}
+}
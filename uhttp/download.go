@@ -0,0 +1,290 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/MDGSF/utils/uhash"
+)
+
+// PartSuffix is appended to the destination of a download in progress.
+const PartSuffix = ".part"
+
+// validatorSuffix is appended to the part file to name the file holding
+// the ETag or Last-Modified of the download, sent in If-Range on resume.
+const validatorSuffix = ".validator"
+
+// ErrChecksumMismatch is returned when a download does not match
+// DownloadOptions.Checksum.
+var ErrChecksumMismatch = errors.New("uhttp: checksum mismatch")
+
+// DownloadOptions configures Download, the zero value is usable.
+type DownloadOptions struct {
+	// Client sends the requests, nil means NewClient().
+	Client *Client
+	// Header is added to the requests.
+	Header http.Header
+	// Checksum is the expected hex digest of the file, computed with
+	// ChecksumAlgo (see uhash.NewHasher, default sha256). Empty skips the
+	// check.
+	Checksum     string
+	ChecksumAlgo string
+	// Progress is called as data arrives, total is -1 if unknown.
+	Progress func(done, total int64)
+	// NoResume always starts from scratch.
+	NoResume bool
+}
+
+/*
+Download fetches url to dest. Data goes to dest + PartSuffix first, which
+is renamed to dest once complete and verified, so dest never holds a partial
+file. A previous partial download is resumed with a Range request when the
+server supports it, with an If-Range of the ETag or Last-Modified of the
+first response so a changed file is downloaded again from the start. Without
+either, the download only resumes when a Checksum is given.
+*/
+func Download(ctx context.Context, url, dest string, opts DownloadOptions) error {
+	client := opts.Client
+	if client == nil {
+		client = NewClient()
+	}
+	var hasher hash.Hash
+	if opts.Checksum != "" {
+		algo := opts.ChecksumAlgo
+		if algo == "" {
+			algo = "sha256"
+		}
+		var err error
+		if hasher, err = uhash.NewHasher(algo); err != nil {
+			return err
+		}
+	}
+
+	part := dest + PartSuffix
+	validatorFile := part + validatorSuffix
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	var offset int64
+	var validator string
+	if opts.NoResume {
+		os.Remove(part)
+		os.Remove(validatorFile)
+	} else if info, err := os.Stat(part); err == nil {
+		offset = info.Size()
+		if data, err := ioutil.ReadFile(validatorFile); err == nil {
+			validator = string(data)
+		}
+		if validator == "" && opts.Checksum == "" {
+			// nothing tells whether the remote file changed
+			offset = 0
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range opts.Header {
+		req.Header[k] = v
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator != "" {
+			req.Header.Set("If-Range", validator)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	total := int64(-1)
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return fmt.Errorf("uhttp: unexpected Content-Range %q", resp.Header.Get("Content-Range"))
+		}
+		total = size
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the part file may already hold everything
+		_, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || size != offset {
+			os.Remove(part)
+			return fmt.Errorf("uhttp: download %s: %s", url, resp.Status)
+		}
+		total = size
+	case resp.StatusCode == http.StatusOK:
+		// no range support or the file changed, start over
+		offset = 0
+		total = resp.ContentLength
+	default:
+		return fmt.Errorf("uhttp: download %s: %s", url, resp.Status)
+	}
+	if offset == 0 {
+		if err := writeValidator(validatorFile, resp.Header); err != nil {
+			return err
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(part, flags, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if hasher != nil && offset > 0 {
+		if err := hashPrefix(hasher, part, offset); err != nil {
+			return err
+		}
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		w := io.Writer(f)
+		if hasher != nil {
+			w = io.MultiWriter(f, hasher)
+		}
+		pw := &progressWriter{w: w, done: offset, total: total, fn: opts.Progress}
+		if _, err := io.Copy(pw, resp.Body); err != nil {
+			return err
+		}
+		if total >= 0 && pw.done != total {
+			return fmt.Errorf("uhttp: download %s: got %d of %d bytes", url, pw.done, total)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if hasher != nil {
+		sum := fmt.Sprintf("%x", hasher.Sum(nil))
+		if !strings.EqualFold(sum, opts.Checksum) {
+			os.Remove(part)
+			os.Remove(validatorFile)
+			return fmt.Errorf("%w: got %s, want %s", ErrChecksumMismatch, sum, opts.Checksum)
+		}
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return err
+	}
+	os.Remove(validatorFile)
+	return nil
+}
+
+// writeValidator saves the strong ETag, or else the Last-Modified, of
+// header to path. Weak ETags can not be used in If-Range.
+func writeValidator(path string, header http.Header) error {
+	validator := header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = header.Get("Last-Modified")
+	}
+	if validator == "" {
+		os.Remove(path)
+		return nil
+	}
+	return ioutil.WriteFile(path, []byte(validator), 0644)
+}
+
+func hashPrefix(h hash.Hash, path string, n int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyN(h, f, n)
+	return err
+}
+
+// parseContentRange parses "bytes start-end/size" and "bytes */size", size
+// is -1 if it is "*".
+func parseContentRange(value string) (start, size int64, ok bool) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "bytes ") {
+		return 0, 0, false
+	}
+	value = value[len("bytes "):]
+	slash := strings.IndexByte(value, '/')
+	if slash < 0 {
+		return 0, 0, false
+	}
+	rng, sizeStr := value[:slash], value[slash+1:]
+
+	size = -1
+	if sizeStr != "*" {
+		var err error
+		if size, err = strconv.ParseInt(sizeStr, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	if rng == "*" {
+		return 0, size, true
+	}
+	dash := strings.IndexByte(rng, '-')
+	if dash < 0 {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(rng[:dash], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, size, true
+}
+
+type progressWriter struct {
+	w     io.Writer
+	done  int64
+	total int64
+	fn    func(done, total int64)
+}
+
+func (pw *progressWriter) Write(p []byte) (int, error) {
+	n, err := pw.w.Write(p)
+	pw.done += int64(n)
+	if pw.fn != nil && n > 0 {
+		pw.fn(pw.done, pw.total)
+	}
+	return n, err
+}
@@ -0,0 +1,234 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var downloadData = []byte(strings.Repeat("huangjian MDGSF ", 4096))
+
+func downloadServer(ranges bool) (*httptest.Server, func() []string) {
+	var lock sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		seen = append(seen, r.Header.Get("Range"))
+		lock.Unlock()
+		if !ranges {
+			w.Write(downloadData)
+			return
+		}
+		http.ServeContent(w, r, "data", time.Time{}, bytes.NewReader(downloadData))
+	}))
+	return server, func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
+
+func downloadDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "uhttp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestDownload(t *testing.T) {
+	server, _ := downloadServer(true)
+	defer server.Close()
+	dest := filepath.Join(downloadDir(t), "sub", "huangjian")
+
+	var last, total int64
+	err := Download(context.Background(), server.URL, dest, DownloadOptions{
+		Checksum: fmt.Sprintf("%X", sha256.Sum256(downloadData)),
+		Progress: func(done, size int64) {
+			last, total = done, size
+		},
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	data, _ := ioutil.ReadFile(dest)
+	assert.Equal(t, downloadData, data, "they should be equal")
+	assert.Equal(t, int64(len(downloadData)), last, "they should be equal")
+	assert.Equal(t, int64(len(downloadData)), total, "they should be equal")
+	_, err = os.Stat(dest + PartSuffix)
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+}
+
+func TestDownloadResume(t *testing.T) {
+	server, seen := downloadServer(true)
+	defer server.Close()
+	dest := filepath.Join(downloadDir(t), "huangjian")
+	ioutil.WriteFile(dest+PartSuffix, downloadData[:1000], 0644)
+
+	var first int64 = -1
+	err := Download(context.Background(), server.URL, dest, DownloadOptions{
+		Checksum:     fmt.Sprintf("%x", sha256.Sum256(downloadData)),
+		ChecksumAlgo: "SHA256",
+		Progress: func(done, size int64) {
+			if first < 0 {
+				first = done
+			}
+		},
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	data, _ := ioutil.ReadFile(dest)
+	assert.Equal(t, downloadData, data, "they should be equal")
+	assert.Equal(t, []string{"bytes=1000-"}, seen(), "they should be equal")
+	assert.Equal(t, true, first > 1000, "they should be equal")
+
+	// a complete part file only needs the rename
+	ioutil.WriteFile(dest+PartSuffix, downloadData, 0644)
+	os.Remove(dest)
+	assert.Equal(t, nil, Download(context.Background(), server.URL, dest, DownloadOptions{}), "they should be equal")
+	data, _ = ioutil.ReadFile(dest)
+	assert.Equal(t, downloadData, data, "they should be equal")
+}
+
+func TestDownloadResumeChanged(t *testing.T) {
+	var lock sync.Mutex
+	content, etag, abort := downloadData, `"v1"`, true
+	var ifRanges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		data, tag, cut := content, etag, abort
+		abort = false
+		ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+		lock.Unlock()
+		w.Header().Set("ETag", tag)
+		if cut {
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data[:1000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "data", time.Time{}, bytes.NewReader(data))
+	}))
+	defer server.Close()
+	dest := filepath.Join(downloadDir(t), "huangjian")
+
+	assert.NotEqual(t, nil, Download(context.Background(), server.URL, dest, DownloadOptions{}), "they should not be equal")
+	info, err := os.Stat(dest + PartSuffix)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, int64(1000), info.Size(), "they should be equal")
+
+	lock.Lock()
+	content, etag = bytes.ToUpper(downloadData), `"v2"`
+	lock.Unlock()
+	assert.Equal(t, nil, Download(context.Background(), server.URL, dest, DownloadOptions{}), "they should be equal")
+	data, _ := ioutil.ReadFile(dest)
+	assert.Equal(t, bytes.ToUpper(downloadData), data, "they should be equal")
+	assert.Equal(t, []string{"", `"v1"`}, ifRanges, "they should be equal")
+	_, err = os.Stat(dest + PartSuffix + validatorSuffix)
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+
+	// unchanged, the download resumes
+	lock.Lock()
+	abort = true
+	lock.Unlock()
+	os.Remove(dest)
+	assert.NotEqual(t, nil, Download(context.Background(), server.URL, dest, DownloadOptions{}), "they should not be equal")
+	var first int64 = -1
+	err = Download(context.Background(), server.URL, dest, DownloadOptions{
+		Progress: func(done, size int64) {
+			if first < 0 {
+				first = done
+			}
+		},
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	data, _ = ioutil.ReadFile(dest)
+	assert.Equal(t, bytes.ToUpper(downloadData), data, "they should be equal")
+	assert.Equal(t, true, first > 1000, "they should be equal")
+}
+
+func TestDownloadNoRangeSupport(t *testing.T) {
+	server, _ := downloadServer(false)
+	defer server.Close()
+	dest := filepath.Join(downloadDir(t), "huangjian")
+	ioutil.WriteFile(dest+PartSuffix, []byte("garbage"), 0644)
+
+	assert.Equal(t, nil, Download(context.Background(), server.URL, dest, DownloadOptions{}), "they should be equal")
+	data, _ := ioutil.ReadFile(dest)
+	assert.Equal(t, downloadData, data, "they should be equal")
+}
+
+func TestDownloadErrors(t *testing.T) {
+	server, _ := downloadServer(true)
+	defer server.Close()
+	dest := filepath.Join(downloadDir(t), "huangjian")
+
+	err := Download(context.Background(), server.URL, dest, DownloadOptions{Checksum: "00"})
+	assert.Equal(t, true, errors.Is(err, ErrChecksumMismatch), "they should be equal")
+	_, err = os.Stat(dest)
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+	_, err = os.Stat(dest + PartSuffix)
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+
+	err = Download(context.Background(), server.URL, dest, DownloadOptions{Checksum: "00", ChecksumAlgo: "MDGSF"})
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+	err = Download(context.Background(), notFound.URL, dest, DownloadOptions{})
+	assert.Equal(t, true, strings.Contains(fmt.Sprint(err), "404"), "they should be equal")
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, size, ok := parseContentRange("bytes 100-199/1000")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, int64(100), start, "they should be equal")
+	assert.Equal(t, int64(1000), size, "they should be equal")
+
+	_, size, ok = parseContentRange("bytes */1000")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, int64(1000), size, "they should be equal")
+
+	_, size, ok = parseContentRange("bytes 0-9/*")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, int64(-1), size, "they should be equal")
+
+	for _, value := range []string{"", "bytes", "bytes 1-2", "items 1-2/3", "bytes x-2/3"} {
+		_, _, ok = parseContentRange(value)
+		assert.Equal(t, false, ok, value)
+	}
+}
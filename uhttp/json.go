@@ -0,0 +1,181 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxJSONBytes is the body limit of ReadJSON when maxBytes <= 0.
+const DefaultMaxJSONBytes = 1 << 20
+
+// RequestError is returned by ReadJSON, Status is the HTTP status to answer
+// with and Message is safe to show to the client.
+type RequestError struct {
+	Status  int
+	Message string
+}
+
+func (e *RequestError) Error() string {
+	return e.Message
+}
+
+/*
+ReadJSON decodes the JSON body of r into v, rejecting bodies larger than
+maxBytes, unknown fields and trailing data. Errors are *RequestError with a
+message which tells the client what is wrong:
+
+	var req CreateUser
+	if err := uhttp.ReadJSON(r, &req, 0); err != nil {
+		uhttp.WriteRequestError(w, err)
+		return
+	}
+*/
+func ReadJSON(r *http.Request, v interface{}, maxBytes int64) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, _ := mime.ParseMediaType(ct)
+		if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+			return &RequestError{http.StatusUnsupportedMediaType, "Content-Type must be application/json"}
+		}
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxJSONBytes
+	}
+
+	body := &limitedReader{r: r.Body, left: maxBytes}
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		if _, err = dec.Token(); err == io.EOF {
+			err = nil
+		} else {
+			err = &RequestError{http.StatusBadRequest, "body must contain a single JSON value"}
+		}
+	}
+	if body.exceeded {
+		return &RequestError{http.StatusRequestEntityTooLarge, fmt.Sprintf("body must not be larger than %d bytes", maxBytes)}
+	}
+	if err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// limitedReader reads up to left bytes and records whether there is more.
+type limitedReader struct {
+	r        io.Reader
+	left     int64
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.left <= 0 {
+		var one [1]byte
+		if n, _ := l.r.Read(one[:]); n > 0 {
+			l.exceeded = true
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.left {
+		p = p[:l.left]
+	}
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	return n, err
+}
+
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return &RequestError{http.StatusBadRequest, "body must not be empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &RequestError{http.StatusBadRequest, "body contains badly-formed JSON"}
+	case errors.As(err, &syntaxErr):
+		return &RequestError{http.StatusBadRequest, fmt.Sprintf("body contains badly-formed JSON at offset %d", syntaxErr.Offset)}
+	case errors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return &RequestError{http.StatusBadRequest, fmt.Sprintf("field %q must be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value)}
+		}
+		return &RequestError{http.StatusBadRequest, fmt.Sprintf("body must be %s, not %s", typeErr.Type, typeErr.Value)}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return &RequestError{http.StatusBadRequest, fmt.Sprintf("body contains unknown field %s", field)}
+	}
+	return &RequestError{http.StatusBadRequest, err.Error()}
+}
+
+// WriteJSON writes v as JSON with status.
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// ErrorBody is the error envelope written by WriteError:
+//
+//	{"error": {"code": "not_found", "message": "user huangjian not found"}}
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail is the content of ErrorBody.
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// WriteError writes an ErrorBody with status.
+func WriteError(w http.ResponseWriter, status int, code, msg string) error {
+	return WriteJSON(w, status, ErrorBody{ErrorDetail{Code: code, Message: msg}})
+}
+
+// WriteRequestError writes err returned by ReadJSON, other errors are
+// written as 500 without exposing their message.
+func WriteRequestError(w http.ResponseWriter, err error) error {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return WriteError(w, reqErr.Status, statusCode(reqErr.Status), reqErr.Message)
+	}
+	return WriteError(w, http.StatusInternalServerError, statusCode(http.StatusInternalServerError), http.StatusText(http.StatusInternalServerError))
+}
+
+// statusCode turns a status into an error code, 413 is
+// "request_entity_too_large".
+func statusCode(status int) string {
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_").Replace(http.StatusText(status)))
+}
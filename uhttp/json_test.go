@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func jsonRequest(body, contentType string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestReadJSON(t *testing.T) {
+	var u testUser
+	err := ReadJSON(jsonRequest(`{"name": "huangjian", "age": 18}`, "application/json; charset=utf-8"), &u, 0)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, testUser{"huangjian", 18}, u, "they should be equal")
+
+	err = ReadJSON(jsonRequest(`{"name": "MDGSF"}`, ""), &u, 0)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	tests := []struct {
+		body        string
+		contentType string
+		maxBytes    int64
+		status      int
+		message     string
+	}{
+		{`{}`, "text/plain", 0, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{``, "", 0, http.StatusBadRequest, "body must not be empty"},
+		{`{"name": "huangjian",}`, "", 0, http.StatusBadRequest, "body contains badly-formed JSON at offset 22"},
+		{`{"name": "huangjian"`, "", 0, http.StatusBadRequest, "body contains badly-formed JSON"},
+		{`{"age": "18"}`, "", 0, http.StatusBadRequest, `field "age" must be int, not string`},
+		{`[]`, "", 0, http.StatusBadRequest, "body must be uhttp.testUser, not array"},
+		{`{"email": "x"}`, "", 0, http.StatusBadRequest, `body contains unknown field "email"`},
+		{`{}{}`, "", 0, http.StatusBadRequest, "body must contain a single JSON value"},
+		{`{"name": "huangjian MDGSF"}`, "", 10, http.StatusRequestEntityTooLarge, "body must not be larger than 10 bytes"},
+		{`{"name": "h"}      `, "", 14, http.StatusRequestEntityTooLarge, "body must not be larger than 14 bytes"},
+	}
+	for _, tt := range tests {
+		err := ReadJSON(jsonRequest(tt.body, tt.contentType), &testUser{}, tt.maxBytes)
+		var reqErr *RequestError
+		assert.Equal(t, true, errors.As(err, &reqErr), tt.body)
+		if reqErr != nil {
+			assert.Equal(t, tt.status, reqErr.Status, tt.body)
+			assert.Equal(t, tt.message, reqErr.Message, tt.body)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.Equal(t, nil, WriteJSON(w, http.StatusCreated, testUser{"huangjian", 18}), "they should be equal")
+	assert.Equal(t, http.StatusCreated, w.Code, "they should be equal")
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"), "they should be equal")
+	assert.Equal(t, `{"name":"huangjian","age":18}`+"\n", w.Body.String(), "they should be equal")
+
+	w = httptest.NewRecorder()
+	assert.NotEqual(t, nil, WriteJSON(w, http.StatusOK, make(chan int)), "they should not be equal")
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "they should be equal")
+}
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, http.StatusNotFound, "not_found", "user huangjian not found")
+	assert.Equal(t, http.StatusNotFound, w.Code, "they should be equal")
+	assert.Equal(t, `{"error":{"code":"not_found","message":"user huangjian not found"}}`+"\n", w.Body.String(), "they should be equal")
+
+	w = httptest.NewRecorder()
+	WriteRequestError(w, ReadJSON(jsonRequest(`{"name": "huangjian MDGSF"}`, ""), &testUser{}, 10))
+	var body ErrorBody
+	json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code, "they should be equal")
+	assert.Equal(t, "request_entity_too_large", body.Error.Code, "they should be equal")
+
+	w = httptest.NewRecorder()
+	WriteRequestError(w, errors.New("MDGSF secret"))
+	json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "they should be equal")
+	assert.Equal(t, "Internal Server Error", body.Error.Message, "they should be equal")
+}
@@ -0,0 +1,409 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/ucompress"
+	"github.com/MDGSF/utils/uuid"
+)
+
+// Middleware wraps a handler.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with mws, the first one is the outermost:
+// Chain(h, a, b) is a(b(h)).
+func Chain(h http.Handler, mws ...Middleware) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	realIPKey
+)
+
+// RequestIDHeader carries the request id.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID takes the request id from the X-Request-ID header, or
+// generates one, stores it in the context and echoes it in the response.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if !validRequestID(id) {
+				id = uuid.MustNewV4().String()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+		})
+	}
+}
+
+// validRequestID accepts ids of reasonable length without control
+// characters, so they are safe to log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] >= 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDFromContext returns the id set by RequestID, or "".
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+/*
+RealIP finds the client address behind proxies from the X-Forwarded-For and
+X-Real-IP headers, get it with ClientIP. trustedProxies are IPs or CIDRs,
+headers are only believed when the peer is one of them, and X-Forwarded-For
+is walked from the right skipping trusted hops. Without trustedProxies
+every peer is trusted, which is only right behind a proxy overwriting the
+headers. It panics on an invalid proxy.
+*/
+func RealIP(trustedProxies ...string) Middleware {
+	var nets []*net.IPNet
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if strings.Contains(proxy, ":") {
+				proxy += "/128"
+			} else {
+				proxy += "/32"
+			}
+		}
+		_, n, err := net.ParseCIDR(proxy)
+		if err != nil {
+			panic(fmt.Sprintf("uhttp: invalid trusted proxy %q", proxy))
+		}
+		nets = append(nets, n)
+	}
+	trusted := func(ip net.IP) bool {
+		if len(nets) == 0 {
+			return true
+		}
+		for _, n := range nets {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := realIP(r, trusted); ip != "" {
+				r = r.WithContext(context.WithValue(r.Context(), realIPKey, ip))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func realIP(r *http.Request, trusted func(net.IP) bool) string {
+	peer := net.ParseIP(remoteHost(r.RemoteAddr))
+	if peer == nil || !trusted(peer) {
+		return ""
+	}
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			if i == 0 || !trusted(ip) {
+				return ip.String()
+			}
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return ""
+}
+
+func remoteHost(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// ClientIP returns the address found by RealIP, or the host of
+// r.RemoteAddr.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(realIPKey).(string); ok {
+		return ip
+	}
+	return remoteHost(r.RemoteAddr)
+}
+
+/*
+Gzip compresses responses of clients accepting gzip with level, see
+ucompress. Responses which already have a Content-Encoding, and HEAD, 204
+and 304 responses are left alone.
+*/
+func Gzip(level int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r) || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			gw := &gzipResponseWriter{ResponseWriter: w, level: level}
+			defer gw.close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		fields := strings.Split(part, ";")
+		if strings.TrimSpace(fields[0]) != "gzip" {
+			continue
+		}
+		if len(fields) > 1 {
+			q := strings.TrimSpace(fields[1])
+			if strings.HasPrefix(q, "q=") {
+				if v, err := strconv.ParseFloat(q[2:], 64); err == nil && v == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	level       int
+	gz          io.WriteCloser
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	h := w.Header()
+	if h.Get("Content-Encoding") == "" && status != http.StatusNoContent &&
+		status != http.StatusNotModified && status >= 200 {
+		gz, err := ucompress.NewCompressingWriter(w.ResponseWriter, ucompress.WithLevel(w.level))
+		if err == nil {
+			h.Set("Content-Encoding", "gzip")
+			h.Del("Content-Length")
+			w.gz = gz
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		// sniff before compressing, http can not do it on gzip data
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *gzipResponseWriter) Flush() {
+	if f, ok := w.gz.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		w.gz.Close()
+	}
+}
+
+// CORSOptions configures CORS.
+type CORSOptions struct {
+	// AllowedOrigins may contain "*", or patterns like
+	// "https://*.example.com". Origins only allowed by "*" get a literal
+	// "*" and never credentials.
+	AllowedOrigins []string
+	// AllowedMethods default to GET, POST, HEAD.
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// originAllowed tells whether origin is allowed by an entry other than
+// "*".
+func (o *CORSOptions) originAllowed(origin string) bool {
+	for _, allowed := range o.AllowedOrigins {
+		if allowed == "*" {
+			continue
+		}
+		if strings.EqualFold(allowed, origin) {
+			return true
+		}
+		if i := strings.IndexByte(allowed, '*'); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) > len(prefix)+len(suffix) &&
+				strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CORS answers preflight requests and adds the CORS headers for allowed
+// origins. Requests from other origins pass without CORS headers, so the
+// browser blocks them.
+func CORS(opts CORSOptions) Middleware {
+	methods := opts.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodPost, http.MethodHead}
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(opts.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(opts.ExposedHeaders, ", ")
+	anyOrigin := false
+	for _, allowed := range opts.AllowedOrigins {
+		if allowed == "*" {
+			anyOrigin = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			switch {
+			case opts.originAllowed(origin):
+				h.Set("Access-Control-Allow-Origin", origin)
+				if opts.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			case anyOrigin:
+				// never reflect the origin, that would give credentialed
+				// access to every site
+				h.Set("Access-Control-Allow-Origin", "*")
+			default:
+				next.ServeHTTP(w, r)
+				return
+			}
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			if !preflight {
+				if exposeHeaders != "" {
+					h.Set("Access-Control-Expose-Headers", exposeHeaders)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", allowMethods)
+			if allowHeaders != "" {
+				h.Set("Access-Control-Allow-Headers", allowHeaders)
+			}
+			if opts.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge/time.Second)))
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
+
+// Timeout cancels the request context after d and answers 503 with an
+// ErrorBody if the handler did not respond yet, see http.TimeoutHandler.
+func Timeout(d time.Duration) Middleware {
+	body := `{"error":{"code":"timeout","message":"request timed out"}}`
+	return func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, d, body)
+	}
+}
+
+// Recover turns panics of the handler into 500 errors and logs them with
+// the stack to logger, nil means the default logger.
+func Recover(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.DefaultLog()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+					panic(v)
+				}
+				logger.Error("uhttp: panic serving %s %s (request id %q): %v\n%s",
+					r.Method, r.URL, RequestIDFromContext(r.Context()), v, debug.Stack())
+				WriteError(w, http.StatusInternalServerError, "internal_server_error",
+					http.StatusText(http.StatusInternalServerError))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
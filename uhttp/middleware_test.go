@@ -0,0 +1,238 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/ucompress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}), mw("huangjian"), mw("MDGSF"))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, []string{"huangjian", "MDGSF", "handler"}, order, "they should be equal")
+}
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	h := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, 36, len(seen), "they should be equal")
+	assert.Equal(t, seen, w.Header().Get(RequestIDHeader), "they should be equal")
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(RequestIDHeader, "huangjian")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, "huangjian", seen, "they should be equal")
+
+	r.Header.Set(RequestIDHeader, "huang\njian")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, 36, len(seen), "they should be equal")
+}
+
+func TestRealIP(t *testing.T) {
+	var seen string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = ClientIP(r)
+	})
+	serve := func(mw Middleware, remote, xff, realIP string) string {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remote
+		if xff != "" {
+			r.Header.Set("X-Forwarded-For", xff)
+		}
+		if realIP != "" {
+			r.Header.Set("X-Real-IP", realIP)
+		}
+		mw(handler).ServeHTTP(httptest.NewRecorder(), r)
+		return seen
+	}
+
+	all := RealIP()
+	assert.Equal(t, "1.2.3.4", serve(all, "10.0.0.1:1234", "1.2.3.4, 10.0.0.2", ""), "they should be equal")
+	assert.Equal(t, "5.6.7.8", serve(all, "10.0.0.1:1234", "", "5.6.7.8"), "they should be equal")
+	assert.Equal(t, "10.0.0.1", serve(all, "10.0.0.1:1234", "", ""), "they should be equal")
+
+	some := RealIP("10.0.0.0/8", "192.168.1.1")
+	// a spoofed first hop is skipped, the last untrusted hop wins
+	assert.Equal(t, "1.2.3.4", serve(some, "10.0.0.1:1234", "6.6.6.6, 1.2.3.4, 10.0.0.2", ""), "they should be equal")
+	assert.Equal(t, "1.2.3.4", serve(some, "192.168.1.1:1234", "1.2.3.4", ""), "they should be equal")
+	// untrusted peers can not set the headers
+	assert.Equal(t, "8.8.8.8", serve(some, "8.8.8.8:1234", "1.2.3.4", ""), "they should be equal")
+
+	assert.Panics(t, func() { RealIP("huangjian") }, "it should panic")
+}
+
+func gzipHandler(level int, h http.HandlerFunc) http.Handler {
+	return Gzip(level)(h)
+}
+
+func TestGzip(t *testing.T) {
+	body := strings.Repeat("<html>huangjian MDGSF</html>", 100)
+	h := gzipHandler(ucompress.BestSpeed, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "2800")
+		w.Write([]byte(body))
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"), "they should be equal")
+	assert.Equal(t, "", w.Header().Get("Content-Length"), "they should be equal")
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"), "they should be equal")
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"), "they should be equal")
+	gz, err := gzip.NewReader(w.Body)
+	assert.Equal(t, nil, err, "they should be equal")
+	data, _ := ioutil.ReadAll(gz)
+	assert.Equal(t, body, string(data), "they should be equal")
+
+	for _, accept := range []string{"", "deflate", "gzip;q=0"} {
+		r.Header.Set("Accept-Encoding", accept)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, "", w.Header().Get("Content-Encoding"), accept)
+		assert.Equal(t, body, w.Body.String(), accept)
+	}
+
+	r.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	gzipHandler(ucompress.BestSpeed, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).ServeHTTP(w, r)
+	assert.Equal(t, "", w.Header().Get("Content-Encoding"), "they should be equal")
+}
+
+func TestCORS(t *testing.T) {
+	h := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://*.MDGSF.com", "https://huangjian.dev"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders:   []string{"Content-Type"},
+		ExposedHeaders:   []string{RequestIDHeader},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("huangjian"))
+	}))
+
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://api.MDGSF.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code, "they should be equal")
+	assert.Equal(t, "https://api.MDGSF.com", w.Header().Get("Access-Control-Allow-Origin"), "they should be equal")
+	assert.Equal(t, "GET, PUT", w.Header().Get("Access-Control-Allow-Methods"), "they should be equal")
+	assert.Equal(t, "Content-Type", w.Header().Get("Access-Control-Allow-Headers"), "they should be equal")
+	assert.Equal(t, "3600", w.Header().Get("Access-Control-Max-Age"), "they should be equal")
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), "they should be equal")
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Origin", "https://huangjian.dev")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "huangjian", w.Body.String(), "they should be equal")
+	assert.Equal(t, RequestIDHeader, w.Header().Get("Access-Control-Expose-Headers"), "they should be equal")
+
+	r.Header.Set("Origin", "https://evil.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "huangjian", w.Body.String(), "they should be equal")
+	assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Origin"), "they should be equal")
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	h := CORS(CORSOptions{
+		AllowedOrigins:   []string{"*", "https://huangjian.dev"},
+		AllowCredentials: true,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Origin", "https://evil.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"), "they should be equal")
+	assert.Equal(t, "", w.Header().Get("Access-Control-Allow-Credentials"), "they should be equal")
+
+	r.Header.Set("Origin", "https://huangjian.dev")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "https://huangjian.dev", w.Header().Get("Access-Control-Allow-Origin"), "they should be equal")
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), "they should be equal")
+}
+
+func TestTimeout(t *testing.T) {
+	h := Timeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code, "they should be equal")
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `"timeout"`), "they should be equal")
+}
+
+func TestRecover(t *testing.T) {
+	logger := log.NewDefaultLog()
+	buf := &syncBuffer{}
+	logger.SetOutput(buf)
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("huangjian")
+	}), RequestID(), Recover(logger))
+
+	r := httptest.NewRequest(http.MethodGet, "/MDGSF", nil)
+	r.Header.Set(RequestIDHeader, "req-1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code, "they should be equal")
+	out := buf.String()
+	assert.Equal(t, true, strings.Contains(out, `panic serving GET /MDGSF (request id "req-1"): huangjian`), "they should be equal")
+
+	abort := Recover(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.Panics(t, func() { abort.ServeHTTP(httptest.NewRecorder(), r) }, "it should panic")
+}
@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+)
+
+type proxyOptions struct {
+	stripPrefix  string
+	preserveHost bool
+	setHeaders   http.Header
+	delHeaders   []string
+	rewrite      func(r *http.Request)
+	transport    http.RoundTripper
+	logger       *log.Logger
+}
+
+// ProxyOption configures NewReverseProxy.
+type ProxyOption func(*proxyOptions)
+
+// WithStripPrefix removes prefix from the request path before forwarding,
+// "/api/users" with prefix "/api" goes upstream as "/users".
+func WithStripPrefix(prefix string) ProxyOption {
+	return func(o *proxyOptions) {
+		o.stripPrefix = strings.TrimSuffix(prefix, "/")
+	}
+}
+
+// WithPreserveHost forwards the Host header of the client instead of the
+// host of the target.
+func WithPreserveHost() ProxyOption {
+	return func(o *proxyOptions) {
+		o.preserveHost = true
+	}
+}
+
+// WithSetHeader sets a header on upstream requests.
+func WithSetHeader(key, value string) ProxyOption {
+	return func(o *proxyOptions) {
+		o.setHeaders.Set(key, value)
+	}
+}
+
+// WithRemoveHeader removes a header from upstream requests.
+func WithRemoveHeader(key string) ProxyOption {
+	return func(o *proxyOptions) {
+		o.delHeaders = append(o.delHeaders, key)
+	}
+}
+
+// WithRewrite calls fn on every upstream request after the other options
+// were applied.
+func WithRewrite(fn func(r *http.Request)) ProxyOption {
+	return func(o *proxyOptions) {
+		o.rewrite = fn
+	}
+}
+
+// WithTransport set the transport to the upstream, default
+// http.DefaultTransport.
+func WithTransport(t http.RoundTripper) ProxyOption {
+	return func(o *proxyOptions) {
+		o.transport = t
+	}
+}
+
+// WithProxyLogger logs upstream latency at debug level and upstream errors
+// to logger, nil means the default logger.
+func WithProxyLogger(logger *log.Logger) ProxyOption {
+	return func(o *proxyOptions) {
+		if logger == nil {
+			logger = log.DefaultLog()
+		}
+		o.logger = logger
+	}
+}
+
+/*
+NewReverseProxy returns an httputil.ReverseProxy to target which sets the
+X-Forwarded-For, X-Forwarded-Host and X-Forwarded-Proto headers. Websocket
+and other upgrade requests are passed through. Upstream errors are answered
+with 502 and an ErrorBody.
+
+	proxy, err := uhttp.NewReverseProxy("http://127.0.0.1:8080",
+		uhttp.WithStripPrefix("/api"), uhttp.WithProxyLogger(nil))
+	http.Handle("/api/", proxy)
+*/
+func NewReverseProxy(target string, opts ...ProxyOption) (*httputil.ReverseProxy, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	o := proxyOptions{setHeaders: make(http.Header), transport: http.DefaultTransport}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	director := httputil.NewSingleHostReverseProxy(u).Director
+	proxy := &httputil.ReverseProxy{
+		Director: func(r *http.Request) {
+			host, proto := r.Host, "http"
+			if r.TLS != nil {
+				proto = "https"
+			}
+			if o.stripPrefix != "" {
+				r.URL.Path = stripPrefix(r.URL.Path, o.stripPrefix)
+				if r.URL.RawPath != "" {
+					r.URL.RawPath = stripPrefix(r.URL.RawPath, o.stripPrefix)
+				}
+			}
+			director(r)
+			if !o.preserveHost {
+				r.Host = u.Host
+			}
+			// always overwrite, clients must not be able to spoof them
+			r.Header.Set("X-Forwarded-Host", host)
+			r.Header.Set("X-Forwarded-Proto", proto)
+			for key, values := range o.setHeaders {
+				r.Header[key] = values
+			}
+			for _, key := range o.delHeaders {
+				r.Header.Del(key)
+			}
+			if o.rewrite != nil {
+				o.rewrite(r)
+			}
+		},
+		Transport: o.transport,
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if o.logger != nil {
+				o.logger.Error("uhttp: proxy %s %s to %s failed: %v", r.Method, r.URL.Path, u.Host, err)
+			}
+			WriteError(w, http.StatusBadGateway, "bad_gateway", http.StatusText(http.StatusBadGateway))
+		},
+	}
+	if o.logger != nil {
+		proxy.Transport = &latencyTransport{next: o.transport, logger: o.logger}
+	}
+	return proxy, nil
+}
+
+func stripPrefix(p, prefix string) string {
+	if p == prefix {
+		return "/"
+	}
+	if strings.HasPrefix(p, prefix+"/") {
+		return p[len(prefix):]
+	}
+	return p
+}
+
+type latencyTransport struct {
+	next   http.RoundTripper
+	logger *log.Logger
+}
+
+func (t *latencyTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(r)
+	if err == nil {
+		t.logger.Debug("uhttp: proxy %s %s -> %d in %v", r.Method, r.URL, resp.StatusCode, time.Since(start))
+	}
+	return resp, err
+}
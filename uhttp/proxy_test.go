@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uhttp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s %s xff=%s host=%s proto=%s name=%s secret=%s",
+			r.Host, r.URL.Path, r.Header.Get("X-Forwarded-For"), r.Header.Get("X-Forwarded-Host"),
+			r.Header.Get("X-Forwarded-Proto"), r.Header.Get("X-Name"), r.Header.Get("X-Secret"))
+	}))
+	defer upstream.Close()
+
+	logger := log.NewDefaultLog()
+	buf := &syncBuffer{}
+	logger.SetOutput(buf)
+	proxy, err := NewReverseProxy(upstream.URL+"/base",
+		WithStripPrefix("/api/"),
+		WithSetHeader("X-Name", "huangjian"),
+		WithRemoveHeader("X-Secret"),
+		WithProxyLogger(logger))
+	assert.Equal(t, nil, err, "they should be equal")
+	front := httptest.NewServer(proxy)
+	defer front.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, front.URL+"/api/users", nil)
+	req.Header.Set("X-Secret", "MDGSF")
+	req.Header.Set("X-Forwarded-Host", "evil.com")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Equal(t, nil, err, "they should be equal")
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	upstreamHost := strings.TrimPrefix(upstream.URL, "http://")
+	frontHost := strings.TrimPrefix(front.URL, "http://")
+	want := fmt.Sprintf("%s /base/users xff=127.0.0.1 host=%s proto=http name=huangjian secret=", upstreamHost, frontHost)
+	assert.Equal(t, want, string(body), "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "proxy GET "), "they should be equal")
+}
+
+func TestReverseProxyError(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	addr := upstream.URL
+	upstream.Close()
+
+	proxy, _ := NewReverseProxy(addr)
+	w := httptest.NewRecorder()
+	proxy.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusBadGateway, w.Code, "they should be equal")
+	assert.Equal(t, true, strings.Contains(w.Body.String(), `"bad_gateway"`), "they should be equal")
+
+	_, err := NewReverseProxy("://huangjian")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestReverseProxyUpgrade(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			http.Error(w, "no upgrade", http.StatusBadRequest)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+		rw.Flush()
+		io.Copy(conn, rw)
+	}))
+	defer upstream.Close()
+
+	proxy, _ := NewReverseProxy(upstream.URL)
+	front := httptest.NewServer(proxy)
+	defer front.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(front.URL, "http://"))
+	assert.Equal(t, nil, err, "they should be equal")
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: huangjian\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n")
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode, "they should be equal")
+
+	conn.Write([]byte("MDGSF"))
+	echo := make([]byte, 5)
+	_, err = io.ReadFull(br, echo)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "MDGSF", string(echo), "they should be equal")
+}
+
+func TestStripPrefix(t *testing.T) {
+	assert.Equal(t, "/users", stripPrefix("/api/users", "/api"), "they should be equal")
+	assert.Equal(t, "/", stripPrefix("/api", "/api"), "they should be equal")
+	assert.Equal(t, "/apix", stripPrefix("/apix", "/api"), "they should be equal")
+}
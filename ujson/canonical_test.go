@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujson
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalCanonical(t *testing.T) {
+	v := struct {
+		Name  string                 `json:"name"`
+		HTML  string                 `json:"html"`
+		Count int                    `json:"count"`
+		Score float64                `json:"score"`
+		Extra map[string]interface{} `json:"extra"`
+	}{
+		Name:  "huangjian",
+		HTML:  "<a&b>",
+		Count: 1,
+		Score: 1.0,
+		Extra: map[string]interface{}{"z": []int{3, 1}, "a": nil},
+	}
+	data, err := MarshalCanonical(v)
+	assert.Equal(t, nil, err, "they should be equal")
+	want := `{"count":1,"extra":{"a":null,"z":[3,1]},"html":"<a&b>","name":"huangjian","score":1}`
+	assert.Equal(t, want, string(data), "they should be equal")
+
+	data2, err := MarshalCanonical(map[string]interface{}{
+		"score": json.Number("1e0"),
+		"name":  "huangjian",
+		"html":  "<a&b>",
+		"count": 1,
+		"extra": map[string]interface{}{"a": nil, "z": []float64{3, 1}},
+	})
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, string(data), string(data2), "they should be equal")
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`{ "b" : 1, "a" : [ true, false, null ] }`, `{"a":[true,false,null],"b":1}`},
+		{`[1.50, -0, -0.0, 100e-2, 12345678901234567890]`, `[1.5,0,0,1,12345678901234567890]`},
+		{`[1e21, 1e-7, -2.5e-7, 123456.789e3]`, `[1e+21,1e-7,-2.5e-7,123456789]`},
+		{`"é<"`, `"é<"`},
+	}
+	for _, test := range tests {
+		got, err := Canonicalize([]byte(test.in))
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, test.want, string(got), "they should be equal")
+	}
+
+	_, err := Canonicalize([]byte(`{`))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when a path does not exist in a document.
+	ErrNotFound = errors.New("ujson: path not found")
+
+	// ErrInvalidPath is returned for malformed paths.
+	ErrInvalidPath = errors.New("ujson: invalid path")
+
+	// ErrInvalidJSON is returned when the document is not valid JSON.
+	ErrInvalidJSON = errors.New("ujson: invalid JSON")
+)
+
+// segment is one step of a path, an object key or an array index.
+type segment struct {
+	key   string
+	index int
+	isKey bool
+}
+
+/*
+parsePath splits "a.b[2].c" into its segments. Keys are separated by dots,
+indexes are written in brackets, "" is the whole document.
+*/
+func parsePath(path string) ([]segment, error) {
+	var segments []segment
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			if i == 0 || i == len(path)-1 || path[i+1] == '.' || path[i+1] == '[' {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			index, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			segments = append(segments, segment{index: index})
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segments = append(segments, segment{key: path[i : i+end], isKey: true})
+			i += end
+		}
+	}
+	return segments, nil
+}
+
+/*
+GetRaw returns the raw JSON at path in data. Paths are dotted keys with
+array indexes in brackets:
+
+	GetRaw(data, "users[2].address.city")
+	GetRaw(data, "[0].name")
+
+Keys containing dots or brackets cannot be addressed.
+*/
+func GetRaw(data []byte, path string) (json.RawMessage, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(data) {
+		return nil, ErrInvalidJSON
+	}
+
+	cur := json.RawMessage(bytes.TrimSpace(data))
+	for i, seg := range segments {
+		walked := describe(segments[:i+1])
+		if seg.isKey {
+			var obj map[string]json.RawMessage
+			if err := json.Unmarshal(cur, &obj); err != nil || obj == nil {
+				return nil, fmt.Errorf("%w: %s is not an object", ErrNotFound, describe(segments[:i]))
+			}
+			next, ok := obj[seg.key]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, walked)
+			}
+			cur = next
+			continue
+		}
+
+		var arr []json.RawMessage
+		if err := json.Unmarshal(cur, &arr); err != nil || arr == nil {
+			return nil, fmt.Errorf("%w: %s is not an array", ErrNotFound, describe(segments[:i]))
+		}
+		if seg.index >= len(arr) {
+			return nil, fmt.Errorf("%w: %s, array has %d elements", ErrNotFound, walked, len(arr))
+		}
+		cur = arr[seg.index]
+	}
+	return cur, nil
+}
+
+// describe formats segments back into a path for errors, "$" for the root.
+func describe(segments []segment) string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, seg := range segments {
+		if seg.isKey {
+			b.WriteString("." + seg.key)
+		} else {
+			b.WriteString("[" + strconv.Itoa(seg.index) + "]")
+		}
+	}
+	return b.String()
+}
+
+/*
+Get returns the value at path in data, see GetRaw for the path syntax.
+Objects are map[string]interface{}, arrays []interface{} and numbers
+json.Number.
+
+	v, err := Get([]byte(`{"a":{"b":[1,2,{"c":"huangjian"}]}}`), "a.b[2].c")
+	// v == "huangjian"
+*/
+func Get(data []byte, path string) (interface{}, error) {
+	raw, err := GetRaw(data, path)
+	if err != nil {
+		return nil, err
+	}
+	return decode(raw)
+}
+
+// GetAs unmarshals the value at path in data into v.
+func GetAs(data []byte, path string, v interface{}) error {
+	raw, err := GetRaw(data, path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
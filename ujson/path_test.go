@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujson
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pathDoc = []byte(`{
+	"name": "huangjian",
+	"users": [
+		{"name": "MDGSF", "age": 30, "tags": ["go", "rust"]},
+		{"name": "huangjian", "address": {"city": "Hangzhou"}}
+	],
+	"empty": null
+}`)
+
+func TestGet(t *testing.T) {
+	tests := map[string]interface{}{
+		"name":                  "huangjian",
+		"users[0].age":          json.Number("30"),
+		"users[0].tags[1]":      "rust",
+		"users[1].address":      map[string]interface{}{"city": "Hangzhou"},
+		"users[1].address.city": "Hangzhou",
+		"empty":                 nil,
+	}
+	for path, want := range tests {
+		got, err := Get(pathDoc, path)
+		assert.Equal(t, nil, err, path)
+		assert.Equal(t, want, got, path)
+	}
+
+	v, err := Get([]byte(`[{"a":1}]`), "[0].a")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, json.Number("1"), v, "they should be equal")
+
+	raw, err := GetRaw(pathDoc, "users[0].tags")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `["go", "rust"]`, string(raw), "they should be equal")
+
+	var tags []string
+	assert.Equal(t, nil, GetAs(pathDoc, "users[0].tags", &tags), "they should be equal")
+	assert.Equal(t, []string{"go", "rust"}, tags, "they should be equal")
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing":          "ujson: path not found: $.missing",
+		"users[5]":         "ujson: path not found: $.users[5], array has 2 elements",
+		"name.first":       "ujson: path not found: $.name is not an object",
+		"users.name":       "ujson: path not found: $.users is not an object",
+		"users[0][1]":      "ujson: path not found: $.users[0] is not an array",
+		"users[0].age.x":   "ujson: path not found: $.users[0].age is not an object",
+		"empty.x":          "ujson: path not found: $.empty is not an object",
+		"users[0].missing": "ujson: path not found: $.users[0].missing",
+	}
+	for path, want := range tests {
+		_, err := Get(pathDoc, path)
+		assert.Equal(t, want, err.Error(), path)
+		assert.Equal(t, true, errors.Is(err, ErrNotFound), path)
+	}
+
+	for _, path := range []string{".a", "a.", "a..b", "a[", "a[x]", "a[-1]", "a.[0]"} {
+		_, err := Get(pathDoc, path)
+		assert.Equal(t, true, errors.Is(err, ErrInvalidPath), path)
+	}
+
+	_, err := Get([]byte(`{"a":`), "a")
+	assert.Equal(t, ErrInvalidJSON, err, "they should be equal")
+}
@@ -0,0 +1,371 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MDGSF/utils/uredact"
+)
+
+// ErrCycle is returned by MarshalSafe for values referencing themselves.
+var ErrCycle = errors.New("ujson: cycle detected")
+
+// maxSafeDepth bounds the nesting followed by MarshalSafe.
+const maxSafeDepth = 1000
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+/*
+MarshalSafe is json.Marshal honoring two struct tags which hide sensitive
+fields:
+
+	redact:"true"  the value is replaced by "[REDACTED]"
+	mask:"kind"    the string is masked with uredact.Mask, e.g. "email",
+	               "phone", "card", "name" or "full"
+
+Masks apply to strings, string pointers and string slices, any other type
+is redacted. The json tag is honored as usual, so an empty field tagged
+omitempty is still left out. The masks are shared with the log package,
+see uredact.
+
+	type User struct {
+		Name     string `json:"name"`
+		Email    string `json:"email" mask:"email"`
+		Password string `json:"password" redact:"true"`
+	}
+	// {"name":"huangjian","email":"h********@MDGSF.com","password":"[REDACTED]"}
+*/
+func MarshalSafe(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeSafe(&buf, reflect.ValueOf(v), 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeSafe(buf *bytes.Buffer, v reflect.Value, depth int) error {
+	if depth > maxSafeDepth {
+		return ErrCycle
+	}
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+	}
+	if implementsMarshaler(v) {
+		return encodeLeaf(buf, v)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return encodeSafe(buf, v.Elem(), depth+1)
+	case reflect.Struct:
+		return encodeStruct(buf, v, depth)
+	case reflect.Map:
+		return encodeMap(buf, v, depth)
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return encodeLeaf(buf, v)
+		}
+		return encodeArray(buf, v, depth)
+	case reflect.Array:
+		return encodeArray(buf, v, depth)
+	}
+	return encodeLeaf(buf, v)
+}
+
+func implementsMarshaler(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if t.Kind() != reflect.Ptr && v.CanAddr() {
+		pt := reflect.PtrTo(t)
+		return pt.Implements(marshalerType) || pt.Implements(textMarshalerType)
+	}
+	return false
+}
+
+func encodeLeaf(buf *bytes.Buffer, v reflect.Value) error {
+	if v.Kind() != reflect.Ptr && v.CanAddr() {
+		v = v.Addr()
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+func encodeArray(buf *bytes.Buffer, v reflect.Value, depth int) error {
+	buf.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeSafe(buf, v.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func encodeMap(buf *bytes.Buffer, v reflect.Value, depth int) error {
+	if v.IsNil() {
+		buf.WriteString("null")
+		return nil
+	}
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{key, iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	buf.WriteByte('{')
+	for i, e := range entries {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, e.key)
+		buf.WriteByte(':')
+		if err := encodeSafe(buf, e.value, depth+1); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s)
+	buf.Write(data)
+}
+
+func encodeStruct(buf *bytes.Buffer, v reflect.Value, depth int) error {
+	buf.WriteByte('{')
+	first := true
+	if err := encodeFields(buf, v, depth, &first); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeFields(buf *bytes.Buffer, v reflect.Value, depth int, first *bool) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			name, opts = tag[:idx], tag[idx+1:]
+		}
+		fv := v.Field(i)
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				if err := encodeFields(buf, fv, depth+1, first); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if hasOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		if !*first {
+			buf.WriteByte(',')
+		}
+		*first = false
+		writeString(buf, name)
+		buf.WriteByte(':')
+
+		if sf.Tag.Get("redact") == "true" {
+			writeString(buf, uredact.Redacted)
+			continue
+		}
+		if kind := sf.Tag.Get("mask"); kind != "" {
+			maskValue(buf, fv, kind)
+			continue
+		}
+		if hasOption(opts, "string") {
+			if err := encodeQuoted(buf, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := encodeSafe(buf, fv, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maskValue writes v masked with kind, values which are not strings are
+// redacted.
+func maskValue(buf *bytes.Buffer, v reflect.Value, kind string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		writeString(buf, uredact.Mask(kind, v.String()))
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() == reflect.String:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buf.WriteString("null")
+			return
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeString(buf, uredact.Mask(kind, v.Index(i).String()))
+		}
+		buf.WriteByte(']')
+	default:
+		writeString(buf, uredact.Redacted)
+	}
+}
+
+// encodeQuoted implements the ",string" json option for scalar fields.
+func encodeQuoted(buf *bytes.Buffer, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		writeString(buf, string(data))
+		return nil
+	}
+	return encodeSafe(buf, v, 0)
+}
+
+func hasOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		if idx := strings.IndexByte(opts, ','); idx >= 0 {
+			opt, opts = opts[:idx], opts[idx+1:]
+		} else {
+			opt, opts = opts, ""
+		}
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
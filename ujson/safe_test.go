@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujson
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type safeBase struct {
+	ID    int    `json:"id"`
+	Token string `json:"token" redact:"true"`
+}
+
+type safeUser struct {
+	safeBase
+	Name     string            `json:"name"`
+	Email    string            `json:"email" mask:"email"`
+	Phone    *string           `json:"phone,omitempty" mask:"phone"`
+	Password string            `json:"password,omitempty" redact:"true"`
+	Cards    []string          `json:"cards" mask:"card"`
+	Age      int               `json:"age,string"`
+	Created  time.Time         `json:"created"`
+	Labels   map[string]string `json:"labels"`
+	Friends  []*safeUser       `json:"friends,omitempty"`
+	Skip     string            `json:"-"`
+	private  string
+}
+
+func TestMarshalSafe(t *testing.T) {
+	phone := "13800138000"
+	u := safeUser{
+		safeBase: safeBase{ID: 1, Token: "abc"},
+		Name:     "huangjian",
+		Email:    "huangjian@MDGSF.com",
+		Phone:    &phone,
+		Password: "hunter2",
+		Cards:    []string{"4111111111111111"},
+		Age:      18,
+		Created:  time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Friends:  []*safeUser{{Name: "MDGSF", Email: "MDGSF@MDGSF.com"}},
+		Skip:     "skip",
+		private:  "private",
+	}
+	data, err := MarshalSafe(u)
+	assert.Equal(t, nil, err, "they should be equal")
+	want := `{"id":1,"token":"[REDACTED]","name":"huangjian","email":"h********@MDGSF.com",` +
+		`"phone":"*******8000","password":"[REDACTED]","cards":["************1111"],"age":"18",` +
+		`"created":"2019-01-02T03:04:05Z","labels":{"a":"1","b":"2"},` +
+		`"friends":[{"id":0,"token":"[REDACTED]","name":"MDGSF","email":"M****@MDGSF.com",` +
+		`"cards":null,"age":"0","created":"0001-01-01T00:00:00Z","labels":null}]}`
+	assert.Equal(t, want, string(data), "they should be equal")
+	assert.Equal(t, true, json.Valid(data), "they should be equal")
+
+	data, err = MarshalSafe(&u)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, want, string(data), "they should be equal")
+}
+
+func TestMarshalSafeMatchesJSON(t *testing.T) {
+	values := []interface{}{
+		nil,
+		1,
+		"huangjian",
+		[]byte("MDGSF"),
+		[]int{1, 2},
+		map[int]string{2: "b", 1: "a"},
+		map[string]interface{}{"a": []interface{}{1.5, true, nil}},
+		struct {
+			A int `json:"a,omitempty"`
+			B string
+		}{B: "b"},
+	}
+	for _, v := range values {
+		want, err := json.Marshal(v)
+		assert.Equal(t, nil, err, "they should be equal")
+		got, err := MarshalSafe(v)
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, string(want), string(got), "they should be equal")
+	}
+}
+
+func TestMarshalSafeMaskNonString(t *testing.T) {
+	v := struct {
+		PIN int `json:"pin" mask:"full"`
+	}{PIN: 1234}
+	data, err := MarshalSafe(v)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"pin":"[REDACTED]"}`, string(data), "they should be equal")
+}
+
+type safeNode struct {
+	Next *safeNode `json:"next"`
+}
+
+func TestMarshalSafeCycle(t *testing.T) {
+	n := &safeNode{}
+	n.Next = n
+	_, err := MarshalSafe(n)
+	assert.Equal(t, ErrCycle, err, "they should be equal")
+}
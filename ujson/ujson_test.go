@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujson
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrettyMinify(t *testing.T) {
+	data := []byte(` {"name": "huangjian",  "tags": ["a", "b"], "n": 1.50} `)
+
+	pretty, err := Pretty(data)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "{\n  \"name\": \"huangjian\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ],\n  \"n\": 1.50\n}", string(pretty), "they should be equal")
+
+	min, err := Minify(pretty)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"name":"huangjian","tags":["a","b"],"n":1.50}`, string(min), "they should be equal")
+
+	_, err = Pretty([]byte(`{"a":`))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = Minify([]byte(`{a}`))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	assert.Equal(t, true, Valid(data), "they should be equal")
+	assert.Equal(t, false, Valid([]byte(`{"a":}`)), "they should be equal")
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		doc, patch, want string
+	}{
+		{`{"a":1,"b":{"c":2,"d":3}}`, `{"b":{"c":null,"e":4}}`, `{"a":1,"b":{"d":3,"e":4}}`},
+		{`{"a":[1,2]}`, `{"a":[3]}`, `{"a":[3]}`},
+		{`{"a":"b"}`, `{"a":{"c":1}}`, `{"a":{"c":1}}`},
+		{`{"a":1}`, `"huangjian"`, `"huangjian"`},
+		{`[1,2]`, `{"a":{"b":null}}`, `{"a":{}}`},
+		{`{"n":12345678901234567890}`, `{}`, `{"n":12345678901234567890}`},
+	}
+	for _, test := range tests {
+		got, err := Merge([]byte(test.doc), []byte(test.patch))
+		assert.Equal(t, nil, err, test.doc)
+		assert.Equal(t, test.want, string(got), test.doc)
+	}
+
+	_, err := Merge([]byte(`{`), []byte(`{}`))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = Merge([]byte(`{}`), []byte(`}`))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
@@ -0,0 +1,317 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ujwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+)
+
+// Errors returned by Sign, Parse and Validate.
+var (
+	ErrUnsupportedAlg    = errors.New("ujwt: unsupported algorithm")
+	ErrInvalidKey        = errors.New("ujwt: invalid key for algorithm")
+	ErrTokenMalformed    = errors.New("ujwt: token is malformed")
+	ErrSignatureInvalid  = errors.New("ujwt: signature is invalid")
+	ErrTokenExpired      = errors.New("ujwt: token is expired")
+	ErrTokenNotValidYet  = errors.New("ujwt: token is not valid yet")
+	ErrAudienceInvalid   = errors.New("ujwt: token audience is invalid")
+	ErrIssuerInvalid     = errors.New("ujwt: token issuer is invalid")
+	ErrClaimTypeMismatch = errors.New("ujwt: claim has invalid type")
+	ErrClaimOutOfRange   = errors.New("ujwt: claim is out of range")
+)
+
+// maxNumericDate bounds numeric date claims, whole seconds up to it are
+// exact in a float64 and far beyond any real date.
+const maxNumericDate = 1 << 53
+
+// Claims jwt payload, registered claims are "exp", "nbf", "iat", "aud",
+// "iss" and "sub", times are unix seconds.
+type Claims map[string]interface{}
+
+// Token a parsed token.
+type Token struct {
+	Header map[string]interface{}
+	Claims Claims
+}
+
+// Alg returns the "alg" header of the token.
+func (t *Token) Alg() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// KeyFunc returns the key to verify token, it is called after the token is
+// decoded and before the signature is verified, so it can select the key by
+// header like "kid". Return []byte for HS256 and *rsa.PublicKey for RS256.
+type KeyFunc func(token *Token) (interface{}, error)
+
+var encoding = base64.RawURLEncoding
+
+/*
+Sign create a signed token.
+@param key: []byte secret for HS256, *rsa.PrivateKey for RS256.
+*/
+func Sign(claims Claims, key interface{}, alg string) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encoding.EncodeToString(header) + "." + encoding.EncodeToString(payload)
+
+	var sig []byte
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return "", ErrInvalidKey
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		sig = mac.Sum(nil)
+	case RS256:
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return "", ErrInvalidKey
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", ErrUnsupportedAlg
+	}
+	return signingInput + "." + encoding.EncodeToString(sig), nil
+}
+
+/*
+Parse decode token, verify its signature with the key returned by keyFunc,
+then check its claims with Validate and opts.
+Returns the token even if validation fails, if the signature is valid.
+*/
+func Parse(tokenString string, keyFunc KeyFunc, opts ...ValidateOption) (*Token, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, ErrTokenMalformed
+	}
+
+	token := &Token{}
+	if err := decodeSegment(parts[0], &token.Header); err != nil {
+		return nil, err
+	}
+	if err := decodeSegment(parts[1], &token.Claims); err != nil {
+		return nil, err
+	}
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrTokenMalformed
+	}
+
+	key, err := keyFunc(token)
+	if err != nil {
+		return nil, err
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	switch token.Alg() {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, ErrSignatureInvalid
+		}
+	case RS256:
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+			return nil, ErrSignatureInvalid
+		}
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+
+	return token, Validate(token.Claims, opts...)
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := encoding.DecodeString(seg)
+	if err != nil {
+		return ErrTokenMalformed
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return ErrTokenMalformed
+	}
+	return nil
+}
+
+type validateOptions struct {
+	audience string
+	issuer   string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// ValidateOption configures Parse and Validate.
+type ValidateOption func(*validateOptions)
+
+// WithAudience requires "aud" claim to contain aud.
+func WithAudience(aud string) ValidateOption {
+	return func(o *validateOptions) {
+		o.audience = aud
+	}
+}
+
+// WithIssuer requires "iss" claim to be iss.
+func WithIssuer(iss string) ValidateOption {
+	return func(o *validateOptions) {
+		o.issuer = iss
+	}
+}
+
+// WithLeeway allows clock skew when checking "exp" and "nbf".
+func WithLeeway(leeway time.Duration) ValidateOption {
+	return func(o *validateOptions) {
+		o.leeway = leeway
+	}
+}
+
+// WithTimeFunc set the function returning current time, default time.Now.
+func WithTimeFunc(now func() time.Time) ValidateOption {
+	return func(o *validateOptions) {
+		o.now = now
+	}
+}
+
+// Validate check "exp" and "nbf" claims if present, and audience and issuer
+// if required by opts.
+func Validate(claims Claims, opts ...ValidateOption) error {
+	o := &validateOptions{now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+	now := o.now()
+
+	if exp, ok, err := claims.Time("exp"); err != nil {
+		return err
+	} else if ok && !now.Before(exp.Add(o.leeway)) {
+		return ErrTokenExpired
+	}
+
+	if nbf, ok, err := claims.Time("nbf"); err != nil {
+		return err
+	} else if ok && now.Add(o.leeway).Before(nbf) {
+		return ErrTokenNotValidYet
+	}
+
+	if len(o.audience) > 0 && !claims.HasAudience(o.audience) {
+		return ErrAudienceInvalid
+	}
+
+	if len(o.issuer) > 0 {
+		if iss, _ := claims["iss"].(string); iss != o.issuer {
+			return ErrIssuerInvalid
+		}
+	}
+	return nil
+}
+
+// Time returns a numeric date claim, ok is false if the claim is absent. A
+// date further than 2^53 seconds from the epoch gives ErrClaimOutOfRange.
+func (c Claims) Time(name string) (t time.Time, ok bool, err error) {
+	v, ok := c[name]
+	if !ok {
+		return
+	}
+	var sec float64
+	switch n := v.(type) {
+	case json.Number:
+		sec, err = n.Float64()
+		if err != nil {
+			return t, true, ErrClaimTypeMismatch
+		}
+	case float64:
+		sec = n
+	case int64:
+		sec = float64(n)
+	case int:
+		sec = float64(n)
+	default:
+		return t, true, ErrClaimTypeMismatch
+	}
+	if math.IsNaN(sec) || sec > maxNumericDate || sec < -maxNumericDate {
+		return t, true, ErrClaimOutOfRange
+	}
+	whole, frac := math.Modf(sec)
+	return time.Unix(int64(whole), int64(frac*float64(time.Second))), true, nil
+}
+
+// HasAudience reports whether "aud" claim, a string or an array of strings, contains aud.
+func (c Claims) HasAudience(aud string) bool {
+	switch v := c["aud"].(type) {
+	case string:
+		return v == aud
+	case []string:
+		for _, s := range v {
+			if s == aud {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, s := range v {
+			if s == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
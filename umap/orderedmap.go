@@ -0,0 +1,259 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umap
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+type orderedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *orderedEntry[K, V]
+}
+
+/*
+OrderedMap is a map which remembers insertion order. Iteration, Keys, Values
+and JSON encoding follow the order in which keys were first set. Get, Set
+and Delete are O(1). OrderedMap is not safe for concurrent use.
+*/
+type OrderedMap[K comparable, V any] struct {
+	entries    map[K]*orderedEntry[K, V]
+	head, tail *orderedEntry[K, V]
+}
+
+// NewOrderedMap create an empty OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{entries: make(map[K]*orderedEntry[K, V])}
+}
+
+// Len returns the number of entries.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Get returns the value of key, ok is false if key is absent.
+func (m *OrderedMap[K, V]) Get(key K) (value V, ok bool) {
+	if e, ok := m.entries[key]; ok {
+		return e.value, true
+	}
+	return value, false
+}
+
+// Has reports whether key is in the map.
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+// Set set the value of key, a new key is appended at the end, an existing
+// key keeps its position.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.entries == nil {
+		m.entries = make(map[K]*orderedEntry[K, V])
+	}
+	if e, ok := m.entries[key]; ok {
+		e.value = value
+		return
+	}
+	e := &orderedEntry[K, V]{key: key, value: value, prev: m.tail}
+	if m.tail != nil {
+		m.tail.next = e
+	} else {
+		m.head = e
+	}
+	m.tail = e
+	m.entries[key] = e
+}
+
+// Delete removes key, returns whether it was present.
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		m.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		m.tail = e.prev
+	}
+	delete(m.entries, key)
+	return true
+}
+
+// Range calls fn for each entry in order, until fn returns false.
+func (m *OrderedMap[K, V]) Range(fn func(key K, value V) bool) {
+	for e := m.head; e != nil; e = e.next {
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// Keys returns keys in order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.entries))
+	for e := m.head; e != nil; e = e.next {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// Values returns values in order.
+func (m *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, len(m.entries))
+	for e := m.head; e != nil; e = e.next {
+		values = append(values, e.value)
+	}
+	return values
+}
+
+// First returns the oldest entry, ok is false if the map is empty.
+func (m *OrderedMap[K, V]) First() (key K, value V, ok bool) {
+	if m.head == nil {
+		return key, value, false
+	}
+	return m.head.key, m.head.value, true
+}
+
+// Last returns the newest entry, ok is false if the map is empty.
+func (m *OrderedMap[K, V]) Last() (key K, value V, ok bool) {
+	if m.tail == nil {
+		return key, value, false
+	}
+	return m.tail.key, m.tail.value, true
+}
+
+// MarshalJSON implements json.Marshaler, keys are written in order.
+// Keys must be strings, integers or implement encoding.TextMarshaler.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for e := m.head; e != nil; e = e.next {
+		if e != m.head {
+			buf.WriteByte(',')
+		}
+		key, err := keyToString(e.key)
+		if err != nil {
+			return nil, err
+		}
+		keyJSON, _ := json.Marshal(key)
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		valueJSON, err := json.Marshal(e.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, entries are appended in the
+// order of the json object.
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("umap: expected json object, got %v", token)
+	}
+	if m.entries == nil {
+		m.entries = make(map[K]*orderedEntry[K, V])
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, err := stringToKey[K](token.(string))
+		if err != nil {
+			return err
+		}
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		m.Set(key, value)
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+func keyToString(key interface{}) (string, error) {
+	if tm, ok := key.(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("umap: unsupported json key type %T", key)
+}
+
+func stringToKey[K comparable](s string) (key K, err error) {
+	if tu, ok := interface{}(&key).(encoding.TextUnmarshaler); ok {
+		err = tu.UnmarshalText([]byte(s))
+		return
+	}
+	v := reflect.ValueOf(&key).Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return key, err
+		}
+		v.SetUint(n)
+	default:
+		err = fmt.Errorf("umap: unsupported json key type %T", key)
+	}
+	return
+}
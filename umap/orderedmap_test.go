@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umap
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 30)
+
+	assert.Equal(t, 3, m.Len(), "they should be equal")
+	assert.Equal(t, []string{"c", "a", "b"}, m.Keys(), "they should be equal")
+	assert.Equal(t, []int{30, 1, 2}, m.Values(), "they should be equal")
+
+	v, ok := m.Get("a")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 1, v, "they should be equal")
+	_, ok = m.Get("x")
+	assert.Equal(t, false, ok, "they should be equal")
+
+	assert.Equal(t, true, m.Delete("a"), "they should be equal")
+	assert.Equal(t, false, m.Delete("a"), "they should be equal")
+	assert.Equal(t, false, m.Has("a"), "they should be equal")
+	assert.Equal(t, []string{"c", "b"}, m.Keys(), "they should be equal")
+
+	m.Set("a", 100)
+	assert.Equal(t, []string{"c", "b", "a"}, m.Keys(), "they should be equal")
+
+	k, v, ok := m.First()
+	assert.Equal(t, "c", k, "they should be equal")
+	assert.Equal(t, 30, v, "they should be equal")
+	k, _, _ = m.Last()
+	assert.Equal(t, "a", k, "they should be equal")
+
+	m.Delete("c")
+	m.Delete("a")
+	m.Delete("b")
+	_, _, ok = m.First()
+	assert.Equal(t, false, ok, "they should be equal")
+	assert.Equal(t, []string{}, m.Keys(), "they should be equal")
+}
+
+func TestOrderedMapRange(t *testing.T) {
+	var m OrderedMap[int, string]
+	m.Set(3, "c")
+	m.Set(1, "a")
+	m.Set(2, "b")
+
+	keys := make([]int, 0)
+	m.Range(func(key int, value string) bool {
+		keys = append(keys, key)
+		return key != 1
+	})
+	assert.Equal(t, []int{3, 1}, keys, "they should be equal")
+}
+
+func TestOrderedMapJSON(t *testing.T) {
+	m := NewOrderedMap[string, interface{}]()
+	m.Set("zeta", 1)
+	m.Set("alpha", []int{1, 2})
+	m.Set("mid", map[string]string{"x": "y"})
+
+	data, err := json.Marshal(m)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"zeta":1,"alpha":[1,2],"mid":{"x":"y"}}`, string(data), "they should be equal")
+
+	m2 := NewOrderedMap[string, json.RawMessage]()
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"b": 1, "a": {"c": 2}, "c": "x"}`), m2), "they should be equal")
+	assert.Equal(t, []string{"b", "a", "c"}, m2.Keys(), "they should be equal")
+	v, _ := m2.Get("a")
+	assert.Equal(t, `{"c": 2}`, string(v), "they should be equal")
+
+	type config struct {
+		Env *OrderedMap[int, string] `json:"env"`
+	}
+	var c config
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"env": {"3": "c", "1": "a"}}`), &c), "they should be equal")
+	assert.Equal(t, []int{3, 1}, c.Env.Keys(), "they should be equal")
+	data, _ = json.Marshal(c)
+	assert.Equal(t, `{"env":{"3":"c","1":"a"}}`, string(data), "they should be equal")
+
+	assert.NotEqual(t, nil, json.Unmarshal([]byte(`[1]`), m2), "they should not be equal")
+	assert.NotEqual(t, nil, json.Unmarshal([]byte(`{"x": "a"}`), c.Env), "they should not be equal")
+}
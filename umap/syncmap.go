@@ -0,0 +1,313 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umap
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// DefaultShardCount is the number of shards used when NewSyncMap is given
+// a non-positive count.
+const DefaultShardCount = 32
+
+// EvictReason tells the eviction callback why an entry was removed.
+type EvictReason int
+
+const (
+	// EvictExpired means the entry's TTL elapsed.
+	EvictExpired EvictReason = iota
+	// EvictDeleted means the entry was removed by Delete or Clear.
+	EvictDeleted
+)
+
+type syncMapEntry[V any] struct {
+	value    V
+	expireAt time.Time // zero means never expire
+}
+
+func (e *syncMapEntry[V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+type syncMapShard[K comparable, V any] struct {
+	lock  sync.RWMutex
+	items map[K]*syncMapEntry[V]
+}
+
+/*
+SyncMap is a concurrent-safe map split into shards, each guarded by its own
+lock. Entries may carry a TTL; expired entries are never returned and are
+removed lazily on access, by DeleteExpired, or by the janitor started with
+StartJanitor. An optional callback is told about every removed entry.
+*/
+type SyncMap[K comparable, V any] struct {
+	shards  []*syncMapShard[K, V]
+	onEvict func(key K, value V, reason EvictReason)
+
+	lock sync.Mutex
+	stop chan struct{}
+}
+
+// NewSyncMap create a SyncMap with shardCount shards.
+func NewSyncMap[K comparable, V any](shardCount int) *SyncMap[K, V] {
+	if shardCount <= 0 {
+		shardCount = DefaultShardCount
+	}
+	m := &SyncMap[K, V]{shards: make([]*syncMapShard[K, V], shardCount)}
+	for i := range m.shards {
+		m.shards[i] = &syncMapShard[K, V]{items: make(map[K]*syncMapEntry[V])}
+	}
+	return m
+}
+
+// OnEvict set the callback invoked after an entry is removed. It is called
+// without any lock held, so it may use the map. Set it before use.
+func (m *SyncMap[K, V]) OnEvict(fn func(key K, value V, reason EvictReason)) {
+	m.onEvict = fn
+}
+
+func (m *SyncMap[K, V]) shard(key K) *syncMapShard[K, V] {
+	return m.shards[shardHash(key)%uint64(len(m.shards))]
+}
+
+func shardHash(key interface{}) uint64 {
+	switch k := key.(type) {
+	case string:
+		h := fnv.New64a()
+		h.Write([]byte(k))
+		return h.Sum64()
+	case int:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case uint:
+		return mix64(uint64(k))
+	case uint32:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	}
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%#v", key)
+	return h.Sum64()
+}
+
+// mix64 is the splitmix64 finalizer, spreads sequential ints over shards.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (m *SyncMap[K, V]) evict(key K, value V, reason EvictReason) {
+	if m.onEvict != nil {
+		m.onEvict(key, value, reason)
+	}
+}
+
+// Set stores value without expiry.
+func (m *SyncMap[K, V]) Set(key K, value V) {
+	m.SetWithTTL(key, value, 0)
+}
+
+// SetWithTTL stores value which expires after ttl, ttl <= 0 means never.
+func (m *SyncMap[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	e := &syncMapEntry[V]{value: value}
+	if ttl > 0 {
+		e.expireAt = time.Now().Add(ttl)
+	}
+	s := m.shard(key)
+	s.lock.Lock()
+	s.items[key] = e
+	s.lock.Unlock()
+}
+
+// Get returns the value of key, ok is false if absent or expired.
+func (m *SyncMap[K, V]) Get(key K) (value V, ok bool) {
+	s := m.shard(key)
+	now := time.Now()
+	s.lock.RLock()
+	e, found := s.items[key]
+	s.lock.RUnlock()
+	if !found {
+		return value, false
+	}
+	if !e.expired(now) {
+		return e.value, true
+	}
+
+	s.lock.Lock()
+	// re-check, the entry may have been replaced meanwhile
+	if cur, found := s.items[key]; found && cur == e {
+		delete(s.items, key)
+		s.lock.Unlock()
+		m.evict(key, e.value, EvictExpired)
+	} else {
+		s.lock.Unlock()
+	}
+	return value, false
+}
+
+// Has reports whether key is present and not expired.
+func (m *SyncMap[K, V]) Has(key K) bool {
+	_, ok := m.Get(key)
+	return ok
+}
+
+// Delete removes key, returns whether a live entry was removed.
+func (m *SyncMap[K, V]) Delete(key K) bool {
+	s := m.shard(key)
+	s.lock.Lock()
+	e, found := s.items[key]
+	if found {
+		delete(s.items, key)
+	}
+	s.lock.Unlock()
+	if !found {
+		return false
+	}
+	if e.expired(time.Now()) {
+		m.evict(key, e.value, EvictExpired)
+		return false
+	}
+	m.evict(key, e.value, EvictDeleted)
+	return true
+}
+
+// Len returns the number of entries, including expired ones not yet removed.
+func (m *SyncMap[K, V]) Len() int {
+	n := 0
+	for _, s := range m.shards {
+		s.lock.RLock()
+		n += len(s.items)
+		s.lock.RUnlock()
+	}
+	return n
+}
+
+// Range calls fn for each live entry until fn returns false. Each shard is
+// snapshotted before fn is called, so fn may modify the map.
+func (m *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	type kv struct {
+		key   K
+		value V
+	}
+	now := time.Now()
+	for _, s := range m.shards {
+		s.lock.RLock()
+		items := make([]kv, 0, len(s.items))
+		for k, e := range s.items {
+			if !e.expired(now) {
+				items = append(items, kv{k, e.value})
+			}
+		}
+		s.lock.RUnlock()
+		for _, item := range items {
+			if !fn(item.key, item.value) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all entries.
+func (m *SyncMap[K, V]) Clear() {
+	for _, s := range m.shards {
+		s.lock.Lock()
+		items := s.items
+		s.items = make(map[K]*syncMapEntry[V])
+		s.lock.Unlock()
+		for k, e := range items {
+			m.evict(k, e.value, EvictDeleted)
+		}
+	}
+}
+
+// DeleteExpired removes all expired entries, returns how many were removed.
+func (m *SyncMap[K, V]) DeleteExpired() int {
+	type kv struct {
+		key   K
+		value V
+	}
+	n := 0
+	now := time.Now()
+	for _, s := range m.shards {
+		var expired []kv
+		s.lock.Lock()
+		for k, e := range s.items {
+			if e.expired(now) {
+				delete(s.items, k)
+				expired = append(expired, kv{k, e.value})
+			}
+		}
+		s.lock.Unlock()
+		for _, item := range expired {
+			m.evict(item.key, item.value, EvictExpired)
+		}
+		n += len(expired)
+	}
+	return n
+}
+
+// StartJanitor runs DeleteExpired every interval in a goroutine until
+// StopJanitor is called. Calling it while the janitor runs does nothing.
+func (m *SyncMap[K, V]) StartJanitor(interval time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.stop != nil {
+		return
+	}
+	stop := make(chan struct{})
+	m.stop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.DeleteExpired()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopJanitor stops the janitor goroutine, if running.
+func (m *SyncMap[K, V]) StopJanitor() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.stop != nil {
+		close(m.stop)
+		m.stop = nil
+	}
+}
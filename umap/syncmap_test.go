@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umap
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncMap(t *testing.T) {
+	m := NewSyncMap[string, int](4)
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	v, ok := m.Get("a")
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, 1, v, "they should be equal")
+	assert.Equal(t, 2, m.Len(), "they should be equal")
+
+	var evicted []string
+	m.OnEvict(func(key string, value int, reason EvictReason) {
+		assert.Equal(t, EvictDeleted, reason, "they should be equal")
+		evicted = append(evicted, key)
+	})
+	assert.Equal(t, true, m.Delete("a"), "they should be equal")
+	assert.Equal(t, false, m.Delete("a"), "they should be equal")
+	assert.Equal(t, false, m.Has("a"), "they should be equal")
+	assert.Equal(t, []string{"a"}, evicted, "they should be equal")
+
+	m.Clear()
+	assert.Equal(t, 0, m.Len(), "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, evicted, "they should be equal")
+}
+
+func TestSyncMapTTL(t *testing.T) {
+	m := NewSyncMap[int, string](0)
+	var lock sync.Mutex
+	expired := make(map[int]bool)
+	m.OnEvict(func(key int, value string, reason EvictReason) {
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, EvictExpired, reason, "they should be equal")
+		expired[key] = true
+	})
+
+	m.SetWithTTL(1, "one", 20*time.Millisecond)
+	m.SetWithTTL(2, "two", 20*time.Millisecond)
+	m.SetWithTTL(3, "three", 20*time.Millisecond)
+	m.Set(4, "four")
+	assert.Equal(t, true, m.Has(1), "they should be equal")
+
+	time.Sleep(30 * time.Millisecond)
+	_, ok := m.Get(1)
+	assert.Equal(t, false, ok, "they should be equal")
+	assert.Equal(t, true, expired[1], "they should be equal")
+
+	count := 0
+	m.Range(func(key int, value string) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 1, count, "they should be equal")
+
+	assert.Equal(t, 2, m.DeleteExpired(), "they should be equal")
+	assert.Equal(t, 1, m.Len(), "they should be equal")
+	assert.Equal(t, 3, len(expired), "they should be equal")
+
+	m.SetWithTTL(5, "five", 10*time.Millisecond)
+	m.StartJanitor(5 * time.Millisecond)
+	defer m.StopJanitor()
+	time.Sleep(50 * time.Millisecond)
+	lock.Lock()
+	assert.Equal(t, true, expired[5], "they should be equal")
+	lock.Unlock()
+}
+
+func TestSyncMapJanitorStart(t *testing.T) {
+	m := NewSyncMap[string, int](4)
+	var evicted int32
+	m.OnEvict(func(key string, value int, reason EvictReason) {
+		atomic.AddInt32(&evicted, 1)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.StartJanitor(time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	m.StartJanitor(time.Millisecond)
+
+	// one stop is enough, no janitor was left running
+	m.StopJanitor()
+	time.Sleep(5 * time.Millisecond)
+	m.SetWithTTL("huangjian", 1, time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&evicted), "they should be equal")
+}
+
+func TestSyncMapConcurrent(t *testing.T) {
+	m := NewSyncMap[string, int](8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := strconv.Itoa(i*1000 + j)
+				m.Set(key, j)
+				m.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 8000, m.Len(), "they should be equal")
+}
+
+func BenchmarkSyncMapSet(b *testing.B) {
+	m := NewSyncMap[int, int](0)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Set(i, i)
+			i++
+		}
+	})
+}
@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umap
+
+import "sort"
+
+// Ordered is a constraint for types which support < <= >= >.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~string
+}
+
+// Keys returns the keys of m, in random order.
+func Keys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SortedKeys returns the keys of m in ascending order.
+func SortedKeys[K Ordered, V any](m map[K]V) []K {
+	keys := Keys(m)
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// Values returns the values of m, in random order.
+func Values[K comparable, V any](m map[K]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Merge returns a new map with entries of all maps, for duplicate keys
+// the value of the last map wins.
+func Merge[K comparable, V any](maps ...map[K]V) map[K]V {
+	return MergeWith(KeepLast[K, V], maps...)
+}
+
+// MergeWith returns a new map with entries of all maps, for duplicate keys
+// the value is resolve(key, value so far, value of the current map).
+func MergeWith[K comparable, V any](resolve func(key K, old, new V) V, maps ...map[K]V) map[K]V {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	result := make(map[K]V, size)
+	for _, m := range maps {
+		for k, v := range m {
+			if old, ok := result[k]; ok {
+				v = resolve(k, old, v)
+			}
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// KeepFirst conflict strategy for MergeWith, the first value wins.
+func KeepFirst[K comparable, V any](key K, old, new V) V {
+	return old
+}
+
+// KeepLast conflict strategy for MergeWith, the last value wins.
+func KeepLast[K comparable, V any](key K, old, new V) V {
+	return new
+}
+
+// Filter returns a new map with entries of m for which keep returns true.
+func Filter[K comparable, V any](m map[K]V, keep func(K, V) bool) map[K]V {
+	result := make(map[K]V)
+	for k, v := range m {
+		if keep(k, v) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Invert returns a new map from values to keys of m. If several keys have
+// the same value, which one is kept is undefined.
+func Invert[K, V comparable](m map[K]V) map[V]K {
+	result := make(map[V]K, len(m))
+	for k, v := range m {
+		result[v] = k
+	}
+	return result
+}
+
+// GetOrDefault returns m[key] if key is in m, otherwise def.
+func GetOrDefault[K comparable, V any](m map[K]V, key K, def V) V {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umap
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysValues(t *testing.T) {
+	m := map[string]int{"b": 2, "a": 1, "c": 3}
+
+	keys := Keys(m)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b", "c"}, keys, "they should be equal")
+	assert.Equal(t, []string{"a", "b", "c"}, SortedKeys(m), "they should be equal")
+
+	values := Values(m)
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2, 3}, values, "they should be equal")
+
+	assert.Equal(t, []int{}, SortedKeys(map[int]bool{}), "they should be equal")
+}
+
+func TestMerge(t *testing.T) {
+	a := map[string]int{"x": 1, "y": 2}
+	b := map[string]int{"y": 20, "z": 30}
+
+	assert.Equal(t, map[string]int{"x": 1, "y": 20, "z": 30}, Merge(a, b), "they should be equal")
+	assert.Equal(t, map[string]int{"x": 1, "y": 2, "z": 30}, MergeWith(KeepFirst[string, int], a, b), "they should be equal")
+
+	sum := MergeWith(func(key string, old, new int) int { return old + new }, a, b, a)
+	assert.Equal(t, map[string]int{"x": 2, "y": 24, "z": 30}, sum, "they should be equal")
+
+	// inputs are not modified.
+	assert.Equal(t, map[string]int{"x": 1, "y": 2}, a, "they should be equal")
+}
+
+func TestFilter(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	odd := Filter(m, func(k string, v int) bool { return v%2 == 1 })
+	assert.Equal(t, map[string]int{"a": 1, "c": 3}, odd, "they should be equal")
+}
+
+func TestInvert(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	assert.Equal(t, map[int]string{1: "a", 2: "b"}, Invert(m), "they should be equal")
+}
+
+func TestGetOrDefault(t *testing.T) {
+	m := map[string]int{"a": 0}
+	assert.Equal(t, 0, GetOrDefault(m, "a", 5), "they should be equal")
+	assert.Equal(t, 5, GetOrDefault(m, "b", 5), "they should be equal")
+	assert.Equal(t, 5, GetOrDefault[string, int](nil, "b", 5), "they should be equal")
+}
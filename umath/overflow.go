@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umath
+
+import "errors"
+
+// ErrOverflow is returned when an integer result does not fit its type.
+var ErrOverflow = errors.New("umath: integer overflow")
+
+func isSigned[T Integer]() bool {
+	var zero T
+	return ^zero < 0
+}
+
+// Add returns a + b, or ErrOverflow when the sum does not fit T.
+func Add[T Integer](a, b T) (T, error) {
+	c := a + b
+	if isSigned[T]() {
+		if (b > 0 && c < a) || (b < 0 && c > a) {
+			return 0, ErrOverflow
+		}
+	} else if c < a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// Sub returns a - b, or ErrOverflow when the difference does not fit T.
+func Sub[T Integer](a, b T) (T, error) {
+	c := a - b
+	if isSigned[T]() {
+		if (b > 0 && c > a) || (b < 0 && c < a) {
+			return 0, ErrOverflow
+		}
+	} else if b > a {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
+
+// Mul returns a * b, or ErrOverflow when the product does not fit T.
+func Mul[T Integer](a, b T) (T, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	c := a * b
+	// The sign check catches the smallest signed value times -1, whose
+	// division check passes.
+	if c/b != a || (c < 0) != ((a < 0) != (b < 0)) {
+		return 0, ErrOverflow
+	}
+	return c, nil
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	n, err := Add(int8(100), int8(27))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, int8(127), n, "they should be equal")
+
+	_, err = Add(int8(100), int8(28))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+	_, err = Add(int8(-100), int8(-29))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+	_, err = Add(uint8(200), uint8(56))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+	_, err = Add(int64(math.MaxInt64), 1)
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+}
+
+func TestSub(t *testing.T) {
+	n, err := Sub(int8(-100), int8(28))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, int8(-128), n, "they should be equal")
+
+	_, err = Sub(int8(-100), int8(29))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+	_, err = Sub(int8(100), int8(-28))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+	_, err = Sub(uint(1), uint(2))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+}
+
+func TestMul(t *testing.T) {
+	n, err := Mul(int8(-16), int8(8))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, int8(-128), n, "they should be equal")
+
+	zero, err := Mul(0, int64(math.MaxInt64))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, int64(0), zero, "they should be equal")
+
+	for _, c := range [][2]int8{{16, 8}, {-128, -1}, {-1, -128}, {-16, -8}, {64, 64}} {
+		_, err = Mul(c[0], c[1])
+		assert.Equal(t, ErrOverflow, err, "%v", c)
+	}
+	_, err = Mul(uint32(1<<16), uint32(1<<16))
+	assert.Equal(t, ErrOverflow, err, "they should be equal")
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umath
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+/*
+RoundTo rounds x to decimals digits after the decimal point, half away from
+zero. It rounds the shortest decimal representation of x, so values like
+1.005, stored as 1.00499999999999989..., round up as written. Negative
+decimals round to tens, hundreds and so on.
+
+	RoundTo(1.005, 2) == 1.01
+	RoundTo(-2.5, 0) == -3
+	RoundTo(1234, -2) == 1200
+*/
+func RoundTo(x float64, decimals int) float64 {
+	if x == 0 || math.IsNaN(x) || math.IsInf(x, 0) {
+		return x
+	}
+
+	// "d.ddddde±xx" holds the shortest digits which read back as x.
+	s := strconv.FormatFloat(math.Abs(x), 'e', -1, 64)
+	mantissa, expText := s[:strings.IndexByte(s, 'e')], s[strings.IndexByte(s, 'e')+1:]
+	exp, _ := strconv.Atoi(expText)
+	digits := []byte(strings.Replace(mantissa, ".", "", 1))
+
+	// The value is 0.digits * 10^(exp+1), keep that many leading digits.
+	keep := exp + 1 + decimals
+	if keep >= len(digits) {
+		return x
+	}
+	if keep < 0 {
+		return math.Copysign(0, x)
+	}
+
+	roundUp := digits[keep] >= '5'
+	digits = digits[:keep]
+	if roundUp {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i >= 0 {
+			digits[i]++
+		} else {
+			digits = append([]byte{'1'}, digits...)
+			exp++
+		}
+	}
+	if len(digits) == 0 {
+		return math.Copysign(0, x)
+	}
+
+	// digits now stand for 0.digits * 10^(exp+1) with fewer digits kept.
+	value, _ := strconv.ParseFloat("0."+string(digits)+"e"+strconv.Itoa(exp+1), 64)
+	return math.Copysign(value, x)
+}
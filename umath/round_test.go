@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTo(t *testing.T) {
+	tests := []struct {
+		x        float64
+		decimals int
+		want     float64
+	}{
+		{1.005, 2, 1.01},
+		{1.004, 2, 1},
+		{-1.005, 2, -1.01},
+		{2.5, 0, 3},
+		{-2.5, 0, -3},
+		{0.4, 0, 0},
+		{9.99, 1, 10},
+		{0.125, 2, 0.13},
+		{1234, -2, 1200},
+		{1250, -2, 1300},
+		{49, -2, 0},
+		{3.14159, 10, 3.14159},
+		{1e-20, 2, 0},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.want, RoundTo(test.x, test.decimals), "%v %d", test.x, test.decimals)
+	}
+	assert.Equal(t, true, math.IsNaN(RoundTo(math.NaN(), 2)), "they should be equal")
+	assert.Equal(t, math.Inf(1), RoundTo(math.Inf(1), 2), "they should be equal")
+}
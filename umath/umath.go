@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umath
+
+import (
+	"math"
+	"sort"
+)
+
+// Signed is a constraint for signed integer types.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is a constraint for unsigned integer types.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is a constraint for integer types.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Float is a constraint for floating point types.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Number is a constraint for integer and floating point types.
+type Number interface {
+	Integer | Float
+}
+
+// Ordered is a constraint for types which support < <= >= >.
+type Ordered interface {
+	Number | ~string
+}
+
+// Min returns the smallest of its arguments.
+func Min[T Ordered](a T, rest ...T) T {
+	for _, v := range rest {
+		if v < a {
+			a = v
+		}
+	}
+	return a
+}
+
+// Max returns the largest of its arguments.
+func Max[T Ordered](a T, rest ...T) T {
+	for _, v := range rest {
+		if v > a {
+			a = v
+		}
+	}
+	return a
+}
+
+// Clamp limits v to [lo, hi].
+func Clamp[T Ordered](v, lo, hi T) T {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// Abs returns the absolute value of v. For the smallest signed integer the
+// result overflows and stays negative, as in two's complement.
+func Abs[T Signed | Float](v T) T {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Sum returns the sum of vals, 0 for none.
+func Sum[T Number](vals ...T) T {
+	var sum T
+	for _, v := range vals {
+		sum += v
+	}
+	return sum
+}
+
+// Mean returns the arithmetic mean of vals, 0 for none. It sums in float64
+// so integers do not overflow.
+func Mean[T Number](vals []T) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range vals {
+		sum += float64(v)
+	}
+	return sum / float64(len(vals))
+}
+
+// Median returns the middle value of vals, the mean of the two middle ones
+// for an even count, 0 for none. vals is not modified.
+func Median[T Number](vals []T) float64 {
+	return Percentile(vals, 50)
+}
+
+/*
+Percentile returns the p-th percentile of vals, p in [0, 100], with linear
+interpolation between the closest ranks, 0 for none. vals is not modified.
+
+	Percentile([]int{1, 2, 3, 4}, 50) == 2.5
+	Percentile(latencies, 99)
+*/
+func Percentile[T Number](vals []T, p float64) float64 {
+	if len(vals) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(vals))
+	for i, v := range vals {
+		sorted[i] = float64(v)
+	}
+	sort.Float64s(sorted)
+
+	rank := Clamp(p, 0, 100) / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
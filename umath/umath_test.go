@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umath
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinMax(t *testing.T) {
+	assert.Equal(t, 1, Min(3, 1, 2), "they should be equal")
+	assert.Equal(t, 3, Max(3, 1, 2), "they should be equal")
+	assert.Equal(t, "MDGSF", Min("huangjian", "MDGSF"), "they should be equal")
+	assert.Equal(t, time.Second, Max(time.Millisecond, time.Second), "they should be equal")
+	assert.Equal(t, 5, Min(5), "they should be equal")
+
+	assert.Equal(t, 10, Clamp(11, 0, 10), "they should be equal")
+	assert.Equal(t, 0, Clamp(-1, 0, 10), "they should be equal")
+	assert.Equal(t, 0.5, Clamp(0.5, 0, 1), "they should be equal")
+
+	assert.Equal(t, 3, Abs(-3), "they should be equal")
+	assert.Equal(t, 1.5, Abs(-1.5), "they should be equal")
+	assert.Equal(t, time.Second, Abs(-time.Second), "they should be equal")
+}
+
+func TestStats(t *testing.T) {
+	assert.Equal(t, 6, Sum(1, 2, 3), "they should be equal")
+	assert.Equal(t, 0, Sum[int](), "they should be equal")
+	assert.Equal(t, 2.0, Mean([]int{1, 2, 3}), "they should be equal")
+	assert.Equal(t, 0.0, Mean([]int{}), "they should be equal")
+
+	vals := []int{4, 1, 3, 2}
+	assert.Equal(t, 2.5, Median(vals), "they should be equal")
+	assert.Equal(t, []int{4, 1, 3, 2}, vals, "they should be equal")
+	assert.Equal(t, 3.0, Median([]int{5, 1, 3}), "they should be equal")
+
+	assert.Equal(t, 1.0, Percentile(vals, 0), "they should be equal")
+	assert.Equal(t, 4.0, Percentile(vals, 100), "they should be equal")
+	assert.Equal(t, 4.0, Percentile(vals, 150), "they should be equal")
+	assert.Equal(t, 1.75, Percentile(vals, 25), "they should be equal")
+	assert.Equal(t, 0.0, Percentile([]float64{}, 50), "they should be equal")
+}
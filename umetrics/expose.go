@@ -0,0 +1,225 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Quantiles are the quantiles of histograms exposed by WritePrometheus and
+// Publish.
+var Quantiles = []float64{0.5, 0.9, 0.95, 0.99}
+
+// splitName splits `name{labels}` into a Prometheus safe name and its
+// labels without braces.
+func splitName(name string) (base, labels string) {
+	base = name
+	if i := strings.IndexByte(name, '{'); i >= 0 && strings.HasSuffix(name, "}") {
+		base, labels = name[:i], name[i+1:len(name)-1]
+	}
+	safe := []byte(base)
+	for i, c := range safe {
+		ok := c == '_' || c == ':' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9'
+		if !ok {
+			safe[i] = '_'
+		}
+	}
+	return string(safe), labels
+}
+
+// withLabels returns base{labels,extra}, without braces when both are
+// empty.
+func withLabels(base, labels, extra string) string {
+	switch {
+	case labels != "" && extra != "":
+		return base + "{" + labels + "," + extra + "}"
+	case labels != "":
+		return base + "{" + labels + "}"
+	case extra != "":
+		return base + "{" + extra + "}"
+	}
+	return base
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+/*
+WritePrometheus writes the metrics of r in the Prometheus text format,
+histograms as summaries:
+
+	# TYPE db_query_seconds summary
+	db_query_seconds{quantile="0.5"} 0.0123
+	...
+	db_query_seconds_sum 4.56
+	db_query_seconds_count 321
+*/
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	type line struct {
+		kind string
+		text []string
+	}
+	byBase := make(map[string]*line)
+	add := func(name, kind string, text func(base, labels string) []string) {
+		base, labels := splitName(name)
+		l, ok := byBase[base]
+		if !ok {
+			l = &line{kind: kind}
+			byBase[base] = l
+		}
+		l.text = append(l.text, text(base, labels)...)
+	}
+
+	r.lock.Lock()
+	counters := make(map[string]*Counter, len(r.counters))
+	for name, c := range r.counters {
+		counters[name] = c
+	}
+	gauges := make(map[string]*Gauge, len(r.gauges))
+	for name, g := range r.gauges {
+		gauges[name] = g
+	}
+	histograms := make(map[string]*Histogram, len(r.histograms))
+	for name, h := range r.histograms {
+		histograms[name] = h
+	}
+	r.lock.Unlock()
+
+	all := make(map[string]bool)
+	for name := range counters {
+		all[name] = true
+	}
+	for name := range gauges {
+		all[name] = true
+	}
+	for name := range histograms {
+		all[name] = true
+	}
+	for _, name := range names(all) {
+		if c, ok := counters[name]; ok {
+			add(name, "counter", func(base, labels string) []string {
+				return []string{withLabels(base, labels, "") + " " + strconv.FormatInt(c.Value(), 10)}
+			})
+		}
+		if g, ok := gauges[name]; ok {
+			add(name, "gauge", func(base, labels string) []string {
+				return []string{withLabels(base, labels, "") + " " + formatFloat(g.Value())}
+			})
+		}
+		if h, ok := histograms[name]; ok {
+			add(name, "summary", func(base, labels string) []string {
+				s := h.Snapshot()
+				var text []string
+				for _, q := range Quantiles {
+					text = append(text, fmt.Sprintf("%s %s", withLabels(base, labels, `quantile="`+formatFloat(q)+`"`), formatFloat(s.Quantile(q))))
+				}
+				return append(text,
+					withLabels(base+"_sum", labels, "")+" "+formatFloat(s.Sum),
+					withLabels(base+"_count", labels, "")+" "+strconv.FormatUint(s.Count, 10))
+			})
+		}
+	}
+
+	bases := make(map[string]bool, len(byBase))
+	for base := range byBase {
+		bases[base] = true
+	}
+	bw := bufio.NewWriter(w)
+	for _, base := range names(bases) {
+		l := byBase[base]
+		fmt.Fprintf(bw, "# TYPE %s %s\n", base, l.kind)
+		for _, text := range l.text {
+			bw.WriteString(text)
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// Handler returns an http.Handler serving the metrics of r in the
+// Prometheus text format, to mount on /metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WritePrometheus(w)
+	})
+}
+
+// Handler returns the http.Handler of Default.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// expvarValue returns the metrics of r as a JSON friendly map.
+func (r *Registry) expvarValue() interface{} {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	counters := make(map[string]int64, len(r.counters))
+	for name, c := range r.counters {
+		counters[name] = c.Value()
+	}
+	gauges := make(map[string]float64, len(r.gauges))
+	for name, g := range r.gauges {
+		gauges[name] = g.Value()
+	}
+	histograms := make(map[string]map[string]float64, len(r.histograms))
+	for name, h := range r.histograms {
+		s := h.Snapshot()
+		m := map[string]float64{
+			"count": float64(s.Count),
+			"sum":   s.Sum,
+			"min":   s.Min,
+			"max":   s.Max,
+			"mean":  s.Mean(),
+		}
+		for _, q := range Quantiles {
+			m["p"+strconv.FormatFloat(q*100, 'g', -1, 64)] = s.Quantile(q)
+		}
+		histograms[name] = m
+	}
+	return map[string]interface{}{
+		"counters":   counters,
+		"gauges":     gauges,
+		"histograms": histograms,
+	}
+}
+
+// Publish exposes the metrics of r under name in expvar, served on
+// /debug/vars. Like expvar.Publish it panics if name is already used.
+func (r *Registry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(r.expvarValue))
+}
@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	r := NewRegistry()
+	r.Counter(`http.requests{code="200"}`).Add(3)
+	r.Counter(`http.requests{code="500"}`).Inc()
+	r.Gauge("queue-size").Set(2.5)
+	r.Histogram("db.query").Observe(1)
+
+	var buf bytes.Buffer
+	assert.Nil(t, r.WritePrometheus(&buf))
+	want := `# TYPE db_query summary
+db_query{quantile="0.5"} 1
+db_query{quantile="0.9"} 1
+db_query{quantile="0.95"} 1
+db_query{quantile="0.99"} 1
+db_query_sum 1
+db_query_count 1
+# TYPE http_requests counter
+http_requests{code="200"} 3
+http_requests{code="500"} 1
+# TYPE queue_size gauge
+queue_size 2.5
+`
+	assert.Equal(t, want, buf.String(), "they should be equal")
+}
+
+func TestHandler(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("huangjian").Inc()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "they should be equal")
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+	assert.True(t, strings.Contains(rec.Body.String(), "huangjian 1\n"))
+}
+
+func TestPublish(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("huangjian").Add(2)
+	r.Histogram("MDGSF").Observe(4)
+	r.Publish("umetrics_test")
+
+	var v struct {
+		Counters   map[string]int64
+		Histograms map[string]map[string]float64
+	}
+	assert.Nil(t, json.Unmarshal([]byte(expvar.Get("umetrics_test").String()), &v))
+	assert.Equal(t, int64(2), v.Counters["huangjian"], "they should be equal")
+	assert.Equal(t, 1.0, v.Histograms["MDGSF"]["count"], "they should be equal")
+	assert.Equal(t, 4.0, v.Histograms["MDGSF"]["p50"], "they should be equal")
+}
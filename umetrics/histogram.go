@@ -0,0 +1,178 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// subBuckets is the number of linear buckets per power of two, values are
+// recorded with a relative error below 1/(2*subBuckets), about 3%.
+const subBuckets = 16
+
+/*
+Histogram records the distribution of values in log-linear buckets, like
+an HDR histogram: memory grows with the range of values, not their count,
+and quantiles are accurate to a few percent whatever the range. Values
+<= 0 are counted as 0.
+*/
+type Histogram struct {
+	lock    sync.Mutex
+	buckets map[int]uint64
+	zeros   uint64
+	count   uint64
+	sum     float64
+	min     float64
+	max     float64
+}
+
+// NewHistogram returns an empty Histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{buckets: make(map[int]uint64)}
+}
+
+// bucketOf returns the bucket of v > 0.
+func bucketOf(v float64) int {
+	frac, exp := math.Frexp(v) // v = frac * 2^exp, frac in [0.5, 1)
+	sub := int((frac - 0.5) * 2 * subBuckets)
+	if sub >= subBuckets {
+		sub = subBuckets - 1
+	}
+	return exp*subBuckets + sub
+}
+
+// bucketMid returns the middle of bucket b.
+func bucketMid(b int) float64 {
+	exp := b / subBuckets
+	sub := b % subBuckets
+	if sub < 0 {
+		exp--
+		sub += subBuckets
+	}
+	lo := math.Ldexp(0.5+float64(sub)/(2*subBuckets), exp)
+	hi := math.Ldexp(0.5+float64(sub+1)/(2*subBuckets), exp)
+	return (lo + hi) / 2
+}
+
+// Observe records v.
+func (h *Histogram) Observe(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	if v < 0 {
+		v = 0
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if v == 0 {
+		h.zeros++
+	} else {
+		h.buckets[bucketOf(v)]++
+	}
+	if h.count == 0 || v < h.min {
+		h.min = v
+	}
+	if h.count == 0 || v > h.max {
+		h.max = v
+	}
+	h.count++
+	h.sum += v
+}
+
+// HistogramSnapshot is the state of a Histogram at some point.
+type HistogramSnapshot struct {
+	Count uint64
+	Sum   float64
+	Min   float64
+	Max   float64
+
+	zeros   uint64
+	buckets []bucketCount
+}
+
+type bucketCount struct {
+	bucket int
+	count  uint64
+}
+
+// Snapshot returns the current state of h.
+func (h *Histogram) Snapshot() *HistogramSnapshot {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	s := &HistogramSnapshot{
+		Count:   h.count,
+		Sum:     h.sum,
+		Min:     h.min,
+		Max:     h.max,
+		zeros:   h.zeros,
+		buckets: make([]bucketCount, 0, len(h.buckets)),
+	}
+	for b, n := range h.buckets {
+		s.buckets = append(s.buckets, bucketCount{b, n})
+	}
+	sort.Slice(s.buckets, func(i, j int) bool { return s.buckets[i].bucket < s.buckets[j].bucket })
+	return s
+}
+
+// Mean returns the mean of the values, 0 when empty.
+func (s *HistogramSnapshot) Mean() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// Quantile returns the value below which a fraction q of the values fall,
+// q in [0, 1], e.g. 0.99 for the 99th percentile. It is 0 when empty.
+func (s *HistogramSnapshot) Quantile(q float64) float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return s.Min
+	}
+	if q >= 1 {
+		return s.Max
+	}
+	rank := uint64(math.Ceil(q * float64(s.Count)))
+	seen := s.zeros
+	if seen >= rank {
+		return 0
+	}
+	for _, b := range s.buckets {
+		seen += b.count
+		if seen >= rank {
+			v := bucketMid(b.bucket)
+			if v < s.Min {
+				v = s.Min
+			}
+			if v > s.Max {
+				v = s.Max
+			}
+			return v
+		}
+	}
+	return s.Max
+}
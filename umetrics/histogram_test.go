@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramQuantile(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 10000; i++ {
+		h.Observe(float64(i))
+	}
+	s := h.Snapshot()
+	assert.Equal(t, uint64(10000), s.Count, "they should be equal")
+	assert.Equal(t, 1.0, s.Min, "they should be equal")
+	assert.Equal(t, 10000.0, s.Max, "they should be equal")
+	assert.Equal(t, 5000.5, s.Mean(), "they should be equal")
+	for _, q := range []float64{0.5, 0.9, 0.99} {
+		want := q * 10000
+		assert.InDelta(t, want, s.Quantile(q), want*0.04)
+	}
+	assert.Equal(t, 1.0, s.Quantile(0), "they should be equal")
+	assert.Equal(t, 10000.0, s.Quantile(1), "they should be equal")
+}
+
+func TestHistogramZeros(t *testing.T) {
+	h := NewHistogram()
+	h.Observe(0)
+	h.Observe(-1)
+	h.Observe(math.NaN())
+	h.Observe(0.001)
+	s := h.Snapshot()
+	assert.Equal(t, uint64(3), s.Count, "they should be equal")
+	assert.Equal(t, 0.0, s.Quantile(0.5), "they should be equal")
+	assert.InDelta(t, 0.001, s.Quantile(1), 0.0001)
+}
+
+func TestHistogramEmpty(t *testing.T) {
+	s := NewHistogram().Snapshot()
+	assert.Equal(t, uint64(0), s.Count, "they should be equal")
+	assert.Equal(t, 0.0, s.Mean(), "they should be equal")
+	assert.Equal(t, 0.0, s.Quantile(0.5), "they should be equal")
+}
@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+/*
+Middleware counts the requests of a handler by status code in
+prefix_requests_total{code="200"}, records their duration in
+prefix_request_duration_seconds, and the requests being served in
+prefix_requests_in_flight. It is a uhttp.Middleware:
+
+	h := uhttp.Chain(mux, umetrics.Default.Middleware("api"))
+*/
+func (r *Registry) Middleware(prefix string) func(http.Handler) http.Handler {
+	inFlight := r.Gauge(prefix + "_requests_in_flight")
+	duration := r.Histogram(prefix + "_request_duration_seconds")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			inFlight.Inc()
+			sw := &statusWriter{ResponseWriter: w}
+			defer func() {
+				inFlight.Dec()
+				duration.Observe(time.Since(start).Seconds())
+				status := sw.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				r.Counter(prefix + `_requests_total{code="` + strconv.Itoa(status) + `"}`).Inc()
+			}()
+			next.ServeHTTP(sw, req)
+		})
+	}
+}
+
+// Middleware returns the Middleware of Default.
+func Middleware(prefix string) func(http.Handler) http.Handler {
+	return Default.Middleware(prefix)
+}
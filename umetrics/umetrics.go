@@ -0,0 +1,203 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counter is a value which only goes up, like a number of requests.
+type Counter struct {
+	v int64
+}
+
+// Inc adds one to c.
+func (c *Counter) Inc() {
+	atomic.AddInt64(&c.v, 1)
+}
+
+// Add adds n to c, negative values are ignored.
+func (c *Counter) Add(n int64) {
+	if n > 0 {
+		atomic.AddInt64(&c.v, n)
+	}
+}
+
+// Value returns the value of c.
+func (c *Counter) Value() int64 {
+	return atomic.LoadInt64(&c.v)
+}
+
+// Gauge is a value which goes up and down, like a queue length.
+type Gauge struct {
+	bits uint64
+}
+
+// Set sets g to v.
+func (g *Gauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+// Add adds delta to g.
+func (g *Gauge) Add(delta float64) {
+	for {
+		old := atomic.LoadUint64(&g.bits)
+		v := math.Float64bits(math.Float64frombits(old) + delta)
+		if atomic.CompareAndSwapUint64(&g.bits, old, v) {
+			return
+		}
+	}
+}
+
+// Inc adds one to g.
+func (g *Gauge) Inc() {
+	g.Add(1)
+}
+
+// Dec subtracts one from g.
+func (g *Gauge) Dec() {
+	g.Add(-1)
+}
+
+// Value returns the value of g.
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Registry holds named metrics. A name may end with Prometheus labels,
+// like `http_requests_total{code="200"}`.
+type Registry struct {
+	lock       sync.Mutex
+	counters   map[string]*Counter
+	gauges     map[string]*Gauge
+	histograms map[string]*Histogram
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		counters:   make(map[string]*Counter),
+		gauges:     make(map[string]*Gauge),
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Counter returns the counter name, created on first use.
+func (r *Registry) Counter(name string) *Counter {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	c, ok := r.counters[name]
+	if !ok {
+		c = &Counter{}
+		r.counters[name] = c
+	}
+	return c
+}
+
+// Gauge returns the gauge name, created on first use.
+func (r *Registry) Gauge(name string) *Gauge {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	g, ok := r.gauges[name]
+	if !ok {
+		g = &Gauge{}
+		r.gauges[name] = g
+	}
+	return g
+}
+
+// Histogram returns the histogram name, created on first use.
+func (r *Registry) Histogram(name string) *Histogram {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	h, ok := r.histograms[name]
+	if !ok {
+		h = NewHistogram()
+		r.histograms[name] = h
+	}
+	return h
+}
+
+// Time returns a function observing the seconds elapsed since Time was
+// called in the histogram name.
+func (r *Registry) Time(name string) func() {
+	h := r.Histogram(name)
+	start := time.Now()
+	return func() {
+		h.Observe(time.Since(start).Seconds())
+	}
+}
+
+// Reset removes all the metrics.
+func (r *Registry) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.counters = make(map[string]*Counter)
+	r.gauges = make(map[string]*Gauge)
+	r.histograms = make(map[string]*Histogram)
+}
+
+// names returns the sorted keys of m.
+func names(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for name := range m {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Default is the registry of the package level functions.
+var Default = NewRegistry()
+
+// GetCounter returns the counter name of Default.
+func GetCounter(name string) *Counter {
+	return Default.Counter(name)
+}
+
+// GetGauge returns the gauge name of Default.
+func GetGauge(name string) *Gauge {
+	return Default.Gauge(name)
+}
+
+// GetHistogram returns the histogram name of Default.
+func GetHistogram(name string) *Histogram {
+	return Default.Histogram(name)
+}
+
+/*
+Time returns a function observing the seconds elapsed since Time was
+called in the histogram name of Default:
+
+	func query() {
+		defer umetrics.Time("db_query_seconds")()
+		...
+	}
+*/
+func Time(name string) func() {
+	return Default.Time(name)
+}
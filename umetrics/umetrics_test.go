@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package umetrics
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r.Counter("huangjian").Inc()
+			}
+		}()
+	}
+	wg.Wait()
+	r.Counter("huangjian").Add(5)
+	assert.Equal(t, int64(1005), r.Counter("huangjian").Value(), "they should be equal")
+}
+
+func TestGauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.Gauge("MDGSF")
+	g.Set(1.5)
+	g.Inc()
+	g.Add(0.25)
+	g.Dec()
+	assert.Equal(t, 1.75, g.Value(), "they should be equal")
+	assert.Equal(t, g, r.Gauge("MDGSF"), "they should be equal")
+}
+
+func TestTime(t *testing.T) {
+	r := NewRegistry()
+	stop := r.Time("db.query")
+	time.Sleep(10 * time.Millisecond)
+	stop()
+	s := r.Histogram("db.query").Snapshot()
+	assert.Equal(t, uint64(1), s.Count, "they should be equal")
+	assert.True(t, s.Sum >= 0.01)
+}
+
+func TestReset(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("huangjian").Inc()
+	r.Reset()
+	assert.Equal(t, int64(0), r.Counter("huangjian").Value(), "they should be equal")
+}
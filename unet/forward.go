@@ -0,0 +1,240 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ForwardStats are the counters of a Forwarder.
+type ForwardStats struct {
+	Active   int64 // connections being relayed
+	Total    int64 // connections accepted
+	Rejected int64 // connections refused by WithMaxConns or a failed dial
+	BytesIn  int64 // from clients to the target
+	BytesOut int64 // from the target to clients
+}
+
+type forwardOptions struct {
+	maxConns    int64
+	dialTimeout time.Duration
+}
+
+// ForwardOption configures Forward.
+type ForwardOption func(*forwardOptions)
+
+// WithMaxConns closes new connections at once while n are relayed, 0 means
+// no limit.
+func WithMaxConns(n int) ForwardOption {
+	return func(o *forwardOptions) {
+		o.maxConns = int64(n)
+	}
+}
+
+// WithForwardDialTimeout limits dialing the target, default 5s.
+func WithForwardDialTimeout(d time.Duration) ForwardOption {
+	return func(o *forwardOptions) {
+		o.dialTimeout = d
+	}
+}
+
+// Forwarder relays TCP connections, see Forward.
+type Forwarder struct {
+	listener net.Listener
+	target   string
+	opts     forwardOptions
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+
+	active, total, rejected, bytesIn, bytesOut int64
+
+	lock  sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+/*
+Forward listens on listenAddr and relays every connection to targetAddr
+until ctx is done or Close is called.
+
+	fw, err := unet.Forward(ctx, "127.0.0.1:0", "db:5432")
+	defer fw.Close()
+	connect(fw.Addr().String())
+*/
+func Forward(ctx context.Context, listenAddr, targetAddr string, opts ...ForwardOption) (*Forwarder, error) {
+	o := forwardOptions{dialTimeout: 5 * time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	l, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Forwarder{
+		listener: l,
+		target:   targetAddr,
+		opts:     o,
+		ctx:      ctx,
+		cancel:   cancel,
+		conns:    make(map[net.Conn]struct{}),
+	}
+	f.wg.Add(2)
+	go f.accept()
+	go func() {
+		defer f.wg.Done()
+		<-ctx.Done()
+		l.Close()
+		f.lock.Lock()
+		for conn := range f.conns {
+			conn.Close()
+		}
+		f.lock.Unlock()
+	}()
+	return f, nil
+}
+
+// Addr returns the listening address, useful with port 0.
+func (f *Forwarder) Addr() net.Addr {
+	return f.listener.Addr()
+}
+
+// Stats returns a snapshot of the counters.
+func (f *Forwarder) Stats() ForwardStats {
+	return ForwardStats{
+		Active:   atomic.LoadInt64(&f.active),
+		Total:    atomic.LoadInt64(&f.total),
+		Rejected: atomic.LoadInt64(&f.rejected),
+		BytesIn:  atomic.LoadInt64(&f.bytesIn),
+		BytesOut: atomic.LoadInt64(&f.bytesOut),
+	}
+}
+
+// Close stops listening, closes the relayed connections and waits for
+// them.
+func (f *Forwarder) Close() error {
+	f.cancel()
+	f.wg.Wait()
+	return nil
+}
+
+func (f *Forwarder) accept() {
+	defer f.wg.Done()
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			if f.ctx.Err() != nil {
+				return
+			}
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				time.Sleep(10 * time.Millisecond)
+				continue
+			}
+			return
+		}
+		atomic.AddInt64(&f.total, 1)
+		if f.opts.maxConns > 0 && atomic.LoadInt64(&f.active) >= f.opts.maxConns {
+			atomic.AddInt64(&f.rejected, 1)
+			conn.Close()
+			continue
+		}
+		atomic.AddInt64(&f.active, 1)
+		f.wg.Add(1)
+		go f.relay(conn)
+	}
+}
+
+// track registers conn for Close, false if the forwarder is closing.
+func (f *Forwarder) track(conn net.Conn) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.ctx.Err() != nil {
+		return false
+	}
+	f.conns[conn] = struct{}{}
+	return true
+}
+
+func (f *Forwarder) untrack(conn net.Conn) {
+	f.lock.Lock()
+	delete(f.conns, conn)
+	f.lock.Unlock()
+	conn.Close()
+}
+
+func (f *Forwarder) relay(client net.Conn) {
+	defer f.wg.Done()
+	defer atomic.AddInt64(&f.active, -1)
+	if !f.track(client) {
+		client.Close()
+		return
+	}
+	defer f.untrack(client)
+
+	dialer := net.Dialer{Timeout: f.opts.dialTimeout}
+	target, err := dialer.DialContext(f.ctx, "tcp", f.target)
+	if err != nil {
+		atomic.AddInt64(&f.rejected, 1)
+		return
+	}
+	if !f.track(target) {
+		target.Close()
+		return
+	}
+	defer f.untrack(target)
+
+	done := make(chan struct{})
+	go func() {
+		pipe(target, client, &f.bytesIn)
+		close(done)
+	}()
+	pipe(client, target, &f.bytesOut)
+	<-done
+}
+
+// pipe copies src to dst, then half closes dst so the peer sees EOF.
+func pipe(dst, src net.Conn, counter *int64) {
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if _, werr := dst.Write(buf[:n]); werr != nil {
+				break
+			}
+			atomic.AddInt64(counter, int64(n))
+		}
+		if err != nil {
+			break
+		}
+	}
+	if tcp, ok := dst.(*net.TCPConn); ok {
+		tcp.CloseWrite()
+	} else {
+		dst.Close()
+	}
+}
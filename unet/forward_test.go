@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// echoServer echoes every connection until closed.
+func echoServer(t *testing.T) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return l
+}
+
+func TestForward(t *testing.T) {
+	echo := echoServer(t)
+	defer echo.Close()
+
+	fw, err := Forward(context.Background(), "127.0.0.1:0", echo.Addr().String())
+	assert.Equal(t, nil, err, "they should be equal")
+	defer fw.Close()
+
+	conn, err := net.Dial("tcp", fw.Addr().String())
+	assert.Equal(t, nil, err, "they should be equal")
+	conn.Write([]byte("huangjian"))
+	conn.(*net.TCPConn).CloseWrite()
+	data, err := ioutil.ReadAll(conn)
+	conn.Close()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", string(data), "they should be equal")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for fw.Stats().Active != 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(t, ForwardStats{Total: 1, BytesIn: 9, BytesOut: 9}, fw.Stats(), "they should be equal")
+}
+
+func TestForwardMaxConns(t *testing.T) {
+	echo := echoServer(t)
+	defer echo.Close()
+
+	fw, err := Forward(context.Background(), "127.0.0.1:0", echo.Addr().String(), WithMaxConns(1))
+	assert.Equal(t, nil, err, "they should be equal")
+	defer fw.Close()
+
+	first, _ := net.Dial("tcp", fw.Addr().String())
+	defer first.Close()
+	first.Write([]byte("MDGSF"))
+	buf := make([]byte, 5)
+	io.ReadFull(first, buf)
+
+	second, _ := net.Dial("tcp", fw.Addr().String())
+	defer second.Close()
+	second.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = second.Read(buf)
+	assert.Equal(t, io.EOF, err, "they should be equal")
+	assert.Equal(t, int64(1), fw.Stats().Rejected, "they should be equal")
+}
+
+func TestForwardClose(t *testing.T) {
+	echo := echoServer(t)
+	defer echo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fw, err := Forward(ctx, "127.0.0.1:0", echo.Addr().String())
+	assert.Equal(t, nil, err, "they should be equal")
+
+	conn, _ := net.Dial("tcp", fw.Addr().String())
+	defer conn.Close()
+	conn.Write([]byte("MDGSF"))
+	buf := make([]byte, 5)
+	io.ReadFull(conn, buf)
+
+	cancel()
+	fw.Close()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = conn.Read(buf)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = net.DialTimeout("tcp", fw.Addr().String(), 100*time.Millisecond)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	// a dead target counts as rejected
+	port, _ := FreePort()
+	fw, _ = Forward(context.Background(), "127.0.0.1:0", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	defer fw.Close()
+	conn2, _ := net.Dial("tcp", fw.Addr().String())
+	defer conn2.Close()
+	conn2.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, err = conn2.Read(buf)
+	assert.Equal(t, io.EOF, err, "they should be equal")
+	assert.Equal(t, int64(1), fw.Stats().Rejected, "they should be equal")
+}
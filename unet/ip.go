@@ -0,0 +1,254 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+)
+
+// MaxRangeSize is the most addresses IPsInRange returns.
+const MaxRangeSize = 1 << 16
+
+// ErrRangeTooLarge is returned by IPsInRange for more than MaxRangeSize
+// addresses.
+var ErrRangeTooLarge = errors.New("unet: range too large")
+
+// CIDRContains reports whether cidr contains ip.
+func CIDRContains(cidr, ip string) (bool, error) {
+	_, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, err
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false, fmt.Errorf("unet: invalid ip %q", ip)
+	}
+	return n.Contains(parsed), nil
+}
+
+// normalize returns the 4 byte form of IPv4 addresses and the 16 byte form
+// of IPv6 ones.
+func normalize(ip net.IP) net.IP {
+	if v4 := ip.To4(); v4 != nil {
+		return v4
+	}
+	return ip.To16()
+}
+
+// NextIP returns the address after ip, nil after the last address.
+func NextIP(ip net.IP) net.IP {
+	next := append(net.IP(nil), normalize(ip)...)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next
+		}
+	}
+	return nil
+}
+
+// PrevIP returns the address before ip, nil before the first address.
+func PrevIP(ip net.IP) net.IP {
+	prev := append(net.IP(nil), normalize(ip)...)
+	for i := len(prev) - 1; i >= 0; i-- {
+		prev[i]--
+		if prev[i] != 0xff {
+			return prev
+		}
+	}
+	return nil
+}
+
+// compareIP orders addresses of the same family.
+func compareIP(a, b net.IP) int {
+	return bytes.Compare(normalize(a), normalize(b))
+}
+
+func sameFamily(a, b net.IP) bool {
+	return (a.To4() == nil) == (b.To4() == nil)
+}
+
+// IPsInRange returns the addresses from start to end, both included.
+func IPsInRange(start, end net.IP) ([]net.IP, error) {
+	if start == nil || end == nil || !sameFamily(start, end) {
+		return nil, fmt.Errorf("unet: invalid range %v-%v", start, end)
+	}
+	if compareIP(start, end) > 0 {
+		return nil, fmt.Errorf("unet: range start %v after end %v", start, end)
+	}
+	size := new(big.Int).Sub(new(big.Int).SetBytes(normalize(end)), new(big.Int).SetBytes(normalize(start)))
+	if size.Cmp(big.NewInt(MaxRangeSize-1)) > 0 {
+		return nil, ErrRangeTooLarge
+	}
+
+	ips := make([]net.IP, 0, size.Int64()+1)
+	for ip := normalize(start); ; ip = NextIP(ip) {
+		ips = append(ips, ip)
+		if compareIP(ip, end) == 0 {
+			return ips, nil
+		}
+	}
+}
+
+var specialNets = parseNets(
+	"0.0.0.0/8",          // this network
+	"100.64.0.0/10",      // carrier grade NAT
+	"192.0.0.0/24",       // IETF protocol assignments
+	"192.0.2.0/24",       // documentation
+	"198.18.0.0/15",      // benchmarking
+	"198.51.100.0/24",    // documentation
+	"203.0.113.0/24",     // documentation
+	"240.0.0.0/4",        // reserved
+	"255.255.255.255/32", // broadcast
+	"64:ff9b:1::/48",     // local use translation
+	"100::/64",           // discard only
+	"2001::/23",          // IETF protocol assignments
+	"2001:db8::/32",      // documentation
+)
+
+func parseNets(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
+
+// IsPrivateIP reports whether ip is in a private range, RFC 1918 for IPv4
+// and RFC 4193 for IPv6.
+func IsPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate()
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address: not
+// private, loopback, link local, multicast, unspecified, carrier grade NAT,
+// documentation or otherwise reserved.
+func IsPublicIP(ip net.IP) bool {
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range specialNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ipRange is an inclusive range of addresses of one family.
+type ipRange struct {
+	start, end net.IP
+}
+
+/*
+IPSet is a set of addresses built from single IPs, CIDRs and ranges, for
+allowlists and ACLs. The zero value is an empty set.
+*/
+type IPSet struct {
+	ranges []ipRange
+}
+
+/*
+ParseIPList parses a list of IPs, CIDRs and ranges separated by commas or
+white space.
+
+	set, err := unet.ParseIPList("10.0.0.0/8, 192.168.1.10-192.168.1.20 ::1")
+*/
+func ParseIPList(s string) (*IPSet, error) {
+	set := &IPSet{}
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	for _, field := range fields {
+		if err := set.Add(field); err != nil {
+			return nil, err
+		}
+	}
+	return set, nil
+}
+
+// Add adds an IP, a CIDR or a range "start-end" to the set.
+func (s *IPSet) Add(entry string) error {
+	entry = strings.TrimSpace(entry)
+	switch {
+	case strings.Contains(entry, "/"):
+		_, n, err := net.ParseCIDR(entry)
+		if err != nil {
+			return err
+		}
+		start := normalize(n.IP)
+		end := make(net.IP, len(start))
+		for i := range start {
+			end[i] = start[i] | ^n.Mask[len(n.Mask)-len(start)+i]
+		}
+		s.ranges = append(s.ranges, ipRange{start, end})
+	case strings.Contains(entry, "-"):
+		parts := strings.SplitN(entry, "-", 2)
+		start := net.ParseIP(strings.TrimSpace(parts[0]))
+		end := net.ParseIP(strings.TrimSpace(parts[1]))
+		if start == nil || end == nil || !sameFamily(start, end) || compareIP(start, end) > 0 {
+			return fmt.Errorf("unet: invalid ip range %q", entry)
+		}
+		s.ranges = append(s.ranges, ipRange{normalize(start), normalize(end)})
+	default:
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return fmt.Errorf("unet: invalid ip %q", entry)
+		}
+		s.ranges = append(s.ranges, ipRange{normalize(ip), normalize(ip)})
+	}
+	return nil
+}
+
+// Contains reports whether ip is in the set.
+func (s *IPSet) Contains(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, r := range s.ranges {
+		if sameFamily(ip, r.start) && compareIP(ip, r.start) >= 0 && compareIP(ip, r.end) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsString is Contains for a textual address, false if invalid.
+func (s *IPSet) ContainsString(ip string) bool {
+	return s.Contains(net.ParseIP(ip))
+}
+
+// Len returns the number of entries added.
+func (s *IPSet) Len() int {
+	return len(s.ranges)
+}
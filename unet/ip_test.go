@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCIDRContains(t *testing.T) {
+	ok, err := CIDRContains("10.0.0.0/8", "10.1.2.3")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, ok, "they should be equal")
+	ok, _ = CIDRContains("10.0.0.0/8", "11.1.2.3")
+	assert.Equal(t, false, ok, "they should be equal")
+	ok, _ = CIDRContains("2001:db8::/32", "2001:db8::1")
+	assert.Equal(t, true, ok, "they should be equal")
+
+	_, err = CIDRContains("huangjian", "10.1.2.3")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = CIDRContains("10.0.0.0/8", "MDGSF")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestNextPrevIP(t *testing.T) {
+	assert.Equal(t, "10.0.1.0", NextIP(net.ParseIP("10.0.0.255")).String(), "they should be equal")
+	assert.Equal(t, "10.0.0.255", PrevIP(net.ParseIP("10.0.1.0")).String(), "they should be equal")
+	assert.Equal(t, "::1:0", NextIP(net.ParseIP("::ffff")).String(), "they should be equal")
+	assert.Equal(t, true, NextIP(net.ParseIP("255.255.255.255")) == nil, "they should be equal")
+	assert.Equal(t, true, PrevIP(net.ParseIP("0.0.0.0")) == nil, "they should be equal")
+}
+
+func TestIPsInRange(t *testing.T) {
+	ips, err := IPsInRange(net.ParseIP("192.168.0.254"), net.ParseIP("192.168.1.1"))
+	assert.Equal(t, nil, err, "they should be equal")
+	var strs []string
+	for _, ip := range ips {
+		strs = append(strs, ip.String())
+	}
+	assert.Equal(t, []string{"192.168.0.254", "192.168.0.255", "192.168.1.0", "192.168.1.1"}, strs, "they should be equal")
+
+	ips, _ = IPsInRange(net.ParseIP("::1"), net.ParseIP("::1"))
+	assert.Equal(t, 1, len(ips), "they should be equal")
+
+	_, err = IPsInRange(net.ParseIP("10.0.0.0"), net.ParseIP("10.255.255.255"))
+	assert.Equal(t, true, errors.Is(err, ErrRangeTooLarge), "they should be equal")
+	_, err = IPsInRange(net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = IPsInRange(net.ParseIP("10.0.0.1"), net.ParseIP("::1"))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestIPClassification(t *testing.T) {
+	for _, ip := range []string{"10.1.2.3", "172.16.0.1", "192.168.1.1", "fd00::1"} {
+		assert.Equal(t, true, IsPrivateIP(net.ParseIP(ip)), ip)
+		assert.Equal(t, false, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"127.0.0.1", "169.254.1.1", "100.64.0.1", "192.0.2.1", "0.0.0.0", "224.0.0.1", "::1", "fe80::1", "2001:db8::1"} {
+		assert.Equal(t, false, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "1.1.1.1", "2606:4700:4700::1111"} {
+		assert.Equal(t, true, IsPublicIP(net.ParseIP(ip)), ip)
+		assert.Equal(t, false, IsPrivateIP(net.ParseIP(ip)), ip)
+	}
+	assert.Equal(t, false, IsPublicIP(nil), "they should be equal")
+}
+
+func TestParseIPList(t *testing.T) {
+	set, err := ParseIPList("10.0.0.0/8, 192.168.1.10-192.168.1.20\n::1 2001:db8::/126 8.8.8.8")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 5, set.Len(), "they should be equal")
+
+	for _, ip := range []string{"10.255.255.255", "192.168.1.10", "192.168.1.20", "::1", "2001:db8::3", "8.8.8.8", "::ffff:8.8.8.8"} {
+		assert.Equal(t, true, set.ContainsString(ip), ip)
+	}
+	for _, ip := range []string{"11.0.0.0", "192.168.1.21", "::2", "2001:db8::4", "8.8.4.4", "huangjian"} {
+		assert.Equal(t, false, set.ContainsString(ip), ip)
+	}
+
+	for _, list := range []string{"huangjian", "10.0.0.0/33", "10.0.0.2-10.0.0.1", "10.0.0.1-::1", "1.2.3.4-MDGSF"} {
+		_, err = ParseIPList(list)
+		assert.NotEqual(t, nil, err, list)
+	}
+
+	var empty IPSet
+	assert.Equal(t, false, empty.ContainsString("10.0.0.1"), "they should be equal")
+}
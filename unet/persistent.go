@@ -0,0 +1,341 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/uretry"
+)
+
+var (
+	// ErrNotConnected is returned by Write while the connection is down.
+	ErrNotConnected = errors.New("unet: not connected")
+	// ErrConnClosed is returned by Write after Close.
+	ErrConnClosed = errors.New("unet: connection closed")
+)
+
+/*
+ConnHandler receives the events of a PersistentConn. The callbacks run on
+the goroutine of the connection, so they must not block for long. Embed
+NopConnHandler to implement only some of them.
+*/
+type ConnHandler interface {
+	// OnConnect is called after every successful dial.
+	OnConnect(conn net.Conn)
+	// OnDisconnect is called when an established connection is lost.
+	OnDisconnect(err error)
+	// OnDialError is called after a failed dial, before waiting delay.
+	OnDialError(attempt int, err error, delay time.Duration)
+	// OnData is called with data read from the connection, data is only
+	// valid during the call.
+	OnData(data []byte)
+}
+
+// NopConnHandler ignores all events.
+type NopConnHandler struct{}
+
+func (NopConnHandler) OnConnect(conn net.Conn)                                 {}
+func (NopConnHandler) OnDisconnect(err error)                                  {}
+func (NopConnHandler) OnDialError(attempt int, err error, delay time.Duration) {}
+func (NopConnHandler) OnData(data []byte)                                      {}
+
+type connOptions struct {
+	dialTimeout    time.Duration
+	writeTimeout   time.Duration
+	backoff        uretry.Backoff
+	minUptime      time.Duration
+	heartbeat      time.Duration
+	heartbeatFrame []byte
+	handler        ConnHandler
+}
+
+// ConnOption configures a PersistentConn.
+type ConnOption func(*connOptions)
+
+// WithDialTimeout limits every dial, default 5s.
+func WithDialTimeout(d time.Duration) ConnOption {
+	return func(o *connOptions) {
+		o.dialTimeout = d
+	}
+}
+
+// WithWriteTimeout limits every write, 0 means none.
+func WithWriteTimeout(d time.Duration) ConnOption {
+	return func(o *connOptions) {
+		o.writeTimeout = d
+	}
+}
+
+// WithReconnectBackoff set the delay between dials, default exponential
+// from 100ms to 30s with jitter.
+func WithReconnectBackoff(b uretry.Backoff) ConnOption {
+	return func(o *connOptions) {
+		o.backoff = b
+	}
+}
+
+// WithMinUptime set how long a connection must stay up before the backoff
+// is reset, default 5s. A connection lost earlier is redialed after the next
+// backoff delay, so a server that accepts and closes at once is not
+// hammered.
+func WithMinUptime(d time.Duration) ConnOption {
+	return func(o *connOptions) {
+		o.minUptime = d
+	}
+}
+
+// WithHeartbeat writes frame every interval while connected, a failed
+// heartbeat triggers a reconnect.
+func WithHeartbeat(interval time.Duration, frame []byte) ConnOption {
+	return func(o *connOptions) {
+		o.heartbeat = interval
+		o.heartbeatFrame = frame
+	}
+}
+
+// WithConnHandler set the event handler.
+func WithConnHandler(h ConnHandler) ConnOption {
+	return func(o *connOptions) {
+		o.handler = h
+	}
+}
+
+/*
+PersistentConn keeps a TCP or Unix connection to an address open,
+redialing with backoff when it is lost. Writes fail fast with
+ErrNotConnected while the connection is down, so callers decide whether to
+buffer or drop.
+*/
+type PersistentConn struct {
+	network string
+	addr    string
+	opts    connOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	lock      sync.Mutex
+	conn      net.Conn
+	ready     chan struct{} // closed while connected
+	writeLock sync.Mutex
+}
+
+// NewPersistentConn starts connecting to addr on network ("tcp", "unix"...)
+// in the background.
+func NewPersistentConn(network, addr string, opts ...ConnOption) *PersistentConn {
+	o := connOptions{
+		dialTimeout: 5 * time.Second,
+		backoff:     uretry.JitterBackoff(uretry.ExponentialBackoff(100*time.Millisecond, 30*time.Second)),
+		minUptime:   5 * time.Second,
+		handler:     NopConnHandler{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &PersistentConn{
+		network: network,
+		addr:    addr,
+		opts:    o,
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		ready:   make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// Connected reports whether the connection is up.
+func (p *PersistentConn) Connected() bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.conn != nil
+}
+
+// WaitConnected blocks until the connection is up or ctx is done.
+func (p *PersistentConn) WaitConnected(ctx context.Context) error {
+	p.lock.Lock()
+	ready := p.ready
+	p.lock.Unlock()
+	select {
+	case <-ready:
+		return nil
+	case <-p.done:
+		return ErrConnClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Write writes b to the current connection. A failed write drops the
+// connection, which is then redialed.
+func (p *PersistentConn) Write(b []byte) (int, error) {
+	if p.ctx.Err() != nil {
+		return 0, ErrConnClosed
+	}
+	p.lock.Lock()
+	conn := p.conn
+	p.lock.Unlock()
+	if conn == nil {
+		return 0, ErrNotConnected
+	}
+
+	p.writeLock.Lock()
+	defer p.writeLock.Unlock()
+	if p.opts.writeTimeout > 0 {
+		conn.SetWriteDeadline(time.Now().Add(p.opts.writeTimeout))
+	}
+	n, err := conn.Write(b)
+	if err != nil {
+		// unblocks the read loop, which reconnects
+		conn.Close()
+	}
+	return n, err
+}
+
+// Close closes the connection and stops reconnecting.
+func (p *PersistentConn) Close() error {
+	p.cancel()
+	p.lock.Lock()
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.lock.Unlock()
+	<-p.done
+	return nil
+}
+
+func (p *PersistentConn) run() {
+	defer close(p.done)
+	dialer := net.Dialer{Timeout: p.opts.dialTimeout}
+	for attempt := 1; ; attempt++ {
+		conn, err := dialer.DialContext(p.ctx, p.network, p.addr)
+		if p.ctx.Err() != nil {
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+		if err != nil {
+			delay := p.opts.backoff(attempt)
+			p.opts.handler.OnDialError(attempt, err, delay)
+			if !p.sleep(delay) {
+				return
+			}
+			continue
+		}
+
+		start := time.Now()
+		p.serve(conn)
+		if p.ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) >= p.opts.minUptime {
+			attempt = 0
+			continue
+		}
+		if !p.sleep(p.opts.backoff(attempt)) {
+			return
+		}
+	}
+}
+
+// serve runs an established connection until it fails.
+func (p *PersistentConn) serve(conn net.Conn) {
+	p.lock.Lock()
+	if p.ctx.Err() != nil {
+		p.lock.Unlock()
+		conn.Close()
+		return
+	}
+	p.conn = conn
+	close(p.ready)
+	p.lock.Unlock()
+	p.opts.handler.OnConnect(conn)
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	if p.opts.heartbeat > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.heartbeat(stop)
+		}()
+	}
+
+	buf := make([]byte, 32<<10)
+	var err error
+	for {
+		var n int
+		n, err = conn.Read(buf)
+		if n > 0 {
+			p.opts.handler.OnData(buf[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+	conn.Close()
+	p.lock.Lock()
+	p.conn = nil
+	p.ready = make(chan struct{})
+	p.lock.Unlock()
+	if p.ctx.Err() == nil {
+		p.opts.handler.OnDisconnect(err)
+	}
+}
+
+func (p *PersistentConn) heartbeat(stop chan struct{}) {
+	ticker := time.NewTicker(p.opts.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := p.Write(p.opts.heartbeatFrame); err != nil {
+				return
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *PersistentConn) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,216 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/uretry"
+	"github.com/stretchr/testify/assert"
+)
+
+type testHandler struct {
+	NopConnHandler
+	connects    chan struct{}
+	disconnects chan error
+	data        chan string
+	dialErrors  int32
+}
+
+func newTestHandler() *testHandler {
+	return &testHandler{
+		connects:    make(chan struct{}, 10),
+		disconnects: make(chan error, 10),
+		data:        make(chan string, 10),
+	}
+}
+
+func (h *testHandler) OnConnect(conn net.Conn) { h.connects <- struct{}{} }
+func (h *testHandler) OnDisconnect(err error)  { h.disconnects <- err }
+func (h *testHandler) OnData(data []byte)      { h.data <- string(data) }
+
+func (h *testHandler) OnDialError(attempt int, err error, delay time.Duration) {
+	atomic.AddInt32(&h.dialErrors, 1)
+}
+
+func wait(t *testing.T, ch interface{}) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	switch c := ch.(type) {
+	case chan struct{}:
+		select {
+		case <-c:
+			return
+		case <-timeout:
+		}
+	case chan error:
+		select {
+		case <-c:
+			return
+		case <-timeout:
+		}
+	}
+	t.Fatal("timeout waiting for event")
+}
+
+func TestPersistentConn(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer l.Close()
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	h := newTestHandler()
+	p := NewPersistentConn("tcp", l.Addr().String(),
+		WithConnHandler(h),
+		WithReconnectBackoff(uretry.FixedBackoff(10*time.Millisecond)))
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	assert.Equal(t, nil, p.WaitConnected(ctx), "they should be equal")
+	assert.Equal(t, true, p.Connected(), "they should be equal")
+	wait(t, h.connects)
+
+	server := <-conns
+	_, err = p.Write([]byte("huangjian\n"))
+	assert.Equal(t, nil, err, "they should be equal")
+	line, _ := bufio.NewReader(server).ReadString('\n')
+	assert.Equal(t, "huangjian\n", line, "they should be equal")
+
+	server.Write([]byte("MDGSF"))
+	select {
+	case data := <-h.data:
+		assert.Equal(t, "MDGSF", data, "they should be equal")
+	case <-time.After(2 * time.Second):
+		t.Fatal("no data")
+	}
+
+	// the server drops the connection, it comes back
+	server.Close()
+	wait(t, h.disconnects)
+	wait(t, h.connects)
+	server = <-conns
+	defer server.Close()
+	assert.Equal(t, nil, p.WaitConnected(ctx), "they should be equal")
+
+	p.Close()
+	_, err = p.Write([]byte("huangjian"))
+	assert.Equal(t, true, errors.Is(err, ErrConnClosed), "they should be equal")
+	assert.Equal(t, true, errors.Is(p.WaitConnected(ctx), ErrConnClosed), "they should be equal")
+}
+
+func TestPersistentConnDialErrors(t *testing.T) {
+	port, _ := FreePort()
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	h := newTestHandler()
+	p := NewPersistentConn("tcp", addr,
+		WithConnHandler(h),
+		WithReconnectBackoff(uretry.FixedBackoff(10*time.Millisecond)))
+	defer p.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	_, err := p.Write([]byte("huangjian"))
+	assert.Equal(t, true, errors.Is(err, ErrNotConnected), "they should be equal")
+	assert.Equal(t, true, atomic.LoadInt32(&h.dialErrors) >= 2, "they should be equal")
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip("port taken meanwhile")
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			defer conn.Close()
+			buf := make([]byte, 64)
+			for {
+				if _, err := conn.Read(buf); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	wait(t, h.connects)
+}
+
+func TestPersistentConnHeartbeat(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer l.Close()
+
+	p := NewPersistentConn("tcp", l.Addr().String(), WithHeartbeat(10*time.Millisecond, []byte("ping\n")))
+	defer p.Close()
+	server, err := l.Accept()
+	assert.Equal(t, nil, err, "they should be equal")
+	defer server.Close()
+
+	r := bufio.NewReader(server)
+	for i := 0; i < 3; i++ {
+		server.SetReadDeadline(time.Now().Add(2 * time.Second))
+		line, err := r.ReadString('\n')
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, "ping\n", line, "they should be equal")
+	}
+}
+
+func TestPersistentConnAcceptThenClose(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer l.Close()
+	var accepts int32
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&accepts, 1)
+			conn.Close()
+		}
+	}()
+
+	p := NewPersistentConn("tcp", l.Addr().String(),
+		WithReconnectBackoff(uretry.ExponentialBackoff(10*time.Millisecond, time.Second)))
+	time.Sleep(300 * time.Millisecond)
+	p.Close()
+
+	// 10+20+40+80+160ms, without backoff it would be thousands
+	n := atomic.LoadInt32(&accepts)
+	assert.Equal(t, true, n >= 2 && n <= 7, "they should be equal")
+}
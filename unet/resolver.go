@@ -0,0 +1,418 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/usingle"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+type resolverOptions struct {
+	servers     []string
+	timeout     time.Duration
+	minTTL      time.Duration
+	maxTTL      time.Duration
+	negativeTTL time.Duration
+	defaultTTL  time.Duration
+}
+
+// ResolverOption configures a Resolver.
+type ResolverOption func(*resolverOptions)
+
+// WithServers queries the given DNS servers ("8.8.8.8" or "8.8.8.8:53") in
+// order instead of the system resolver. Only then the record TTLs are
+// known.
+func WithServers(servers ...string) ResolverOption {
+	return func(o *resolverOptions) {
+		for _, server := range servers {
+			if _, _, err := net.SplitHostPort(server); err != nil {
+				server = net.JoinHostPort(server, "53")
+			}
+			o.servers = append(o.servers, server)
+		}
+	}
+}
+
+// WithLookupTimeout limits every lookup, default 5s.
+func WithLookupTimeout(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.timeout = d
+	}
+}
+
+// WithTTLBounds clamps record TTLs to [min, max], default [1s, 1h].
+func WithTTLBounds(min, max time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.minTTL, o.maxTTL = min, max
+	}
+}
+
+// WithNegativeTTL caches failed lookups for d, default 5s, 0 disables it.
+func WithNegativeTTL(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.negativeTTL = d
+	}
+}
+
+// WithDefaultTTL set how long answers of the system resolver, which hides
+// the TTLs, are cached, default 30s.
+func WithDefaultTTL(d time.Duration) ResolverOption {
+	return func(o *resolverOptions) {
+		o.defaultTTL = d
+	}
+}
+
+type resolverEntry struct {
+	ips     []net.IP
+	err     error
+	expires time.Time
+}
+
+/*
+Resolver is a caching DNS resolver. Answers are cached for their TTL and
+concurrent lookups of the same host share one query.
+
+	r := unet.NewResolver(unet.WithServers("1.1.1.1", "8.8.8.8"))
+	ips, err := r.LookupIP(ctx, "example.com")
+*/
+type Resolver struct {
+	opts  resolverOptions
+	group usingle.Group[string, []net.IP]
+	now   func() time.Time
+
+	lock  sync.Mutex
+	cache map[string]resolverEntry
+}
+
+// NewResolver create a caching resolver.
+func NewResolver(opts ...ResolverOption) *Resolver {
+	o := resolverOptions{
+		timeout:     5 * time.Second,
+		minTTL:      time.Second,
+		maxTTL:      time.Hour,
+		negativeTTL: 5 * time.Second,
+		defaultTTL:  30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Resolver{opts: o, now: time.Now, cache: make(map[string]resolverEntry)}
+}
+
+// LookupIP returns the IPv4 and IPv6 addresses of host.
+func (r *Resolver) LookupIP(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	key := strings.ToLower(strings.TrimSuffix(host, "."))
+
+	r.lock.Lock()
+	entry, ok := r.cache[key]
+	if ok && r.now().Before(entry.expires) {
+		r.lock.Unlock()
+		return copyIPs(entry.ips), entry.err
+	}
+	r.lock.Unlock()
+
+	// the lookup is shared by every caller of key, so it must not stop when
+	// the caller which started it goes away
+	ch := r.group.DoChan(key, func() ([]net.IP, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), r.opts.timeout)
+		defer cancel()
+		ips, ttl, err := r.lookup(ctx, key)
+		if err != nil {
+			ttl = r.opts.negativeTTL
+			if ctx.Err() != nil {
+				// do not cache a timeout
+				ttl = 0
+			}
+		}
+		if ttl > 0 {
+			r.lock.Lock()
+			r.cache[key] = resolverEntry{ips: ips, err: err, expires: r.now().Add(ttl)}
+			r.lock.Unlock()
+		}
+		return ips, err
+	})
+	select {
+	case res := <-ch:
+		return copyIPs(res.Val), res.Err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LookupIPv4 returns the IPv4 addresses of host.
+func (r *Resolver) LookupIPv4(ctx context.Context, host string) ([]net.IP, error) {
+	ips, err := r.LookupIP(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var v4 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+		}
+	}
+	if len(v4) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return v4, nil
+}
+
+// LookupAll resolves hosts concurrently. The map holds the hosts which
+// resolved, the error is the first failure.
+func (r *Resolver) LookupAll(ctx context.Context, hosts []string) (map[string][]net.IP, error) {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var firstErr error
+	result := make(map[string][]net.IP, len(hosts))
+	for _, host := range hosts {
+		wg.Add(1)
+		go func(host string) {
+			defer wg.Done()
+			ips, err := r.LookupIP(ctx, host)
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			result[host] = ips
+		}(host)
+	}
+	wg.Wait()
+	return result, firstErr
+}
+
+// Flush empties the cache.
+func (r *Resolver) Flush() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cache = make(map[string]resolverEntry)
+}
+
+// Len returns the number of cached hosts, expired ones included.
+func (r *Resolver) Len() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.cache)
+}
+
+func copyIPs(ips []net.IP) []net.IP {
+	if ips == nil {
+		return nil
+	}
+	return append([]net.IP(nil), ips...)
+}
+
+func (r *Resolver) clampTTL(ttl time.Duration) time.Duration {
+	if ttl < r.opts.minTTL {
+		return r.opts.minTTL
+	}
+	if r.opts.maxTTL > 0 && ttl > r.opts.maxTTL {
+		return r.opts.maxTTL
+	}
+	return ttl
+}
+
+func (r *Resolver) lookup(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	if len(r.opts.servers) == 0 {
+		ips, err := net.DefaultResolver.LookupIP(ctx, "ip", host)
+		return ips, r.opts.defaultTTL, err
+	}
+
+	var lastErr error
+	for _, server := range r.opts.servers {
+		ips, ttl, err := r.queryServer(ctx, server, host)
+		if err == nil {
+			return ips, r.clampTTL(ttl), nil
+		}
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return nil, 0, err
+		}
+		lastErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, 0, lastErr
+}
+
+// queryServer asks server for the A and AAAA records of host.
+func (r *Resolver) queryServer(ctx context.Context, server, host string) ([]net.IP, time.Duration, error) {
+	var ips []net.IP
+	ttl := time.Duration(-1)
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		found, foundTTL, err := exchange(ctx, server, host, qtype)
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		ips = append(ips, found...)
+		if len(found) > 0 && (ttl < 0 || foundTTL < ttl) {
+			ttl = foundTTL
+		}
+	}
+	if len(ips) == 0 {
+		return nil, 0, &net.DNSError{Err: "no such host", Name: host, Server: server, IsNotFound: true}
+	}
+	return ips, ttl, nil
+}
+
+func exchange(ctx context.Context, server, host string, qtype dnsmessage.Type) ([]net.IP, time.Duration, error) {
+	name, err := dnsmessage.NewName(host + ".")
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: "invalid host name", Name: host}
+	}
+	// an unpredictable id makes spoofed answers harder to slip in
+	var idBuf [2]byte
+	if _, err := rand.Read(idBuf[:]); err != nil {
+		return nil, 0, err
+	}
+	id := binary.BigEndian.Uint16(idBuf[:])
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	query, err := msg.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := exchangeUDP(ctx, server, query, id)
+	if err == nil && resp.Truncated {
+		resp, err = exchangeTCP(ctx, server, query, id)
+	}
+	if err != nil {
+		return nil, 0, &net.DNSError{Err: err.Error(), Name: host, Server: server, IsTimeout: ctx.Err() != nil}
+	}
+
+	switch resp.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, 0, &net.DNSError{Err: "no such host", Name: host, Server: server, IsNotFound: true}
+	default:
+		return nil, 0, &net.DNSError{Err: "server failure: " + resp.RCode.String(), Name: host, Server: server, IsTemporary: true}
+	}
+
+	var ips []net.IP
+	ttl := time.Duration(-1)
+	for _, answer := range resp.Answers {
+		var ip net.IP
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			ip = net.IP(body.A[:])
+		case *dnsmessage.AAAAResource:
+			ip = net.IP(body.AAAA[:])
+		default:
+			continue
+		}
+		ips = append(ips, append(net.IP(nil), ip...))
+		if d := time.Duration(answer.Header.TTL) * time.Second; ttl < 0 || d < ttl {
+			ttl = d
+		}
+	}
+	return ips, ttl, nil
+}
+
+func exchangeUDP(ctx context.Context, server string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 1232)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var resp dnsmessage.Message
+		if err := resp.Unpack(buf[:n]); err != nil || resp.ID != id || !resp.Response {
+			// not ours, keep waiting
+			continue
+		}
+		return &resp, nil
+	}
+}
+
+func exchangeTCP(ctx context.Context, server string, query []byte, id uint16) (*dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	framed := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(framed, uint16(len(query)))
+	copy(framed[2:], query)
+	if _, err := conn.Write(framed); err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	var resp dnsmessage.Message
+	if err := resp.Unpack(buf); err != nil {
+		return nil, err
+	}
+	if resp.ID != id {
+		return nil, fmt.Errorf("unet: dns response id mismatch")
+	}
+	return &resp, nil
+}
@@ -0,0 +1,264 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fakeDNS answers on UDP and TCP of the same port.
+type fakeDNS struct {
+	udp     net.PacketConn
+	tcp     net.Listener
+	queries int32
+}
+
+func newFakeDNS(t *testing.T) *fakeDNS {
+	for i := 0; i < 10; i++ {
+		tcp, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		udp, err := net.ListenPacket("udp", tcp.Addr().String())
+		if err != nil {
+			tcp.Close()
+			continue
+		}
+		f := &fakeDNS{udp: udp, tcp: tcp}
+		go f.serveUDP()
+		go f.serveTCP()
+		return f
+	}
+	t.Skip("no free port for udp and tcp")
+	return nil
+}
+
+func (f *fakeDNS) addr() string {
+	return f.tcp.Addr().String()
+}
+
+func (f *fakeDNS) close() {
+	f.udp.Close()
+	f.tcp.Close()
+}
+
+func (f *fakeDNS) answer(query []byte, overTCP bool) []byte {
+	atomic.AddInt32(&f.queries, 1)
+	var msg dnsmessage.Message
+	if err := msg.Unpack(query); err != nil || len(msg.Questions) != 1 {
+		return nil
+	}
+	q := msg.Questions[0]
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: msg.ID, Response: true, RecursionAvailable: true},
+		Questions: msg.Questions,
+	}
+	header := func(ttl uint32) dnsmessage.ResourceHeader {
+		return dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: dnsmessage.ClassINET, TTL: ttl}
+	}
+
+	switch q.Name.String() {
+	case "huangjian.test.":
+		if q.Type == dnsmessage.TypeA {
+			resp.Answers = append(resp.Answers,
+				dnsmessage.Resource{Header: header(60), Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+				dnsmessage.Resource{Header: header(30), Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}})
+		} else {
+			resp.Answers = append(resp.Answers,
+				dnsmessage.Resource{Header: header(90), Body: &dnsmessage.AAAAResource{AAAA: [16]byte{15: 1}}})
+		}
+	case "big.test.":
+		if !overTCP {
+			resp.Truncated = true
+		} else if q.Type == dnsmessage.TypeA {
+			resp.Answers = append(resp.Answers,
+				dnsmessage.Resource{Header: header(60), Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 3}}})
+		}
+	case "slow.test.":
+		time.Sleep(100 * time.Millisecond)
+		if q.Type == dnsmessage.TypeA {
+			resp.Answers = append(resp.Answers,
+				dnsmessage.Resource{Header: header(60), Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 4}}})
+		}
+	case "broken.test.":
+		resp.RCode = dnsmessage.RCodeServerFailure
+	default:
+		resp.RCode = dnsmessage.RCodeNameError
+	}
+	data, _ := resp.Pack()
+	return data
+}
+
+func (f *fakeDNS) serveUDP() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := f.udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := f.answer(buf[:n], false); resp != nil {
+			f.udp.WriteTo(resp, addr)
+		}
+	}
+}
+
+func (f *fakeDNS) serveTCP() {
+	for {
+		conn, err := f.tcp.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			var length [2]byte
+			if _, err := io.ReadFull(conn, length[:]); err != nil {
+				return
+			}
+			query := make([]byte, binary.BigEndian.Uint16(length[:]))
+			if _, err := io.ReadFull(conn, query); err != nil {
+				return
+			}
+			resp := f.answer(query, true)
+			binary.BigEndian.PutUint16(length[:], uint16(len(resp)))
+			conn.Write(append(length[:], resp...))
+		}()
+	}
+}
+
+func TestResolver(t *testing.T) {
+	dns := newFakeDNS(t)
+	defer dns.close()
+
+	r := NewResolver(WithServers(dns.addr()))
+	var lock sync.Mutex
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time {
+		lock.Lock()
+		defer lock.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		lock.Lock()
+		now = now.Add(d)
+		lock.Unlock()
+	}
+	ctx := context.Background()
+
+	ips, err := r.LookupIP(ctx, "huangjian.test")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.1").To4(), net.ParseIP("10.0.0.2").To4(), net.ParseIP("::1")}, ips, "they should be equal")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&dns.queries), "they should be equal")
+
+	// cached for the smallest TTL, 30s
+	advance(29 * time.Second)
+	r.LookupIP(ctx, "HuangJian.test.")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&dns.queries), "they should be equal")
+	advance(2 * time.Second)
+	r.LookupIP(ctx, "huangjian.test")
+	assert.Equal(t, int32(4), atomic.LoadInt32(&dns.queries), "they should be equal")
+
+	v4, err := r.LookupIPv4(ctx, "huangjian.test")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 2, len(v4), "they should be equal")
+
+	// negative answers are cached too
+	_, err = r.LookupIP(ctx, "MDGSF.test")
+	var dnsErr *net.DNSError
+	assert.Equal(t, true, errors.As(err, &dnsErr) && dnsErr.IsNotFound, "they should be equal")
+	queries := atomic.LoadInt32(&dns.queries)
+	r.LookupIP(ctx, "MDGSF.test")
+	assert.Equal(t, queries, atomic.LoadInt32(&dns.queries), "they should be equal")
+	assert.Equal(t, 2, r.Len(), "they should be equal")
+	r.Flush()
+	assert.Equal(t, 0, r.Len(), "they should be equal")
+
+	ips, err = r.LookupIP(ctx, "big.test")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.3").To4()}, ips, "they should be equal")
+
+	_, err = r.LookupIP(ctx, "broken.test")
+	assert.Equal(t, true, errors.As(err, &dnsErr) && dnsErr.IsTemporary, "they should be equal")
+
+	ips, _ = r.LookupIP(ctx, "127.0.0.1")
+	assert.Equal(t, "127.0.0.1", ips[0].String(), "they should be equal")
+}
+
+func TestResolverLookupAll(t *testing.T) {
+	dns := newFakeDNS(t)
+	defer dns.close()
+
+	// the first server is dead, the second answers
+	port, _ := FreePort()
+	dead := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	r := NewResolver(WithServers(dead, dns.addr()), WithLookupTimeout(time.Second))
+	result, err := r.LookupAll(context.Background(), []string{"huangjian.test", "big.test", "MDGSF.test"})
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	assert.Equal(t, 2, len(result), "they should be equal")
+	assert.Equal(t, 3, len(result["huangjian.test"]), "they should be equal")
+}
+
+func TestResolverSharedLookupCancel(t *testing.T) {
+	dns := newFakeDNS(t)
+	defer dns.close()
+	r := NewResolver(WithServers(dns.addr()), WithLookupTimeout(2*time.Second))
+
+	// the first caller gives up, the one sharing its lookup still gets the answer
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	var ips []net.IP
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(5 * time.Millisecond)
+		ips, err = r.LookupIP(context.Background(), "slow.test")
+	}()
+	_, cerr := r.LookupIP(ctx, "slow.test")
+	assert.Equal(t, context.DeadlineExceeded, cerr, "they should be equal")
+	wg.Wait()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.4").To4()}, ips, "they should be equal")
+}
+
+func TestResolverSystem(t *testing.T) {
+	r := NewResolver()
+	ips, err := r.LookupIP(context.Background(), "localhost")
+	if err != nil {
+		t.Skip("no localhost entry")
+	}
+	assert.NotEqual(t, 0, len(ips), "they should not be equal")
+	assert.Equal(t, 1, r.Len(), "they should be equal")
+}
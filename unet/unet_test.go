@@ -0,0 +1,89 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package unet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalIP(t *testing.T) {
+	ips, err := LocalIPs()
+	assert.Equal(t, nil, err, "they should be equal")
+	for _, ip := range ips {
+		assert.Equal(t, false, ip.IsLoopback(), "they should be equal")
+	}
+
+	ip, err := LocalIP()
+	if err == nil {
+		assert.NotEqual(t, nil, ip.To4(), "they should not be equal")
+	} else {
+		assert.Equal(t, true, errors.Is(err, ErrNotFound), "they should be equal")
+	}
+}
+
+func TestPorts(t *testing.T) {
+	port, err := FreePort()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, port > 0, "they should be equal")
+	assert.Equal(t, false, IsPortOpen("127.0.0.1", port, 100*time.Millisecond), "they should be equal")
+
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		done <- WaitForPort(ctx, addr)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skip("port taken meanwhile")
+	}
+	defer l.Close()
+	assert.Equal(t, nil, <-done, "they should be equal")
+	assert.Equal(t, true, IsPortOpen("127.0.0.1", port, time.Second), "they should be equal")
+
+	l.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, WaitForPort(ctx, addr), "they should be equal")
+}
+
+func TestMACAddress(t *testing.T) {
+	macs, err := MACAddresses()
+	assert.Equal(t, nil, err, "they should be equal")
+	for _, mac := range macs {
+		assert.NotEqual(t, 0, len(mac), "they should not be equal")
+	}
+
+	_, err = MACAddress(net.ParseIP("192.0.2.123"))
+	assert.Equal(t, true, errors.Is(err, ErrNotFound), "they should be equal")
+}
@@ -0,0 +1,196 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uotp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/uhash"
+)
+
+// Default options, the same as Google Authenticator.
+const (
+	DefaultPeriod    = 30
+	DefaultDigits    = 6
+	DefaultAlgorithm = "sha1"
+	DefaultSkew      = 1
+)
+
+// Options of one time passwords, zero values use defaults.
+type Options struct {
+	Period    uint   // TOTP time step in seconds
+	Digits    int    // 6 or 8
+	Algorithm string // sha1, sha256, sha512
+	Skew      uint   // number of periods before and after now accepted by Validate
+}
+
+func (o Options) withDefaults() Options {
+	if o.Period == 0 {
+		o.Period = DefaultPeriod
+	}
+	if o.Digits == 0 {
+		o.Digits = DefaultDigits
+	}
+	if len(o.Algorithm) == 0 {
+		o.Algorithm = DefaultAlgorithm
+	}
+	return o
+}
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret generate a random base32 secret of size bytes, 20 if size <= 0.
+func GenerateSecret(size int) (string, error) {
+	if size <= 0 {
+		size = 20
+	}
+	secret := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(secret), nil
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	key, err := b32.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return nil, errors.New("uotp: invalid base32 secret")
+	}
+	return key, nil
+}
+
+// GenerateHOTP generate a HOTP (RFC 4226) passcode for counter, secret is base32.
+func GenerateHOTP(secret string, counter uint64, opts Options) (string, error) {
+	opts = opts.withDefaults()
+	if opts.Digits < 1 || opts.Digits > 9 {
+		return "", errors.New("uotp: digits must be between 1 and 9")
+	}
+	// RFC 6238 algorithms only, the truncation needs a sum of 20+ bytes
+	switch strings.ToLower(opts.Algorithm) {
+	case "sha1", "sha256", "sha512":
+	default:
+		return "", fmt.Errorf("uotp: unsupported algorithm %q", opts.Algorithm)
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	mac, err := uhash.NewHMAC(opts.Algorithm, key)
+	if err != nil {
+		return "", err
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < opts.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", opts.Digits, code%mod), nil
+}
+
+// ValidateHOTP reports whether passcode is the HOTP passcode for counter.
+func ValidateHOTP(passcode, secret string, counter uint64, opts Options) bool {
+	expected, err := GenerateHOTP(secret, counter, opts)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(passcode)) == 1
+}
+
+// GenerateTOTP generate a TOTP (RFC 6238) passcode at t with default options.
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	return GenerateTOTPCustom(secret, t, Options{})
+}
+
+// GenerateTOTPCustom generate a TOTP passcode at t.
+func GenerateTOTPCustom(secret string, t time.Time, opts Options) (string, error) {
+	opts = opts.withDefaults()
+	return GenerateHOTP(secret, uint64(t.Unix())/uint64(opts.Period), opts)
+}
+
+// Validate reports whether passcode is the TOTP passcode now, with default options.
+func Validate(passcode, secret string) bool {
+	return ValidateCustom(passcode, secret, time.Now(), Options{Skew: DefaultSkew})
+}
+
+// ValidateCustom reports whether passcode is the TOTP passcode at t,
+// passcodes of opts.Skew periods before and after t are accepted too.
+func ValidateCustom(passcode, secret string, t time.Time, opts Options) bool {
+	opts = opts.withDefaults()
+	if len(passcode) != opts.Digits {
+		return false
+	}
+	counter := int64(t.Unix()) / int64(opts.Period)
+	for i := -int64(opts.Skew); i <= int64(opts.Skew); i++ {
+		if counter+i < 0 {
+			continue
+		}
+		if ValidateHOTP(passcode, secret, uint64(counter+i), opts) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ProvisioningURI Returns an otpauth:// uri for authenticator apps, usually
+shown as a QR code.
+@param issuer: service name, like "MDGSF".
+@param account: user name or email.
+*/
+func ProvisioningURI(secret, issuer, account string, opts Options) string {
+	opts = opts.withDefaults()
+
+	label := url.PathEscape(account)
+	if len(issuer) > 0 {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+
+	params := url.Values{}
+	params.Set("secret", strings.ToUpper(strings.TrimRight(secret, "=")))
+	if len(issuer) > 0 {
+		params.Set("issuer", issuer)
+	}
+	params.Set("algorithm", strings.ToUpper(opts.Algorithm))
+	params.Set("digits", fmt.Sprint(opts.Digits))
+	params.Set("period", fmt.Sprint(opts.Period))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
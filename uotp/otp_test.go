@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uotp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 Appendix B, secret "12345678901234567890" (sha1).
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateHOTP(t *testing.T) {
+	// RFC 4226 Appendix D
+	expected := []string{"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489"}
+	for i, code := range expected {
+		passcode, err := GenerateHOTP(rfcSecret, uint64(i), Options{})
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, code, passcode, "counter %v", i)
+		assert.Equal(t, true, ValidateHOTP(code, rfcSecret, uint64(i), Options{}), "they should be equal")
+	}
+}
+
+func TestGenerateTOTP(t *testing.T) {
+	cases := map[int64]string{
+		59:         "94287082",
+		1111111109: "07081804",
+		1111111111: "14050471",
+		1234567890: "89005924",
+		2000000000: "69279037",
+	}
+	for sec, code := range cases {
+		passcode, err := GenerateTOTPCustom(rfcSecret, time.Unix(sec, 0), Options{Digits: 8})
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, code, passcode, "time %v", sec)
+	}
+
+	passcode, err := GenerateTOTP(rfcSecret, time.Unix(59, 0))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "287082", passcode, "they should be equal")
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret(0)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 32, len(secret), "they should be equal")
+
+	passcode, _ := GenerateTOTP(secret, time.Now())
+	assert.Equal(t, true, Validate(passcode, secret), "they should be equal")
+	assert.Equal(t, false, Validate("12345", secret), "they should be equal")
+
+	now := time.Unix(1000000, 0)
+	prev, _ := GenerateTOTP(secret, now.Add(-30*time.Second))
+	assert.Equal(t, true, ValidateCustom(prev, secret, now, Options{Skew: 1}), "they should be equal")
+	assert.Equal(t, false, ValidateCustom(prev, secret, now, Options{}), "they should be equal")
+}
+
+func TestInvalid(t *testing.T) {
+	_, err := GenerateTOTP("invalid!", time.Now())
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = GenerateTOTPCustom(rfcSecret, time.Now(), Options{Algorithm: "md4"})
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	_, err = GenerateTOTPCustom(rfcSecret, time.Now(), Options{Digits: 10})
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestUnsupportedAlgorithm(t *testing.T) {
+	for _, algo := range []string{"md5", "blake2b", "sha224"} {
+		for counter := uint64(0); counter < 32; counter++ {
+			_, err := GenerateHOTP(rfcSecret, counter, Options{Algorithm: algo})
+			assert.NotEqual(t, nil, err, "they should not be equal")
+		}
+	}
+	_, err := GenerateHOTP(rfcSecret, 0, Options{Algorithm: "SHA256"})
+	assert.Equal(t, nil, err, "they should be equal")
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("JBSWY3DPEHPK3PXP", "MDGSF", "huangjian@example.com", Options{})
+	u, err := url.Parse(uri)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "otpauth", u.Scheme, "they should be equal")
+	assert.Equal(t, "totp", u.Host, "they should be equal")
+	assert.Equal(t, "/MDGSF:huangjian@example.com", u.Path, "they should be equal")
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", u.Query().Get("secret"), "they should be equal")
+	assert.Equal(t, "MDGSF", u.Query().Get("issuer"), "they should be equal")
+	assert.Equal(t, "SHA1", u.Query().Get("algorithm"), "they should be equal")
+	assert.Equal(t, "6", u.Query().Get("digits"), "they should be equal")
+	assert.Equal(t, "30", u.Query().Get("period"), "they should be equal")
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/MDGSF/utils/uencode"
+)
+
+// ErrInvalidCursor is returned when decoding a malformed or tampered
+// cursor.
+var ErrInvalidCursor = errors.New("upage: invalid cursor")
+
+// macSize is the size of the signature of signed cursors.
+const macSize = 16
+
+type cursorOptions struct {
+	secret []byte
+}
+
+// CursorOption configures the encoding of cursors.
+type CursorOption func(*cursorOptions)
+
+// WithSecret signs cursors with secret, so that clients can not forge
+// them. Decoding must be given the same secret.
+func WithSecret(secret []byte) CursorOption {
+	return func(o *cursorOptions) {
+		o.secret = secret
+	}
+}
+
+func newCursorOptions(opts []CursorOption) *cursorOptions {
+	o := &cursorOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *cursorOptions) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, o.secret)
+	mac.Write(data)
+	return mac.Sum(nil)[:macSize]
+}
+
+/*
+EncodeCursor encodes c to an opaque token safe in urls, to be returned to
+clients and given back to fetch the next page:
+
+	type cursor struct {
+		ID        int64     `json:"id"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+	next, err := upage.EncodeCursor(cursor{last.ID, last.CreatedAt})
+*/
+func EncodeCursor[T any](c T, opts ...CursorOption) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("upage: encode cursor: %w", err)
+	}
+	if o := newCursorOptions(opts); o.secret != nil {
+		data = append(data, o.sign(data)...)
+	}
+	return uencode.EncodeToken(data), nil
+}
+
+// DecodeCursor decodes a cursor of EncodeCursor, returning
+// ErrInvalidCursor if it is malformed or its signature does not match.
+func DecodeCursor[T any](s string, opts ...CursorOption) (T, error) {
+	var c T
+	data, err := uencode.DecodeToken(s)
+	if err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if o := newCursorOptions(opts); o.secret != nil {
+		if len(data) < macSize {
+			return c, ErrInvalidCursor
+		}
+		sig := data[len(data)-macSize:]
+		data = data[:len(data)-macSize]
+		if !hmac.Equal(sig, o.sign(data)) {
+			return c, fmt.Errorf("%w: bad signature", ErrInvalidCursor)
+		}
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return c, nil
+}
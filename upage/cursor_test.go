@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MDGSF/utils/uencode"
+	"github.com/stretchr/testify/assert"
+)
+
+type cursor struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCursor(t *testing.T) {
+	s, err := EncodeCursor(cursor{ID: 42, Name: "huangjian"})
+	assert.Nil(t, err)
+	c, err := DecodeCursor[cursor](s)
+	assert.Nil(t, err)
+	assert.Equal(t, cursor{ID: 42, Name: "huangjian"}, c, "they should be equal")
+
+	_, err = DecodeCursor[cursor]("!!!")
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+	_, err = DecodeCursor[cursor](uencode.EncodeToken([]byte("MDGSF")))
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+}
+
+func TestCursorSecret(t *testing.T) {
+	secret := WithSecret([]byte("MDGSF"))
+	s, err := EncodeCursor(cursor{ID: 42}, secret)
+	assert.Nil(t, err)
+	c, err := DecodeCursor[cursor](s, secret)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), c.ID, "they should be equal")
+
+	forged, _ := EncodeCursor(cursor{ID: 1})
+	_, err = DecodeCursor[cursor](forged, secret)
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+	_, err = DecodeCursor[cursor](s, WithSecret([]byte("huangjian")))
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+	_, err = DecodeCursor[cursor]("", secret)
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+}
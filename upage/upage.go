@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upage
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	// DefaultLimit is the limit used when none is given.
+	DefaultLimit = 20
+	// MaxLimit is the largest limit allowed by default.
+	MaxLimit = 100
+)
+
+type options struct {
+	defaultLimit int
+	maxLimit     int
+}
+
+// Option configures the normalization of offsets and limits.
+type Option func(*options)
+
+// WithDefaultLimit sets the limit used when none is given, default is
+// DefaultLimit.
+func WithDefaultLimit(n int) Option {
+	return func(o *options) {
+		o.defaultLimit = n
+	}
+}
+
+// WithMaxLimit sets the largest limit allowed, default is MaxLimit.
+func WithMaxLimit(n int) Option {
+	return func(o *options) {
+		o.maxLimit = n
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{defaultLimit: DefaultLimit, maxLimit: MaxLimit}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.maxLimit > 0 && o.defaultLimit > o.maxLimit {
+		o.defaultLimit = o.maxLimit
+	}
+	return o
+}
+
+// Normalize returns offset and limit made safe to query with: a negative
+// offset becomes 0, a limit <= 0 the default limit, and a limit above the
+// max limit the max limit.
+func Normalize(offset, limit int, opts ...Option) (int, int) {
+	o := newOptions(opts)
+	if offset < 0 {
+		offset = 0
+	}
+	if limit <= 0 {
+		limit = o.defaultLimit
+	}
+	if o.maxLimit > 0 && limit > o.maxLimit {
+		limit = o.maxLimit
+	}
+	return offset, limit
+}
+
+// Offset returns the offset of page, counted from 1, of size items.
+func Offset(page, size int) int {
+	if page < 1 || size <= 0 {
+		return 0
+	}
+	return (page - 1) * size
+}
+
+// TotalPages returns the number of pages of size items holding total
+// items.
+func TotalPages(total, size int) int {
+	if total <= 0 || size <= 0 {
+		return 0
+	}
+	return (total + size - 1) / size
+}
+
+/*
+FromRequest returns the normalized offset and limit of the query of r,
+given either as ?offset=40&limit=20 or as ?page=3&page_size=20. Invalid
+numbers are ignored.
+*/
+func FromRequest(r *http.Request, opts ...Option) (offset, limit int) {
+	q := r.URL.Query()
+	atoi := func(key string) int {
+		n, _ := strconv.Atoi(q.Get(key))
+		return n
+	}
+	limit = atoi("limit")
+	if limit == 0 {
+		limit = atoi("page_size")
+	}
+	offset, limit = Normalize(atoi("offset"), limit, opts...)
+	if q.Get("offset") == "" && q.Get("page") != "" {
+		offset = Offset(atoi("page"), limit)
+	}
+	return offset, limit
+}
+
+// Meta describes a page of results, to return along with them.
+type Meta struct {
+	Offset     int  `json:"offset"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	Page       int  `json:"page"`
+	TotalPages int  `json:"total_pages"`
+	HasNext    bool `json:"has_next"`
+	HasPrev    bool `json:"has_prev"`
+}
+
+// NewMeta returns the Meta of the page at offset of limit items out of
+// total.
+func NewMeta(offset, limit, total int) Meta {
+	m := Meta{Offset: offset, Limit: limit, Total: total}
+	if limit > 0 {
+		m.Page = offset/limit + 1
+		m.TotalPages = TotalPages(total, limit)
+	}
+	m.HasNext = offset+limit < total
+	m.HasPrev = offset > 0
+	return m
+}
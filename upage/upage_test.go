@@ -0,0 +1,86 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upage
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		offset, limit int
+		wantOffset    int
+		wantLimit     int
+	}{
+		{0, 0, 0, DefaultLimit},
+		{-5, 10, 0, 10},
+		{40, 1000, 40, MaxLimit},
+	}
+	for _, tt := range tests {
+		offset, limit := Normalize(tt.offset, tt.limit)
+		assert.Equal(t, tt.wantOffset, offset, "they should be equal")
+		assert.Equal(t, tt.wantLimit, limit, "they should be equal")
+	}
+
+	_, limit := Normalize(0, 0, WithDefaultLimit(50), WithMaxLimit(30))
+	assert.Equal(t, 30, limit, "they should be equal")
+	_, limit = Normalize(0, 1000, WithMaxLimit(0))
+	assert.Equal(t, 1000, limit, "they should be equal")
+}
+
+func TestOffsetTotalPages(t *testing.T) {
+	assert.Equal(t, 40, Offset(3, 20), "they should be equal")
+	assert.Equal(t, 0, Offset(0, 20), "they should be equal")
+	assert.Equal(t, 3, TotalPages(41, 20), "they should be equal")
+	assert.Equal(t, 2, TotalPages(40, 20), "they should be equal")
+	assert.Equal(t, 0, TotalPages(0, 20), "they should be equal")
+}
+
+func TestFromRequest(t *testing.T) {
+	tests := []struct {
+		url           string
+		offset, limit int
+	}{
+		{"/users", 0, DefaultLimit},
+		{"/users?offset=40&limit=10", 40, 10},
+		{"/users?page=3&page_size=10", 20, 10},
+		{"/users?page=2", DefaultLimit, DefaultLimit},
+		{"/users?offset=huangjian&limit=1000", 0, MaxLimit},
+	}
+	for _, tt := range tests {
+		offset, limit := FromRequest(httptest.NewRequest("GET", tt.url, nil))
+		assert.Equal(t, tt.offset, offset, tt.url)
+		assert.Equal(t, tt.limit, limit, tt.url)
+	}
+}
+
+func TestNewMeta(t *testing.T) {
+	m := NewMeta(20, 10, 45)
+	assert.Equal(t, Meta{Offset: 20, Limit: 10, Total: 45, Page: 3, TotalPages: 5, HasNext: true, HasPrev: true}, m, "they should be equal")
+	m = NewMeta(40, 10, 45)
+	assert.False(t, m.HasNext)
+	assert.Equal(t, 5, m.Page, "they should be equal")
+}
@@ -0,0 +1,294 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upool
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ObjectPoolConfig configures an ObjectPool.
+type ObjectPoolConfig[T any] struct {
+	// New creates an object, it is required.
+	New func(ctx context.Context) (T, error)
+	// Reset is called on an object given back by Put, before it is idle.
+	Reset func(obj T)
+	// Validate is called on an idle object before Get returns it, objects
+	// for which it returns false are destroyed, e.g. closed connections.
+	Validate func(obj T) bool
+	// Destroy is called on the objects removed from the pool.
+	Destroy func(obj T)
+	// MaxIdle is the max number of idle objects kept, 0 means unlimited.
+	MaxIdle int
+	// MaxActive is the max number of objects in use or idle, Get waits
+	// when it is reached. 0 means unlimited.
+	MaxActive int
+	// IdleTimeout destroys the objects idle for longer, 0 keeps them.
+	IdleTimeout time.Duration
+}
+
+// ObjectPoolStats are counters of an ObjectPool.
+type ObjectPoolStats struct {
+	// Active is the number of objects in use or idle.
+	Active int
+	// Idle is the number of idle objects.
+	Idle int
+	// Hits counts the Get returning an idle object, Misses the ones which
+	// created one.
+	Hits   uint64
+	Misses uint64
+	// Waits counts the Get which waited because MaxActive was reached.
+	Waits uint64
+	// Created and Destroyed count the objects created and destroyed.
+	Created   uint64
+	Destroyed uint64
+	// Invalid counts the idle objects which failed Validate.
+	Invalid uint64
+}
+
+type idleObject[T any] struct {
+	obj   T
+	since time.Time
+}
+
+/*
+ObjectPool reuses expensive objects like buffers, connections or parsers.
+Unlike sync.Pool it bounds the number of objects, checks them before
+reuse and keeps them until they are idle for too long.
+
+	pool := upool.NewObjectPool(upool.ObjectPoolConfig[*Conn]{
+		New:       func(ctx context.Context) (*Conn, error) { return Dial(ctx, addr) },
+		Validate:  func(c *Conn) bool { return c.Ping() == nil },
+		Destroy:   func(c *Conn) { c.Close() },
+		MaxIdle:   4,
+		MaxActive: 16,
+	})
+	conn, err := pool.Get(ctx)
+	...
+	pool.Put(conn)
+*/
+type ObjectPool[T any] struct {
+	conf ObjectPoolConfig[T]
+	stop chan struct{}
+
+	lock   sync.Mutex
+	idle   []idleObject[T] // most recently used last
+	stats  ObjectPoolStats
+	closed bool
+	// changed is closed and replaced when an object is given back or
+	// destroyed, waking up the Get waiting for MaxActive.
+	changed chan struct{}
+}
+
+// NewObjectPool returns an empty pool, it panics if conf.New is nil.
+func NewObjectPool[T any](conf ObjectPoolConfig[T]) *ObjectPool[T] {
+	if conf.New == nil {
+		panic("upool: ObjectPoolConfig.New is nil")
+	}
+	p := &ObjectPool[T]{
+		conf:    conf,
+		stop:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	if conf.IdleTimeout > 0 {
+		go p.reap()
+	}
+	return p
+}
+
+func (p *ObjectPool[T]) broadcast() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+func (p *ObjectPool[T]) destroy(obj T) {
+	if p.conf.Destroy != nil {
+		p.conf.Destroy(obj)
+	}
+}
+
+// release counts n objects as destroyed, p.lock must be held.
+func (p *ObjectPool[T]) release(n int) {
+	p.stats.Active -= n
+	p.stats.Destroyed += uint64(n)
+	p.broadcast()
+}
+
+/*
+Get returns an idle object, or creates one. When MaxActive objects exist
+it waits until one is given back or ctx is done. It returns ErrPoolClosed
+after Close.
+*/
+func (p *ObjectPool[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+	waited := false
+	for {
+		p.lock.Lock()
+		if p.closed {
+			p.lock.Unlock()
+			return zero, ErrPoolClosed
+		}
+		if n := len(p.idle); n > 0 {
+			obj := p.idle[n-1].obj
+			p.idle[n-1] = idleObject[T]{}
+			p.idle = p.idle[:n-1]
+			p.lock.Unlock()
+
+			if p.conf.Validate != nil && !p.conf.Validate(obj) {
+				p.destroy(obj)
+				p.lock.Lock()
+				p.stats.Invalid++
+				p.release(1)
+				p.lock.Unlock()
+				continue
+			}
+			p.lock.Lock()
+			p.stats.Hits++
+			p.lock.Unlock()
+			return obj, nil
+		}
+		if p.conf.MaxActive <= 0 || p.stats.Active < p.conf.MaxActive {
+			p.stats.Active++
+			p.stats.Misses++
+			p.lock.Unlock()
+
+			obj, err := p.conf.New(ctx)
+			p.lock.Lock()
+			if err != nil {
+				p.stats.Active--
+				p.broadcast()
+			} else {
+				p.stats.Created++
+			}
+			p.lock.Unlock()
+			return obj, err
+		}
+		if !waited {
+			waited = true
+			p.stats.Waits++
+		}
+		changed := p.changed
+		p.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Put gives obj back to the pool, it is reset and kept idle unless
+// MaxIdle idle objects are already kept or the pool is closed.
+func (p *ObjectPool[T]) Put(obj T) {
+	if p.conf.Reset != nil {
+		p.conf.Reset(obj)
+	}
+	p.lock.Lock()
+	if !p.closed && (p.conf.MaxIdle <= 0 || len(p.idle) < p.conf.MaxIdle) {
+		p.idle = append(p.idle, idleObject[T]{obj: obj, since: time.Now()})
+		p.broadcast()
+		p.lock.Unlock()
+		return
+	}
+	p.release(1)
+	p.lock.Unlock()
+	p.destroy(obj)
+}
+
+// Discard destroys obj instead of giving it back, e.g. when it is broken.
+func (p *ObjectPool[T]) Discard(obj T) {
+	p.lock.Lock()
+	p.release(1)
+	p.lock.Unlock()
+	p.destroy(obj)
+}
+
+// Stats returns the counters of p.
+func (p *ObjectPool[T]) Stats() ObjectPoolStats {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	stats := p.stats
+	stats.Idle = len(p.idle)
+	return stats
+}
+
+func (p *ObjectPool[T]) reap() {
+	interval := p.conf.IdleTimeout / 2
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.reapIdle(time.Now().Add(-p.conf.IdleTimeout))
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// reapIdle destroys the objects idle since before deadline.
+func (p *ObjectPool[T]) reapIdle(deadline time.Time) {
+	p.lock.Lock()
+	n := 0
+	for n < len(p.idle) && p.idle[n].since.Before(deadline) {
+		n++
+	}
+	if n == 0 {
+		p.lock.Unlock()
+		return
+	}
+	expired := make([]idleObject[T], n)
+	copy(expired, p.idle)
+	p.idle = append(p.idle[:0], p.idle[n:]...)
+	p.release(n)
+	p.lock.Unlock()
+
+	for _, o := range expired {
+		p.destroy(o.obj)
+	}
+}
+
+// Close destroys the idle objects and makes Get fail, objects given back
+// later are destroyed.
+func (p *ObjectPool[T]) Close() {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return
+	}
+	p.closed = true
+	close(p.stop)
+	idle := p.idle
+	p.idle = nil
+	p.release(len(idle))
+	p.lock.Unlock()
+
+	for _, o := range idle {
+		p.destroy(o.obj)
+	}
+}
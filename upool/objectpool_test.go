@@ -0,0 +1,146 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newBufferPool(conf ObjectPoolConfig[*bytes.Buffer]) *ObjectPool[*bytes.Buffer] {
+	conf.New = func(ctx context.Context) (*bytes.Buffer, error) {
+		return new(bytes.Buffer), nil
+	}
+	conf.Reset = func(b *bytes.Buffer) { b.Reset() }
+	return NewObjectPool(conf)
+}
+
+func TestObjectPoolReuse(t *testing.T) {
+	p := newBufferPool(ObjectPoolConfig[*bytes.Buffer]{})
+	ctx := context.Background()
+	b1, err := p.Get(ctx)
+	assert.Nil(t, err)
+	b1.WriteString("huangjian")
+	p.Put(b1)
+
+	b2, _ := p.Get(ctx)
+	assert.True(t, b1 == b2)
+	assert.Equal(t, 0, b2.Len(), "they should be equal")
+
+	stats := p.Stats()
+	assert.Equal(t, uint64(1), stats.Hits, "they should be equal")
+	assert.Equal(t, uint64(1), stats.Misses, "they should be equal")
+	assert.Equal(t, 1, stats.Active, "they should be equal")
+	assert.Equal(t, 0, stats.Idle, "they should be equal")
+}
+
+func TestObjectPoolMaxIdle(t *testing.T) {
+	var destroyed int32
+	p := newBufferPool(ObjectPoolConfig[*bytes.Buffer]{
+		MaxIdle: 1,
+		Destroy: func(*bytes.Buffer) { atomic.AddInt32(&destroyed, 1) },
+	})
+	ctx := context.Background()
+	b1, _ := p.Get(ctx)
+	b2, _ := p.Get(ctx)
+	p.Put(b1)
+	p.Put(b2)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&destroyed), "they should be equal")
+	assert.Equal(t, 1, p.Stats().Idle, "they should be equal")
+
+	p.Close()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&destroyed), "they should be equal")
+	_, err := p.Get(ctx)
+	assert.Equal(t, ErrPoolClosed, err, "they should be equal")
+	assert.Equal(t, 0, p.Stats().Active, "they should be equal")
+}
+
+func TestObjectPoolMaxActive(t *testing.T) {
+	p := newBufferPool(ObjectPoolConfig[*bytes.Buffer]{MaxActive: 1})
+	ctx := context.Background()
+	b, _ := p.Get(ctx)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := p.Get(timeout)
+	assert.Equal(t, context.DeadlineExceeded, err, "they should be equal")
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p.Put(b)
+	}()
+	b2, err := p.Get(ctx)
+	assert.Nil(t, err)
+	assert.True(t, b == b2)
+	assert.Equal(t, uint64(2), p.Stats().Waits, "they should be equal")
+
+	p.Discard(b2)
+	_, err = p.Get(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), p.Stats().Created, "they should be equal")
+}
+
+func TestObjectPoolValidate(t *testing.T) {
+	p := newBufferPool(ObjectPoolConfig[*bytes.Buffer]{
+		Validate: func(b *bytes.Buffer) bool { return b.Cap() < 1024 },
+	})
+	ctx := context.Background()
+	b, _ := p.Get(ctx)
+	b.Grow(4096)
+	p.Put(b)
+	b2, _ := p.Get(ctx)
+	assert.False(t, b == b2)
+	assert.Equal(t, uint64(1), p.Stats().Invalid, "they should be equal")
+	assert.Equal(t, 1, p.Stats().Active, "they should be equal")
+}
+
+func TestObjectPoolIdleTimeout(t *testing.T) {
+	var destroyed int32
+	p := newBufferPool(ObjectPoolConfig[*bytes.Buffer]{
+		IdleTimeout: 20 * time.Millisecond,
+		Destroy:     func(*bytes.Buffer) { atomic.AddInt32(&destroyed, 1) },
+	})
+	defer p.Close()
+	b, _ := p.Get(context.Background())
+	p.Put(b)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&destroyed), "they should be equal")
+	assert.Equal(t, 0, p.Stats().Active, "they should be equal")
+}
+
+func TestObjectPoolNewError(t *testing.T) {
+	errNew := errors.New("MDGSF")
+	p := NewObjectPool(ObjectPoolConfig[int]{
+		New:       func(ctx context.Context) (int, error) { return 0, errNew },
+		MaxActive: 1,
+	})
+	_, err := p.Get(context.Background())
+	assert.Equal(t, errNew, err, "they should be equal")
+	assert.Equal(t, 0, p.Stats().Active, "they should be equal")
+}
@@ -0,0 +1,216 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upool
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/MDGSF/utils/uerrors"
+)
+
+var (
+	// ErrPoolClosed is returned when submitting to a closed pool.
+	ErrPoolClosed = errors.New("upool: pool is closed")
+	// ErrQueueFull is returned by TrySubmit when the task queue is full.
+	ErrQueueFull = errors.New("upool: task queue is full")
+)
+
+// PanicError is the error of a task which panicked.
+type PanicError = uerrors.PanicError
+
+// Task is a unit of work run by the pool, ctx is done when the pool is
+// stopped or its parent context is cancelled.
+type Task[T any] func(ctx context.Context) (T, error)
+
+// Result is the outcome of a task, Index is its submission order.
+type Result[T any] struct {
+	Index int
+	Value T
+	Err   error
+}
+
+type job[T any] struct {
+	index int
+	task  Task[T]
+}
+
+/*
+Pool runs tasks on a fixed number of workers through a bounded queue.
+Submit blocks while the queue is full. Wait stops accepting tasks, drains
+the queue and returns all results in submission order. Stop cancels the
+context, tasks still queued are not run and report the context error.
+*/
+type Pool[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	jobs   chan job[T]
+	wg     sync.WaitGroup
+
+	// sendLock is held for reading while sending to jobs and for writing
+	// when closing it
+	sendLock sync.RWMutex
+	closed   bool
+
+	lock    sync.Mutex
+	next    int
+	results []Result[T]
+}
+
+// New create a pool with workers goroutines and a queue of queueSize tasks.
+func New[T any](ctx context.Context, workers, queueSize int) *Pool[T] {
+	if workers <= 0 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool[T]{
+		ctx:    ctx,
+		cancel: cancel,
+		jobs:   make(chan job[T], queueSize),
+	}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.worker()
+	}
+	return p
+}
+
+func (p *Pool[T]) worker() {
+	defer p.wg.Done()
+	for j := range p.jobs {
+		r := Result[T]{Index: j.index}
+		if err := p.ctx.Err(); err != nil {
+			r.Err = err
+		} else {
+			r.Value, r.Err = p.run(j.task)
+		}
+		p.lock.Lock()
+		p.results[j.index] = r
+		p.lock.Unlock()
+	}
+}
+
+func (p *Pool[T]) run(task Task[T]) (value T, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = uerrors.NewPanicError(v)
+		}
+	}()
+	return task(p.ctx)
+}
+
+func (p *Pool[T]) reserve() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	index := p.next
+	p.next++
+	p.results = append(p.results, Result[T]{Index: index})
+	return index
+}
+
+/*
+Submit queues task, blocking while the queue is full. It returns
+ErrPoolClosed after Wait or Stop, or the context error if the pool's
+context is done while blocked. Submit must not be called concurrently
+with Wait.
+*/
+func (p *Pool[T]) Submit(task Task[T]) error {
+	p.sendLock.RLock()
+	defer p.sendLock.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	index := p.reserve()
+	select {
+	case p.jobs <- job[T]{index: index, task: task}:
+		return nil
+	case <-p.ctx.Done():
+		p.setResult(index, p.ctx.Err())
+		return p.ctx.Err()
+	}
+}
+
+// TrySubmit queues task without blocking, returns ErrQueueFull if full.
+// A rejected task has no result.
+func (p *Pool[T]) TrySubmit(task Task[T]) error {
+	p.sendLock.RLock()
+	defer p.sendLock.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	// the send does not block, so the index is only taken when it succeeds
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	index := p.next
+	select {
+	case p.jobs <- job[T]{index: index, task: task}:
+		p.next++
+		p.results = append(p.results, Result[T]{Index: index})
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (p *Pool[T]) setResult(index int, err error) {
+	p.lock.Lock()
+	p.results[index].Err = err
+	p.lock.Unlock()
+}
+
+// Wait stops accepting tasks, waits until every queued task finished and
+// returns the results in submission order with the first error, if any.
+func (p *Pool[T]) Wait() ([]Result[T], error) {
+	p.close()
+	p.wg.Wait()
+	p.cancel()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, r := range p.results {
+		if r.Err != nil {
+			return p.results, r.Err
+		}
+	}
+	return p.results, nil
+}
+
+// Stop cancels running tasks, skips queued ones and waits for workers.
+func (p *Pool[T]) Stop() {
+	p.cancel()
+	p.close()
+	p.wg.Wait()
+}
+
+func (p *Pool[T]) close() {
+	p.sendLock.Lock()
+	defer p.sendLock.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.jobs)
+	}
+}
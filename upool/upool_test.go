@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package upool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	p := New[int](context.Background(), 4, 2)
+	var running, maxRunning int32
+	for i := 0; i < 20; i++ {
+		i := i
+		err := p.Submit(func(ctx context.Context) (int, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return i * i, nil
+		})
+		assert.Equal(t, nil, err, "they should be equal")
+	}
+
+	results, err := p.Wait()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 20, len(results), "they should be equal")
+	for i, r := range results {
+		assert.Equal(t, i, r.Index, "they should be equal")
+		assert.Equal(t, i*i, r.Value, "they should be equal")
+	}
+	assert.Equal(t, true, atomic.LoadInt32(&maxRunning) <= 4, "they should be equal")
+
+	err = p.Submit(func(ctx context.Context) (int, error) { return 0, nil })
+	assert.Equal(t, ErrPoolClosed, err, "they should be equal")
+}
+
+func TestPoolErrors(t *testing.T) {
+	p := New[string](context.Background(), 2, 10)
+	errTest := errors.New("huangjian")
+	p.Submit(func(ctx context.Context) (string, error) { return "MDGSF", nil })
+	p.Submit(func(ctx context.Context) (string, error) { return "", errTest })
+	p.Submit(func(ctx context.Context) (string, error) { panic("boom") })
+
+	results, err := p.Wait()
+	assert.Equal(t, errTest, err, "they should be equal")
+	assert.Equal(t, "MDGSF", results[0].Value, "they should be equal")
+	assert.Equal(t, errTest, results[1].Err, "they should be equal")
+	pe, ok := results[2].Err.(*PanicError)
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, "boom", pe.Value, "they should be equal")
+	assert.NotEqual(t, 0, len(pe.StackTrace()), "they should not be equal")
+}
+
+func TestPoolTrySubmit(t *testing.T) {
+	p := New[int](context.Background(), 1, 1)
+	release := make(chan struct{})
+	block := func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	}
+	assert.Equal(t, nil, p.Submit(block), "they should be equal")
+	time.Sleep(10 * time.Millisecond) // let the worker pick it up
+	assert.Equal(t, nil, p.TrySubmit(block), "they should be equal")
+	assert.Equal(t, ErrQueueFull, p.TrySubmit(block), "they should be equal")
+	close(release)
+
+	// the rejected task has no result
+	results, err := p.Wait()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 2, len(results), "they should be equal")
+	assert.Equal(t, 1, results[1].Value, "they should be equal")
+}
+
+func TestPoolCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := New[int](ctx, 1, 10)
+	started := make(chan struct{})
+	p.Submit(func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	var ran int32
+	for i := 0; i < 5; i++ {
+		p.Submit(func(ctx context.Context) (int, error) {
+			atomic.AddInt32(&ran, 1)
+			return 0, nil
+		})
+	}
+	<-started
+	cancel()
+
+	results, err := p.Wait()
+	assert.Equal(t, context.Canceled, err, "they should be equal")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&ran), "they should be equal")
+	for _, r := range results {
+		assert.Equal(t, context.Canceled, r.Err, "they should be equal")
+	}
+}
+
+func TestPoolStop(t *testing.T) {
+	p := New[int](context.Background(), 1, 1)
+	p.Submit(func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	p.Stop()
+	assert.Equal(t, ErrPoolClosed, p.TrySubmit(nil), "they should be equal")
+}
@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uproc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MDGSF/utils/ufile"
+)
+
+var (
+	// ErrAlreadyRunning is returned by WritePidFile when the pidfile names
+	// another running process.
+	ErrAlreadyRunning = errors.New("uproc: already running")
+	// ErrInvalidPidFile is returned when a pidfile does not hold a pid.
+	ErrInvalidPidFile = errors.New("uproc: invalid pidfile")
+)
+
+// ReadPidFile returns the pid written in the pidfile path.
+func ReadPidFile(path string) (int, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPidFile, path)
+	}
+	return pid, nil
+}
+
+// CheckPidFile returns the pid of the pidfile path and whether that
+// process is running. A missing pidfile gives 0 and false without error.
+func CheckPidFile(path string) (pid int, running bool, err error) {
+	pid, err = ReadPidFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return pid, Alive(pid), nil
+}
+
+/*
+WritePidFile writes the pid of the current process to path, so a single
+instance runs at a time. It fails with ErrAlreadyRunning when path names
+another running process; pidfiles left by processes which died are
+replaced. The check and the write happen under a file lock on path+".lock",
+so of several processes starting at once only one wins. The lock file is
+left in place.
+
+	if err := uproc.WritePidFile("/var/run/agent.pid"); err != nil {
+		log.Fatal(err)
+	}
+	defer uproc.RemovePidFile("/var/run/agent.pid")
+*/
+func WritePidFile(path string) error {
+	lock, err := ufile.Lock(context.Background(), path+".lock")
+	if err == nil {
+		defer lock.Unlock()
+	} else if !errors.Is(err, ufile.ErrLockNotSupported) {
+		return err
+	}
+
+	self := os.Getpid()
+	pid, running, err := CheckPidFile(path)
+	if err != nil && !errors.Is(err, ErrInvalidPidFile) {
+		return err
+	}
+	if running && pid != self {
+		return fmt.Errorf("%w: pid %d in %s", ErrAlreadyRunning, pid, path)
+	}
+	return ufile.WriteFileAtomic(path, []byte(strconv.Itoa(self)+"\n"), 0644)
+}
+
+// RemovePidFile removes the pidfile path if it holds the pid of the
+// current process, leaving the ones of other processes.
+func RemovePidFile(path string) error {
+	pid, err := ReadPidFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pid != os.Getpid() {
+		return nil
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uproc
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPidFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uproc")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "huangjian.pid")
+
+	pid, running, err := CheckPidFile(path)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 0, pid, "they should be equal")
+	assert.Equal(t, false, running, "they should be equal")
+
+	assert.Equal(t, nil, WritePidFile(path), "they should be equal")
+	pid, running, err = CheckPidFile(path)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, os.Getpid(), pid, "they should be equal")
+	assert.Equal(t, true, running, "they should be equal")
+	// Writing again from the same process is fine.
+	assert.Equal(t, nil, WritePidFile(path), "they should be equal")
+
+	assert.Equal(t, nil, RemovePidFile(path), "they should be equal")
+	_, err = os.Stat(path)
+	assert.Equal(t, true, os.IsNotExist(err), "they should be equal")
+	assert.Equal(t, nil, RemovePidFile(path), "they should be equal")
+}
+
+func TestPidFileOtherProcess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uproc")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "MDGSF.pid")
+
+	// Running process: the parent of the test.
+	ioutil.WriteFile(path, []byte(strconv.Itoa(os.Getppid())), 0644)
+	err = WritePidFile(path)
+	assert.Equal(t, true, errors.Is(err, ErrAlreadyRunning), "they should be equal")
+	assert.Equal(t, nil, RemovePidFile(path), "they should be equal")
+	_, err = os.Stat(path)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	// Stale pidfile.
+	cmd := exec.Command("true")
+	assert.Equal(t, nil, cmd.Run(), "they should be equal")
+	ioutil.WriteFile(path, []byte(strconv.Itoa(cmd.Process.Pid)), 0644)
+	assert.Equal(t, nil, WritePidFile(path), "they should be equal")
+
+	// Garbage.
+	ioutil.WriteFile(path, []byte("MDGSF"), 0644)
+	_, err = ReadPidFile(path)
+	assert.Equal(t, true, errors.Is(err, ErrInvalidPidFile), "they should be equal")
+	assert.Equal(t, nil, WritePidFile(path), "they should be equal")
+	pid, err := ReadPidFile(path)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, os.Getpid(), pid, "they should be equal")
+}
+
+// TestPidFileHelper is run as a child process by TestPidFileConcurrent.
+func TestPidFileHelper(t *testing.T) {
+	path := os.Getenv("UPROC_PIDFILE")
+	if path == "" {
+		t.Skip("helper process")
+	}
+	err := WritePidFile(path)
+	fmt.Println("result:", err == nil)
+	// stay alive so the others see a running process
+	time.Sleep(300 * time.Millisecond)
+}
+
+func TestPidFileConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "uproc")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "huangjian.pid")
+
+	var cmds []*exec.Cmd
+	var outputs []*strings.Builder
+	for i := 0; i < 5; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestPidFileHelper$")
+		cmd.Env = append(os.Environ(), "UPROC_PIDFILE="+path)
+		out := &strings.Builder{}
+		cmd.Stdout = out
+		assert.Equal(t, nil, cmd.Start(), "they should be equal")
+		cmds = append(cmds, cmd)
+		outputs = append(outputs, out)
+	}
+	winners := 0
+	for i, cmd := range cmds {
+		cmd.Wait()
+		if strings.Contains(outputs[i].String(), "result: true") {
+			winners++
+		}
+	}
+	assert.Equal(t, 1, winners, "they should be equal")
+}
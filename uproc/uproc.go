@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uproc
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ErrNotSupported is returned on platforms without process information.
+var ErrNotSupported = errors.New("uproc: not supported on this platform")
+
+// Process is a running process.
+type Process struct {
+	PID  int
+	PPID int
+	// Name is the executable name, without its directory.
+	Name string
+}
+
+// List returns the running processes.
+func List() ([]Process, error) {
+	return listProcesses()
+}
+
+// Alive reports whether the process pid is running.
+func Alive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	return alive(pid)
+}
+
+// FindByName returns the processes whose executable is name, compared
+// case-insensitively on Windows where ".exe" is optional.
+func FindByName(name string) ([]Process, error) {
+	procs, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+	var found []Process
+	for _, p := range procs {
+		if sameName(p.Name, name) {
+			found = append(found, p)
+		}
+	}
+	return found, nil
+}
+
+func sameName(procName, name string) bool {
+	procName = filepath.Base(procName)
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(strings.TrimSuffix(strings.ToLower(procName), ".exe"),
+			strings.TrimSuffix(strings.ToLower(name), ".exe"))
+	}
+	return procName == name
+}
+
+// Usage is the resource usage of the current process.
+type Usage struct {
+	// CPUUser and CPUSystem are the CPU times spent in user and kernel
+	// mode since the process started.
+	CPUUser   time.Duration
+	CPUSystem time.Duration
+	// RSS is the resident memory in bytes, 0 where unknown.
+	RSS uint64
+	// MaxRSS is the peak resident memory in bytes, 0 where unknown.
+	MaxRSS uint64
+	// HeapAlloc is the memory allocated by the Go heap in bytes.
+	HeapAlloc  uint64
+	Goroutines int
+}
+
+// Self returns the resource usage of the current process.
+func Self() (*Usage, error) {
+	u, err := selfUsage()
+	if err != nil {
+		return nil, err
+	}
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	u.HeapAlloc = ms.HeapAlloc
+	u.Goroutines = runtime.NumGoroutine()
+	return u, nil
+}
+
+/*
+CPUPercent measures the CPU used by the current process during interval,
+100 being one core fully busy.
+
+	pct, err := uproc.CPUPercent(time.Second)
+*/
+func CPUPercent(interval time.Duration) (float64, error) {
+	before, err := selfUsage()
+	if err != nil {
+		return 0, err
+	}
+	start := time.Now()
+	time.Sleep(interval)
+	after, err := selfUsage()
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(start)
+	used := (after.CPUUser + after.CPUSystem) - (before.CPUUser + before.CPUSystem)
+	return float64(used) / float64(elapsed) * 100, nil
+}
+
+// descendants returns the processes below pid, children first, from the
+// parent links of procs.
+func descendants(pid int, procs []Process) []int {
+	children := make(map[int][]int)
+	for _, p := range procs {
+		if p.PID != p.PPID {
+			children[p.PPID] = append(children[p.PPID], p.PID)
+		}
+	}
+	var out []int
+	seen := map[int]bool{pid: true}
+	queue := []int{pid}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, c := range children[cur] {
+			if !seen[c] {
+				seen[c] = true
+				out = append(out, c)
+				queue = append(queue, c)
+			}
+		}
+	}
+	return out
+}
+
+/*
+KillTree terminates pid and all its descendants: they are asked to stop,
+SIGTERM on Unix, and those still running after grace are killed. Windows
+has no graceful termination, processes are killed right away.
+
+	err := uproc.KillTree(cmd.Process.Pid, 5*time.Second)
+*/
+func KillTree(pid int, grace time.Duration) error {
+	procs, err := listProcesses()
+	if err != nil {
+		return err
+	}
+	// Stop the parent first so it does not spawn new children.
+	tree := append([]int{pid}, descendants(pid, procs)...)
+
+	var firstErr error
+	for _, p := range tree {
+		if err := terminate(p); err != nil && Alive(p) && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	deadline := time.Now().Add(grace)
+	for {
+		running := tree[:0]
+		for _, p := range tree {
+			if Alive(p) {
+				running = append(running, p)
+			}
+		}
+		tree = running
+		if len(tree) == 0 {
+			return firstErr
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, p := range tree {
+		if err := kill(p); err != nil && Alive(p) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uproc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func alive(pid int) bool {
+	if !signalAlive(pid) {
+		return false
+	}
+	// Zombies answer signals until their parent waits for them.
+	stat, err := readStat(pid)
+	return err != nil || stat.state != 'Z'
+}
+
+type procStat struct {
+	name  string
+	state byte
+	ppid  int
+}
+
+// readStat parses /proc/<pid>/stat: "pid (comm) state ppid ...", comm
+// may contain spaces and parentheses.
+func readStat(pid int) (*procStat, error) {
+	data, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return nil, err
+	}
+	open := bytes.IndexByte(data, '(')
+	end := bytes.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return nil, os.ErrInvalid
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 2 {
+		return nil, os.ErrInvalid
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, err
+	}
+	return &procStat{name: string(data[open+1 : end]), state: fields[0][0], ppid: ppid}, nil
+}
+
+func listProcesses() ([]Process, error) {
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var procs []Process
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		stat, err := readStat(pid)
+		if err != nil {
+			// exited meanwhile
+			continue
+		}
+		name := stat.name
+		// comm is truncated to 15 characters, prefer the executable.
+		if exe, err := os.Readlink("/proc/" + e.Name() + "/exe"); err == nil {
+			exe = strings.TrimSuffix(exe, " (deleted)")
+			if i := strings.LastIndexByte(exe, '/'); i >= 0 && strings.HasPrefix(exe[i+1:], name) {
+				name = exe[i+1:]
+			}
+		}
+		procs = append(procs, Process{PID: pid, PPID: stat.ppid, Name: name})
+	}
+	return procs, nil
+}
+
+func selfUsage() (*Usage, error) {
+	u, err := rusage()
+	if err != nil {
+		return nil, err
+	}
+	// statm: size resident shared ..., in pages
+	if data, err := ioutil.ReadFile("/proc/self/statm"); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 1 {
+			if pages, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+				u.RSS = pages * uint64(os.Getpagesize())
+			}
+		}
+	}
+	return u, nil
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uproc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestList(t *testing.T) {
+	procs, err := List()
+	assert.Equal(t, nil, err, "they should be equal")
+	found := false
+	for _, p := range procs {
+		if p.PID == os.Getpid() {
+			found = true
+			assert.Equal(t, os.Getppid(), p.PPID, "they should be equal")
+		}
+	}
+	assert.Equal(t, true, found, "they should be equal")
+}
+
+func TestAlive(t *testing.T) {
+	assert.Equal(t, true, Alive(os.Getpid()), "they should be equal")
+	assert.Equal(t, false, Alive(0), "they should be equal")
+	assert.Equal(t, false, Alive(-1), "they should be equal")
+
+	cmd := exec.Command("true")
+	assert.Equal(t, nil, cmd.Run(), "they should be equal")
+	assert.Equal(t, false, Alive(cmd.Process.Pid), "they should be equal")
+}
+
+func TestFindByName(t *testing.T) {
+	exe, err := os.Executable()
+	assert.Equal(t, nil, err, "they should be equal")
+	procs, err := FindByName(filepath.Base(exe))
+	assert.Equal(t, nil, err, "they should be equal")
+	pids := make(map[int]bool)
+	for _, p := range procs {
+		pids[p.PID] = true
+	}
+	assert.Equal(t, true, pids[os.Getpid()], "they should be equal")
+
+	procs, err = FindByName("uproc-no-such-program")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 0, len(procs), "they should be equal")
+}
+
+func TestSelf(t *testing.T) {
+	u, err := Self()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, u.CPUUser+u.CPUSystem > 0, "they should be equal")
+	assert.Equal(t, true, u.MaxRSS > 0, "they should be equal")
+	assert.Equal(t, true, u.HeapAlloc > 0, "they should be equal")
+	assert.Equal(t, true, u.Goroutines > 0, "they should be equal")
+
+	pct, err := CPUPercent(20 * time.Millisecond)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, pct >= 0, "they should be equal")
+}
+
+func TestDescendants(t *testing.T) {
+	procs := []Process{
+		{PID: 1, PPID: 0},
+		{PID: 2, PPID: 1},
+		{PID: 3, PPID: 2},
+		{PID: 4, PPID: 2},
+		{PID: 5, PPID: 1},
+		{PID: 6, PPID: 3},
+		{PID: 7, PPID: 99},
+	}
+	assert.Equal(t, []int{3, 4, 6}, descendants(2, procs), "they should be equal")
+	assert.Equal(t, []int(nil), descendants(7, procs), "they should be equal")
+}
+
+func TestKillTree(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30; wait")
+	assert.Equal(t, nil, cmd.Start(), "they should be equal")
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	var children []int
+	deadline := time.Now().Add(2 * time.Second)
+	for len(children) < 2 && time.Now().Before(deadline) {
+		procs, err := List()
+		assert.Equal(t, nil, err, "they should be equal")
+		children = descendants(cmd.Process.Pid, procs)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 2, len(children), "they should be equal")
+
+	assert.Equal(t, nil, KillTree(cmd.Process.Pid, time.Second), "they should be equal")
+	<-done
+	for _, pid := range children {
+		assert.Equal(t, false, Alive(pid), "they should be equal")
+	}
+}
@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uptr
+
+// To returns a pointer to a copy of v, handy for optional fields:
+// User{Age: uptr.To(30)}.
+func To[T any](v T) *T {
+	return &v
+}
+
+// ToOrNil returns a pointer to v, or nil when v is the zero value.
+func ToOrNil[T comparable](v T) *T {
+	if IsZero(v) {
+		return nil
+	}
+	return &v
+}
+
+// Deref returns *p, or def when p is nil.
+func Deref[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// Value returns *p, or the zero value when p is nil.
+func Value[T any](p *T) T {
+	var zero T
+	return Deref(p, zero)
+}
+
+// IsZero reports whether v is the zero value of its type.
+func IsZero[T comparable](v T) bool {
+	var zero T
+	return v == zero
+}
+
+// IsNilOrZero reports whether p is nil or points to the zero value.
+func IsNilOrZero[T comparable](p *T) bool {
+	return p == nil || IsZero(*p)
+}
+
+// Equal reports whether a and b are both nil or point to equal values.
+func Equal[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// Coalesce returns the first of vals which is not the zero value, or the
+// zero value when all are.
+func Coalesce[T comparable](vals ...T) T {
+	var zero T
+	for _, v := range vals {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+// CoalescePtr returns the first of ptrs which is not nil, or nil.
+func CoalescePtr[T any](ptrs ...*T) *T {
+	for _, p := range ptrs {
+		if p != nil {
+			return p
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uptr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTo(t *testing.T) {
+	v := 3
+	p := To(v)
+	*p = 4
+	assert.Equal(t, 3, v, "they should be equal")
+	assert.Equal(t, "huangjian", *To("huangjian"), "they should be equal")
+
+	assert.Equal(t, (*string)(nil), ToOrNil(""), "they should be equal")
+	assert.Equal(t, "MDGSF", *ToOrNil("MDGSF"), "they should be equal")
+}
+
+func TestDeref(t *testing.T) {
+	var p *int
+	assert.Equal(t, 8080, Deref(p, 8080), "they should be equal")
+	assert.Equal(t, 80, Deref(To(80), 8080), "they should be equal")
+	assert.Equal(t, 0, Value(p), "they should be equal")
+	assert.Equal(t, "huangjian", Value(To("huangjian")), "they should be equal")
+}
+
+func TestIsZero(t *testing.T) {
+	type point struct{ X, Y int }
+	assert.Equal(t, true, IsZero(0), "they should be equal")
+	assert.Equal(t, true, IsZero(point{}), "they should be equal")
+	assert.Equal(t, false, IsZero(point{X: 1}), "they should be equal")
+
+	var p *string
+	assert.Equal(t, true, IsNilOrZero(p), "they should be equal")
+	assert.Equal(t, true, IsNilOrZero(To("")), "they should be equal")
+	assert.Equal(t, false, IsNilOrZero(To("MDGSF")), "they should be equal")
+}
+
+func TestEqual(t *testing.T) {
+	var a, b *int
+	assert.Equal(t, true, Equal(a, b), "they should be equal")
+	assert.Equal(t, false, Equal(a, To(1)), "they should be equal")
+	assert.Equal(t, true, Equal(To(1), To(1)), "they should be equal")
+	assert.Equal(t, false, Equal(To(1), To(2)), "they should be equal")
+}
+
+func TestCoalesce(t *testing.T) {
+	assert.Equal(t, "MDGSF", Coalesce("", "MDGSF", "huangjian"), "they should be equal")
+	assert.Equal(t, 0, Coalesce(0, 0), "they should be equal")
+	assert.Equal(t, "", Coalesce[string](), "they should be equal")
+
+	p := To(2)
+	assert.Equal(t, p, CoalescePtr(nil, p, To(3)), "they should be equal")
+	assert.Equal(t, (*int)(nil), CoalescePtr[int](nil, nil), "they should be equal")
+}
@@ -0,0 +1,174 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urand
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	mrand "math/rand"
+	"sync"
+)
+
+// Common charsets for String.
+const (
+	Digits       = "0123456789"
+	LowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	UpperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Letters      = LowerLetters + UpperLetters
+	Alphanumeric = Digits + Letters
+	HexDigits    = "0123456789abcdef"
+)
+
+/*
+Rand random data generator. The package level functions use crypto/rand,
+New returns a deterministic generator for tests, which must never be used
+for secrets.
+*/
+type Rand struct {
+	lock sync.Mutex
+	src  io.Reader
+}
+
+var std = &Rand{src: rand.Reader}
+
+// New create a deterministic generator, the same seed generates the same data.
+func New(seed int64) *Rand {
+	return &Rand{src: mrand.New(mrand.NewSource(seed))}
+}
+
+func (r *Rand) read(b []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err := io.ReadFull(r.src, b)
+	return err
+}
+
+// Bytes returns n random bytes.
+func (r *Rand) Bytes(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, errors.New("urand: negative length")
+	}
+	b := make([]byte, n)
+	if err := r.read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Int returns a uniform random number in [min, max).
+func (r *Rand) Int(min, max int64) (int64, error) {
+	if min >= max {
+		return 0, errors.New("urand: min must be less than max")
+	}
+	span := new(big.Int).Sub(big.NewInt(max), big.NewInt(min))
+	r.lock.Lock()
+	n, err := rand.Int(r.src, span)
+	r.lock.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return new(big.Int).Add(n, big.NewInt(min)).Int64(), nil
+}
+
+// String returns a random string of n characters from charset, charset may
+// contain any unicode characters, each one has the same probability.
+func (r *Rand) String(n int, charset string) (string, error) {
+	chars := []rune(charset)
+	if len(chars) == 0 {
+		return "", errors.New("urand: empty charset")
+	}
+	if n < 0 {
+		return "", errors.New("urand: negative length")
+	}
+	result := make([]rune, n)
+	for i := range result {
+		idx, err := r.Int(0, int64(len(chars)))
+		if err != nil {
+			return "", err
+		}
+		result[i] = chars[idx]
+	}
+	return string(result), nil
+}
+
+// Hex returns n random bytes, hex encoded, 2n characters.
+func (r *Rand) Hex(n int) (string, error) {
+	b, err := r.Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Base64 returns n random bytes, standard base64 encoded.
+func (r *Rand) Base64(n int) (string, error) {
+	b, err := r.Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Token returns n random bytes, url safe base64 encoded without padding,
+// suitable for session ids, api keys and reset links. 32 is a good n.
+func (r *Rand) Token(n int) (string, error) {
+	b, err := r.Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Bytes returns n crypto random bytes.
+func Bytes(n int) ([]byte, error) {
+	return std.Bytes(n)
+}
+
+// Int returns a crypto random number in [min, max).
+func Int(min, max int64) (int64, error) {
+	return std.Int(min, max)
+}
+
+// String returns a crypto random string of n characters from charset.
+func String(n int, charset string) (string, error) {
+	return std.String(n, charset)
+}
+
+// Hex returns n crypto random bytes, hex encoded.
+func Hex(n int) (string, error) {
+	return std.Hex(n)
+}
+
+// Base64 returns n crypto random bytes, standard base64 encoded.
+func Base64(n int) (string, error) {
+	return std.Base64(n)
+}
+
+// Token returns n crypto random bytes, url safe base64 encoded without padding.
+func Token(n int) (string, error) {
+	return std.Token(n)
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urand
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	a, err := Bytes(16)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 16, len(a), "they should be equal")
+	b, _ := Bytes(16)
+	assert.NotEqual(t, a, b, "they should not be equal")
+
+	_, err = Bytes(-1)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestInt(t *testing.T) {
+	seen := make(map[int64]bool)
+	for i := 0; i < 1000; i++ {
+		n, err := Int(-3, 3)
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, true, n >= -3 && n < 3, n)
+		seen[n] = true
+	}
+	assert.Equal(t, 6, len(seen), "they should be equal")
+
+	_, err := Int(3, 3)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestString(t *testing.T) {
+	s, err := String(32, Alphanumeric)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 32, len(s), "they should be equal")
+	for _, c := range s {
+		assert.Equal(t, true, strings.ContainsRune(Alphanumeric, c), s)
+	}
+
+	s, err = String(10, "黄健")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 10, utf8.RuneCountInString(s), "they should be equal")
+
+	_, err = String(10, "")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
+
+func TestEncoded(t *testing.T) {
+	h, err := Hex(16)
+	assert.Equal(t, nil, err, "they should be equal")
+	b, err := hex.DecodeString(h)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 16, len(b), "they should be equal")
+
+	s, err := Base64(16)
+	assert.Equal(t, nil, err, "they should be equal")
+	b, err = base64.StdEncoding.DecodeString(s)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 16, len(b), "they should be equal")
+
+	token, err := Token(32)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 43, len(token), "they should be equal")
+	assert.Equal(t, false, strings.ContainsAny(token, "+/="), token)
+}
+
+func TestSeeded(t *testing.T) {
+	a, b := New(1), New(1)
+	for i := 0; i < 10; i++ {
+		x, _ := a.String(16, Alphanumeric)
+		y, _ := b.String(16, Alphanumeric)
+		assert.Equal(t, x, y, "they should be equal")
+
+		m, _ := a.Int(0, 1000)
+		n, _ := b.Int(0, 1000)
+		assert.Equal(t, m, n, "they should be equal")
+
+		p, _ := a.Token(8)
+		q, _ := b.Token(8)
+		assert.Equal(t, p, q, "they should be equal")
+	}
+
+	x, _ := New(1).Hex(8)
+	y, _ := New(2).Hex(8)
+	assert.NotEqual(t, x, y, "they should not be equal")
+}
@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urate
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type keyedEntry struct {
+	limiter  Limiter
+	lastSeen time.Time
+}
+
+/*
+Keyed keeps one Limiter per key, e.g. per client IP or API token. Limiters
+are created on first use; call Cleanup periodically to drop idle keys.
+*/
+type Keyed struct {
+	lock     sync.Mutex
+	newFunc  func() Limiter
+	limiters map[string]*keyedEntry
+}
+
+// NewKeyed create a Keyed which builds limiters with newFunc, e.g.
+//
+//	urate.NewKeyed(func() urate.Limiter { return urate.NewTokenBucket(10, 20) })
+func NewKeyed(newFunc func() Limiter) *Keyed {
+	return &Keyed{
+		newFunc:  newFunc,
+		limiters: make(map[string]*keyedEntry),
+	}
+}
+
+// Get returns the limiter of key, creating it if needed.
+func (k *Keyed) Get(key string) Limiter {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	e, ok := k.limiters[key]
+	if !ok {
+		e = &keyedEntry{limiter: k.newFunc()}
+		k.limiters[key] = e
+	}
+	e.lastSeen = time.Now()
+	return e.limiter
+}
+
+// AllowKey reports whether an event for key may happen now.
+func (k *Keyed) AllowKey(key string) bool {
+	return k.Get(key).Allow()
+}
+
+// WaitKey blocks until an event for key may happen or ctx is done.
+func (k *Keyed) WaitKey(ctx context.Context, key string) error {
+	return k.Get(key).Wait(ctx)
+}
+
+// Len returns the number of keys.
+func (k *Keyed) Len() int {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	return len(k.limiters)
+}
+
+// Cleanup removes keys not used for idle, returns how many were removed.
+func (k *Keyed) Cleanup(idle time.Duration) int {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	n := 0
+	deadline := time.Now().Add(-idle)
+	for key, e := range k.limiters {
+		if e.lastSeen.Before(deadline) {
+			delete(k.limiters, key)
+			n++
+		}
+	}
+	return n
+}
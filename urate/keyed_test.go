@@ -0,0 +1,48 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyed(t *testing.T) {
+	k := NewKeyed(func() Limiter { return NewTokenBucket(1, 1) })
+	assert.Equal(t, true, k.AllowKey("huangjian"), "they should be equal")
+	assert.Equal(t, false, k.AllowKey("huangjian"), "they should be equal")
+	assert.Equal(t, true, k.AllowKey("MDGSF"), "they should be equal")
+	assert.Equal(t, 2, k.Len(), "they should be equal")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.NotEqual(t, nil, k.WaitKey(ctx, "MDGSF"), "they should not be equal")
+
+	time.Sleep(10 * time.Millisecond)
+	k.Get("MDGSF")
+	assert.Equal(t, 1, k.Cleanup(5*time.Millisecond), "they should be equal")
+	assert.Equal(t, 1, k.Len(), "they should be equal")
+}
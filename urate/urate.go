@@ -0,0 +1,225 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urate
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrExceedsBurst is returned by TokenBucket.WaitN when n is larger than the
+// burst, such a wait could never succeed.
+var ErrExceedsBurst = errors.New("urate: n exceeds burst")
+
+// Limiter is implemented by TokenBucket and SlidingWindow.
+type Limiter interface {
+	// Allow reports whether one event may happen now, and consumes it if so.
+	Allow() bool
+	// Wait blocks until one event may happen or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// wait calls try until it succeeds, sleeping the returned delay between
+// calls, or returns ctx.Err().
+func wait(ctx context.Context, try func() (bool, time.Duration)) error {
+	for {
+		ok, delay := try()
+		if ok {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if deadline, has := ctx.Deadline(); has && time.Until(deadline) < delay {
+			return context.DeadlineExceeded
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+/*
+TokenBucket is a token bucket limiter: it holds up to burst tokens and is
+refilled with rate tokens per second, every event takes one token.
+*/
+type TokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket create a full bucket which allows rate events per second
+// on average and bursts of up to burst events.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// Allow takes one token if available.
+func (b *TokenBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+// AllowN takes n tokens if available.
+func (b *TokenBucket) AllowN(n int) bool {
+	ok, _ := b.tryN(n)
+	return ok
+}
+
+func (b *TokenBucket) tryN(n int) (bool, time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill(time.Now())
+	if b.tokens >= float64(n) {
+		b.tokens -= float64(n)
+		return true, 0
+	}
+	if b.rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	missing := float64(n) - b.tokens
+	return false, time.Duration(missing / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or ctx is done.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return b.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are available or ctx is done. It fails at
+// once if ctx's deadline is too close, or with ErrExceedsBurst if n is
+// larger than the burst.
+func (b *TokenBucket) WaitN(ctx context.Context, n int) error {
+	if float64(n) > b.burst {
+		return ErrExceedsBurst
+	}
+	return wait(ctx, func() (bool, time.Duration) {
+		return b.tryN(n)
+	})
+}
+
+// Tokens returns the number of tokens currently available.
+func (b *TokenBucket) Tokens() float64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.refill(time.Now())
+	return b.tokens
+}
+
+/*
+SlidingWindow allows at most limit events in any window. It uses the sliding
+window counter approximation: the count of the previous fixed window is
+weighted by how much of it still overlaps the sliding window.
+*/
+type SlidingWindow struct {
+	lock     sync.Mutex
+	limit    int
+	window   time.Duration
+	start    time.Time // start of the current fixed window
+	current  int
+	previous int
+}
+
+// NewSlidingWindow create a limiter of limit events per window, it panics
+// if window is not positive.
+func NewSlidingWindow(limit int, window time.Duration) *SlidingWindow {
+	if window <= 0 {
+		panic("urate: non-positive window for NewSlidingWindow")
+	}
+	return &SlidingWindow{
+		limit:  limit,
+		window: window,
+		start:  time.Now(),
+	}
+}
+
+func (w *SlidingWindow) advance(now time.Time) {
+	elapsed := now.Sub(w.start)
+	if elapsed < w.window {
+		return
+	}
+	if elapsed < 2*w.window {
+		w.previous = w.current
+	} else {
+		w.previous = 0
+	}
+	w.current = 0
+	w.start = w.start.Add(elapsed / w.window * w.window)
+}
+
+func (w *SlidingWindow) try() (bool, time.Duration) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	now := time.Now()
+	w.advance(now)
+
+	weight := 1 - float64(now.Sub(w.start))/float64(w.window)
+	estimate := float64(w.previous)*weight + float64(w.current)
+	if estimate+1 <= float64(w.limit) {
+		w.current++
+		return true, 0
+	}
+	if w.current >= w.limit {
+		// nothing frees up before the next fixed window
+		return false, w.start.Add(w.window).Sub(now)
+	}
+	// the previous window fades out by previous/window per unit of time
+	need := estimate + 1 - float64(w.limit)
+	return false, time.Duration(need / float64(w.previous) * float64(w.window))
+}
+
+// Allow reports whether an event may happen now.
+func (w *SlidingWindow) Allow() bool {
+	ok, _ := w.try()
+	return ok
+}
+
+// Wait blocks until an event may happen or ctx is done.
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	return wait(ctx, w.try)
+}
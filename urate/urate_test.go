@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package urate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	b := NewTokenBucket(100, 3)
+	assert.Equal(t, true, b.Allow(), "they should be equal")
+	assert.Equal(t, true, b.Allow(), "they should be equal")
+	assert.Equal(t, true, b.Allow(), "they should be equal")
+	assert.Equal(t, false, b.Allow(), "they should be equal")
+
+	time.Sleep(25 * time.Millisecond)
+	assert.Equal(t, true, b.AllowN(2), "they should be equal")
+	assert.Equal(t, true, b.Tokens() < 3, "they should be equal")
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	b := NewTokenBucket(100, 1)
+	b.Allow()
+	start := time.Now()
+	assert.Equal(t, nil, b.Wait(context.Background()), "they should be equal")
+	assert.Equal(t, true, time.Since(start) >= 5*time.Millisecond, "they should be equal")
+
+	b = NewTokenBucket(1, 1)
+	b.Allow()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	assert.Equal(t, context.DeadlineExceeded, b.Wait(ctx), "they should be equal")
+	assert.Equal(t, true, time.Since(start) < 5*time.Millisecond, "they should be equal")
+
+	// more than the burst never fits, even without a deadline
+	b = NewTokenBucket(100, 2)
+	assert.Equal(t, ErrExceedsBurst, b.WaitN(context.Background(), 3), "they should be equal")
+	assert.Equal(t, nil, b.WaitN(context.Background(), 2), "they should be equal")
+}
+
+func TestSlidingWindow(t *testing.T) {
+	w := NewSlidingWindow(3, 50*time.Millisecond)
+	assert.Equal(t, true, w.Allow(), "they should be equal")
+	assert.Equal(t, true, w.Allow(), "they should be equal")
+	assert.Equal(t, true, w.Allow(), "they should be equal")
+	assert.Equal(t, false, w.Allow(), "they should be equal")
+
+	// two full windows later nothing is remembered
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, true, w.Allow(), "they should be equal")
+
+	start := time.Now()
+	w = NewSlidingWindow(2, 20*time.Millisecond)
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, nil, w.Wait(context.Background()), "they should be equal")
+	}
+	assert.Equal(t, true, time.Since(start) >= 20*time.Millisecond, "they should be equal")
+}
+
+func TestSlidingWindowInvalid(t *testing.T) {
+	assert.Panics(t, func() { NewSlidingWindow(3, 0) })
+	assert.Panics(t, func() { NewSlidingWindow(3, -time.Second) })
+}
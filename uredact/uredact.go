@@ -0,0 +1,139 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uredact
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Redacted replaces values which are removed entirely.
+const Redacted = "[REDACTED]"
+
+// MaskFunc masks a sensitive string.
+type MaskFunc func(s string) string
+
+var (
+	lock  sync.RWMutex
+	masks = map[string]MaskFunc{
+		"email": MaskEmail,
+		"phone": MaskDigits,
+		"card":  MaskDigits,
+		"full":  MaskFull,
+		"name":  MaskName,
+	}
+)
+
+// RegisterMask adds or replaces the mask kind used by Mask and by the
+// `mask:"kind"` tag of ujson.MarshalSafe.
+func RegisterMask(kind string, fn MaskFunc) {
+	lock.Lock()
+	defer lock.Unlock()
+	masks[kind] = fn
+}
+
+/*
+Mask masks s with the mask kind: "email", "phone", "card", "name", "full"
+or one added by RegisterMask. Unknown kinds give Redacted, so a typo never
+leaks the value.
+*/
+func Mask(kind, s string) string {
+	lock.RLock()
+	fn := masks[kind]
+	lock.RUnlock()
+	if fn == nil {
+		return Redacted
+	}
+	return fn(s)
+}
+
+// MaskFull replaces every character of s by "*".
+func MaskFull(s string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(s))
+}
+
+// MaskEmail keeps the first character of the local part and the domain:
+// "huangjian@MDGSF.com" becomes "h********@MDGSF.com".
+func MaskEmail(s string) string {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 {
+		return MaskFull(s)
+	}
+	first, size := utf8.DecodeRuneInString(s)
+	return string(first) + strings.Repeat("*", utf8.RuneCountInString(s[size:at])) + s[at:]
+}
+
+// MaskDigits masks all digits but the last four, keeping separators:
+// "+86 138-0013-8000" becomes "+** ***-****-8000".
+func MaskDigits(s string) string {
+	digits := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+	var b strings.Builder
+	seen := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			seen++
+			if seen <= digits-4 {
+				c = '*'
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// MaskName keeps the first character of s: "huangjian" becomes
+// "h********".
+func MaskName(s string) string {
+	if s == "" {
+		return ""
+	}
+	first, size := utf8.DecodeRuneInString(s)
+	return string(first) + strings.Repeat("*", utf8.RuneCountInString(s[size:]))
+}
+
+var (
+	emailRegexp = regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`)
+	digitRegexp = regexp.MustCompile(`\+?\d[\d -]{8,}\d`)
+	tokenRegexp = regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api_key|apikey|authorization)\s*[=:]\s*)("[^"]*"|\S+)`)
+)
+
+/*
+Text masks sensitive data in free text such as log messages: email
+addresses, runs of ten or more digits like phone and card numbers, and the
+values of password=, secret=, token=, api_key= and authorization: pairs.
+It is a safety net, prefer not to log secrets in the first place.
+
+	logger.SetRedactor(uredact.Text)
+*/
+func Text(s string) string {
+	s = tokenRegexp.ReplaceAllString(s, "${1}"+Redacted)
+	s = emailRegexp.ReplaceAllStringFunc(s, MaskEmail)
+	return digitRegexp.ReplaceAllStringFunc(s, MaskDigits)
+}
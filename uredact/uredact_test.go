@@ -0,0 +1,52 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uredact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "h********@MDGSF.com", Mask("email", "huangjian@MDGSF.com"), "they should be equal")
+	assert.Equal(t, "*****", Mask("email", "MDGSF"), "they should be equal")
+	assert.Equal(t, "+** ***-****-8000", Mask("phone", "+86 138-0013-8000"), "they should be equal")
+	assert.Equal(t, "************1111", Mask("card", "4111111111111111"), "they should be equal")
+	assert.Equal(t, "123", Mask("card", "123"), "they should be equal")
+	assert.Equal(t, "h********", Mask("name", "huangjian"), "they should be equal")
+	assert.Equal(t, "黄*", Mask("name", "黄剑"), "they should be equal")
+	assert.Equal(t, "******", Mask("full", "secret"), "they should be equal")
+	assert.Equal(t, Redacted, Mask("unknown", "secret"), "they should be equal")
+
+	RegisterMask("upper", strings.ToUpper)
+	assert.Equal(t, "MDGSF", Mask("upper", "mdgsf"), "they should be equal")
+}
+
+func TestText(t *testing.T) {
+	in := `login huangjian@MDGSF.com phone 13800138000 password=hunter2 token: "abc def" ok`
+	want := `login h********@MDGSF.com phone *******8000 password=[REDACTED] token: [REDACTED] ok`
+	assert.Equal(t, want, Text(in), "they should be equal")
+	assert.Equal(t, "took 1234 ms", Text("took 1234 ms"), "they should be equal")
+}
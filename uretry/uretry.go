@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uretry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+)
+
+// DefaultAttempts is the number of attempts when WithAttempts is not given.
+const DefaultAttempts = 3
+
+// Backoff returns the delay before retry number attempt, attempt starts at 1.
+type Backoff func(attempt int) time.Duration
+
+// FixedBackoff waits d between attempts.
+func FixedBackoff(d time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff waits base, 2*base, 4*base... capped at max,
+// max <= 0 means no cap.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		if base <= 0 {
+			return 0
+		}
+		d := base
+		for i := 1; i < attempt; i++ {
+			d *= 2
+			if d <= 0 {
+				// overflow
+				if max > 0 {
+					return max
+				}
+				return math.MaxInt64
+			}
+			if max > 0 && d >= max {
+				return max
+			}
+		}
+		if max > 0 && d > max {
+			return max
+		}
+		return d
+	}
+}
+
+var (
+	jitterLock sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// JitterBackoff picks a random delay in [0, b(attempt)], which spreads
+// retries of many clients ("full jitter").
+func JitterBackoff(b Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		d := b(attempt)
+		if d <= 0 {
+			return 0
+		}
+		jitterLock.Lock()
+		defer jitterLock.Unlock()
+		return time.Duration(jitterRand.Int63n(int64(d) + 1))
+	}
+}
+
+type options struct {
+	attempts       int
+	backoff        Backoff
+	retryIf        func(err error) bool
+	attemptTimeout time.Duration
+	onRetry        func(attempt int, err error, delay time.Duration)
+}
+
+// Option configures Do.
+type Option func(*options)
+
+// WithAttempts set the max number of attempts, n <= 0 means retry until
+// the context is done.
+func WithAttempts(n int) Option {
+	return func(o *options) {
+		o.attempts = n
+	}
+}
+
+// WithBackoff set the delay strategy, default ExponentialBackoff(100ms, 10s)
+// with jitter.
+func WithBackoff(b Backoff) Option {
+	return func(o *options) {
+		o.backoff = b
+	}
+}
+
+// WithRetryIf only retries errors for which fn returns true, other errors
+// are returned immediately.
+func WithRetryIf(fn func(err error) bool) Option {
+	return func(o *options) {
+		o.retryIf = fn
+	}
+}
+
+// WithAttemptTimeout gives every attempt a context which is done after d.
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.attemptTimeout = d
+	}
+}
+
+// WithOnRetry set a callback invoked after a failed attempt, before waiting
+// delay for the next one.
+func WithOnRetry(fn func(attempt int, err error, delay time.Duration)) Option {
+	return func(o *options) {
+		o.onRetry = fn
+	}
+}
+
+// LogRetry returns an OnRetry callback which logs a warning to l, nil l
+// means the default logger.
+func LogRetry(l *log.Logger) func(attempt int, err error, delay time.Duration) {
+	if l == nil {
+		l = log.DefaultLog()
+	}
+	return func(attempt int, err error, delay time.Duration) {
+		l.Warn("attempt %d failed: %v, retrying in %v", attempt, err, delay)
+	}
+}
+
+/*
+Do calls fn until it succeeds, the attempts are used up, the error is not
+retryable or ctx is done. It returns nil on success, otherwise the last
+error of fn, or ctx.Err() if ctx was done while waiting to retry.
+*/
+func Do(ctx context.Context, fn func(ctx context.Context) error, opts ...Option) error {
+	o := options{
+		attempts: DefaultAttempts,
+		backoff:  JitterBackoff(ExponentialBackoff(100*time.Millisecond, 10*time.Second)),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := runAttempt(ctx, fn, o.attemptTimeout)
+		if err == nil {
+			return nil
+		}
+		if o.retryIf != nil && !o.retryIf(err) {
+			return err
+		}
+		if o.attempts > 0 && attempt >= o.attempts {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delay := o.backoff(attempt)
+		if o.onRetry != nil {
+			o.onRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func runAttempt(ctx context.Context, fn func(ctx context.Context) error, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,127 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uretry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("huangjian")
+		}
+		return nil
+	}, WithBackoff(FixedBackoff(time.Millisecond)))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, 3, calls, "they should be equal")
+
+	calls = 0
+	errTest := errors.New("MDGSF")
+	var delays []time.Duration
+	err = Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errTest
+	}, WithAttempts(4), WithBackoff(ExponentialBackoff(time.Millisecond, 3*time.Millisecond)),
+		WithOnRetry(func(attempt int, err error, delay time.Duration) {
+			delays = append(delays, delay)
+		}))
+	assert.Equal(t, errTest, err, "they should be equal")
+	assert.Equal(t, 4, calls, "they should be equal")
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}, delays, "they should be equal")
+}
+
+func TestDoRetryIf(t *testing.T) {
+	errFatal := errors.New("fatal")
+	calls := 0
+	err := Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls == 2 {
+			return errFatal
+		}
+		return errors.New("temporary")
+	}, WithAttempts(10), WithBackoff(FixedBackoff(0)), WithRetryIf(func(err error) bool {
+		return err != errFatal
+	}))
+	assert.Equal(t, errFatal, err, "they should be equal")
+	assert.Equal(t, 2, calls, "they should be equal")
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	err := Do(ctx, func(ctx context.Context) error {
+		return errors.New("huangjian")
+	}, WithAttempts(0), WithBackoff(FixedBackoff(5*time.Millisecond)))
+	assert.Equal(t, context.DeadlineExceeded, err, "they should be equal")
+
+	calls := 0
+	err = Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithAttempts(2), WithAttemptTimeout(5*time.Millisecond), WithBackoff(FixedBackoff(0)))
+	assert.Equal(t, context.DeadlineExceeded, err, "they should be equal")
+	assert.Equal(t, 2, calls, "they should be equal")
+}
+
+func TestBackoff(t *testing.T) {
+	b := ExponentialBackoff(time.Second, 0)
+	assert.Equal(t, time.Second, b(1), "they should be equal")
+	assert.Equal(t, 8*time.Second, b(4), "they should be equal")
+
+	b = ExponentialBackoff(time.Second, time.Minute)
+	assert.Equal(t, time.Minute, b(100), "they should be equal")
+
+	j := JitterBackoff(FixedBackoff(time.Second))
+	for i := 0; i < 100; i++ {
+		d := j(1)
+		assert.Equal(t, true, d >= 0 && d <= time.Second, "they should be equal")
+	}
+}
+
+func TestLogRetry(t *testing.T) {
+	var buf bytes.Buffer
+	l := log.NewDefaultLog()
+	l.SetOutput(&buf)
+	Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("huangjian")
+	}, WithAttempts(2), WithBackoff(FixedBackoff(0)), WithOnRetry(LogRetry(l)))
+	assert.Equal(t, true, strings.Contains(buf.String(), "attempt 1 failed: huangjian"), "they should be equal")
+}
+
+func TestBackoffOverflow(t *testing.T) {
+	b := ExponentialBackoff(time.Second, 0)
+	assert.Equal(t, time.Duration(math.MaxInt64), b(100), "they should be equal")
+}
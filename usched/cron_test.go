@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usched
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2019, 3, 15, 10, 30, 45, 0, time.UTC) // a friday
+	testCases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2019, 3, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2019, 3, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2019, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * *", time.Date(2019, 3, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2019, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * mon-wed", time.Date(2019, 3, 18, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2019, 3, 17, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"5,10 10-11 * * *", time.Date(2019, 3, 15, 11, 5, 0, 0, time.UTC)},
+		{"0 0 20 * 1", time.Date(2019, 3, 18, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2019, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2019, 3, 15, 11, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tc := range testCases {
+		s, err := ParseCronInLocation(tc.spec, time.UTC)
+		assert.Equal(t, nil, err, tc.spec)
+		assert.Equal(t, tc.next, s.Next(base), tc.spec)
+	}
+}
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "*/0 * * * *", "5-1 * * * *", "@every x", "a * * * *",
+	} {
+		_, err := ParseCron(spec)
+		assert.NotEqual(t, nil, err, spec)
+	}
+
+	defer func() {
+		assert.NotEqual(t, nil, recover(), "they should not be equal")
+	}()
+	MustParseCron("huangjian")
+}
@@ -0,0 +1,336 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usched
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/utime"
+)
+
+// Overlap decides what happens when a job is due while its previous run is
+// still running.
+type Overlap int
+
+const (
+	// OverlapSkip drops the new run.
+	OverlapSkip Overlap = iota
+	// OverlapQueue runs the new run once the previous one finished.
+	OverlapQueue
+	// OverlapAllow runs the new run concurrently.
+	OverlapAllow
+)
+
+var (
+	// ErrJobExists is returned when adding a job whose name is taken.
+	ErrJobExists = errors.New("usched: job already exists")
+	// ErrJobNotFound is returned when removing an unknown job.
+	ErrJobNotFound = errors.New("usched: job not found")
+)
+
+// JobFunc is the function run by a job, ctx is cancelled when the job
+// times out or the scheduler stops.
+type JobFunc func(ctx context.Context) error
+
+type jobOptions struct {
+	overlap Overlap
+	jitter  time.Duration
+	timeout time.Duration
+}
+
+// JobOption configures a job.
+type JobOption func(*jobOptions)
+
+// WithOverlap set the overlap policy, default OverlapSkip.
+func WithOverlap(o Overlap) JobOption {
+	return func(opts *jobOptions) {
+		opts.overlap = o
+	}
+}
+
+// WithJitter delays every run by a random duration in [0, d), which
+// spreads jobs of many instances scheduled at the same time.
+func WithJitter(d time.Duration) JobOption {
+	return func(opts *jobOptions) {
+		opts.jitter = d
+	}
+}
+
+// WithTimeout cancels the context of a run after d.
+func WithTimeout(d time.Duration) JobOption {
+	return func(opts *jobOptions) {
+		opts.timeout = d
+	}
+}
+
+type job struct {
+	name     string
+	schedule Schedule
+	fn       JobFunc
+	options  jobOptions
+	stop     chan struct{}
+
+	lock    sync.Mutex
+	running int
+	queued  int
+}
+
+/*
+Scheduler runs jobs on cron schedules or fixed intervals. Every job has its
+own goroutine waiting for the next activation; runs are logged, panics are
+recovered and logged as errors.
+*/
+type Scheduler struct {
+	logger *log.Logger
+	clock  utime.Clock
+
+	lock    sync.Mutex
+	jobs    map[string]*job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	started bool
+	wg      sync.WaitGroup // job loops and runs
+}
+
+// New create a scheduler which logs to logger, nil means the default logger.
+func New(logger *log.Logger) *Scheduler {
+	if logger == nil {
+		logger = log.DefaultLog()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		logger: logger,
+		clock:  utime.RealClock(),
+		jobs:   make(map[string]*job),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// AddCron adds a job running on the cron expression spec, see ParseCron.
+func (s *Scheduler) AddCron(name, spec string, fn JobFunc, opts ...JobOption) error {
+	schedule, err := ParseCron(spec)
+	if err != nil {
+		return err
+	}
+	return s.Add(name, schedule, fn, opts...)
+}
+
+// AddInterval adds a job running every interval.
+func (s *Scheduler) AddInterval(name string, interval time.Duration, fn JobFunc, opts ...JobOption) error {
+	return s.Add(name, Every(interval), fn, opts...)
+}
+
+// Add adds a job running on schedule. Jobs added to a started scheduler
+// start at once.
+func (s *Scheduler) Add(name string, schedule Schedule, fn JobFunc, opts ...JobOption) error {
+	j := &job{name: name, schedule: schedule, fn: fn, stop: make(chan struct{})}
+	for _, opt := range opts {
+		opt(&j.options)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		return fmt.Errorf("%w: %s", ErrJobExists, name)
+	}
+	s.jobs[name] = j
+	if s.started {
+		s.startJob(j)
+	}
+	return nil
+}
+
+// Remove stops scheduling the job, a run in progress is not interrupted.
+func (s *Scheduler) Remove(name string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, name)
+	}
+	delete(s.jobs, name)
+	close(j.stop)
+	return nil
+}
+
+// Jobs returns the names of the registered jobs.
+func (s *Scheduler) Jobs() []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// SetClock replaces the real clock, for tests. It must be called before
+// Start.
+func (s *Scheduler) SetClock(clock utime.Clock) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.clock = clock
+}
+
+// Start starts scheduling all jobs.
+func (s *Scheduler) Start() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, j := range s.jobs {
+		s.startJob(j)
+	}
+}
+
+/*
+Stop stops scheduling, cancels the context of running jobs and waits for
+them until ctx is done. A stopped scheduler can not be restarted.
+*/
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.lock.Lock()
+	for name, j := range s.jobs {
+		delete(s.jobs, name)
+		close(j.stop)
+	}
+	s.lock.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) startJob(j *job) {
+	s.wg.Add(1)
+	go s.loop(j)
+}
+
+func (s *Scheduler) loop(j *job) {
+	defer s.wg.Done()
+	for {
+		now := s.clock.Now()
+		next := j.schedule.Next(now)
+		if next.IsZero() {
+			s.logger.Warn("usched: job %s has no next activation, stopped", j.name)
+			return
+		}
+		delay := next.Sub(now)
+		if j.options.jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(j.options.jitter)))
+		}
+
+		timer := s.clock.NewTimer(delay)
+		select {
+		case <-timer.C():
+			s.dispatch(j)
+		case <-j.stop:
+			timer.Stop()
+			return
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (s *Scheduler) dispatch(j *job) {
+	j.lock.Lock()
+	if j.running > 0 {
+		switch j.options.overlap {
+		case OverlapSkip:
+			j.lock.Unlock()
+			s.logger.Warn("usched: job %s is still running, run skipped", j.name)
+			return
+		case OverlapQueue:
+			j.queued++
+			j.lock.Unlock()
+			return
+		}
+	}
+	j.running++
+	j.lock.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			s.run(j)
+			j.lock.Lock()
+			if j.queued > 0 && s.ctx.Err() == nil {
+				j.queued--
+				j.lock.Unlock()
+				continue
+			}
+			j.queued = 0
+			j.running--
+			j.lock.Unlock()
+			return
+		}
+	}()
+}
+
+func (s *Scheduler) run(j *job) {
+	ctx := s.ctx
+	if j.options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, j.options.timeout)
+		defer cancel()
+	}
+
+	start := s.clock.Now()
+	s.logger.Info("usched: job %s started", j.name)
+	err := safeRun(ctx, j.fn)
+	if err != nil {
+		s.logger.Error("usched: job %s failed after %v: %v", j.name, s.clock.Since(start), err)
+		return
+	}
+	s.logger.Info("usched: job %s finished in %v", j.name, s.clock.Since(start))
+}
+
+func safeRun(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("panic: %v\n%s", v, debug.Stack())
+		}
+	}()
+	return fn(ctx)
+}
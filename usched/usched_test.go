@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usched
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/MDGSF/utils/utime"
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func newTestLogger() (*log.Logger, *syncBuffer) {
+	buf := &syncBuffer{}
+	l := log.NewDefaultLog()
+	l.SetOutput(buf)
+	return l, buf
+}
+
+func TestScheduler(t *testing.T) {
+	logger, buf := newTestLogger()
+	s := New(logger)
+	var runs, failures int32
+	assert.Equal(t, nil, s.AddInterval("count", 10*time.Millisecond, func(ctx context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}), "they should be equal")
+	assert.Equal(t, nil, s.AddInterval("fail", 10*time.Millisecond, func(ctx context.Context) error {
+		if atomic.AddInt32(&failures, 1) == 1 {
+			panic("huangjian")
+		}
+		return errors.New("MDGSF")
+	}), "they should be equal")
+	assert.Equal(t, true, errors.Is(s.AddInterval("count", time.Second, nil), ErrJobExists), "they should be equal")
+	assert.NotEqual(t, nil, s.AddCron("bad", "* *", nil), "they should not be equal")
+	assert.Equal(t, 2, len(s.Jobs()), "they should be equal")
+
+	s.Start()
+	time.Sleep(55 * time.Millisecond)
+	assert.Equal(t, nil, s.Remove("fail"), "they should be equal")
+	assert.Equal(t, true, errors.Is(s.Remove("fail"), ErrJobNotFound), "they should be equal")
+	assert.Equal(t, nil, s.Stop(context.Background()), "they should be equal")
+
+	n := atomic.LoadInt32(&runs)
+	assert.Equal(t, true, n >= 3, "they should be equal")
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, n, atomic.LoadInt32(&runs), "they should be equal")
+
+	out := buf.String()
+	assert.Equal(t, true, strings.Contains(out, "job count finished"), "they should be equal")
+	assert.Equal(t, true, strings.Contains(out, "panic: huangjian"), "they should be equal")
+	assert.Equal(t, true, strings.Contains(out, "job fail failed"), "they should be equal")
+}
+
+func TestSchedulerOverlap(t *testing.T) {
+	logger, _ := newTestLogger()
+	s := New(logger)
+	var skipRuns, queueRuns int32
+	slow := func(counter *int32) JobFunc {
+		return func(ctx context.Context) error {
+			atomic.AddInt32(counter, 1)
+			time.Sleep(25 * time.Millisecond)
+			return nil
+		}
+	}
+	s.AddInterval("skip", 10*time.Millisecond, slow(&skipRuns))
+	s.AddInterval("queue", 10*time.Millisecond, slow(&queueRuns), WithOverlap(OverlapQueue))
+	s.Start()
+	time.Sleep(105 * time.Millisecond)
+	s.Stop(context.Background())
+
+	// skip runs at most every 30ms, queue keeps one run after another
+	assert.Equal(t, true, atomic.LoadInt32(&skipRuns) <= 4, "they should be equal")
+	assert.Equal(t, true, atomic.LoadInt32(&queueRuns) >= 3, "they should be equal")
+}
+
+func TestSchedulerStopCancelsJobs(t *testing.T) {
+	logger, _ := newTestLogger()
+	s := New(logger)
+	started := make(chan struct{})
+	s.AddInterval("block", 5*time.Millisecond, func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Start()
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Equal(t, nil, s.Stop(ctx), "they should be equal")
+}
+
+func TestSchedulerTimeout(t *testing.T) {
+	logger, buf := newTestLogger()
+	s := New(logger)
+	done := make(chan struct{})
+	s.AddInterval("timeout", 5*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		select {
+		case <-done:
+		default:
+			close(done)
+		}
+		return ctx.Err()
+	}, WithTimeout(5*time.Millisecond), WithJitter(time.Millisecond))
+	s.Start()
+	<-done
+	s.Stop(context.Background())
+	assert.Equal(t, true, strings.Contains(buf.String(), "deadline exceeded"), "they should be equal")
+}
+
+func TestSchedulerFakeClock(t *testing.T) {
+	logger, buf := newTestLogger()
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	clock := utime.NewFakeClock(start)
+	logger.SetNowFunc(clock.Now)
+
+	s := New(logger)
+	s.SetClock(clock)
+	runs := make(chan time.Time, 10)
+	s.AddInterval("hourly", time.Hour, func(ctx context.Context) error {
+		runs <- clock.Now()
+		return nil
+	})
+	s.Start()
+
+	clock.BlockUntil(1)
+	clock.Advance(59 * time.Minute)
+	select {
+	case <-runs:
+		t.Fatal("job ran too early")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Hour), <-runs, "they should be equal")
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+	assert.Equal(t, start.Add(2*time.Hour), <-runs, "they should be equal")
+	s.Stop(context.Background())
+
+	stamp := start.Add(time.Hour).Format("2006/01/02 15:04:05")
+	assert.Equal(t, true, strings.Contains(buf.String(), stamp), "they should be equal")
+}
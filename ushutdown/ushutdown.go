@@ -0,0 +1,227 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ushutdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+)
+
+// DefaultTimeout is the time given to all hooks together.
+const DefaultTimeout = 30 * time.Second
+
+// Hook is a cleanup function, ctx is done when the global timeout elapsed.
+type Hook func(ctx context.Context) error
+
+type hook struct {
+	name string
+	fn   Hook
+}
+
+type options struct {
+	timeout time.Duration
+	logger  *log.Logger
+	signals []os.Signal
+	flush   func() error
+}
+
+// Option configures a Manager.
+type Option func(*options)
+
+// WithTimeout set the global timeout of all hooks, default DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithLogger set the logger used to report progress, default the default
+// logger.
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithSignals set the signals which trigger shutdown, default SIGINT and
+// SIGTERM.
+func WithSignals(sigs ...os.Signal) Option {
+	return func(o *options) {
+		o.signals = sigs
+	}
+}
+
+// WithFlush set a function run after all hooks even if they timed out,
+// e.g. to flush a buffered log writer so the shutdown logs are not lost.
+func WithFlush(fn func() error) Option {
+	return func(o *options) {
+		o.flush = fn
+	}
+}
+
+// exit is replaced in tests.
+var exit = os.Exit
+
+/*
+Manager coordinates graceful shutdown. It waits for a signal or a call to
+Shutdown, then runs the registered hooks one by one in registration order
+within the global timeout. A second signal during shutdown exits at once
+with status 1.
+*/
+type Manager struct {
+	options options
+
+	lock     sync.Mutex
+	hooks    []hook
+	trigger  chan struct{}
+	once     sync.Once
+	finished chan struct{}
+	err      error
+}
+
+// NewManager create a Manager, it starts listening for signals at once.
+func NewManager(opts ...Option) *Manager {
+	o := options{
+		timeout: DefaultTimeout,
+		signals: []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.logger == nil {
+		o.logger = log.DefaultLog()
+	}
+	m := &Manager{
+		options:  o,
+		trigger:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, o.signals...)
+	go func() {
+		sig := <-sigs
+		m.options.logger.Info("ushutdown: received %v, shutting down", sig)
+		m.Shutdown()
+		select {
+		case sig = <-sigs:
+			m.options.logger.Error("ushutdown: received %v again, exiting now", sig)
+			exit(1)
+		case <-m.finished:
+		}
+		signal.Stop(sigs)
+	}()
+	go m.run()
+	return m
+}
+
+// Register adds a hook, hooks run in registration order.
+func (m *Manager) Register(name string, fn Hook) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown starts the shutdown as if a signal was received.
+func (m *Manager) Shutdown() {
+	m.once.Do(func() {
+		close(m.trigger)
+	})
+}
+
+// Done is closed when the shutdown starts, e.g. to stop accepting work.
+func (m *Manager) Done() <-chan struct{} {
+	return m.trigger
+}
+
+// Wait blocks until the shutdown finished, it returns the first hook
+// error, or the context error if the timeout elapsed.
+func (m *Manager) Wait() error {
+	<-m.finished
+	return m.err
+}
+
+func (m *Manager) run() {
+	<-m.trigger
+	defer close(m.finished)
+
+	m.lock.Lock()
+	hooks := append([]hook(nil), m.hooks...)
+	m.lock.Unlock()
+
+	logger := m.options.logger
+	ctx, cancel := context.WithTimeout(context.Background(), m.options.timeout)
+	defer cancel()
+
+	for _, h := range hooks {
+		if ctx.Err() != nil {
+			logger.Error("ushutdown: timeout, hook %s not run", h.name)
+			continue
+		}
+		start := time.Now()
+		if err := runHook(ctx, h.fn); err != nil {
+			logger.Error("ushutdown: hook %s failed: %v", h.name, err)
+			if m.err == nil {
+				m.err = fmt.Errorf("ushutdown: hook %s: %w", h.name, err)
+			}
+			continue
+		}
+		logger.Info("ushutdown: hook %s done in %v", h.name, time.Since(start))
+	}
+	if m.err == nil && ctx.Err() != nil {
+		m.err = ctx.Err()
+	}
+	logger.Info("ushutdown: shutdown complete")
+
+	if m.options.flush != nil {
+		if err := m.options.flush(); err != nil && m.err == nil {
+			m.err = err
+		}
+	}
+}
+
+// runHook runs fn but returns when ctx is done even if fn does not.
+func runHook(ctx context.Context, fn Hook) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
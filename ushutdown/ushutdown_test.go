@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ushutdown
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	lock sync.Mutex
+	buf  bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func newTestLogger() (*log.Logger, *syncBuffer) {
+	buf := &syncBuffer{}
+	l := log.NewDefaultLog()
+	l.SetOutput(buf)
+	return l, buf
+}
+
+func TestManager(t *testing.T) {
+	logger, buf := newTestLogger()
+	flushed := false
+	m := NewManager(WithLogger(logger), WithFlush(func() error {
+		flushed = true
+		return nil
+	}))
+	var order []string
+	m.Register("http", func(ctx context.Context) error {
+		order = append(order, "http")
+		return nil
+	})
+	m.Register("db", func(ctx context.Context) error {
+		order = append(order, "db")
+		return errors.New("huangjian")
+	})
+	m.Register("cache", func(ctx context.Context) error {
+		order = append(order, "cache")
+		return nil
+	})
+
+	select {
+	case <-m.Done():
+		t.Fatal("shutdown started too early")
+	default:
+	}
+	m.Shutdown()
+	m.Shutdown()
+	<-m.Done()
+
+	err := m.Wait()
+	assert.NotEqual(t, nil, err, "they should not be equal")
+	assert.Equal(t, "ushutdown: hook db: huangjian", err.Error(), "they should be equal")
+	assert.Equal(t, []string{"http", "db", "cache"}, order, "they should be equal")
+	assert.Equal(t, true, flushed, "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "hook db failed"), "they should be equal")
+}
+
+func TestManagerTimeout(t *testing.T) {
+	logger, buf := newTestLogger()
+	m := NewManager(WithLogger(logger), WithTimeout(20*time.Millisecond))
+	m.Register("stuck", func(ctx context.Context) error {
+		select {}
+	})
+	ran := false
+	m.Register("later", func(ctx context.Context) error {
+		ran = true
+		return nil
+	})
+	m.Shutdown()
+	assert.Equal(t, context.DeadlineExceeded, errors.Unwrap(m.Wait()), "they should be equal")
+	assert.Equal(t, false, ran, "they should be equal")
+	assert.Equal(t, true, strings.Contains(buf.String(), "hook later not run"), "they should be equal")
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usingle
+
+import (
+	"sync"
+
+	"github.com/MDGSF/utils/uerrors"
+)
+
+// PanicError is returned to callers sharing a call whose function panicked.
+type PanicError = uerrors.PanicError
+
+// Result holds the outcome of Do, it is sent on the channel of DoChan.
+type Result[V any] struct {
+	Val    V
+	Err    error
+	Shared bool
+}
+
+type call[V any] struct {
+	wg    sync.WaitGroup
+	val   V
+	err   error
+	dups  int
+	chans []chan<- Result[V]
+}
+
+/*
+Group deduplicates concurrent calls with the same key, only one function
+runs per key at a time and its result is handed to every caller waiting
+on it. The zero Group is ready to use.
+*/
+type Group[K comparable, V any] struct {
+	lock  sync.Mutex
+	calls map[K]*call[V]
+}
+
+/*
+Do run fn and returns its result, if a call for key is already in flight
+Do waits for it and returns the same result. shared reports whether the
+result was given to more than one caller. If fn panics the panic is
+re-raised in the caller which ran it and the waiters get a *PanicError.
+*/
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.lock.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	g.doCall(c, key, fn)
+	return c.val, c.err, c.dups > 0
+}
+
+// DoChan is like Do but returns a channel which receives the result.
+func (g *Group[K, V]) DoChan(key K, fn func() (V, error)) <-chan Result[V] {
+	ch := make(chan Result[V], 1)
+	g.lock.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.lock.Unlock()
+		return ch
+	}
+	c := &call[V]{chans: []chan<- Result[V]{ch}}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	go func() {
+		defer func() {
+			// the panic has already been handed to the waiters as an error
+			recover()
+		}()
+		g.doCall(c, key, fn)
+	}()
+	return ch
+}
+
+func (g *Group[K, V]) doCall(c *call[V], key K, fn func() (V, error)) {
+	panicked := true
+	var panicValue interface{}
+	defer func() {
+		if panicked {
+			panicValue = recover()
+			c.err = uerrors.NewPanicError(panicValue)
+		}
+
+		g.lock.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		c.wg.Done()
+		for _, ch := range c.chans {
+			ch <- Result[V]{Val: c.val, Err: c.err, Shared: c.dups > 0}
+		}
+		g.lock.Unlock()
+
+		if panicked {
+			panic(panicValue)
+		}
+	}()
+
+	c.val, c.err = fn()
+	panicked = false
+}
+
+// Forget makes the next call for key run fn instead of waiting for an
+// in-flight call. Callers already waiting still get the old result.
+func (g *Group[K, V]) Forget(key K) {
+	g.lock.Lock()
+	delete(g.calls, key)
+	g.lock.Unlock()
+}
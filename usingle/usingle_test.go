@@ -0,0 +1,137 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usingle
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	var g Group[string, int]
+	v, err, shared := g.Do("key", func() (int, error) {
+		return 100, nil
+	})
+	assert.Equal(t, 100, v, "they should be equal")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, false, shared, "they should be equal")
+
+	errTest := errors.New("huangjian")
+	_, err, _ = g.Do("key", func() (int, error) {
+		return 0, errTest
+	})
+	assert.Equal(t, errTest, err, "they should be equal")
+}
+
+func TestDoDedup(t *testing.T) {
+	var g Group[string, string]
+	var calls int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, shared := g.Do("key", func() (string, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return "MDGSF", nil
+			})
+			assert.Equal(t, "MDGSF", v, "they should be equal")
+			assert.Equal(t, nil, err, "they should be equal")
+			assert.Equal(t, true, shared, "they should be equal")
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "they should be equal")
+}
+
+func TestDoChan(t *testing.T) {
+	var g Group[int, int]
+	release := make(chan struct{})
+	ch1 := g.DoChan(1, func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	ch2 := g.DoChan(1, func() (int, error) {
+		return 2, nil
+	})
+	close(release)
+	r1, r2 := <-ch1, <-ch2
+	assert.Equal(t, 1, r1.Val, "they should be equal")
+	assert.Equal(t, 1, r2.Val, "they should be equal")
+	assert.Equal(t, true, r2.Shared, "they should be equal")
+}
+
+func TestForget(t *testing.T) {
+	var g Group[string, int]
+	release := make(chan struct{})
+	ch := g.DoChan("key", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	g.Forget("key")
+	v, _, shared := g.Do("key", func() (int, error) {
+		return 2, nil
+	})
+	assert.Equal(t, 2, v, "they should be equal")
+	assert.Equal(t, false, shared, "they should be equal")
+	close(release)
+	assert.Equal(t, 1, (<-ch).Val, "they should be equal")
+}
+
+func TestDoPanic(t *testing.T) {
+	var g Group[string, int]
+	release := make(chan struct{})
+	ch := g.DoChan("key", func() (int, error) {
+		<-release
+		panic("boom")
+	})
+
+	done := make(chan error)
+	go func() {
+		_, err, _ := g.Do("key", func() (int, error) { return 0, nil })
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	r := <-ch
+	_, ok := r.Err.(*PanicError)
+	assert.Equal(t, true, ok, "they should be equal")
+	_, ok = (<-done).(*PanicError)
+	assert.Equal(t, true, ok, "they should be equal")
+
+	defer func() {
+		assert.Equal(t, "boom", recover(), "they should be equal")
+	}()
+	g.Do("other", func() (int, error) { panic("boom") })
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uslice
+
+// Contains reports whether v is in s.
+func Contains[T comparable](s []T, v T) bool {
+	return IndexOf(s, v) >= 0
+}
+
+// IndexOf returns the index of the first v in s, or -1 if not present.
+func IndexOf[T comparable](s []T, v T) int {
+	for i := range s {
+		if s[i] == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Unique returns elements of s without duplicates, in order of first appearance.
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Filter returns elements of s for which keep returns true.
+func Filter[T any](s []T, keep func(T) bool) []T {
+	result := make([]T, 0)
+	for _, v := range s {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Map returns the result of fn for each element of s.
+func Map[T, R any](s []T, fn func(T) R) []R {
+	result := make([]R, len(s))
+	for i, v := range s {
+		result[i] = fn(v)
+	}
+	return result
+}
+
+// Reduce fold s from left to right, starting with initial.
+func Reduce[T, R any](s []T, initial R, fn func(acc R, v T) R) R {
+	acc := initial
+	for _, v := range s {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// Chunk split s into slices of size elements, the last one may be shorter.
+// The chunks share memory with s. It panics if size <= 0.
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("uslice: chunk size must be positive")
+	}
+	result := make([][]T, 0, (len(s)+size-1)/size)
+	for size < len(s) {
+		s, result = s[size:], append(result, s[0:size:size])
+	}
+	if len(s) > 0 {
+		result = append(result, s)
+	}
+	return result
+}
+
+// Reverse returns a new slice with elements of s in reverse order, s is not modified.
+func Reverse[T any](s []T) []T {
+	result := make([]T, len(s))
+	for i, v := range s {
+		result[len(s)-1-i] = v
+	}
+	return result
+}
+
+// Intersect returns unique elements which are in both a and b, in order of a.
+func Intersect[T comparable](a, b []T) []T {
+	set := toSet(b)
+	result := make([]T, 0)
+	for _, v := range Unique(a) {
+		if _, ok := set[v]; ok {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Difference returns elements of a which are not in b, in order of a.
+func Difference[T comparable](a, b []T) []T {
+	set := toSet(b)
+	result := make([]T, 0)
+	for _, v := range a {
+		if _, ok := set[v]; !ok {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// GroupBy group elements of s by key, elements keep their order in each group.
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		result[k] = append(result[k], v)
+	}
+	return result
+}
+
+func toSet[T comparable](s []T) map[T]struct{} {
+	set := make(map[T]struct{}, len(s))
+	for _, v := range s {
+		set[v] = struct{}{}
+	}
+	return set
+}
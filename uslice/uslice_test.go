@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uslice
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContains(t *testing.T) {
+	assert.Equal(t, true, Contains([]int{1, 2, 3}, 2), "they should be equal")
+	assert.Equal(t, false, Contains([]string{"a", "b"}, "c"), "they should be equal")
+	assert.Equal(t, false, Contains(nil, 1), "they should be equal")
+	assert.Equal(t, 1, IndexOf([]string{"a", "b", "b"}, "b"), "they should be equal")
+	assert.Equal(t, -1, IndexOf([]string{"a"}, "b"), "they should be equal")
+}
+
+func TestUnique(t *testing.T) {
+	assert.Equal(t, []int{3, 1, 2}, Unique([]int{3, 1, 3, 2, 1}), "they should be equal")
+	assert.Equal(t, []int{}, Unique([]int(nil)), "they should be equal")
+}
+
+func TestFilterMapReduce(t *testing.T) {
+	even := Filter([]int{1, 2, 3, 4}, func(v int) bool { return v%2 == 0 })
+	assert.Equal(t, []int{2, 4}, even, "they should be equal")
+
+	strs := Map([]int{1, 2}, strconv.Itoa)
+	assert.Equal(t, []string{"1", "2"}, strs, "they should be equal")
+
+	sum := Reduce([]int{1, 2, 3}, 0, func(acc, v int) int { return acc + v })
+	assert.Equal(t, 6, sum, "they should be equal")
+
+	joined := Reduce([]int{1, 2}, "", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+	assert.Equal(t, "12", joined, "they should be equal")
+}
+
+func TestChunk(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, Chunk([]int{1, 2, 3, 4, 5}, 2), "they should be equal")
+	assert.Equal(t, [][]int{{1, 2}}, Chunk([]int{1, 2}, 5), "they should be equal")
+	assert.Equal(t, [][]int{}, Chunk([]int{}, 2), "they should be equal")
+
+	// appending to a chunk does not overwrite the next one.
+	s := []int{1, 2, 3, 4}
+	chunks := Chunk(s, 2)
+	_ = append(chunks[0], 9)
+	assert.Equal(t, []int{1, 2, 3, 4}, s, "they should be equal")
+
+	assert.Panics(t, func() { Chunk(s, 0) }, "should panic")
+}
+
+func TestReverse(t *testing.T) {
+	s := []int{1, 2, 3}
+	assert.Equal(t, []int{3, 2, 1}, Reverse(s), "they should be equal")
+	assert.Equal(t, []int{1, 2, 3}, s, "they should be equal")
+}
+
+func TestSetOperations(t *testing.T) {
+	assert.Equal(t, []int{2, 3}, Intersect([]int{1, 2, 3, 2}, []int{3, 2, 5}), "they should be equal")
+	assert.Equal(t, []int{1, 1}, Difference([]int{1, 2, 1, 3}, []int{2, 3}), "they should be equal")
+	assert.Equal(t, []int{}, Intersect([]int{1}, nil), "they should be equal")
+}
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy([]string{"apple", "avocado", "banana"}, func(s string) byte { return s[0] })
+	assert.Equal(t, map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana"},
+	}, groups, "they should be equal")
+}
+
+func benchData(n int) []string {
+	s := make([]string, n)
+	for i := range s {
+		s[i] = strconv.Itoa(i % (n / 2))
+	}
+	return s
+}
+
+func BenchmarkContains(b *testing.B) {
+	s := benchData(1000)
+	for i := 0; i < b.N; i++ {
+		Contains(s, "not found")
+	}
+}
+
+func BenchmarkUnique(b *testing.B) {
+	s := benchData(1000)
+	for i := 0; i < b.N; i++ {
+		Unique(s)
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	s := benchData(1000)
+	for i := 0; i < b.N; i++ {
+		Filter(s, func(v string) bool { return strings.HasPrefix(v, "1") })
+	}
+}
+
+func BenchmarkMap(b *testing.B) {
+	s := benchData(1000)
+	for i := 0; i < b.N; i++ {
+		Map(s, strings.ToUpper)
+	}
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	s := benchData(1000)
+	other := benchData(500)
+	for i := 0; i < b.N; i++ {
+		Intersect(s, other)
+	}
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	s := benchData(1000)
+	for i := 0; i < b.N; i++ {
+		GroupBy(s, func(v string) int { return len(v) })
+	}
+}
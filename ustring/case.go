@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+Words split s into words, for case conversion.
+Words are separated by any character which is not a letter or digit, and by
+case changes: "fooBar" is foo, Bar and "HTTPServer" is HTTP, Server.
+Digits belong to the word before them, "Version2Beta" is Version2, Beta.
+*/
+func Words(s string) []string {
+	runes := []rune(s)
+	words := make([]string, 0)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		if unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextIsLower {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func joinWords(s, sep string, convert func(i int, word string) string) string {
+	words := Words(s)
+	for i, word := range words {
+		words[i] = convert(i, word)
+	}
+	return strings.Join(words, sep)
+}
+
+func title(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func lower(i int, word string) string {
+	return strings.ToLower(word)
+}
+
+// ToSnake convert s to snake_case, "HTTPServer" -> "http_server".
+func ToSnake(s string) string {
+	return joinWords(s, "_", lower)
+}
+
+// ToScreamingSnake convert s to SCREAMING_SNAKE_CASE, "httpServer" -> "HTTP_SERVER".
+func ToScreamingSnake(s string) string {
+	return joinWords(s, "_", func(i int, word string) string {
+		return strings.ToUpper(word)
+	})
+}
+
+// ToKebab convert s to kebab-case, "HTTPServer" -> "http-server".
+func ToKebab(s string) string {
+	return joinWords(s, "-", lower)
+}
+
+// ToCamel convert s to camelCase, "http_server" -> "httpServer".
+func ToCamel(s string) string {
+	return joinWords(s, "", func(i int, word string) string {
+		if i == 0 {
+			return strings.ToLower(word)
+		}
+		return title(word)
+	})
+}
+
+// ToPascal convert s to PascalCase, "http_server" -> "HttpServer".
+func ToPascal(s string) string {
+	return joinWords(s, "", func(i int, word string) string {
+		return title(word)
+	})
+}
@@ -0,0 +1,88 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWords(t *testing.T) {
+	cases := map[string][]string{
+		"HTTPServer":      {"HTTP", "Server"},
+		"fooBar":          {"foo", "Bar"},
+		"foo_bar-baz qux": {"foo", "bar", "baz", "qux"},
+		"Version2Beta":    {"Version2", "Beta"},
+		"userID":          {"user", "ID"},
+		"getHTTPResponse": {"get", "HTTP", "Response"},
+		"__a__":           {"a"},
+		"ÉcoleNormale":    {"École", "Normale"},
+		"黄健":              {"黄健"},
+		"":                {},
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, Words(input), input)
+	}
+}
+
+func TestToSnake(t *testing.T) {
+	cases := map[string]string{
+		"HTTPServer":    "http_server",
+		"userID":        "user_id",
+		"UserName":      "user_name",
+		"user-name":     "user_name",
+		"Version2Beta":  "version2_beta",
+		"already_snake": "already_snake",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, ToSnake(input), input)
+	}
+	assert.Equal(t, "HTTP_SERVER", ToScreamingSnake("httpServer"), "they should be equal")
+	assert.Equal(t, "http-server", ToKebab("HTTPServer"), "they should be equal")
+}
+
+func TestToCamel(t *testing.T) {
+	cases := map[string]string{
+		"http_server":   "httpServer",
+		"HTTPServer":    "httpServer",
+		"user-id":       "userId",
+		"UserName":      "userName",
+		"école_normale": "écoleNormale",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, ToCamel(input), input)
+	}
+}
+
+func TestToPascal(t *testing.T) {
+	cases := map[string]string{
+		"http_server": "HttpServer",
+		"userID":      "UserId",
+		"foo bar":     "FooBar",
+		"x":           "X",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, ToPascal(input), input)
+	}
+}
@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Format replace ${name} placeholders in tpl with values from vars.
+
+	Format("Hello ${name}, you have ${count} items", map[string]interface{}{
+		"name":  "huangjian",
+		"count": 3,
+	})
+
+Values are formatted with fmt.Sprint, "$$" is a literal "$".
+Placeholders missing in vars, and unterminated ones, are kept as is.
+*/
+func Format(tpl string, vars map[string]interface{}) string {
+	result, _ := format(tpl, vars, false)
+	return result
+}
+
+// FormatStrict is the same as Format, but returns an error if a placeholder
+// is missing in vars or is unterminated.
+func FormatStrict(tpl string, vars map[string]interface{}) (string, error) {
+	return format(tpl, vars, true)
+}
+
+func format(tpl string, vars map[string]interface{}, strict bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(tpl); i++ {
+		c := tpl[i]
+		if c != '$' || i+1 >= len(tpl) {
+			b.WriteByte(c)
+			continue
+		}
+
+		switch tpl[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(tpl[i+2:], '}')
+			if end < 0 {
+				if strict {
+					return "", fmt.Errorf("unterminated placeholder at %v", i)
+				}
+				b.WriteString(tpl[i:])
+				return b.String(), nil
+			}
+			name := tpl[i+2 : i+2+end]
+			value, ok := vars[name]
+			if !ok {
+				if strict {
+					return "", fmt.Errorf("missing value for placeholder %q", name)
+				}
+				b.WriteString(tpl[i : i+3+end])
+			} else {
+				b.WriteString(fmt.Sprint(value))
+			}
+			i += 2 + end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
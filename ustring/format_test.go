@@ -0,0 +1,57 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	vars := map[string]interface{}{
+		"name":  "huangjian",
+		"count": 3,
+	}
+	assert.Equal(t, "Hello huangjian, you have 3 items", Format("Hello ${name}, you have ${count} items", vars), "they should be equal")
+	assert.Equal(t, "price: $3, ${name}", Format("price: $$${count}, $${name}", vars), "they should be equal")
+	assert.Equal(t, "$ and $", Format("$ and $", vars), "they should be equal")
+	assert.Equal(t, "hi ${missing}", Format("hi ${missing}", vars), "they should be equal")
+	assert.Equal(t, "hi ${name", Format("hi ${name", vars), "they should be equal")
+	assert.Equal(t, "黄健 huangjian", Format("黄健 ${name}", vars), "they should be equal")
+	assert.Equal(t, "", Format("", vars), "they should be equal")
+}
+
+func TestFormatStrict(t *testing.T) {
+	vars := map[string]interface{}{"name": "huangjian"}
+
+	result, err := FormatStrict("Hello ${name}", vars)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "Hello huangjian", result, "they should be equal")
+
+	_, err = FormatStrict("Hello ${missing}", vars)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	_, err = FormatStrict("Hello ${name", vars)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
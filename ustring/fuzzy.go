@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"sort"
+)
+
+// Levenshtein Returns the edit distance between a and b, the minimum number
+// of single character insertions, deletions and substitutions. It works on
+// runes, so "黄健" and "黄" have distance 1.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	// only two rows of the matrix are needed.
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a int, others ...int) int {
+	for _, b := range others {
+		if b < a {
+			a = b
+		}
+	}
+	return a
+}
+
+// Similarity Returns how similar a and b are, from 0 (nothing in common) to
+// 1 (equal), based on Levenshtein distance.
+func Similarity(a, b string) float64 {
+	maxLen := len([]rune(a))
+	if l := len([]rune(b)); l > maxLen {
+		maxLen = l
+	}
+	if maxLen == 0 {
+		return 1
+	}
+	return 1 - float64(Levenshtein(a, b))/float64(maxLen)
+}
+
+// BestMatch Returns the candidate most similar to target and its similarity,
+// the first one wins a tie. Returns "", 0 if candidates is empty.
+func BestMatch(target string, candidates []string) (string, float64) {
+	best := ""
+	bestScore := -1.0
+	for _, candidate := range candidates {
+		if score := Similarity(target, candidate); score > bestScore {
+			best = candidate
+			bestScore = score
+		}
+	}
+	if bestScore < 0 {
+		return "", 0
+	}
+	return best, bestScore
+}
+
+/*
+Suggest Returns candidates with similarity to target at least minSimilarity,
+most similar first, for "did you mean ...?" messages.
+
+	Suggest("stauts", []string{"status", "stash", "commit"}, 0.5) // [status stash]
+*/
+func Suggest(target string, candidates []string, minSimilarity float64) []string {
+	type scored struct {
+		candidate string
+		score     float64
+	}
+	matches := make([]scored, 0)
+	for _, candidate := range candidates {
+		if score := Similarity(target, candidate); score >= minSimilarity {
+			matches = append(matches, scored{candidate, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]string, len(matches))
+	for i, m := range matches {
+		result[i] = m.candidate
+	}
+	return result
+}
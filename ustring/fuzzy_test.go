@@ -0,0 +1,61 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 3, Levenshtein("kitten", "sitting"), "they should be equal")
+	assert.Equal(t, 3, Levenshtein("sitting", "kitten"), "they should be equal")
+	assert.Equal(t, 0, Levenshtein("huangjian", "huangjian"), "they should be equal")
+	assert.Equal(t, 9, Levenshtein("", "huangjian"), "they should be equal")
+	assert.Equal(t, 9, Levenshtein("huangjian", ""), "they should be equal")
+	assert.Equal(t, 1, Levenshtein("黄健", "黄"), "they should be equal")
+	assert.Equal(t, 2, Levenshtein("flaw", "lawn"), "they should be equal")
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, Similarity("", ""), "they should be equal")
+	assert.Equal(t, 1.0, Similarity("abc", "abc"), "they should be equal")
+	assert.Equal(t, 0.0, Similarity("abc", "xyz"), "they should be equal")
+	assert.Equal(t, 0.75, Similarity("abcd", "abce"), "they should be equal")
+}
+
+func TestBestMatch(t *testing.T) {
+	best, score := BestMatch("stauts", []string{"commit", "status", "stash"})
+	assert.Equal(t, "status", best, "they should be equal")
+	assert.Equal(t, true, score > 0.6, score)
+
+	best, score = BestMatch("stauts", nil)
+	assert.Equal(t, "", best, "they should be equal")
+	assert.Equal(t, 0.0, score, "they should be equal")
+}
+
+func TestSuggest(t *testing.T) {
+	assert.Equal(t, []string{"status", "stash"}, Suggest("stauts", []string{"status", "stash", "commit"}, 0.5), "they should be equal")
+	assert.Equal(t, []string{}, Suggest("stauts", []string{"commit"}, 0.5), "they should be equal")
+}
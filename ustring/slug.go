@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// transliterations latin characters and their ascii replacement.
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c",
+	'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ĝ': "g", 'ġ': "g", 'ģ': "g",
+	'ĥ': "h", 'ħ': "h",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ĵ': "j", 'ķ': "k",
+	'ł': "l", 'ľ': "l", 'ĺ': "l", 'ļ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ņ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'ŕ': "r", 'ř': "r", 'ŗ': "r",
+	'ś': "s", 'š': "s", 'ş': "s", 'ŝ': "s", 'ș': "s",
+	'ť': "t", 'ţ': "t", 'ț': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th",
+}
+
+/*
+Slugify convert s to a lower case url slug, like "Hello, World!" -> "hello-world".
+Accented latin characters are transliterated to ascii, other letters and
+digits are kept, everything else becomes a single hyphen.
+*/
+func Slugify(s string) string {
+	s = strings.Replace(strings.ToLower(s), "&", " and ", -1)
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range s {
+		t, ok := transliterations[r]
+		if !ok {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				hyphen = true
+				continue
+			}
+			t = string(r)
+		}
+		if hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		hyphen = false
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// windowsReserved file names which can not be used on windows, even with an extension.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// maxFilenameBytes most file systems limit a file name to 255 bytes.
+const maxFilenameBytes = 255
+
+/*
+SanitizeFilename make s safe to use as a file name on linux, mac and windows.
+Path separators, control characters and characters invalid on windows
+(<>:"|?*) are replaced with '_', trailing dots and spaces are removed,
+reserved windows names like "CON" or "nul.txt" are prefixed with '_', and
+the name is cut to 255 bytes, keeping the extension. Never returns an empty
+string, "." or "..".
+*/
+func SanitizeFilename(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r < 0x20 || r == 0x7f || unicode.IsControl(r):
+			b.WriteByte('_')
+		case strings.ContainsRune(`/\<>:"|?*`, r):
+			b.WriteByte('_')
+		case r == utf8.RuneError:
+			b.WriteByte('_')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	name := strings.TrimRight(strings.TrimSpace(b.String()), ". ")
+
+	if len(name) == 0 {
+		return "_"
+	}
+
+	base := name
+	if idx := strings.Index(base, "."); idx >= 0 {
+		base = base[:idx]
+	}
+	if windowsReserved[strings.ToUpper(strings.TrimSpace(base))] {
+		name = "_" + name
+	}
+
+	if len(name) > maxFilenameBytes {
+		ext := filepath.Ext(name)
+		if len(ext) > maxFilenameBytes/2 {
+			ext = ""
+		}
+		name = truncateBytes(name[:len(name)-len(ext)], maxFilenameBytes-len(ext)) + ext
+	}
+	return name
+}
+
+// truncateBytes cut s to at most n bytes, without splitting a character.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := map[string]string{
+		"Hello, World!":             "hello-world",
+		"  --Go is fun--  ":         "go-is-fun",
+		"Crème Brûlée":              "creme-brulee",
+		"Straße & Co.":              "strasse-and-co",
+		"Ælfred the Great":          "aelfred-the-great",
+		"Version 2.0 released":      "version-2-0-released",
+		"黄健 的 博客":                   "黄健-的-博客",
+		"":                          "",
+		"!!!":                       "",
+		"C'est déjà l'été":          "c-est-deja-l-ete",
+		"multiple   spaces___under": "multiple-spaces-under",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, Slugify(input), input)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := map[string]string{
+		"report.pdf":          "report.pdf",
+		"../../etc/passwd":    ".._.._etc_passwd",
+		`a\b/c:d*e?f"g<h>i|j`: "a_b_c_d_e_f_g_h_i_j",
+		"tab\there\x00":       "tab_here_",
+		"trailing. . ":        "trailing",
+		"CON":                 "_CON",
+		"nul.txt":             "_nul.txt",
+		"com1.tar.gz":         "_com1.tar.gz",
+		"console.txt":         "console.txt",
+		"":                    "_",
+		".":                   "_",
+		"..":                  "_",
+		"黄健.txt":              "黄健.txt",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, SanitizeFilename(input), input)
+	}
+}
+
+func TestSanitizeFilenameLong(t *testing.T) {
+	name := SanitizeFilename(strings.Repeat("黄", 100) + ".txt")
+	assert.Equal(t, true, len(name) <= 255, len(name))
+	assert.Equal(t, true, strings.HasSuffix(name, ".txt"), name)
+	assert.Equal(t, true, utf8.ValidString(name), name)
+}
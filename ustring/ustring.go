@@ -0,0 +1,138 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultEllipsis appended by Truncate.
+const DefaultEllipsis = "..."
+
+// Truncate shorten s to at most n runes, ending with DefaultEllipsis if cut.
+func Truncate(s string, n int) string {
+	return TruncateWith(s, n, DefaultEllipsis)
+}
+
+/*
+TruncateWith shorten s to at most n runes, ending with ellipsis if cut.
+The result, including ellipsis, is never longer than n runes, and never
+splits a multi-byte character.
+*/
+func TruncateWith(s string, n int, ellipsis string) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	ellipsisLen := utf8.RuneCountInString(ellipsis)
+	if ellipsisLen >= n {
+		return SubstrByRunes(ellipsis, 0, n)
+	}
+	return SubstrByRunes(s, 0, n-ellipsisLen) + ellipsis
+}
+
+// PadLeft pad s on the left with pad until it is length runes long.
+func PadLeft(s string, length int, pad rune) string {
+	count := length - utf8.RuneCountInString(s)
+	if count <= 0 {
+		return s
+	}
+	return strings.Repeat(string(pad), count) + s
+}
+
+// PadRight pad s on the right with pad until it is length runes long.
+func PadRight(s string, length int, pad rune) string {
+	count := length - utf8.RuneCountInString(s)
+	if count <= 0 {
+		return s
+	}
+	return s + strings.Repeat(string(pad), count)
+}
+
+// Reverse reverse s by characters, combining marks stay after the
+// character they belong to, so "é" written as "é" is kept intact.
+func Reverse(s string) string {
+	runes := []rune(s)
+	result := make([]rune, 0, len(runes))
+	for end := len(runes); end > 0; {
+		start := end - 1
+		for start > 0 && unicode.Is(unicode.Mn, runes[start]) {
+			start--
+		}
+		result = append(result, runes[start:end]...)
+		end = start
+	}
+	return string(result)
+}
+
+// IsBlank reports whether s is empty or contains only white space.
+func IsBlank(s string) bool {
+	return len(strings.TrimSpace(s)) == 0
+}
+
+// DefaultIfEmpty returns def if s is empty, otherwise s.
+func DefaultIfEmpty(s, def string) string {
+	if len(s) == 0 {
+		return def
+	}
+	return s
+}
+
+// DefaultIfBlank returns def if s is blank, otherwise s.
+func DefaultIfBlank(s, def string) string {
+	if IsBlank(s) {
+		return def
+	}
+	return s
+}
+
+// SubstrByRunes returns at most length runes of s starting at rune start.
+// Out of range start and length are clamped, a negative length means to the end.
+func SubstrByRunes(s string, start, length int) string {
+	if start < 0 {
+		start = 0
+	}
+	runes := []rune(s)
+	if start >= len(runes) {
+		return ""
+	}
+	end := len(runes)
+	if length >= 0 && start+length < end {
+		end = start + length
+	}
+	return string(runes[start:end])
+}
+
+// ContainsAny reports whether any of substrs is within s.
+func ContainsAny(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "huangjian", Truncate("huangjian", 9), "they should be equal")
+	assert.Equal(t, "huang...", Truncate("huangjian", 8), "they should be equal")
+	assert.Equal(t, "黄健...", Truncate("黄健你好世界", 5), "they should be equal")
+	assert.Equal(t, "黄健…", TruncateWith("黄健你好", 3, "…"), "they should be equal")
+	assert.Equal(t, "..", Truncate("huangjian", 2), "they should be equal")
+	assert.Equal(t, "", Truncate("huangjian", 0), "they should be equal")
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "00042", PadLeft("42", 5, '0'), "they should be equal")
+	assert.Equal(t, "42   ", PadRight("42", 5, ' '), "they should be equal")
+	assert.Equal(t, "**黄健", PadLeft("黄健", 4, '*'), "they should be equal")
+	assert.Equal(t, "huangjian", PadLeft("huangjian", 4, '*'), "they should be equal")
+	assert.Equal(t, "huangjian", PadRight("huangjian", 4, '*'), "they should be equal")
+}
+
+func TestReverse(t *testing.T) {
+	assert.Equal(t, "naijgnauh", Reverse("huangjian"), "they should be equal")
+	assert.Equal(t, "健黄", Reverse("黄健"), "they should be equal")
+	assert.Equal(t, "éfac", Reverse("café"), "they should be equal")
+	assert.Equal(t, "", Reverse(""), "they should be equal")
+}
+
+func TestBlank(t *testing.T) {
+	assert.Equal(t, true, IsBlank(""), "they should be equal")
+	assert.Equal(t, true, IsBlank(" \t\n　"), "they should be equal")
+	assert.Equal(t, false, IsBlank(" a "), "they should be equal")
+
+	assert.Equal(t, "def", DefaultIfEmpty("", "def"), "they should be equal")
+	assert.Equal(t, " ", DefaultIfEmpty(" ", "def"), "they should be equal")
+	assert.Equal(t, "def", DefaultIfBlank(" ", "def"), "they should be equal")
+	assert.Equal(t, "a", DefaultIfBlank("a", "def"), "they should be equal")
+}
+
+func TestSubstrByRunes(t *testing.T) {
+	assert.Equal(t, "健你", SubstrByRunes("黄健你好", 1, 2), "they should be equal")
+	assert.Equal(t, "健你好", SubstrByRunes("黄健你好", 1, -1), "they should be equal")
+	assert.Equal(t, "健你好", SubstrByRunes("黄健你好", 1, 100), "they should be equal")
+	assert.Equal(t, "黄", SubstrByRunes("黄健你好", -1, 1), "they should be equal")
+	assert.Equal(t, "", SubstrByRunes("黄健你好", 4, 1), "they should be equal")
+}
+
+func TestContainsAny(t *testing.T) {
+	assert.Equal(t, true, ContainsAny("huangjian", "xx", "jian"), "they should be equal")
+	assert.Equal(t, false, ContainsAny("huangjian", "xx", "yy"), "they should be equal")
+	assert.Equal(t, false, ContainsAny("huangjian"), "they should be equal")
+}
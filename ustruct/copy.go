@@ -0,0 +1,259 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// copier copies values deeply, keeping shared and cyclic pointers shared
+// in the copy.
+type copier struct {
+	seen map[visit]reflect.Value
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func newCopier() *copier {
+	return &copier{seen: make(map[visit]reflect.Value)}
+}
+
+func (c *copier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		key := visit{v.Pointer(), v.Type()}
+		if p, ok := c.seen[key]; ok {
+			return p
+		}
+		p := reflect.New(v.Type().Elem())
+		c.seen[key] = p
+		p.Elem().Set(c.copy(v.Elem()))
+		return p
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(c.copy(v.Elem()))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(v.Type())
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), c.copy(iter.Value()))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := out.Field(i); f.CanSet() {
+				f.Set(c.copy(v.Field(i)))
+			}
+		}
+		return out
+	}
+	return v
+}
+
+/*
+DeepCopy copies src into the value dst points to. src has the type of
+*dst, or is a pointer to it. Pointers, slices, maps and nested structs are
+copied, so the copy shares no memory with src except through unexported
+fields, which are copied shallowly, and map keys. Pointers shared inside
+src stay shared, cycles are preserved.
+*/
+func DeepCopy(dst, src interface{}) error {
+	dv, sv, err := pair(dst, src, "DeepCopy")
+	if err != nil {
+		return err
+	}
+	dv.Set(newCopier().copy(sv))
+	return nil
+}
+
+// Clone returns a deep copy of v, see DeepCopy.
+func Clone[T any](v T) T {
+	var out T
+	reflect.ValueOf(&out).Elem().Set(newCopier().copy(reflect.ValueOf(&v).Elem()))
+	return out
+}
+
+// pair checks the arguments of DeepCopy and DeepMerge and returns *dst and
+// the src value.
+func pair(dst, src interface{}, name string) (reflect.Value, reflect.Value, error) {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return reflect.Value{}, reflect.Value{}, fmt.Errorf("ustruct: %s needs a non-nil pointer, got %T", name, dst)
+	}
+	dv = dv.Elem()
+
+	sv := reflect.ValueOf(src)
+	if sv.IsValid() && sv.Type() == reflect.PtrTo(dv.Type()) {
+		if sv.IsNil() {
+			return reflect.Value{}, reflect.Value{}, fmt.Errorf("ustruct: %s from nil %T", name, src)
+		}
+		sv = sv.Elem()
+	}
+	if !sv.IsValid() || sv.Type() != dv.Type() {
+		return reflect.Value{}, reflect.Value{}, fmt.Errorf("ustruct: %s %T into %T", name, src, dst)
+	}
+	return dv, sv, nil
+}
+
+// MergeStrategy tells DeepMerge how to combine values.
+type MergeStrategy int
+
+const (
+	// MergeOverride replaces dst values by non-zero src values, slices
+	// included.
+	MergeOverride MergeStrategy = iota
+	// MergeAppend is MergeOverride but appends src slices to dst slices.
+	MergeAppend
+	// MergeFillEmpty only sets dst values which are zero, like defaults.
+	MergeFillEmpty
+)
+
+/*
+DeepMerge merges src into the value dst points to, src has the type of
+*dst or is a pointer to it. Structs, maps and pointers are merged
+recursively field by field and key by key; other values are combined by
+strategy. Zero values in src never change dst, as they cannot be told
+apart from unset ones. Values taken from src are deep copies.
+
+	base := Config{Port: 80, Hosts: []string{"a"}}
+	DeepMerge(&base, Config{Hosts: []string{"b"}}, MergeAppend)
+	// base == Config{Port: 80, Hosts: []string{"a", "b"}}
+*/
+func DeepMerge(dst, src interface{}, strategy MergeStrategy) error {
+	dv, sv, err := pair(dst, src, "DeepMerge")
+	if err != nil {
+		return err
+	}
+	m := merger{strategy: strategy, copier: newCopier()}
+	m.merge(dv, sv)
+	return nil
+}
+
+type merger struct {
+	strategy MergeStrategy
+	copier   *copier
+}
+
+func (m *merger) merge(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(m.copier.copy(src))
+			return
+		}
+		m.merge(dst.Elem(), src.Elem())
+	case reflect.Struct:
+		if !isNested(src.Type()) {
+			m.mergeValue(dst, src)
+			return
+		}
+		for i := 0; i < src.NumField(); i++ {
+			if f := dst.Field(i); f.CanSet() {
+				m.merge(f, src.Field(i))
+			}
+		}
+	case reflect.Map:
+		if src.Len() == 0 {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(m.copier.copy(src))
+			return
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			key := iter.Key()
+			old := dst.MapIndex(key)
+			if !old.IsValid() {
+				dst.SetMapIndex(key, m.copier.copy(iter.Value()))
+				continue
+			}
+			// Map values are not addressable, merge into a copy.
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			elem.Set(old)
+			m.merge(elem, iter.Value())
+			dst.SetMapIndex(key, elem)
+		}
+	case reflect.Slice:
+		if src.Len() == 0 {
+			return
+		}
+		switch m.strategy {
+		case MergeAppend:
+			dst.Set(reflect.AppendSlice(dst, m.copier.copy(src)))
+		case MergeFillEmpty:
+			if dst.Len() == 0 {
+				dst.Set(m.copier.copy(src))
+			}
+		default:
+			dst.Set(m.copier.copy(src))
+		}
+	default:
+		m.mergeValue(dst, src)
+	}
+}
+
+// mergeValue combines values which are not merged recursively.
+func (m *merger) mergeValue(dst, src reflect.Value) {
+	if src.IsZero() {
+		return
+	}
+	if m.strategy == MergeFillEmpty && !dst.IsZero() {
+		return
+	}
+	dst.Set(m.copier.copy(src))
+}
@@ -0,0 +1,152 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type node struct {
+	Name     string
+	Children []*node
+	Parent   *node
+	Attrs    map[string][]string
+	Any      interface{}
+	Created  time.Time
+	hidden   []int
+}
+
+func TestDeepCopy(t *testing.T) {
+	root := &node{
+		Name:    "huangjian",
+		Attrs:   map[string][]string{"tags": {"a", "b"}},
+		Any:     []int{1, 2},
+		Created: time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC),
+		hidden:  []int{1},
+	}
+	child := &node{Name: "MDGSF", Parent: root}
+	root.Children = []*node{child, child}
+
+	var cp node
+	assert.Equal(t, nil, DeepCopy(&cp, root), "they should be equal")
+	assert.Equal(t, "huangjian", cp.Name, "they should be equal")
+	assert.Equal(t, root.Created, cp.Created, "they should be equal")
+	assert.Equal(t, []int{1}, cp.hidden, "they should be equal")
+
+	// Nothing is shared with the source.
+	cp.Attrs["tags"][0] = "x"
+	cp.Any.([]int)[0] = 9
+	cp.Children[0].Name = "changed"
+	assert.Equal(t, "a", root.Attrs["tags"][0], "they should be equal")
+	assert.Equal(t, 1, root.Any.([]int)[0], "they should be equal")
+	assert.Equal(t, "MDGSF", child.Name, "they should be equal")
+
+	// Shared pointers stay shared, cycles point into the copy.
+	assert.Equal(t, true, cp.Children[0] == cp.Children[1], "they should be equal")
+	assert.Equal(t, "huangjian", cp.Children[0].Parent.Name, "they should be equal")
+	assert.Equal(t, true, cp.Children[0].Parent.Children[0] == cp.Children[0], "they should be equal")
+
+	assert.NotEqual(t, nil, DeepCopy(cp, root), "they should not be equal")
+	assert.NotEqual(t, nil, DeepCopy(&cp, "huangjian"), "they should not be equal")
+}
+
+func TestClone(t *testing.T) {
+	m := map[string][]int{"a": {1}}
+	c := Clone(m)
+	c["a"][0] = 2
+	assert.Equal(t, 1, m["a"][0], "they should be equal")
+
+	var e error
+	assert.Equal(t, nil, Clone(e), "they should be equal")
+}
+
+type mergeConfig struct {
+	Name    string
+	Port    int
+	Debug   bool
+	Hosts   []string
+	Labels  map[string]string
+	DB      *mergeDB
+	Servers map[string]mergeDB
+}
+
+type mergeDB struct {
+	Host    string
+	Timeout time.Duration
+}
+
+func TestDeepMerge(t *testing.T) {
+	base := func() mergeConfig {
+		return mergeConfig{
+			Name:    "huangjian",
+			Port:    80,
+			Hosts:   []string{"a"},
+			Labels:  map[string]string{"env": "dev"},
+			DB:      &mergeDB{Host: "localhost", Timeout: time.Second},
+			Servers: map[string]mergeDB{"main": {Host: "a"}},
+		}
+	}
+	src := mergeConfig{
+		Port:    8080,
+		Debug:   true,
+		Hosts:   []string{"b"},
+		Labels:  map[string]string{"team": "go"},
+		DB:      &mergeDB{Host: "db"},
+		Servers: map[string]mergeDB{"main": {Timeout: time.Minute}, "backup": {Host: "b"}},
+	}
+
+	dst := base()
+	assert.Equal(t, nil, DeepMerge(&dst, src, MergeOverride), "they should be equal")
+	assert.Equal(t, mergeConfig{
+		Name:    "huangjian",
+		Port:    8080,
+		Debug:   true,
+		Hosts:   []string{"b"},
+		Labels:  map[string]string{"env": "dev", "team": "go"},
+		DB:      &mergeDB{Host: "db", Timeout: time.Second},
+		Servers: map[string]mergeDB{"main": {Host: "a", Timeout: time.Minute}, "backup": {Host: "b"}},
+	}, dst, "they should be equal")
+
+	dst = base()
+	assert.Equal(t, nil, DeepMerge(&dst, &src, MergeAppend), "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, dst.Hosts, "they should be equal")
+	assert.Equal(t, 8080, dst.Port, "they should be equal")
+
+	dst = base()
+	assert.Equal(t, nil, DeepMerge(&dst, src, MergeFillEmpty), "they should be equal")
+	assert.Equal(t, 80, dst.Port, "they should be equal")
+	assert.Equal(t, true, dst.Debug, "they should be equal")
+	assert.Equal(t, []string{"a"}, dst.Hosts, "they should be equal")
+	assert.Equal(t, &mergeDB{Host: "localhost", Timeout: time.Second}, dst.DB, "they should be equal")
+
+	// Values taken from src are copies.
+	var empty mergeConfig
+	assert.Equal(t, nil, DeepMerge(&empty, src, MergeOverride), "they should be equal")
+	empty.DB.Host = "changed"
+	empty.Hosts[0] = "changed"
+	assert.Equal(t, "db", src.DB.Host, "they should be equal")
+	assert.Equal(t, "b", src.Hosts[0], "they should be equal")
+}
@@ -0,0 +1,231 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Change is a field which differs between the two values given to Diff.
+type Change struct {
+	Path string // dotted path of the field, map keys are segments too
+	Old  interface{}
+	New  interface{}
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+type diffOptions struct {
+	tag    string
+	ignore []string
+}
+
+// DiffOption configures Diff.
+type DiffOption func(*diffOptions)
+
+// WithDiffTag names fields after tag instead of DefaultTag.
+func WithDiffTag(tag string) DiffOption {
+	return func(o *diffOptions) {
+		o.tag = tag
+	}
+}
+
+// WithIgnore skips the given paths and everything below them, like
+// "updated_at" or "address.zip".
+func WithIgnore(paths ...string) DiffOption {
+	return func(o *diffOptions) {
+		o.ignore = append(o.ignore, paths...)
+	}
+}
+
+/*
+Diff compares two structs of the same type, or pointers to them, and
+returns the fields which differ in field order. Nested structs and pointers
+to them are compared field by field and maps key by key, with a missing key
+or nil pointer reported as nil; other values, slices included, are compared
+as a whole and times with time.Time.Equal. Fields are named by DefaultTag
+like ToMap, fields tagged "-" there or `diff:"-"` are skipped. Diff panics
+when a and b have different types, a nil pointer counts as the zero value.
+
+	changes := Diff(before, after, WithIgnore("updated_at"))
+	// [{Path: "email", Old: "a@MDGSF.com", New: "b@MDGSF.com"}]
+*/
+func Diff(a, b interface{}, opts ...DiffOption) []Change {
+	o := diffOptions{tag: DefaultTag}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	av, bv := derefZero(reflect.ValueOf(a)), derefZero(reflect.ValueOf(b))
+	if av.Type() != bv.Type() {
+		panic(fmt.Sprintf("ustruct: Diff of %T and %T", a, b))
+	}
+	d := differ{opts: o}
+	d.diff("", av, bv)
+	return d.changes
+}
+
+type differ struct {
+	opts    diffOptions
+	changes []Change
+}
+
+func (d *differ) ignored(path string) bool {
+	for _, p := range d.opts.ignore {
+		if path == p || strings.HasPrefix(path, p+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *differ) add(path string, a, b reflect.Value) {
+	d.changes = append(d.changes, Change{Path: path, Old: valueOf(a), New: valueOf(b)})
+}
+
+func valueOf(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+	if path != "" && d.ignored(path) {
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.add(path, indirectValue(a), indirectValue(b))
+			}
+			return
+		}
+		if isNested(a.Type().Elem()) {
+			d.diff(path, a.Elem(), b.Elem())
+			return
+		}
+		if !reflect.DeepEqual(a.Elem().Interface(), b.Elem().Interface()) {
+			d.add(path, a.Elem(), b.Elem())
+		}
+	case reflect.Struct:
+		if a.Type() == timeType {
+			if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+				d.add(path, a, b)
+			}
+			return
+		}
+		d.diffStruct(path, a, b)
+	case reflect.Map:
+		d.diffMap(path, a, b)
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			d.add(path, a, b)
+		}
+	}
+}
+
+func (d *differ) diffStruct(path string, a, b reflect.Value) {
+	for _, f := range fieldsOf(a.Type(), d.opts.tag) {
+		if f.sf.Tag.Get("diff") == "-" {
+			continue
+		}
+		af, bf := a.Field(f.index), b.Field(f.index)
+		if f.inline {
+			af, bf = indirectValue(af), indirectValue(bf)
+			if af.IsValid() && bf.IsValid() {
+				d.diffStruct(path, af, bf)
+				continue
+			}
+			// One side has a nil embedded pointer, compare it as a field.
+			af, bf = a.Field(f.index), b.Field(f.index)
+		}
+		d.diff(joinPath(path, f.name), af, bf)
+	}
+}
+
+func (d *differ) diffMap(path string, a, b reflect.Value) {
+	keys := make(map[string]reflect.Value)
+	for _, m := range []reflect.Value{a, b} {
+		iter := m.MapRange()
+		for iter.Next() {
+			keys[fmt.Sprint(iter.Key().Interface())] = iter.Key()
+		}
+	}
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		key := keys[name]
+		av, bv := a.MapIndex(key), b.MapIndex(key)
+		keyPath := joinPath(path, name)
+		switch {
+		case !av.IsValid() || !bv.IsValid():
+			if !d.ignored(keyPath) {
+				d.add(keyPath, av, bv)
+			}
+		default:
+			d.diff(keyPath, av, bv)
+		}
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// indirectValue follows pointers, an invalid Value for nil.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// derefZero follows pointers, nil ones give the zero value.
+func derefZero(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Zero(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+	return v
+}
@@ -0,0 +1,102 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diffAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip"`
+}
+
+type diffUser struct {
+	Base
+	Name      string            `json:"name" db:"user_name"`
+	Email     string            `json:"email"`
+	Tags      []string          `json:"tags"`
+	Address   diffAddress       `json:"address"`
+	Manager   *diffAddress      `json:"manager"`
+	Nickname  *string           `json:"nickname"`
+	Labels    map[string]string `json:"labels"`
+	Password  string            `json:"-"`
+	Version   int               `json:"version" diff:"-"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func TestDiff(t *testing.T) {
+	nick := "MDGSF"
+	created := time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC)
+	before := diffUser{
+		Base:      Base{ID: 1, Created: created},
+		Name:      "huangjian",
+		Email:     "a@MDGSF.com",
+		Tags:      []string{"go"},
+		Address:   diffAddress{City: "Hangzhou", Zip: "310000"},
+		Labels:    map[string]string{"env": "dev", "team": "go"},
+		Password:  "old",
+		Version:   1,
+		UpdatedAt: created,
+	}
+	after := before
+	after.Created = created.In(time.FixedZone("CST", 8*3600))
+	after.Email = "b@MDGSF.com"
+	after.Tags = []string{"go", "rust"}
+	after.Address.Zip = "100000"
+	after.Manager = &diffAddress{City: "Beijing"}
+	after.Nickname = &nick
+	after.Labels = map[string]string{"env": "prod", "owner": "huangjian"}
+	after.Password = "new"
+	after.Version = 2
+	after.UpdatedAt = created.Add(time.Hour)
+
+	changes := Diff(before, &after, WithIgnore("updated_at"))
+	assert.Equal(t, []Change{
+		{Path: "email", Old: "a@MDGSF.com", New: "b@MDGSF.com"},
+		{Path: "tags", Old: []string{"go"}, New: []string{"go", "rust"}},
+		{Path: "address.zip", Old: "310000", New: "100000"},
+		{Path: "manager", Old: nil, New: diffAddress{City: "Beijing"}},
+		{Path: "nickname", Old: nil, New: "MDGSF"},
+		{Path: "labels.env", Old: "dev", New: "prod"},
+		{Path: "labels.owner", Old: nil, New: "huangjian"},
+		{Path: "labels.team", Old: "go", New: nil},
+	}, changes, "they should be equal")
+	assert.Equal(t, "email: a@MDGSF.com -> b@MDGSF.com", changes[0].String(), "they should be equal")
+
+	changes = Diff(&before, &after, WithDiffTag("db"), WithIgnore("Address", "Labels", "Tags", "Manager", "Nickname", "Email"))
+	assert.Equal(t, []Change{
+		{Path: "Password", Old: "old", New: "new"},
+		{Path: "UpdatedAt", Old: created, New: created.Add(time.Hour)},
+	}, changes, "they should be equal")
+
+	assert.Equal(t, []Change(nil), Diff(before, before), "they should be equal")
+	var none *diffAddress
+	assert.Equal(t, []Change{
+		{Path: "city", Old: "", New: "Beijing"},
+	}, Diff(none, &diffAddress{City: "Beijing"}), "they should be equal")
+	assert.Panics(t, func() { Diff(before, before.Address) })
+}
@@ -0,0 +1,333 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/MDGSF/utils/uconv"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+/*
+ToMap converts the struct v, or a pointer to one, to a map keyed by the
+field names in tag, DefaultTag when tag is "". Tags work like in
+encoding/json: "-" skips a field, "omitempty" drops zero values and
+embedded structs are inlined. Nested structs, also in slices and maps,
+become nested maps; time.Time is kept as is.
+
+	type User struct {
+		Name  string `json:"name"`
+		Email string `json:"email,omitempty"`
+	}
+	ToMap(User{Name: "huangjian"}, "") // map[name:huangjian]
+*/
+func ToMap(v interface{}, tag string) (map[string]interface{}, error) {
+	if tag == "" {
+		tag = DefaultTag
+	}
+	rv, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	structToMap(rv, tag, m)
+	return m, nil
+}
+
+func structToMap(rv reflect.Value, tag string, m map[string]interface{}) {
+	for _, f := range fieldsOf(rv.Type(), tag) {
+		fv := rv.Field(f.index)
+		if f.inline {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			structToMap(fv, tag, m)
+			continue
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		m[f.name] = toMapValue(fv, tag)
+	}
+}
+
+func toMapValue(v reflect.Value, tag string) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if isNested(indirectType(v.Type())) || v.Kind() == reflect.Interface {
+			return toMapValue(v.Elem(), tag)
+		}
+	case reflect.Struct:
+		if isNested(v.Type()) {
+			m := make(map[string]interface{})
+			structToMap(v, tag, m)
+			return m
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			break
+		}
+		if hasStructs(v.Type().Elem()) {
+			list := make([]interface{}, v.Len())
+			for i := range list {
+				list[i] = toMapValue(v.Index(i), tag)
+			}
+			return list
+		}
+	case reflect.Map:
+		if !v.IsNil() && hasStructs(v.Type().Elem()) {
+			m := make(map[string]interface{}, v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				m[fmt.Sprint(iter.Key().Interface())] = toMapValue(iter.Value(), tag)
+			}
+			return m
+		}
+	}
+	return v.Interface()
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// hasStructs reports whether values of t may hold structs to convert.
+func hasStructs(t reflect.Type) bool {
+	t = indirectType(t)
+	switch t.Kind() {
+	case reflect.Struct:
+		return isNested(t)
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// FromMap sets the fields of the struct pointed to by v from m, keyed by
+// DefaultTag names, see FromMapTag.
+func FromMap(m map[string]interface{}, v interface{}) error {
+	return FromMapTag(m, v, DefaultTag)
+}
+
+/*
+FromMapTag sets the fields of the struct pointed to by v from m, keyed by
+the field names in tag. Keys match exactly or else case-insensitively.
+Only fields present in m are touched, so it applies PATCH-style partial
+updates, also inside nested structs; a nil value resets a field. Values are
+converted as needed with uconv, "42" fills an int, and unknown keys are
+ignored.
+
+	user := User{Name: "huangjian", Email: "huangjian@MDGSF.com"}
+	FromMap(map[string]interface{}{"email": nil}, &user)
+	// user.Email == ""
+*/
+func FromMapTag(m map[string]interface{}, v interface{}, tag string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("%w: FromMap needs a non-nil pointer, got %T", ErrNotStruct, v)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrNotStruct, v)
+	}
+	return mapToStruct(m, rv, tag, "")
+}
+
+func mapToStruct(m map[string]interface{}, rv reflect.Value, tag, path string) error {
+	for _, f := range fieldsOf(rv.Type(), tag) {
+		fv := rv.Field(f.index)
+		if f.inline {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						continue
+					}
+					fv.Set(reflect.New(fv.Type().Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := mapToStruct(m, fv, tag, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw, ok := lookupKey(m, f.name)
+		if !ok {
+			continue
+		}
+		fieldPath := f.name
+		if path != "" {
+			fieldPath = path + "." + f.name
+		}
+		if err := assign(fv, raw, tag, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func lookupKey(m map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for key, v := range m {
+		if strings.EqualFold(key, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// assign sets v from raw, converting where needed.
+func assign(v reflect.Value, raw interface{}, tag, path string) error {
+	if raw == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	rraw := reflect.ValueOf(raw)
+	if rraw.Type().AssignableTo(v.Type()) {
+		v.Set(rraw)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return assign(v.Elem(), raw, tag, path)
+	case reflect.Struct:
+		if sub, ok := raw.(map[string]interface{}); ok && isNested(v.Type()) {
+			return mapToStruct(sub, v, tag, path)
+		}
+	case reflect.Slice:
+		if rraw.Kind() == reflect.Slice || rraw.Kind() == reflect.Array {
+			s := reflect.MakeSlice(v.Type(), rraw.Len(), rraw.Len())
+			for i := 0; i < rraw.Len(); i++ {
+				if err := assign(s.Index(i), rraw.Index(i).Interface(), tag, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	case reflect.Map:
+		if rraw.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+			mv := reflect.MakeMapWithSize(v.Type(), rraw.Len())
+			iter := rraw.MapRange()
+			for iter.Next() {
+				key := fmt.Sprint(iter.Key().Interface())
+				ev := reflect.New(v.Type().Elem()).Elem()
+				if err := assign(ev, iter.Value().Interface(), tag, path+"."+key); err != nil {
+					return err
+				}
+				mv.SetMapIndex(reflect.ValueOf(key).Convert(v.Type().Key()), ev)
+			}
+			v.Set(mv)
+			return nil
+		}
+	}
+
+	if err := convert(v, raw); err != nil {
+		return fmt.Errorf("ustruct: field %s: %w", path, err)
+	}
+	return nil
+}
+
+// convert sets scalar kinds through uconv.
+func convert(v reflect.Value, raw interface{}) error {
+	switch v.Type() {
+	case timeType:
+		t, err := uconv.ToTime(raw)
+		if err == nil {
+			v.Set(reflect.ValueOf(t))
+		}
+		return err
+	case durationType:
+		d, err := uconv.ToDuration(raw)
+		if err == nil {
+			v.SetInt(int64(d))
+		}
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		s, err := uconv.ToString(raw)
+		if err == nil {
+			v.SetString(s)
+		}
+		return err
+	case reflect.Bool:
+		b, err := uconv.ToBool(raw)
+		if err == nil {
+			v.SetBool(b)
+		}
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := uconv.ToInt64(raw)
+		if err == nil && v.OverflowInt(n) {
+			err = fmt.Errorf("%v overflows %s", raw, v.Type())
+		}
+		if err == nil {
+			v.SetInt(n)
+		}
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := uconv.ToInt64(raw)
+		if err == nil && (n < 0 || v.OverflowUint(uint64(n))) {
+			err = fmt.Errorf("%v overflows %s", raw, v.Type())
+		}
+		if err == nil {
+			v.SetUint(uint64(n))
+		}
+		return err
+	case reflect.Float32, reflect.Float64:
+		f, err := uconv.ToFloat(raw)
+		if err == nil {
+			v.SetFloat(f)
+		}
+		return err
+	case reflect.Interface:
+		if reflect.TypeOf(raw).Implements(v.Type()) {
+			v.Set(reflect.ValueOf(raw))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot assign %T to %s", raw, v.Type())
+}
@@ -0,0 +1,145 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Base struct {
+	ID      int       `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type address struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type user struct {
+	Base
+	Name     string            `json:"name" db:"user_name"`
+	Email    string            `json:"email,omitempty"`
+	Age      int               `json:"age"`
+	Admin    bool              `json:"admin,omitempty"`
+	Address  address           `json:"address"`
+	Previous []address         `json:"previous,omitempty"`
+	Manager  *user             `json:"manager,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Timeout  time.Duration     `json:"timeout,omitempty"`
+	Secret   string            `json:"-"`
+	private  string
+}
+
+func TestToMap(t *testing.T) {
+	created := time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC)
+	u := user{
+		Base:     Base{ID: 1, Created: created},
+		Name:     "huangjian",
+		Address:  address{City: "Hangzhou"},
+		Previous: []address{{City: "Beijing", Zip: "100000"}},
+		Manager:  &user{Name: "MDGSF"},
+		Secret:   "secret",
+		private:  "private",
+	}
+
+	m, err := ToMap(&u, "")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, map[string]interface{}{
+		"id":       1,
+		"created":  created,
+		"name":     "huangjian",
+		"age":      0,
+		"address":  map[string]interface{}{"city": "Hangzhou"},
+		"previous": []interface{}{map[string]interface{}{"city": "Beijing", "zip": "100000"}},
+		"manager": map[string]interface{}{
+			"id":      0,
+			"created": time.Time{},
+			"name":    "MDGSF",
+			"age":     0,
+			"address": map[string]interface{}{"city": ""},
+		},
+	}, m, "they should be equal")
+
+	m, err = ToMap(u, "db")
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", m["user_name"], "they should be equal")
+	assert.Equal(t, "secret", m["Secret"], "they should be equal")
+
+	_, err = ToMap(3, "")
+	assert.Equal(t, ErrNotStruct, err, "they should be equal")
+}
+
+func TestFromMap(t *testing.T) {
+	u := user{
+		Name:    "huangjian",
+		Email:   "huangjian@MDGSF.com",
+		Age:     30,
+		Address: address{City: "Hangzhou", Zip: "310000"},
+	}
+
+	err := FromMap(map[string]interface{}{
+		"id":      "7",
+		"created": "2019-08-13T14:10:00Z",
+		"EMAIL":   nil,
+		"age":     31.0,
+		"admin":   "yes",
+		"address": map[string]interface{}{"city": "Beijing"},
+		"previous": []interface{}{
+			map[string]interface{}{"city": "Shanghai"},
+		},
+		"manager": map[string]interface{}{"name": "MDGSF"},
+		"labels":  map[string]interface{}{"team": "go"},
+		"timeout": "1m",
+		"unknown": 1,
+	}, &u)
+	assert.Equal(t, nil, err, "they should be equal")
+
+	assert.Equal(t, 7, u.ID, "they should be equal")
+	assert.Equal(t, time.Date(2019, 8, 13, 14, 10, 0, 0, time.UTC), u.Created, "they should be equal")
+	assert.Equal(t, "huangjian", u.Name, "they should be equal")
+	assert.Equal(t, "", u.Email, "they should be equal")
+	assert.Equal(t, 31, u.Age, "they should be equal")
+	assert.Equal(t, true, u.Admin, "they should be equal")
+	assert.Equal(t, address{City: "Beijing", Zip: "310000"}, u.Address, "they should be equal")
+	assert.Equal(t, []address{{City: "Shanghai"}}, u.Previous, "they should be equal")
+	assert.Equal(t, "MDGSF", u.Manager.Name, "they should be equal")
+	assert.Equal(t, map[string]string{"team": "go"}, u.Labels, "they should be equal")
+	assert.Equal(t, time.Minute, u.Timeout, "they should be equal")
+}
+
+func TestFromMapErrors(t *testing.T) {
+	var u user
+	err := FromMap(map[string]interface{}{"age": "old"}, &u)
+	assert.Equal(t, `ustruct: field age: uconv: cannot convert "old" to int64`, err.Error(), "they should be equal")
+
+	err = FromMap(map[string]interface{}{"previous": []interface{}{map[string]interface{}{"zip": []int{1}}}}, &u)
+	assert.Equal(t, "ustruct: field previous[0].zip: uconv: cannot convert [1] ([]int) to string", err.Error(), "they should be equal")
+
+	err = FromMap(nil, u)
+	assert.Equal(t, true, errors.Is(err, ErrNotStruct), "they should be equal")
+}
@@ -0,0 +1,125 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package ustruct
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrNotStruct is returned when a struct or a pointer to one is expected.
+var ErrNotStruct = errors.New("ustruct: not a struct")
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// DefaultTag names the fields when no tag is given.
+const DefaultTag = "json"
+
+// structField is a field of a struct as seen through a tag.
+type structField struct {
+	name      string
+	index     int
+	omitEmpty bool
+	inline    bool // embedded struct whose fields are promoted
+	sf        reflect.StructField
+}
+
+/*
+fieldsOf lists the exported fields of t named by tag like encoding/json:
+the tag name or the Go name, "-" skips a field, "omitempty" is recorded and
+embedded structs without a tag name are inlined.
+*/
+func fieldsOf(t reflect.Type, tag string) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		value := sf.Tag.Get(tag)
+		if value == "-" {
+			continue
+		}
+		parts := strings.Split(value, ",")
+		name := parts[0]
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		inline := sf.Anonymous && name == "" && ft.Kind() == reflect.Struct
+		if sf.PkgPath != "" && !inline {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		f := structField{name: name, index: i, inline: inline, sf: sf}
+		for _, opt := range parts[1:] {
+			if opt == "omitempty" {
+				f.omitEmpty = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// isNested reports whether t is a struct handled field by field.
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+// isEmptyValue reports the values dropped by omitempty, as encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// structValue follows pointers to a struct value.
+func structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, ErrNotStruct
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, ErrNotStruct
+	}
+	return rv, nil
+}
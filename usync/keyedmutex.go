@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usync
+
+import "sync"
+
+type keyedLock struct {
+	lock sync.Mutex
+	refs int
+}
+
+/*
+KeyedMutex is a set of mutexes addressed by string key, operations on the
+same key are serialized while different keys proceed in parallel. Unused
+keys are freed automatically. The zero KeyedMutex is ready to use.
+*/
+type KeyedMutex struct {
+	lock  sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// Lock locks key, blocking until it is available.
+func (m *KeyedMutex) Lock(key string) {
+	m.ref(key).lock.Lock()
+}
+
+// TryLock locks key if it is not held, returns whether it succeeded.
+func (m *KeyedMutex) TryLock(key string) bool {
+	l := m.ref(key)
+	if l.lock.TryLock() {
+		return true
+	}
+	m.unref(key)
+	return false
+}
+
+// Unlock unlocks key, it panics if key is not locked.
+func (m *KeyedMutex) Unlock(key string) {
+	m.lock.Lock()
+	l, ok := m.locks[key]
+	m.lock.Unlock()
+	if !ok {
+		panic("usync: unlock of unlocked key " + key)
+	}
+	l.lock.Unlock()
+	m.unref(key)
+}
+
+// Do runs fn with key locked.
+func (m *KeyedMutex) Do(key string, fn func()) {
+	m.Lock(key)
+	defer m.Unlock(key)
+	fn()
+}
+
+// Len returns the number of keys locked or waited for.
+func (m *KeyedMutex) Len() int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return len(m.locks)
+}
+
+func (m *KeyedMutex) ref(key string) *keyedLock {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedLock)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.refs++
+	return l
+}
+
+func (m *KeyedMutex) unref(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	l := m.locks[key]
+	l.refs--
+	if l.refs == 0 {
+		delete(m.locks, key)
+	}
+}
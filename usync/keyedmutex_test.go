@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usync
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var m KeyedMutex
+	a, b := 0, 0
+	counters := map[string]*int{"huangjian": &a, "MDGSF": &b}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		for _, key := range []string{"huangjian", "MDGSF"} {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				m.Do(key, func() {
+					// unsynchronized read-modify-write, protected by the key
+					*counters[key]++
+				})
+			}(key)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, 100, a, "they should be equal")
+	assert.Equal(t, 100, b, "they should be equal")
+	assert.Equal(t, 0, m.Len(), "they should be equal")
+}
+
+func TestKeyedMutexTryLock(t *testing.T) {
+	var m KeyedMutex
+	m.Lock("a")
+	assert.Equal(t, false, m.TryLock("a"), "they should be equal")
+	assert.Equal(t, true, m.TryLock("b"), "they should be equal")
+	assert.Equal(t, 2, m.Len(), "they should be equal")
+	m.Unlock("a")
+	m.Unlock("b")
+	assert.Equal(t, 0, m.Len(), "they should be equal")
+
+	defer func() {
+		assert.NotEqual(t, nil, recover(), "they should not be equal")
+	}()
+	m.Unlock("a")
+}
@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usync
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(10)
+	ctx := context.Background()
+	assert.Equal(t, nil, s.Acquire(ctx, 6), "they should be equal")
+	assert.Equal(t, true, s.TryAcquire(4), "they should be equal")
+	assert.Equal(t, false, s.TryAcquire(1), "they should be equal")
+
+	acquired := make(chan struct{})
+	go func() {
+		s.Acquire(ctx, 5)
+		close(acquired)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	s.Release(4)
+	select {
+	case <-acquired:
+		t.Fatal("acquired before enough units were released")
+	case <-time.After(10 * time.Millisecond):
+	}
+	s.Release(1)
+	<-acquired
+
+	s.Release(5)
+	s.Release(5)
+	assert.Equal(t, true, s.TryAcquire(10), "they should be equal")
+}
+
+func TestSemaphoreCancel(t *testing.T) {
+	s := NewSemaphore(2)
+	s.Acquire(context.Background(), 2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, s.Acquire(ctx, 1), "they should be equal")
+	assert.Equal(t, context.DeadlineExceeded, s.Acquire(ctx, 3), "they should be equal")
+
+	s.Release(2)
+	assert.Equal(t, true, s.TryAcquire(2), "they should be equal")
+
+	defer func() {
+		assert.NotEqual(t, nil, recover(), "they should not be equal")
+	}()
+	s.Release(3)
+}
+
+func TestSemaphoreConcurrent(t *testing.T) {
+	s := NewSemaphore(3)
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Acquire(context.Background(), 1)
+			defer s.Release(1)
+			n := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, true, maxRunning <= 3, "they should be equal")
+}
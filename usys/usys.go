@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usys
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"time"
+)
+
+// ErrNotSupported is returned on platforms where the information is not
+// available.
+var ErrNotSupported = errors.New("usys: not supported on this platform")
+
+// Memory is the physical memory of the system in bytes.
+type Memory struct {
+	Total uint64 `json:"total"`
+	// Free is the memory available to new allocations without swapping,
+	// MemAvailable on Linux.
+	Free uint64 `json:"free"`
+}
+
+// GetMemory returns the physical memory of the system.
+func GetMemory() (*Memory, error) {
+	return memory()
+}
+
+// DiskUsage is the usage of a file system in bytes.
+type DiskUsage struct {
+	Path  string `json:"path"`
+	Total uint64 `json:"total"`
+	// Free is the space available to unprivileged users.
+	Free uint64 `json:"free"`
+	Used uint64 `json:"used"`
+}
+
+// UsedPercent returns the used space in percent of the space usable by
+// unprivileged users, as df shows it.
+func (d *DiskUsage) UsedPercent() float64 {
+	if d.Used+d.Free == 0 {
+		return 0
+	}
+	return float64(d.Used) / float64(d.Used+d.Free) * 100
+}
+
+// GetDiskUsage returns the usage of the file system holding path.
+func GetDiskUsage(path string) (*DiskUsage, error) {
+	d, err := diskUsage(path)
+	if err != nil {
+		return nil, err
+	}
+	d.Path = path
+	return d, nil
+}
+
+// Uptime returns the time since the system booted.
+func Uptime() (time.Duration, error) {
+	return uptime()
+}
+
+// Hostname returns the host name reported by the kernel.
+func Hostname() (string, error) {
+	return os.Hostname()
+}
+
+// CPUCount returns the number of logical CPUs usable by the process.
+func CPUCount() int {
+	return runtime.NumCPU()
+}
+
+// Info describes the system, for health endpoints and diagnostics logs.
+type Info struct {
+	Hostname  string        `json:"hostname"`
+	OS        string        `json:"os"`
+	Arch      string        `json:"arch"`
+	CPUs      int           `json:"cpus"`
+	GoVersion string        `json:"go_version"`
+	Memory    *Memory       `json:"memory,omitempty"`
+	Disk      *DiskUsage    `json:"disk,omitempty"`
+	Uptime    time.Duration `json:"uptime"`
+}
+
+/*
+GetInfo returns the system information, with the disk usage of the file
+system holding diskPath unless empty. Information not available on the
+platform is left empty rather than failing.
+
+	info := usys.GetInfo("/var/lib/app")
+	log.Infof("host %s, %d cpus, %s free", info.Hostname, info.CPUs, ubytes.Humanize(int64(info.Memory.Free)))
+*/
+func GetInfo(diskPath string) *Info {
+	info := &Info{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		GoVersion: runtime.Version(),
+	}
+	info.Hostname, _ = os.Hostname()
+	info.Memory, _ = memory()
+	if diskPath != "" {
+		info.Disk, _ = GetDiskUsage(diskPath)
+	}
+	info.Uptime, _ = uptime()
+	return info
+}
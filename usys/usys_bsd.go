@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build darwin || freebsd
+// +build darwin freebsd
+
+package usys
+
+import (
+	"encoding/binary"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// sysctlUint64 reads a little endian integer sysctl. syscall.Sysctl
+// drops the last byte when it is zero, hence the padding.
+func sysctlUint64(name string) (uint64, error) {
+	s, err := syscall.Sysctl(name)
+	if err != nil {
+		return 0, err
+	}
+	var buf [8]byte
+	copy(buf[:], s)
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+func memory() (*Memory, error) {
+	totalName, freeName := "hw.memsize", "vm.page_free_count"
+	if runtime.GOOS == "freebsd" {
+		totalName, freeName = "hw.physmem", "vm.stats.vm.v_free_count"
+	}
+	total, err := sysctlUint64(totalName)
+	if err != nil {
+		return nil, err
+	}
+	m := &Memory{Total: total}
+	if pages, err := sysctlUint64(freeName); err == nil {
+		// the free count is a 32 bits value
+		m.Free = (pages & 0xffffffff) * uint64(os.Getpagesize())
+	}
+	return m, nil
+}
+
+func diskUsage(path string) (*DiskUsage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+	bsize := uint64(st.Bsize)
+	avail := int64(st.Bavail)
+	if avail < 0 {
+		avail = 0
+	}
+	return &DiskUsage{
+		Total: uint64(st.Blocks) * bsize,
+		Free:  uint64(avail) * bsize,
+		Used:  (uint64(st.Blocks) - uint64(st.Bfree)) * bsize,
+	}, nil
+}
+
+func uptime() (time.Duration, error) {
+	// kern.boottime is a struct timeval, seconds first.
+	s, err := syscall.Sysctl("kern.boottime")
+	if err != nil {
+		return 0, err
+	}
+	var buf [16]byte
+	copy(buf[:], s)
+	boot := time.Unix(int64(binary.LittleEndian.Uint64(buf[:8])), 0)
+	return time.Since(boot), nil
+}
@@ -0,0 +1,85 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package usys
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMemory(t *testing.T) {
+	m, err := GetMemory()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, m.Total > 0, "they should be equal")
+	assert.Equal(t, true, m.Free > 0 && m.Free <= m.Total, "they should be equal")
+}
+
+func TestGetDiskUsage(t *testing.T) {
+	d, err := GetDiskUsage(os.TempDir())
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, os.TempDir(), d.Path, "they should be equal")
+	assert.Equal(t, true, d.Total > 0, "they should be equal")
+	assert.Equal(t, true, d.Used <= d.Total && d.Free <= d.Total, "they should be equal")
+	pct := d.UsedPercent()
+	assert.Equal(t, true, pct >= 0 && pct <= 100, "they should be equal")
+
+	_, err = GetDiskUsage("/usys/no/such/dir")
+	assert.NotEqual(t, nil, err, "they should not be equal")
+
+	assert.Equal(t, float64(0), (&DiskUsage{}).UsedPercent(), "they should be equal")
+	assert.Equal(t, float64(25), (&DiskUsage{Total: 100, Used: 20, Free: 60}).UsedPercent(), "they should be equal")
+}
+
+func TestUptime(t *testing.T) {
+	up, err := Uptime()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, up > 0, "they should be equal")
+}
+
+func TestGetInfo(t *testing.T) {
+	host, err := Hostname()
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, runtime.NumCPU(), CPUCount(), "they should be equal")
+
+	info := GetInfo(os.TempDir())
+	assert.Equal(t, host, info.Hostname, "they should be equal")
+	assert.Equal(t, runtime.GOOS, info.OS, "they should be equal")
+	assert.Equal(t, runtime.GOARCH, info.Arch, "they should be equal")
+	assert.Equal(t, runtime.NumCPU(), info.CPUs, "they should be equal")
+	assert.NotEqual(t, (*Memory)(nil), info.Memory, "they should not be equal")
+	assert.NotEqual(t, (*DiskUsage)(nil), info.Disk, "they should not be equal")
+
+	info = GetInfo("")
+	assert.Equal(t, (*DiskUsage)(nil), info.Disk, "they should be equal")
+	data, err := json.Marshal(info)
+	assert.Equal(t, nil, err, "they should be equal")
+	var back map[string]interface{}
+	assert.Equal(t, nil, json.Unmarshal(data, &back), "they should be equal")
+	assert.Equal(t, runtime.GOOS, back["os"], "they should be equal")
+	_, hasDisk := back["disk"]
+	assert.Equal(t, false, hasDisk, "they should be equal")
+}
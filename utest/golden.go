@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UpdateFlag names the flag rewriting golden files.
+const UpdateFlag = "update"
+
+func init() {
+	// A test package may define -update itself, it is honored as well.
+	if flag.Lookup(UpdateFlag) == nil {
+		flag.Bool(UpdateFlag, false, "update golden files")
+	}
+}
+
+func updating() bool {
+	f := flag.Lookup(UpdateFlag)
+	return f != nil && f.Value.String() == "true"
+}
+
+/*
+Golden compares got to the content of the golden file path. When the test
+runs with -update, the file is written with got instead, so expected
+outputs are regenerated with:
+
+	go test ./... -update
+
+	func TestRender(t *testing.T) {
+		utest.Golden(t, render(page), "testdata/page.golden")
+	}
+*/
+func Golden(t TB, got []byte, path string) bool {
+	t.Helper()
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Errorf("update golden file: %v", err)
+			return false
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Errorf("update golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("read golden file: %v, run with -%s to create it", err, UpdateFlag)
+		return false
+	}
+	if bytes.Equal(want, got) {
+		return true
+	}
+	t.Errorf("output differs from %s, run with -%s to update it\n%s", path, UpdateFlag, firstDiff(string(want), string(got)))
+	return false
+}
+
+// firstDiff describes the first line differing between want and got.
+func firstDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return fmt.Sprintf("line %d\nwant: %q\n got: %q", i+1, w, g)
+		}
+	}
+	return ""
+}
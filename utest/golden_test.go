@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGolden(t *testing.T) {
+	f := &fakeT{}
+	assert.Equal(t, true, Golden(f, []byte("huangjian\nMDGSF\n"), "testdata/hello.golden"), "they should be equal")
+	assert.Equal(t, false, Golden(f, []byte("huangjian\nmdgsf\n"), "testdata/hello.golden"), "they should be equal")
+	assert.Equal(t, true, strings.HasSuffix(f.errors[0], "line 2\nwant: \"MDGSF\"\n got: \"mdgsf\""), "they should be equal")
+	assert.Equal(t, false, Golden(f, nil, "testdata/missing.golden"), "they should be equal")
+	assert.Equal(t, 2, len(f.errors), "they should be equal")
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "utest")
+	assert.Equal(t, nil, err, "they should be equal")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sub", "x.golden")
+
+	flag.Set(UpdateFlag, "true")
+	defer flag.Set(UpdateFlag, "false")
+	f := &fakeT{}
+	assert.Equal(t, true, Golden(f, []byte("MDGSF"), path), "they should be equal")
+	data, err := ioutil.ReadFile(path)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "MDGSF", string(data), "they should be equal")
+}
+
+func TestFirstDiff(t *testing.T) {
+	assert.Equal(t, "line 3\nwant: \"\"\n got: \"c\"", firstDiff("a\nb", "a\nb\nc"), "they should be equal")
+	assert.Equal(t, "", firstDiff("a", "a"), "they should be equal")
+}
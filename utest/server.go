@@ -0,0 +1,207 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Response is a canned response of a JSONServer.
+type Response struct {
+	// Status defaults to 200.
+	Status int
+	// Body is encoded as JSON, except []byte and string written as they
+	// are. A nil Body writes no body.
+	Body   interface{}
+	Header http.Header
+	// Delay is waited before responding, to test timeouts.
+	Delay time.Duration
+}
+
+// Routes maps routes to responses. A route is a method and a path like
+// "POST /users", or a path alone matching all methods.
+type Routes map[string]Response
+
+// RecordedRequest is a request received by a JSONServer.
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodes the body of r into v.
+func (r RecordedRequest) JSON(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// JSONServer is an httptest.Server answering canned responses and
+// recording the requests it receives.
+type JSONServer struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	routes   map[string][]Response
+	served   map[string]int
+	requests []RecordedRequest
+}
+
+/*
+NewJSONServer starts a server answering routes, other requests get a 404.
+Close it when done.
+
+	srv := utest.NewJSONServer(utest.Routes{
+		"GET /users/1": {Body: User{Name: "huangjian"}},
+		"POST /users":  {Status: http.StatusCreated},
+	})
+	defer srv.Close()
+	... call srv.URL + "/users/1" ...
+	req, _ := srv.LastRequest()
+*/
+func NewJSONServer(routes Routes) *JSONServer {
+	s := &JSONServer{
+		routes: make(map[string][]Response, len(routes)),
+		served: make(map[string]int),
+	}
+	for route, resp := range routes {
+		s.routes[route] = []Response{resp}
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Handle sets the responses of route, given in turn to successive
+// requests and the last one repeated, e.g. a 503 then a 200 to test
+// retries.
+func (s *JSONServer) Handle(route string, resps ...Response) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.routes[route] = resps
+	delete(s.served, route)
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *JSONServer) Requests() []RecordedRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// LastRequest returns the last request received, false if there is none.
+func (s *JSONServer) LastRequest() (RecordedRequest, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if len(s.requests) == 0 {
+		return RecordedRequest{}, false
+	}
+	return s.requests[len(s.requests)-1], true
+}
+
+// Reset forgets the requests received and restarts response sequences.
+func (s *JSONServer) Reset() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests = nil
+	s.served = make(map[string]int)
+}
+
+// response records r and returns its response.
+func (s *JSONServer) response(r *http.Request, body []byte) (Response, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	route := r.Method + " " + r.URL.Path
+	resps, ok := s.routes[route]
+	if !ok {
+		route = r.URL.Path
+		resps, ok = s.routes[route]
+	}
+	if !ok || len(resps) == 0 {
+		return Response{}, false
+	}
+	i := s.served[route]
+	s.served[route]++
+	if i >= len(resps) {
+		i = len(resps) - 1
+	}
+	return resps[i], true
+}
+
+func (s *JSONServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	resp, ok := s.response(r, body)
+	if !ok {
+		resp = Response{
+			Status: http.StatusNotFound,
+			Body:   map[string]string{"error": fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path)},
+		}
+	}
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	var data []byte
+	switch b := resp.Body.(type) {
+	case nil:
+	case []byte:
+		data = b
+	case string:
+		data = []byte(b)
+	default:
+		var err error
+		if data, err = json.Marshal(b); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+	}
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
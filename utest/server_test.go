@@ -0,0 +1,116 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, method, url, body string) (*http.Response, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.Equal(t, nil, err, "they should be equal")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Equal(t, nil, err, "they should be equal")
+	defer resp.Body.Close()
+	data, _ := ioutil.ReadAll(resp.Body)
+	return resp, string(data)
+}
+
+func TestJSONServer(t *testing.T) {
+	srv := NewJSONServer(Routes{
+		"GET /users/1": {Body: map[string]string{"name": "huangjian"}},
+		"POST /users":  {Status: http.StatusCreated, Header: http.Header{"Location": {"/users/2"}}},
+		"/raw":         {Body: "MDGSF"},
+	})
+	defer srv.Close()
+
+	resp, body := get(t, "GET", srv.URL+"/users/1", "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, "they should be equal")
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"), "they should be equal")
+	assert.Equal(t, `{"name":"huangjian"}`, body, "they should be equal")
+
+	resp, body = get(t, "POST", srv.URL+"/users?dry=1", `{"name":"MDGSF"}`)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode, "they should be equal")
+	assert.Equal(t, "/users/2", resp.Header.Get("Location"), "they should be equal")
+	assert.Equal(t, "", body, "they should be equal")
+
+	_, body = get(t, "DELETE", srv.URL+"/raw", "")
+	assert.Equal(t, "MDGSF", body, "they should be equal")
+
+	resp, body = get(t, "GET", srv.URL+"/missing", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "they should be equal")
+	assert.Equal(t, `{"error":"no route for GET /missing"}`, body, "they should be equal")
+
+	reqs := srv.Requests()
+	assert.Equal(t, 4, len(reqs), "they should be equal")
+	assert.Equal(t, "POST", reqs[1].Method, "they should be equal")
+	assert.Equal(t, "/users", reqs[1].Path, "they should be equal")
+	assert.Equal(t, "1", reqs[1].Query.Get("dry"), "they should be equal")
+	var user struct{ Name string }
+	assert.Equal(t, nil, reqs[1].JSON(&user), "they should be equal")
+	assert.Equal(t, "MDGSF", user.Name, "they should be equal")
+
+	last, ok := srv.LastRequest()
+	assert.Equal(t, true, ok, "they should be equal")
+	assert.Equal(t, "/missing", last.Path, "they should be equal")
+
+	srv.Reset()
+	_, ok = srv.LastRequest()
+	assert.Equal(t, false, ok, "they should be equal")
+}
+
+func TestJSONServerSequence(t *testing.T) {
+	srv := NewJSONServer(nil)
+	defer srv.Close()
+	srv.Handle("GET /flaky", Response{Status: http.StatusServiceUnavailable}, Response{Body: "ok"})
+
+	resp, _ := get(t, "GET", srv.URL+"/flaky", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "they should be equal")
+	for i := 0; i < 2; i++ {
+		resp, body := get(t, "GET", srv.URL+"/flaky", "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode, "they should be equal")
+		assert.Equal(t, "ok", body, "they should be equal")
+	}
+
+	srv.Reset()
+	resp, _ = get(t, "GET", srv.URL+"/flaky", "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "they should be equal")
+}
+
+func TestJSONServerDelay(t *testing.T) {
+	srv := NewJSONServer(Routes{"/slow": {Delay: time.Second}})
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/slow", nil)
+	_, err := http.DefaultClient.Do(req)
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
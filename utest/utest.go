@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// TB is the part of testing.TB used by the assertions, so they can be
+// tested themselves.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// message formats the optional message of an assertion: a format string
+// with its arguments, or a single value.
+func message(msgAndArgs []interface{}) string {
+	if len(msgAndArgs) == 0 {
+		return ""
+	}
+	if format, ok := msgAndArgs[0].(string); ok {
+		return ": " + fmt.Sprintf(format, msgAndArgs[1:]...)
+	}
+	return ": " + fmt.Sprint(msgAndArgs...)
+}
+
+func equal(want, got interface{}) bool {
+	if want == nil || got == nil {
+		return want == got
+	}
+	if w, ok := want.([]byte); ok {
+		g, ok := got.([]byte)
+		return ok && bytes.Equal(w, g)
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// Equal checks that got deeply equals want, like testify assert.Equal.
+// The types must match too, int 1 and int64 1 are not equal.
+func Equal(t TB, want, got interface{}, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	if equal(want, got) {
+		return true
+	}
+	t.Errorf("not equal%s\nwant: %#v\n got: %#v", message(msgAndArgs), want, got)
+	return false
+}
+
+// NotEqual checks that got does not deeply equal want.
+func NotEqual(t TB, want, got interface{}, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	if !equal(want, got) {
+		return true
+	}
+	t.Errorf("should not be equal%s\n got: %#v", message(msgAndArgs), got)
+	return false
+}
+
+// isNil reports whether v is nil, typed nil pointers, maps, slices,
+// channels and functions included.
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// Nil checks that v is nil.
+func Nil(t TB, v interface{}, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	if isNil(v) {
+		return true
+	}
+	t.Errorf("should be nil%s\n got: %#v", message(msgAndArgs), v)
+	return false
+}
+
+// NotNil checks that v is not nil.
+func NotNil(t TB, v interface{}, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	if !isNil(v) {
+		return true
+	}
+	t.Errorf("should not be nil%s", message(msgAndArgs))
+	return false
+}
+
+// NoError checks that err is nil.
+func NoError(t TB, err error, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	if err == nil {
+		return true
+	}
+	t.Errorf("unexpected error%s\n got: %v", message(msgAndArgs), err)
+	return false
+}
+
+// ErrorIs checks that errors.Is(err, target).
+func ErrorIs(t TB, err, target error, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	if errors.Is(err, target) {
+		return true
+	}
+	t.Errorf("error is not target%s\nwant: %v\n got: %v", message(msgAndArgs), target, err)
+	return false
+}
+
+/*
+Eventually checks that cond returns true within timeout, polling it
+frequently. Use it for results of goroutines instead of time.Sleep.
+
+	utest.Eventually(t, func() bool { return cache.Len() == 0 }, time.Second)
+*/
+func Eventually(t TB, cond func() bool, timeout time.Duration, msgAndArgs ...interface{}) bool {
+	t.Helper()
+	tick := timeout / 50
+	if tick < time.Millisecond {
+		tick = time.Millisecond
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond() {
+			return true
+		}
+		if time.Now().After(deadline) {
+			t.Errorf("condition not met within %v%s", timeout, message(msgAndArgs))
+			return false
+		}
+		time.Sleep(tick)
+	}
+}
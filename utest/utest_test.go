@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT records the failures of assertions.
+type fakeT struct {
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestEqual(t *testing.T) {
+	f := &fakeT{}
+	assert.Equal(t, true, Equal(f, 1, 1), "they should be equal")
+	assert.Equal(t, true, Equal(f, []byte("MDGSF"), []byte("MDGSF")), "they should be equal")
+	assert.Equal(t, true, Equal(f, nil, nil), "they should be equal")
+	assert.Equal(t, true, Equal(f, map[string]int{"a": 1}, map[string]int{"a": 1}), "they should be equal")
+	assert.Equal(t, 0, len(f.errors), "they should be equal")
+
+	assert.Equal(t, false, Equal(f, 1, int64(1)), "they should be equal")
+	assert.Equal(t, false, Equal(f, "huangjian", "MDGSF", "user %d", 1), "they should be equal")
+	assert.Equal(t, "not equal: user 1\nwant: \"huangjian\"\n got: \"MDGSF\"", f.errors[1], "they should be equal")
+
+	assert.Equal(t, true, NotEqual(f, 1, 2), "they should be equal")
+	assert.Equal(t, false, NotEqual(f, 1, 1), "they should be equal")
+	assert.Equal(t, 3, len(f.errors), "they should be equal")
+}
+
+func TestNil(t *testing.T) {
+	f := &fakeT{}
+	var p *int
+	var m map[string]int
+	assert.Equal(t, true, Nil(f, nil), "they should be equal")
+	assert.Equal(t, true, Nil(f, p), "they should be equal")
+	assert.Equal(t, true, Nil(f, m), "they should be equal")
+	assert.Equal(t, false, Nil(f, 0), "they should be equal")
+	assert.Equal(t, true, NotNil(f, 0), "they should be equal")
+	assert.Equal(t, false, NotNil(f, p), "they should be equal")
+	assert.Equal(t, true, NoError(f, nil), "they should be equal")
+	assert.Equal(t, false, NoError(f, io.EOF), "they should be equal")
+	assert.Equal(t, 3, len(f.errors), "they should be equal")
+}
+
+func TestErrorIs(t *testing.T) {
+	f := &fakeT{}
+	assert.Equal(t, true, ErrorIs(f, fmt.Errorf("open: %w", os.ErrNotExist), os.ErrNotExist), "they should be equal")
+	assert.Equal(t, false, ErrorIs(f, io.EOF, os.ErrNotExist), "they should be equal")
+	assert.Equal(t, "error is not target\nwant: file does not exist\n got: EOF", f.errors[0], "they should be equal")
+}
+
+func TestEventually(t *testing.T) {
+	f := &fakeT{}
+	var n int32
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		atomic.StoreInt32(&n, 1)
+	}()
+	assert.Equal(t, true, Eventually(f, func() bool { return atomic.LoadInt32(&n) == 1 }, time.Second), "they should be equal")
+
+	start := time.Now()
+	assert.Equal(t, false, Eventually(f, func() bool { return false }, 30*time.Millisecond, "waiting"), "they should be equal")
+	assert.Equal(t, true, time.Since(start) >= 30*time.Millisecond, "they should be equal")
+	assert.Equal(t, true, strings.HasPrefix(f.errors[0], "condition not met within 30ms: waiting"), "they should be equal")
+}
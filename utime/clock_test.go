@@ -0,0 +1,128 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRealClock(t *testing.T) {
+	c := RealClock()
+	start := c.Now()
+	c.Sleep(time.Millisecond)
+	assert.Equal(t, true, c.Since(start) >= time.Millisecond, "they should be equal")
+	<-c.After(time.Millisecond)
+
+	timer := c.NewTimer(time.Hour)
+	assert.Equal(t, true, timer.Reset(time.Millisecond), "they should be equal")
+	<-timer.C()
+	assert.Equal(t, false, timer.Stop(), "they should be equal")
+
+	ticker := c.NewTicker(time.Millisecond)
+	<-ticker.C()
+	ticker.Stop()
+}
+
+func TestFakeClockTimer(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	assert.Equal(t, start, c.Now(), "they should be equal")
+
+	timer := c.NewTimer(time.Minute)
+	c.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired too early")
+	default:
+	}
+	c.Advance(time.Minute)
+	assert.Equal(t, start.Add(time.Minute), <-timer.C(), "they should be equal")
+	assert.Equal(t, start.Add(90*time.Second), c.Now(), "they should be equal")
+	assert.Equal(t, 90*time.Second, c.Since(start), "they should be equal")
+
+	assert.Equal(t, false, timer.Reset(time.Second), "they should be equal")
+	assert.Equal(t, true, timer.Stop(), "they should be equal")
+	assert.Equal(t, 0, c.Waiters(), "they should be equal")
+	c.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+
+	timer.Reset(0)
+	<-timer.C()
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	ticker := c.NewTicker(time.Second)
+
+	c.Advance(time.Second)
+	assert.Equal(t, start.Add(time.Second), <-ticker.C(), "they should be equal")
+	// ticks the receiver misses are dropped
+	c.Advance(5 * time.Second)
+	assert.Equal(t, start.Add(2*time.Second), <-ticker.C(), "they should be equal")
+	select {
+	case <-ticker.C():
+		t.Fatal("missed ticks should be dropped")
+	default:
+	}
+
+	ticker.Reset(time.Minute)
+	c.Advance(time.Minute)
+	assert.Equal(t, start.Add(6*time.Second+time.Minute), <-ticker.C(), "they should be equal")
+	ticker.Stop()
+	assert.Equal(t, 0, c.Waiters(), "they should be equal")
+}
+
+func TestFakeClockOrder(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	late := c.After(2 * time.Second)
+	early := c.After(time.Second)
+	c.Set(start.Add(time.Hour))
+	assert.Equal(t, start.Add(time.Second), <-early, "they should be equal")
+	assert.Equal(t, start.Add(2*time.Second), <-late, "they should be equal")
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	c := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	done := make(chan struct{})
+	go func() {
+		c.Sleep(time.Hour)
+		close(done)
+	}()
+
+	c.BlockUntil(1)
+	c.Advance(time.Hour)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sleep did not return")
+	}
+}
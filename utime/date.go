@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"time"
+)
+
+/*
+The helpers below work in the location of their argument, convert first to
+work in another one:
+
+	utime.BeginOfDay(t.In(loc))
+
+They are built with time.Date instead of adding hours, so they stay right on
+days which are 23 or 25 hours long because of DST.
+*/
+
+// BeginOfDay returns midnight at the start of t's day.
+func BeginOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the last nanosecond of t's day.
+func EndOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
+
+// BeginOfWeek returns the start of t's ISO week, which starts on Monday.
+func BeginOfWeek(t time.Time) time.Time {
+	y, m, d := t.Date()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfWeek returns the last nanosecond of t's ISO week.
+func EndOfWeek(t time.Time) time.Time {
+	return BeginOfWeek(t).AddDate(0, 0, 7).Add(-time.Nanosecond)
+}
+
+// BeginOfMonth returns the start of t's month.
+func BeginOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfMonth returns the last nanosecond of t's month.
+func EndOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m+1, 1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
+
+// BeginOfYear returns the start of t's year.
+func BeginOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfYear returns the last nanosecond of t's year.
+func EndOfYear(t time.Time) time.Time {
+	return time.Date(t.Year()+1, time.January, 1, 0, 0, 0, 0, t.Location()).Add(-time.Nanosecond)
+}
+
+// dayNumber counts calendar days, ignoring the time of day and location.
+func dayNumber(t time.Time) int64 {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
+
+// DaysBetween returns the number of calendar days from a to b, negative if
+// b is before a. Each date is taken in its own location.
+func DaysBetween(a, b time.Time) int {
+	return int(dayNumber(b) - dayNumber(a))
+}
+
+// IsSameDay reports whether a and b fall on the same day in a's location.
+func IsSameDay(a, b time.Time) bool {
+	b = b.In(a.Location())
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// ISOWeekStart returns Monday midnight of the ISO week of year in loc.
+func ISOWeekStart(year, week int, loc *time.Location) time.Time {
+	// January 4th is always in week 1
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	start := BeginOfWeek(jan4)
+	y, m, d := start.Date()
+	return time.Date(y, m, d+(week-1)*7, 0, 0, 0, 0, loc)
+}
+
+// ISOWeeksInYear returns 52 or 53, the number of ISO weeks in year.
+func ISOWeeksInYear(year int) int {
+	// December 28th is always in the last week
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+/*
+EachDay calls fn with midnight of every day from start to end, both
+included, until fn returns false.
+
+	utime.EachDay(from, to, func(day time.Time) bool {
+		fmt.Println(day.Format("2006-01-02"))
+		return true
+	})
+*/
+func EachDay(start, end time.Time, fn func(day time.Time) bool) {
+	y, m, d := start.Date()
+	n := DaysBetween(start, end.In(start.Location()))
+	for i := 0; i <= n; i++ {
+		if !fn(time.Date(y, m, d+i, 0, 0, 0, 0, start.Location())) {
+			return
+		}
+	}
+}
+
+// Days returns midnight of every day from start to end, both included.
+func Days(start, end time.Time) []time.Time {
+	var days []time.Time
+	EachDay(start, end, func(day time.Time) bool {
+		days = append(days, day)
+		return true
+	})
+	return days
+}
+
+// IsWeekend reports whether t is a Saturday or Sunday.
+func IsWeekend(t time.Time) bool {
+	wd := t.Weekday()
+	return wd == time.Saturday || wd == time.Sunday
+}
+
+// AddBusinessDays adds n days skipping weekends, n may be negative. With n
+// 0 a weekend moves to the following Monday.
+func AddBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	y, m, d := t.Date()
+	h, min, sec := t.Clock()
+	for i := 0; n > 0 || IsWeekend(t); {
+		i += step
+		t = time.Date(y, m, d+i, h, min, sec, t.Nanosecond(), t.Location())
+		if !IsWeekend(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// BusinessDaysBetween counts the weekdays from a to b, a included and b
+// excluded. It is negative if b is before a.
+func BusinessDaysBetween(a, b time.Time) int {
+	sign := 1
+	if DaysBetween(a, b) < 0 {
+		a, b, sign = b, a, -1
+	}
+	days := DaysBetween(a, b)
+	count := days / 7 * 5
+	wd := int(a.Weekday())
+	for i := 0; i < days%7; i++ {
+		if w := (wd + i) % 7; w != int(time.Saturday) && w != int(time.Sunday) {
+			count++
+		}
+	}
+	return sign * count
+}
@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestBeginEnd(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2020, 2, 12, 15, 4, 5, 6, time.UTC)
+	assert.Equal(t, date(2020, 2, 12), BeginOfDay(now), "they should be equal")
+	assert.Equal(t, date(2020, 2, 13).Add(-1), EndOfDay(now), "they should be equal")
+	assert.Equal(t, date(2020, 2, 10), BeginOfWeek(now), "they should be equal")
+	assert.Equal(t, date(2020, 2, 17).Add(-1), EndOfWeek(now), "they should be equal")
+	assert.Equal(t, date(2020, 2, 1), BeginOfMonth(now), "they should be equal")
+	assert.Equal(t, date(2020, 3, 1).Add(-1), EndOfMonth(now), "they should be equal")
+	assert.Equal(t, date(2020, 1, 1), BeginOfYear(now), "they should be equal")
+	assert.Equal(t, date(2021, 1, 1).Add(-1), EndOfYear(now), "they should be equal")
+
+	sunday := date(2020, 2, 16)
+	assert.Equal(t, date(2020, 2, 10), BeginOfWeek(sunday), "they should be equal")
+}
+
+func TestDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database")
+	}
+	// clocks go forward on 2020-03-08, the day has 23 hours
+	noon := time.Date(2020, 3, 8, 12, 0, 0, 0, loc)
+	begin, end := BeginOfDay(noon), EndOfDay(noon)
+	assert.Equal(t, 0, begin.Hour(), "they should be equal")
+	assert.Equal(t, 23*time.Hour, end.Sub(begin)+time.Nanosecond, "they should be equal")
+
+	days := Days(time.Date(2020, 3, 7, 10, 0, 0, 0, loc), time.Date(2020, 3, 9, 1, 0, 0, 0, loc))
+	assert.Equal(t, 3, len(days), "they should be equal")
+	for _, day := range days {
+		assert.Equal(t, 0, day.Hour(), "they should be equal")
+	}
+	assert.Equal(t, 1, DaysBetween(time.Date(2020, 3, 7, 23, 0, 0, 0, loc), time.Date(2020, 3, 8, 1, 0, 0, 0, loc)), "they should be equal")
+}
+
+func TestDaysBetween(t *testing.T) {
+	assert.Equal(t, 0, DaysBetween(date(2020, 1, 1), date(2020, 1, 1).Add(23*time.Hour)), "they should be equal")
+	assert.Equal(t, 366, DaysBetween(date(2020, 1, 1), date(2021, 1, 1)), "they should be equal")
+	assert.Equal(t, -31, DaysBetween(date(2020, 2, 1), date(2020, 1, 1)), "they should be equal")
+}
+
+func TestIsSameDay(t *testing.T) {
+	a := time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, true, IsSameDay(a, date(2020, 1, 1)), "they should be equal")
+	assert.Equal(t, false, IsSameDay(a, date(2020, 1, 2)), "they should be equal")
+
+	east := time.FixedZone("east", 8*3600)
+	// the same instant is already the next day east of UTC
+	assert.Equal(t, true, IsSameDay(a, a.In(east)), "they should be equal")
+	assert.Equal(t, false, IsSameDay(a.In(east), a.Add(-12*time.Hour)), "they should be equal")
+}
+
+func TestISOWeek(t *testing.T) {
+	assert.Equal(t, date(2019, 12, 30), ISOWeekStart(2020, 1, time.UTC), "they should be equal")
+	assert.Equal(t, date(2020, 12, 28), ISOWeekStart(2020, 53, time.UTC), "they should be equal")
+	assert.Equal(t, date(2021, 1, 4), ISOWeekStart(2021, 1, time.UTC), "they should be equal")
+	assert.Equal(t, 53, ISOWeeksInYear(2020), "they should be equal")
+	assert.Equal(t, 52, ISOWeeksInYear(2021), "they should be equal")
+
+	year, week := ISOWeekStart(2020, 10, time.UTC).ISOWeek()
+	assert.Equal(t, 2020, year, "they should be equal")
+	assert.Equal(t, 10, week, "they should be equal")
+}
+
+func TestEachDay(t *testing.T) {
+	days := Days(date(2020, 2, 27), date(2020, 3, 2))
+	assert.Equal(t, []time.Time{
+		date(2020, 2, 27), date(2020, 2, 28), date(2020, 2, 29), date(2020, 3, 1), date(2020, 3, 2),
+	}, days, "they should be equal")
+	assert.Equal(t, 0, len(Days(date(2020, 3, 2), date(2020, 3, 1))), "they should be equal")
+
+	count := 0
+	EachDay(date(2020, 1, 1), date(2020, 12, 31), func(day time.Time) bool {
+		count++
+		return count < 3
+	})
+	assert.Equal(t, 3, count, "they should be equal")
+}
+
+func TestBusinessDays(t *testing.T) {
+	friday := time.Date(2020, 2, 14, 9, 30, 0, 0, time.UTC)
+	saturday := date(2020, 2, 15)
+	assert.Equal(t, true, IsWeekend(saturday), "they should be equal")
+	assert.Equal(t, false, IsWeekend(friday), "they should be equal")
+
+	assert.Equal(t, time.Date(2020, 2, 17, 9, 30, 0, 0, time.UTC), AddBusinessDays(friday, 1), "they should be equal")
+	assert.Equal(t, time.Date(2020, 2, 24, 9, 30, 0, 0, time.UTC), AddBusinessDays(friday, 6), "they should be equal")
+	assert.Equal(t, time.Date(2020, 2, 13, 9, 30, 0, 0, time.UTC), AddBusinessDays(friday, -1), "they should be equal")
+	assert.Equal(t, date(2020, 2, 17), AddBusinessDays(saturday, 0), "they should be equal")
+	assert.Equal(t, date(2020, 2, 17), AddBusinessDays(saturday, 1), "they should be equal")
+	assert.Equal(t, date(2020, 2, 14), AddBusinessDays(saturday, -1), "they should be equal")
+
+	assert.Equal(t, 5, BusinessDaysBetween(date(2020, 2, 10), date(2020, 2, 17)), "they should be equal")
+	assert.Equal(t, 1, BusinessDaysBetween(friday, saturday), "they should be equal")
+	assert.Equal(t, 0, BusinessDaysBetween(saturday, date(2020, 2, 17)), "they should be equal")
+	assert.Equal(t, 11, BusinessDaysBetween(date(2020, 2, 3), date(2020, 2, 18)), "they should be equal")
+	assert.Equal(t, -5, BusinessDaysBetween(date(2020, 2, 17), date(2020, 2, 10)), "they should be equal")
+}
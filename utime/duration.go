@@ -0,0 +1,222 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Durations which the time package does not define. A day is always 24
+// hours here, no matter what DST does to the calendar.
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+var humanUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{Day, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+}
+
+/*
+HumanDuration formats d with its two most significant units.
+
+	HumanDuration(2*time.Hour + 3*time.Minute + 4*time.Second) == "2h 3m"
+	HumanDuration(50 * time.Hour) == "2d 2h"
+	HumanDuration(1500 * time.Millisecond) == "1s"
+	HumanDuration(250 * time.Millisecond) == "250ms"
+
+Durations under a second are shown in ms, µs or ns.
+*/
+func HumanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		if d == math.MinInt64 {
+			d++
+		}
+		d = -d
+	}
+
+	switch {
+	case d == 0:
+		return "0s"
+	case d < time.Microsecond:
+		return fmt.Sprintf("%s%dns", sign, int64(d))
+	case d < time.Millisecond:
+		return fmt.Sprintf("%s%dµs", sign, int64(d/time.Microsecond))
+	case d < time.Second:
+		return fmt.Sprintf("%s%dms", sign, int64(d/time.Millisecond))
+	}
+
+	k := 0
+	for d < humanUnits[k].d {
+		k++
+	}
+	parts := []string{fmt.Sprintf("%d%s", d/humanUnits[k].d, humanUnits[k].name)}
+	if k+1 < len(humanUnits) {
+		next := humanUnits[k+1]
+		if n := d % humanUnits[k].d / next.d; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, next.name))
+		}
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+var relativeUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{365 * Day, "year"},
+	{30 * Day, "month"},
+	{Week, "week"},
+	{Day, "day"},
+	{time.Hour, "hour"},
+	{time.Minute, "minute"},
+	{time.Second, "second"},
+}
+
+/*
+RelativeTime describes t relative to now with its most significant unit.
+
+	RelativeTime(now.Add(-3*utime.Day), now) == "3 days ago"
+	RelativeTime(now.Add(time.Hour), now) == "in 1 hour"
+
+Differences under a second are "just now". Months are 30 days and years
+365 days.
+*/
+func RelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Second {
+		return "just now"
+	}
+
+	for _, u := range relativeUnits {
+		n := int64(d / u.d)
+		if n == 0 {
+			continue
+		}
+		s := fmt.Sprintf("%d %s", n, u.name)
+		if n > 1 {
+			s += "s"
+		}
+		if future {
+			return "in " + s
+		}
+		return s + " ago"
+	}
+	return "just now"
+}
+
+// Ago describes t relative to the current time, see RelativeTime.
+func Ago(t time.Time) string {
+	return RelativeTime(t, time.Now())
+}
+
+/*
+ParseDuration is time.ParseDuration which also accepts days "d" and weeks
+"w", which are always 24 hours and 7 days.
+
+	ParseDuration("1d12h") == 36 * time.Hour
+	ParseDuration("2w") == 14 * 24 * time.Hour
+	ParseDuration("-1.5d") == -36 * time.Hour
+*/
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	invalid := fmt.Errorf("utime: invalid duration %q", orig)
+
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, invalid
+	}
+
+	var total time.Duration
+	for s != "" {
+		i := 0
+		for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(s) && !(s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+			j++
+		}
+		number, unit := s[:i], s[i:j]
+		if number == "" || unit == "" {
+			return 0, invalid
+		}
+		s = s[j:]
+
+		var d time.Duration
+		switch unit {
+		case "d", "w":
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return 0, invalid
+			}
+			if unit == "d" {
+				f *= float64(Day)
+			} else {
+				f *= float64(Week)
+			}
+			if f >= math.MaxInt64 {
+				return 0, invalid
+			}
+			d = time.Duration(f)
+		default:
+			var err error
+			if d, err = time.ParseDuration(number + unit); err != nil {
+				return 0, invalid
+			}
+		}
+
+		if total > math.MaxInt64-d {
+			return 0, invalid
+		}
+		total += d
+	}
+
+	if neg {
+		return -total, nil
+	}
+	return total, nil
+}
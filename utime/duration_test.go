@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{42, "42ns"},
+		{42 * time.Microsecond, "42µs"},
+		{250 * time.Millisecond, "250ms"},
+		{1500 * time.Millisecond, "1s"},
+		{90 * time.Second, "1m 30s"},
+		{2*time.Hour + 3*time.Minute + 4*time.Second, "2h 3m"},
+		{2*time.Hour + 4*time.Second, "2h"},
+		{50 * time.Hour, "2d 2h"},
+		{3 * Week, "21d"},
+		{-90 * time.Second, "-1m 30s"},
+		{math.MinInt64, "-106751d 23h"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, HumanDuration(tt.d), "they should be equal")
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now, "just now"},
+		{now.Add(-500 * time.Millisecond), "just now"},
+		{now.Add(-time.Second), "1 second ago"},
+		{now.Add(-3 * Day), "3 days ago"},
+		{now.Add(-3*Day - 5*time.Hour), "3 days ago"},
+		{now.Add(-8 * Day), "1 week ago"},
+		{now.Add(-65 * Day), "2 months ago"},
+		{now.Add(-800 * Day), "2 years ago"},
+		{now.Add(time.Hour), "in 1 hour"},
+		{now.Add(5 * time.Minute), "in 5 minutes"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RelativeTime(tt.t, now), "they should be equal")
+	}
+	assert.Equal(t, "1 minute ago", Ago(time.Now().Add(-90*time.Second)), "they should be equal")
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Duration
+	}{
+		{"0", 0},
+		{"1d12h", 36 * time.Hour},
+		{"2w", 14 * Day},
+		{"-1.5d", -36 * time.Hour},
+		{"+1w2d3h4m5s", Week + 2*Day + 3*time.Hour + 4*time.Minute + 5*time.Second},
+		{"1h30m", 90 * time.Minute},
+		{"300ms", 300 * time.Millisecond},
+		{"1.5h", 90 * time.Minute},
+		{"2us", 2 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		d, err := ParseDuration(tt.s)
+		assert.Equal(t, nil, err, tt.s)
+		assert.Equal(t, tt.want, d, tt.s)
+	}
+
+	for _, s := range []string{"", "-", "d", "1", "1x", "1d-2h", "huangjian", "1..5d", "200000w", "106751d24h"} {
+		_, err := ParseDuration(s)
+		assert.NotEqual(t, nil, err, s)
+	}
+}
@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layouts missing in the time package.
+const (
+	LayoutDateTime      = "2006-01-02 15:04:05"
+	LayoutDate          = "2006-01-02"
+	LayoutTime          = "15:04:05"
+	LayoutISO8601       = "2006-01-02T15:04:05-0700"
+	LayoutISO8601Milli  = "2006-01-02T15:04:05.000-07:00"
+	LayoutCompact       = "20060102150405"
+	LayoutDateTimeMilli = "2006-01-02 15:04:05.000"
+)
+
+// ErrUnknownLayout is returned when no layout matches the value to parse.
+var ErrUnknownLayout = errors.New("utime: no matching layout")
+
+// ParseAnyLayouts are the layouts ParseAny tries, in order.
+var ParseAnyLayouts = []string{
+	time.RFC3339Nano,
+	LayoutISO8601Milli,
+	LayoutISO8601,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999 -07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05.999999999",
+	"2006/01/02",
+	LayoutDate,
+	LayoutCompact,
+	"20060102",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.UnixDate,
+	time.RubyDate,
+	time.ANSIC,
+	time.StampNano,
+	"02 Jan 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"2 January 2006",
+}
+
+// in converts t to loc, a nil loc keeps t's location.
+func in(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+// FormatRFC3339 formats t in loc as "2006-01-02T15:04:05Z07:00".
+func FormatRFC3339(t time.Time, loc *time.Location) string {
+	return in(t, loc).Format(time.RFC3339)
+}
+
+// FormatISO8601 formats t in loc with milliseconds and offset, like
+// "2006-01-02T15:04:05.000+08:00". Unlike RFC3339 UTC is "+00:00".
+func FormatISO8601(t time.Time, loc *time.Location) string {
+	return in(t, loc).Format(LayoutISO8601Milli)
+}
+
+// FormatDateTime formats t in loc as "2006-01-02 15:04:05".
+func FormatDateTime(t time.Time, loc *time.Location) string {
+	return in(t, loc).Format(LayoutDateTime)
+}
+
+// FormatDate formats t in loc as "2006-01-02".
+func FormatDate(t time.Time, loc *time.Location) string {
+	return in(t, loc).Format(LayoutDate)
+}
+
+// UnixMilli returns t as milliseconds since the Unix epoch.
+func UnixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// FromUnixMilli is the inverse of UnixMilli.
+func FromUnixMilli(ms int64) time.Time {
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond))
+}
+
+/*
+Parse parses value with the first matching layout. Values without offset
+are taken in loc, nil means UTC.
+
+	utime.Parse("2020-01-02 03:04:05", loc, utime.LayoutDateTime, utime.LayoutDate)
+*/
+func Parse(value string, loc *time.Location, layouts ...string) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", ErrUnknownLayout, value)
+}
+
+/*
+ParseAny parses value in any of the common formats: the ParseAnyLayouts, and
+Unix timestamps in seconds, milliseconds, microseconds or nanoseconds told
+apart by their number of digits. Values without offset are taken in loc,
+nil means UTC.
+
+	utime.ParseAny("2020-01-02T03:04:05+08:00", nil)
+	utime.ParseAny("1577934245000", nil)
+	utime.ParseAny("Jan 2, 2020", time.Local)
+*/
+func ParseAny(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, ok := parseUnix(value); ok {
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return t, nil
+	}
+	return Parse(value, loc, ParseAnyLayouts...)
+}
+
+func parseUnix(value string) (time.Time, bool) {
+	digits := strings.TrimPrefix(value, "-")
+	if len(digits) == 8 || len(digits) == 14 {
+		// yyyymmdd and yyyymmddhhmmss
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case len(digits) <= 11:
+		return time.Unix(n, 0), true
+	case len(digits) <= 14:
+		return FromUnixMilli(n), true
+	case len(digits) <= 17:
+		return time.Unix(n/1e6, n%1e6*1e3), true
+	default:
+		return time.Unix(0, n), true
+	}
+}
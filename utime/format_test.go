@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	east := time.FixedZone("east", 8*3600)
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+
+	assert.Equal(t, "2020-01-02T03:04:05Z", FormatRFC3339(ts, nil), "they should be equal")
+	assert.Equal(t, "2020-01-02T11:04:05+08:00", FormatRFC3339(ts, east), "they should be equal")
+	assert.Equal(t, "2020-01-02T03:04:05.006+00:00", FormatISO8601(ts, nil), "they should be equal")
+	assert.Equal(t, "2020-01-02T11:04:05.006+08:00", FormatISO8601(ts, east), "they should be equal")
+	assert.Equal(t, "2020-01-02 11:04:05", FormatDateTime(ts, east), "they should be equal")
+	assert.Equal(t, "2020-01-02", FormatDate(ts, nil), "they should be equal")
+
+	assert.Equal(t, int64(1577934245006), UnixMilli(ts), "they should be equal")
+	assert.Equal(t, true, ts.Equal(FromUnixMilli(1577934245006)), "they should be equal")
+}
+
+func TestParse(t *testing.T) {
+	east := time.FixedZone("east", 8*3600)
+	ts, err := Parse("2020-01-02 03:04:05", east, LayoutDate, LayoutDateTime)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, east), ts, "they should be equal")
+
+	_, err = Parse("huangjian", nil, LayoutDate, LayoutDateTime)
+	assert.Equal(t, true, errors.Is(err, ErrUnknownLayout), "they should be equal")
+}
+
+func TestParseAny(t *testing.T) {
+	want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	values := []string{
+		"2020-01-02T03:04:05Z",
+		"2020-01-02T11:04:05+08:00",
+		"2020-01-02T03:04:05.000+00:00",
+		"2020-01-02T03:04:05+0000",
+		"2020-01-02T03:04:05",
+		"2020-01-02 03:04:05",
+		"2020/01/02 03:04:05",
+		"20200102030405",
+		"Thu, 02 Jan 2020 03:04:05 +0000",
+		"Thu Jan  2 03:04:05 2020",
+		"1577934245",
+		"1577934245000",
+		"1577934245000000",
+		"1577934245000000000",
+		" 1577934245 ",
+	}
+	for _, value := range values {
+		ts, err := ParseAny(value, nil)
+		assert.Equal(t, nil, err, value)
+		assert.Equal(t, true, want.Equal(ts), value)
+	}
+
+	dates := []string{"2020-01-02", "2020/01/02", "20200102", "Jan 2, 2020", "January 2, 2020", "2 January 2020", "02 Jan 2020"}
+	for _, value := range dates {
+		ts, err := ParseAny(value, nil)
+		assert.Equal(t, nil, err, value)
+		assert.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), ts, value)
+	}
+
+	east := time.FixedZone("east", 8*3600)
+	ts, err := ParseAny("2020-01-02 11:04:05", east)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, true, want.Equal(ts), "they should be equal")
+	ts, _ = ParseAny("1577934245", east)
+	assert.Equal(t, east, ts.Location(), "they should be equal")
+
+	for _, value := range []string{"", "huangjian", "2020-13-45", "12:00"} {
+		_, err := ParseAny(value, nil)
+		assert.Equal(t, true, errors.Is(err, ErrUnknownLayout), value)
+	}
+}
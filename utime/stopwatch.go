@@ -0,0 +1,169 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+)
+
+// Lap is a named split of a Stopwatch.
+type Lap struct {
+	Name     string
+	Duration time.Duration // since the previous lap
+	Total    time.Duration // since the start
+}
+
+/*
+Stopwatch measures elapsed time with laps, it is safe for concurrent use.
+
+	sw := utime.NewStopwatch()
+	loadUsers()
+	sw.Lap("load users")
+	render()
+	sw.Lap("render")
+	fmt.Println(sw) // load users: 120ms, render: 30ms, total: 150ms
+*/
+type Stopwatch struct {
+	clock Clock
+
+	lock    sync.Mutex
+	running bool
+	start   time.Time     // of the current run
+	elapsed time.Duration // of previous runs
+	lastLap time.Duration // total at the last lap
+	laps    []Lap
+}
+
+// NewStopwatch create a started stopwatch.
+func NewStopwatch() *Stopwatch {
+	return NewStopwatchWithClock(RealClock())
+}
+
+// NewStopwatchWithClock create a started stopwatch reading time from clock.
+func NewStopwatchWithClock(clock Clock) *Stopwatch {
+	sw := &Stopwatch{clock: clock}
+	sw.Start()
+	return sw
+}
+
+// Start starts or resumes the stopwatch.
+func (sw *Stopwatch) Start() {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	if !sw.running {
+		sw.running = true
+		sw.start = sw.clock.Now()
+	}
+}
+
+// Stop pauses the stopwatch, Start resumes it.
+func (sw *Stopwatch) Stop() time.Duration {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	if sw.running {
+		sw.elapsed += sw.clock.Since(sw.start)
+		sw.running = false
+	}
+	return sw.elapsed
+}
+
+// Reset clears the laps and restarts from zero, keeping the running state.
+func (sw *Stopwatch) Reset() {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	sw.elapsed = 0
+	sw.lastLap = 0
+	sw.laps = nil
+	sw.start = sw.clock.Now()
+}
+
+// Elapsed returns the total running time.
+func (sw *Stopwatch) Elapsed() time.Duration {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	return sw.elapsedLocked()
+}
+
+func (sw *Stopwatch) elapsedLocked() time.Duration {
+	if sw.running {
+		return sw.elapsed + sw.clock.Since(sw.start)
+	}
+	return sw.elapsed
+}
+
+// Lap records a split named name and returns the time since the previous
+// one.
+func (sw *Stopwatch) Lap(name string) time.Duration {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	total := sw.elapsedLocked()
+	lap := Lap{Name: name, Duration: total - sw.lastLap, Total: total}
+	sw.lastLap = total
+	sw.laps = append(sw.laps, lap)
+	return lap.Duration
+}
+
+// Laps returns a copy of the recorded laps.
+func (sw *Stopwatch) Laps() []Lap {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	laps := make([]Lap, len(sw.laps))
+	copy(laps, sw.laps)
+	return laps
+}
+
+// String formats the laps and the total.
+func (sw *Stopwatch) String() string {
+	sw.lock.Lock()
+	defer sw.lock.Unlock()
+	parts := make([]string, 0, len(sw.laps)+1)
+	for _, lap := range sw.laps {
+		parts = append(parts, fmt.Sprintf("%s: %v", lap.Name, lap.Duration))
+	}
+	parts = append(parts, fmt.Sprintf("total: %v", sw.elapsedLocked()))
+	return strings.Join(parts, ", ")
+}
+
+/*
+TrackTime logs how long the caller took, at info level. A nil logger means
+the default logger.
+
+	func loadUsers() {
+		defer utime.TrackTime(logger, "load users")()
+		...
+	}
+*/
+func TrackTime(logger *log.Logger, name string) func() {
+	if logger == nil {
+		logger = log.DefaultLog()
+	}
+	start := time.Now()
+	return func() {
+		logger.Info("%s took %v", name, time.Since(start))
+	}
+}
@@ -0,0 +1,74 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utime
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MDGSF/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopwatch(t *testing.T) {
+	c := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	sw := NewStopwatchWithClock(c)
+
+	c.Advance(120 * time.Millisecond)
+	assert.Equal(t, 120*time.Millisecond, sw.Lap("huangjian"), "they should be equal")
+	c.Advance(30 * time.Millisecond)
+	assert.Equal(t, 30*time.Millisecond, sw.Lap("MDGSF"), "they should be equal")
+	assert.Equal(t, []Lap{
+		{"huangjian", 120 * time.Millisecond, 120 * time.Millisecond},
+		{"MDGSF", 30 * time.Millisecond, 150 * time.Millisecond},
+	}, sw.Laps(), "they should be equal")
+	assert.Equal(t, "huangjian: 120ms, MDGSF: 30ms, total: 150ms", sw.String(), "they should be equal")
+
+	assert.Equal(t, 150*time.Millisecond, sw.Stop(), "they should be equal")
+	c.Advance(time.Second)
+	assert.Equal(t, 150*time.Millisecond, sw.Elapsed(), "they should be equal")
+	sw.Start()
+	c.Advance(50 * time.Millisecond)
+	assert.Equal(t, 200*time.Millisecond, sw.Elapsed(), "they should be equal")
+	assert.Equal(t, 50*time.Millisecond, sw.Lap("resumed"), "they should be equal")
+
+	sw.Reset()
+	assert.Equal(t, time.Duration(0), sw.Elapsed(), "they should be equal")
+	assert.Equal(t, 0, len(sw.Laps()), "they should be equal")
+	c.Advance(time.Second)
+	assert.Equal(t, time.Second, sw.Elapsed(), "they should be equal")
+}
+
+func TestTrackTime(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewDefaultLog()
+	logger.SetOutput(&buf)
+
+	func() {
+		defer TrackTime(logger, "load users")()
+		time.Sleep(time.Millisecond)
+	}()
+	assert.Equal(t, true, strings.Contains(buf.String(), "load users took "), "they should be equal")
+}
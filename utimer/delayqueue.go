@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utimer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+DelayQueue holds items which become available once their delay elapsed,
+e.g. jobs to retry or keys to expire. It is backed by a TimingWheel so
+millions of pending items cost O(1) each to add.
+*/
+type DelayQueue[T any] struct {
+	wheel *TimingWheel
+
+	lock    sync.Mutex
+	ready   []T
+	pending int
+	notify  chan struct{}
+}
+
+// NewDelayQueue create a queue scheduled on wheel, the wheel must be started.
+func NewDelayQueue[T any](wheel *TimingWheel) *DelayQueue[T] {
+	return &DelayQueue[T]{wheel: wheel, notify: make(chan struct{})}
+}
+
+// Put adds item which becomes available after delay. Pass the returned
+// Timer to Cancel to drop the item.
+func (q *DelayQueue[T]) Put(item T, delay time.Duration) *Timer {
+	q.lock.Lock()
+	q.pending++
+	q.lock.Unlock()
+
+	return q.wheel.AfterFunc(delay, func() {
+		q.lock.Lock()
+		q.pending--
+		q.ready = append(q.ready, item)
+		close(q.notify)
+		q.notify = make(chan struct{})
+		q.lock.Unlock()
+	})
+}
+
+// PutAt adds item which becomes available at deadline.
+func (q *DelayQueue[T]) PutAt(item T, deadline time.Time) *Timer {
+	return q.Put(item, time.Until(deadline))
+}
+
+// Cancel stops the timer of an item, returns false if it is already
+// available or was cancelled.
+func (q *DelayQueue[T]) Cancel(t *Timer) bool {
+	if !t.Stop() {
+		return false
+	}
+	q.lock.Lock()
+	q.pending--
+	q.lock.Unlock()
+	return true
+}
+
+// Poll returns an available item without blocking.
+func (q *DelayQueue[T]) Poll() (item T, ok bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.ready) == 0 {
+		return item, false
+	}
+	item = q.ready[0]
+	var zero T
+	q.ready[0] = zero
+	q.ready = q.ready[1:]
+	return item, true
+}
+
+// Take blocks until an item is available or ctx is done.
+func (q *DelayQueue[T]) Take(ctx context.Context) (T, error) {
+	for {
+		q.lock.Lock()
+		notify := q.notify
+		q.lock.Unlock()
+
+		if item, ok := q.Poll(); ok {
+			return item, nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of available items.
+func (q *DelayQueue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.ready)
+}
+
+// Pending returns the number of items still waiting for their deadline.
+func (q *DelayQueue[T]) Pending() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pending
+}
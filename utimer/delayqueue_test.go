@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utimer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelayQueue(t *testing.T) {
+	tw := NewTimingWheel(time.Millisecond, 16)
+	tw.Start()
+	defer tw.Stop()
+
+	q := NewDelayQueue[string](tw)
+	q.Put("MDGSF", 30*time.Millisecond)
+	q.Put("huangjian", 10*time.Millisecond)
+	timer := q.Put("cancelled", 5*time.Millisecond)
+	assert.Equal(t, true, q.Cancel(timer), "they should be equal")
+	assert.Equal(t, 2, q.Pending(), "they should be equal")
+
+	_, ok := q.Poll()
+	assert.Equal(t, false, ok, "they should be equal")
+
+	ctx := context.Background()
+	item, err := q.Take(ctx)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, "huangjian", item, "they should be equal")
+	item, _ = q.Take(ctx)
+	assert.Equal(t, "MDGSF", item, "they should be equal")
+	assert.Equal(t, 0, q.Pending(), "they should be equal")
+
+	q.PutAt("late", time.Now().Add(time.Hour))
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = q.Take(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err, "they should be equal")
+	assert.Equal(t, 0, q.Len(), "they should be equal")
+}
@@ -0,0 +1,209 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utimer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Timer is a timer scheduled on a TimingWheel.
+type Timer struct {
+	expire uint64 // absolute tick
+	fn     func()
+	bucket *list.List
+	elem   *list.Element
+	wheel  *TimingWheel
+}
+
+// Stop cancels the timer, returns false if it already fired or was stopped.
+func (t *Timer) Stop() bool {
+	tw := t.wheel
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if t.bucket == nil {
+		return false
+	}
+	t.bucket.Remove(t.elem)
+	t.bucket, t.elem = nil, nil
+	return true
+}
+
+/*
+TimingWheel is a hierarchical timing wheel. Level 0 has wheelSize buckets of
+one tick each, every higher level has wheelSize buckets spanning a full turn
+of the level below; levels are added as needed for long delays. Adding and
+stopping a timer is O(1) no matter how many timers are pending, which makes
+it suitable for very large numbers of timeouts. Timers fire with tick
+granularity, each callback runs in its own goroutine.
+*/
+type TimingWheel struct {
+	tick      time.Duration
+	wheelSize uint64
+
+	lock   sync.Mutex
+	now    uint64        // ticks since Start
+	levels [][]list.List // levels[level][bucket]
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewTimingWheel create a wheel, call Start to run it.
+func NewTimingWheel(tick time.Duration, wheelSize int) *TimingWheel {
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+	if wheelSize < 2 {
+		wheelSize = 2
+	}
+	tw := &TimingWheel{tick: tick, wheelSize: uint64(wheelSize)}
+	tw.addLevel()
+	return tw
+}
+
+func (tw *TimingWheel) addLevel() {
+	tw.levels = append(tw.levels, make([]list.List, tw.wheelSize))
+}
+
+// Start runs the wheel in a goroutine.
+func (tw *TimingWheel) Start() {
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	if tw.stop != nil {
+		return
+	}
+	tw.stop = make(chan struct{})
+	tw.done = make(chan struct{})
+	go tw.run(tw.stop, tw.done)
+}
+
+// Stop stops the wheel, pending timers do not fire until it is started again.
+func (tw *TimingWheel) Stop() {
+	tw.lock.Lock()
+	stop, done := tw.stop, tw.done
+	tw.stop, tw.done = nil, nil
+	tw.lock.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (tw *TimingWheel) run(stop, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(tw.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			tw.advance()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// AfterFunc calls fn in its own goroutine after d, rounded up to a tick.
+func (tw *TimingWheel) AfterFunc(d time.Duration, fn func()) *Timer {
+	ticks := uint64((d + tw.tick - 1) / tw.tick)
+	if d <= 0 || ticks == 0 {
+		ticks = 1
+	}
+	tw.lock.Lock()
+	defer tw.lock.Unlock()
+	t := &Timer{expire: tw.now + ticks, fn: fn, wheel: tw}
+	tw.add(t)
+	return t
+}
+
+// add puts t into the lowest level whose span covers its delay.
+func (tw *TimingWheel) add(t *Timer) {
+	delta := t.expire - tw.now
+	span := tw.wheelSize
+	unit := uint64(1)
+	level := 0
+	for delta >= span {
+		unit = span
+		span *= tw.wheelSize
+		level++
+		if level == len(tw.levels) {
+			tw.addLevel()
+		}
+	}
+	bucket := &tw.levels[level][(t.expire/unit)%tw.wheelSize]
+	t.bucket = bucket
+	t.elem = bucket.PushBack(t)
+}
+
+// advance moves the wheel forward one tick.
+func (tw *TimingWheel) advance() {
+	tw.lock.Lock()
+	tw.now++
+
+	// cascade higher levels whose bucket boundary was reached, from the
+	// top down so that timers can fall through several levels
+	unit := uint64(1)
+	var units []uint64
+	for level := 1; level < len(tw.levels); level++ {
+		unit *= tw.wheelSize
+		if tw.now%unit != 0 {
+			break
+		}
+		units = append(units, unit)
+	}
+	for level := len(units); level >= 1; level-- {
+		bucket := &tw.levels[level][(tw.now/units[level-1])%tw.wheelSize]
+		tw.cascade(bucket)
+	}
+
+	bucket := &tw.levels[0][tw.now%tw.wheelSize]
+	var expired []func()
+	for e := bucket.Front(); e != nil; {
+		next := e.Next()
+		t := e.Value.(*Timer)
+		if t.expire <= tw.now {
+			bucket.Remove(e)
+			t.bucket, t.elem = nil, nil
+			expired = append(expired, t.fn)
+		}
+		e = next
+	}
+	tw.lock.Unlock()
+
+	for _, fn := range expired {
+		go fn()
+	}
+}
+
+func (tw *TimingWheel) cascade(bucket *list.List) {
+	var timers []*Timer
+	for e := bucket.Front(); e != nil; e = e.Next() {
+		timers = append(timers, e.Value.(*Timer))
+	}
+	bucket.Init()
+	for _, t := range timers {
+		tw.add(t)
+	}
+}
@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utimer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingWheel(t *testing.T) {
+	tw := NewTimingWheel(time.Millisecond, 4)
+	tw.Start()
+	defer tw.Stop()
+
+	var lock sync.Mutex
+	var fired []int
+	var wg sync.WaitGroup
+	start := time.Now()
+	// spans several levels of a 4 slot wheel
+	delays := []int{1, 3, 5, 17, 40, 70}
+	for i := len(delays) - 1; i >= 0; i-- {
+		d := delays[i]
+		wg.Add(1)
+		tw.AfterFunc(time.Duration(d)*time.Millisecond, func() {
+			defer wg.Done()
+			assert.Equal(t, true, time.Since(start) >= time.Duration(d)*time.Millisecond, "they should be equal")
+			lock.Lock()
+			fired = append(fired, d)
+			lock.Unlock()
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, delays, fired, "they should be equal")
+}
+
+func TestTimingWheelTicks(t *testing.T) {
+	// drive the wheel by hand to check every timer fires on its exact tick
+	tw := NewTimingWheel(time.Millisecond, 3)
+	fired := make([]chan struct{}, 101)
+	for d := 1; d <= 100; d++ {
+		ch := make(chan struct{})
+		fired[d] = ch
+		tw.AfterFunc(time.Duration(d)*time.Millisecond, func() { close(ch) })
+	}
+	for i := 1; i <= 100; i++ {
+		tw.advance()
+		select {
+		case <-fired[i]:
+		case <-time.After(time.Second):
+			t.Fatalf("timer %d did not fire on tick %d", i, i)
+		}
+		if i < 100 {
+			select {
+			case <-fired[i+1]:
+				t.Fatalf("timer %d fired early on tick %d", i+1, i)
+			default:
+			}
+		}
+	}
+}
+
+func TestTimerStop(t *testing.T) {
+	tw := NewTimingWheel(time.Millisecond, 8)
+	tw.Start()
+	defer tw.Stop()
+
+	fired := make(chan struct{}, 1)
+	timer := tw.AfterFunc(20*time.Millisecond, func() { fired <- struct{}{} })
+	assert.Equal(t, true, timer.Stop(), "they should be equal")
+	assert.Equal(t, false, timer.Stop(), "they should be equal")
+
+	select {
+	case <-fired:
+		t.Fatal("stopped timer fired")
+	case <-time.After(40 * time.Millisecond):
+	}
+
+	timer = tw.AfterFunc(0, func() { fired <- struct{}{} })
+	<-fired
+	assert.Equal(t, false, timer.Stop(), "they should be equal")
+}
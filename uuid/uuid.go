@@ -0,0 +1,184 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uuid
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UUID RFC 4122 universally unique identifier.
+type UUID [16]byte
+
+// Nil the zero uuid, 00000000-0000-0000-0000-000000000000.
+var Nil UUID
+
+// Predefined namespaces for NewV5, see RFC 4122 Appendix C.
+var (
+	NamespaceDNS  = MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceURL  = MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceOID  = MustParse("6ba7b812-9dad-11d1-80b4-00c04fd430c8")
+	NamespaceX500 = MustParse("6ba7b814-9dad-11d1-80b4-00c04fd430c8")
+)
+
+// NewV4 generate a random uuid, using crypto/rand.
+func NewV4() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(rand.Reader, u[:]); err != nil {
+		return Nil, err
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+// MustNewV4 is like NewV4 but panics if random source fails.
+func MustNewV4() UUID {
+	u, err := NewV4()
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// NewV5 generate a name based uuid using sha1, the same namespace and
+// name always generate the same uuid.
+func NewV5(namespace UUID, name string) UUID {
+	hasher := sha1.New()
+	hasher.Write(namespace[:])
+	hasher.Write([]byte(name))
+
+	var u UUID
+	copy(u[:], hasher.Sum(nil))
+	u.setVersion(5)
+	return u
+}
+
+func (u *UUID) setVersion(version byte) {
+	u[6] = (u[6] & 0x0f) | (version << 4)
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+}
+
+// Version returns the version of u.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// IsNil reports whether u is the Nil uuid.
+func (u UUID) IsNil() bool {
+	return u == Nil
+}
+
+// String returns the canonical form, like "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx".
+func (u UUID) String() string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+/*
+Parse parse uuid from string, following forms are accepted:
+
+	xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+	{xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx}
+	urn:uuid:xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+	xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+*/
+func Parse(s string) (UUID, error) {
+	var u UUID
+	text := s
+	switch {
+	case len(text) == 38 && text[0] == '{' && text[37] == '}':
+		text = text[1:37]
+	case len(text) == 45 && strings.EqualFold(text[:9], "urn:uuid:"):
+		text = text[9:]
+	}
+
+	switch len(text) {
+	case 36:
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return Nil, fmt.Errorf("uuid: invalid format %q", s)
+		}
+		text = text[0:8] + text[9:13] + text[14:18] + text[19:23] + text[24:]
+	case 32:
+	default:
+		return Nil, fmt.Errorf("uuid: invalid length %q", s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(text)); err != nil {
+		return Nil, fmt.Errorf("uuid: invalid format %q", s)
+	}
+	return u, nil
+}
+
+// MustParse is like Parse but panics if s is invalid.
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// IsValid reports whether s can be parsed as uuid.
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// FromBytes create uuid from 16 bytes.
+func FromBytes(b []byte) (UUID, error) {
+	var u UUID
+	if len(b) != len(u) {
+		return Nil, errors.New("uuid: invalid length")
+	}
+	copy(u[:], b)
+	return u, nil
+}
@@ -0,0 +1,108 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var v4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewV4(t *testing.T) {
+	seen := make(map[UUID]bool)
+	for i := 0; i < 1000; i++ {
+		u, err := NewV4()
+		assert.Equal(t, nil, err, "they should be equal")
+		assert.Equal(t, 4, u.Version(), "they should be equal")
+		assert.Equal(t, true, v4Pattern.MatchString(u.String()), u.String())
+		assert.Equal(t, false, seen[u], "they should be equal")
+		seen[u] = true
+	}
+}
+
+func TestNewV5(t *testing.T) {
+	// python: uuid.uuid5(uuid.NAMESPACE_DNS, 'python.org')
+	u := NewV5(NamespaceDNS, "python.org")
+	assert.Equal(t, "886313e1-3b8a-5372-9b90-0c9aee199e5d", u.String(), "they should be equal")
+	assert.Equal(t, 5, u.Version(), "they should be equal")
+	assert.Equal(t, u, NewV5(NamespaceDNS, "python.org"), "they should be equal")
+	assert.NotEqual(t, u, NewV5(NamespaceURL, "python.org"), "they should not be equal")
+}
+
+func TestParse(t *testing.T) {
+	expected := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	inputs := []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b8109dad11d180b400c04fd430c8",
+	}
+	for _, input := range inputs {
+		u, err := Parse(input)
+		assert.Equal(t, nil, err, input)
+		assert.Equal(t, expected, u.String(), input)
+	}
+
+	invalids := []string{
+		"",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810x9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cg",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	}
+	for _, input := range invalids {
+		_, err := Parse(input)
+		assert.NotEqual(t, nil, err, input)
+		assert.Equal(t, false, IsValid(input), input)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type user struct {
+		ID UUID `json:"id"`
+	}
+	u := user{ID: NewV5(NamespaceURL, "huangjian")}
+	data, err := json.Marshal(u)
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, `{"id":"`+u.ID.String()+`"}`, string(data), "they should be equal")
+
+	var u2 user
+	assert.Equal(t, nil, json.Unmarshal(data, &u2), "they should be equal")
+	assert.Equal(t, u, u2, "they should be equal")
+}
+
+func TestNil(t *testing.T) {
+	assert.Equal(t, true, Nil.IsNil(), "they should be equal")
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000", Nil.String(), "they should be equal")
+
+	u, err := FromBytes(make([]byte, 16))
+	assert.Equal(t, nil, err, "they should be equal")
+	assert.Equal(t, Nil, u, "they should be equal")
+	_, err = FromBytes(make([]byte, 15))
+	assert.NotEqual(t, nil, err, "they should not be equal")
+}
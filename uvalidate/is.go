@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/MDGSF/utils/uuid"
+)
+
+var (
+	emailRegexp   = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+$")
+	numericRegexp = regexp.MustCompile(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+)
+
+// IsEmail reports whether s is an email address like "name@example.com".
+// Display names, "Name <name@example.com>", are not accepted.
+func IsEmail(s string) bool {
+	return len(s) <= 254 && emailRegexp.MatchString(s)
+}
+
+// IsURL reports whether s is an absolute URL with a scheme and a host.
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// IsUUID reports whether s is a UUID in the canonical 8-4-4-4-12 form.
+func IsUUID(s string) bool {
+	return uuid.IsValid(s)
+}
+
+/*
+IsPhone reports whether s looks like a phone number: 7 to 15 digits,
+optionally starting with "+", with spaces, dots, dashes and parentheses
+allowed as separators.
+
+	IsPhone("+86 138-0013-8000") == true
+	IsPhone("(555) 123-4567") == true
+*/
+func IsPhone(s string) bool {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "+")
+	digits := 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return false
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+// IsIP reports whether s is an IPv4 or IPv6 address.
+func IsIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// IsIPv4 reports whether s is an IPv4 address in dotted form.
+func IsIPv4(s string) bool {
+	return net.ParseIP(s) != nil && !strings.Contains(s, ":")
+}
+
+// IsIPv6 reports whether s is an IPv6 address.
+func IsIPv6(s string) bool {
+	return net.ParseIP(s) != nil && strings.Contains(s, ":")
+}
+
+// IsAlpha reports whether s is not empty and has only letters.
+func IsAlpha(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(c rune) bool { return !unicode.IsLetter(c) }) < 0
+}
+
+// IsAlphanumeric reports whether s is not empty and has only letters and
+// digits.
+func IsAlphanumeric(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	}) < 0
+}
+
+// IsNumeric reports whether s is a decimal number like "-12" or "3.14".
+func IsNumeric(s string) bool {
+	return numericRegexp.MatchString(s)
+}
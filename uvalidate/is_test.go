@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIs(t *testing.T) {
+	tests := []struct {
+		fn    func(string) bool
+		valid []string
+		bad   []string
+	}{
+		{IsEmail, []string{"huangjian@MDGSF.com", "a.b+c@mail.example.cn"}, []string{"huangjian", "a@b", "Huang <a@b.com>", "a@-b.com"}},
+		{IsURL, []string{"https://github.com/MDGSF/utils", "ftp://host:21"}, []string{"github.com", "http://", "/path"}},
+		{IsUUID, []string{"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}, []string{"6ba7b810-9dad-11d1-80b4", "xyz"}},
+		{IsPhone, []string{"+86 138-0013-8000", "(555) 123-4567", "13800138000"}, []string{"123", "+86 138x", "1234567890123456"}},
+		{IsIP, []string{"10.0.0.1", "::1"}, []string{"10.0.0.256", "localhost"}},
+		{IsIPv4, []string{"192.168.1.1"}, []string{"::ffff:192.168.1.1", "::1"}},
+		{IsIPv6, []string{"::1", "fe80::1"}, []string{"127.0.0.1"}},
+		{IsAlpha, []string{"huangjian", "黄剑"}, []string{"", "MDGSF1"}},
+		{IsAlphanumeric, []string{"MDGSF1"}, []string{"", "a-b"}},
+		{IsNumeric, []string{"12", "-3.14", "+0"}, []string{"", "1e5", "1.", "abc"}},
+	}
+	for i, test := range tests {
+		for _, s := range test.valid {
+			assert.Equal(t, true, test.fn(s), "case %d: %q", i, s)
+		}
+		for _, s := range test.bad {
+			assert.Equal(t, false, test.fn(s), "case %d: %q", i, s)
+		}
+	}
+}
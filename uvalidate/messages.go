@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+/*
+MessagesEN are the English messages, used by default. Templates are
+rendered by ustring.Format with the placeholders ${field}, ${param},
+${value} and ${rule}, the "default" entry is used for rules without a
+message of their own.
+*/
+var MessagesEN = map[string]string{
+	"default":  "${field} is invalid (${rule})",
+	"required": "${field} is required",
+	"min":      "${field} must be at least ${param}",
+	"max":      "${field} must be at most ${param}",
+	"len":      "${field} must have length ${param}",
+	"eq":       "${field} must be equal to ${param}",
+	"ne":       "${field} must not be equal to ${param}",
+	"gt":       "${field} must be greater than ${param}",
+	"gte":      "${field} must be greater than or equal to ${param}",
+	"lt":       "${field} must be less than ${param}",
+	"lte":      "${field} must be less than or equal to ${param}",
+	"oneof":    "${field} must be one of [${param}]",
+	"contains": "${field} must contain ${param}",
+	"email":    "${field} must be a valid email address",
+	"url":      "${field} must be a valid URL",
+	"uuid":     "${field} must be a valid UUID",
+	"phone":    "${field} must be a valid phone number",
+	"ip":       "${field} must be a valid IP address",
+	"ipv4":     "${field} must be a valid IPv4 address",
+	"ipv6":     "${field} must be a valid IPv6 address",
+	"alpha":    "${field} must contain only letters",
+	"alphanum": "${field} must contain only letters and digits",
+	"numeric":  "${field} must be numeric",
+}
+
+// MessagesZH are the Chinese messages.
+var MessagesZH = map[string]string{
+	"default":  "${field}未通过${rule}校验",
+	"required": "${field}为必填字段",
+	"min":      "${field}不能小于${param}",
+	"max":      "${field}不能大于${param}",
+	"len":      "${field}的长度必须为${param}",
+	"eq":       "${field}必须等于${param}",
+	"ne":       "${field}不能等于${param}",
+	"gt":       "${field}必须大于${param}",
+	"gte":      "${field}必须大于或等于${param}",
+	"lt":       "${field}必须小于${param}",
+	"lte":      "${field}必须小于或等于${param}",
+	"oneof":    "${field}必须是[${param}]中的一个",
+	"contains": "${field}必须包含${param}",
+	"email":    "${field}必须是有效的邮箱地址",
+	"url":      "${field}必须是有效的URL",
+	"uuid":     "${field}必须是有效的UUID",
+	"phone":    "${field}必须是有效的电话号码",
+	"ip":       "${field}必须是有效的IP地址",
+	"ipv4":     "${field}必须是有效的IPv4地址",
+	"ipv6":     "${field}必须是有效的IPv6地址",
+	"alpha":    "${field}只能包含字母",
+	"alphanum": "${field}只能包含字母和数字",
+	"numeric":  "${field}必须是数字",
+}
@@ -0,0 +1,156 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Func reports whether value passes a rule, param is the text after "=" in
+// the tag. value is never a pointer, nil pointers skip the rules.
+type Func func(value reflect.Value, param string) bool
+
+func builtinRules() map[string]Func {
+	return map[string]Func{
+		"min":      compareRule(func(c int) bool { return c >= 0 }),
+		"max":      compareRule(func(c int) bool { return c <= 0 }),
+		"len":      compareRule(func(c int) bool { return c == 0 }),
+		"gt":       compareRule(func(c int) bool { return c > 0 }),
+		"gte":      compareRule(func(c int) bool { return c >= 0 }),
+		"lt":       compareRule(func(c int) bool { return c < 0 }),
+		"lte":      compareRule(func(c int) bool { return c <= 0 }),
+		"eq":       equalRule(true),
+		"ne":       equalRule(false),
+		"oneof":    oneOf,
+		"contains": contains,
+		"email":    stringRule(IsEmail),
+		"url":      stringRule(IsURL),
+		"uuid":     stringRule(IsUUID),
+		"phone":    stringRule(IsPhone),
+		"ip":       stringRule(IsIP),
+		"ipv4":     stringRule(IsIPv4),
+		"ipv6":     stringRule(IsIPv6),
+		"alpha":    stringRule(IsAlpha),
+		"alphanum": stringRule(IsAlphanumeric),
+		"numeric":  stringRule(IsNumeric),
+	}
+}
+
+// stringRule applies fn to string values, other kinds fail.
+func stringRule(fn func(string) bool) Func {
+	return func(value reflect.Value, param string) bool {
+		return value.Kind() == reflect.String && fn(value.String())
+	}
+}
+
+/*
+measure returns the number a size rule compares and the param parsed for
+it: the value of numbers, the rune count of strings and the length of
+slices, arrays and maps. Durations take params like "1m30s".
+*/
+func measure(value reflect.Value, param string) (float64, float64, bool) {
+	var n float64
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Type() == durationType {
+			d, err := time.ParseDuration(param)
+			return float64(value.Int()), float64(d), err == nil
+		}
+		n = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = value.Float()
+	case reflect.String:
+		n = float64(utf8.RuneCountInString(value.String()))
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		n = float64(value.Len())
+	default:
+		return 0, 0, false
+	}
+	limit, err := strconv.ParseFloat(param, 64)
+	return n, limit, err == nil
+}
+
+// compareRule passes when ok accepts the sign of measure minus param.
+func compareRule(ok func(c int) bool) Func {
+	return func(value reflect.Value, param string) bool {
+		n, limit, valid := measure(value, param)
+		if !valid {
+			return false
+		}
+		switch {
+		case n < limit:
+			return ok(-1)
+		case n > limit:
+			return ok(1)
+		}
+		return ok(0)
+	}
+}
+
+// equalRule compares strings by content and everything else by measure.
+func equalRule(want bool) Func {
+	return func(value reflect.Value, param string) bool {
+		if value.Kind() == reflect.String {
+			return (value.String() == param) == want
+		}
+		n, limit, valid := measure(value, param)
+		return valid && (n == limit) == want
+	}
+}
+
+// oneOf passes when the value printed is one of the space separated
+// params, "oneof=red green blue".
+func oneOf(value reflect.Value, param string) bool {
+	s := fmt.Sprint(value.Interface())
+	for _, option := range strings.Fields(param) {
+		if s == option {
+			return true
+		}
+	}
+	return false
+}
+
+// contains passes strings containing param and slices with an element
+// printed as param.
+func contains(value reflect.Value, param string) bool {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.Contains(value.String(), param)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if fmt.Sprint(value.Index(i).Interface()) == param {
+				return true
+			}
+		}
+	}
+	return false
+}
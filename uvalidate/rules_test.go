@@ -0,0 +1,55 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareRules(t *testing.T) {
+	rules := builtinRules()
+	check := func(rule string, value interface{}, param string) bool {
+		return rules[rule](reflect.ValueOf(value), param)
+	}
+
+	assert.Equal(t, true, check("min", 3, "3"), "they should be equal")
+	assert.Equal(t, false, check("min", 2.5, "3"), "they should be equal")
+	assert.Equal(t, true, check("max", uint8(3), "3"), "they should be equal")
+	assert.Equal(t, true, check("len", "黄剑", "2"), "they should be equal")
+	assert.Equal(t, true, check("len", map[string]int{"a": 1}, "1"), "they should be equal")
+	assert.Equal(t, false, check("gt", 3, "3"), "they should be equal")
+	assert.Equal(t, true, check("lt", []int{1}, "2"), "they should be equal")
+	assert.Equal(t, true, check("gte", time.Minute, "1m"), "they should be equal")
+	assert.Equal(t, false, check("min", 3, "abc"), "they should be equal")
+	assert.Equal(t, false, check("min", struct{}{}, "1"), "they should be equal")
+
+	assert.Equal(t, true, check("eq", "huangjian", "huangjian"), "they should be equal")
+	assert.Equal(t, true, check("ne", 3, "4"), "they should be equal")
+	assert.Equal(t, true, check("oneof", 2, "1 2 3"), "they should be equal")
+	assert.Equal(t, true, check("contains", []string{"a", "b"}, "b"), "they should be equal")
+	assert.Equal(t, false, check("email", 3, ""), "they should be equal")
+}
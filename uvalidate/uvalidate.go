@@ -0,0 +1,445 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/ustring"
+)
+
+var (
+	// ErrUnknownRule is returned for a tag naming a rule which is not
+	// registered.
+	ErrUnknownRule = errors.New("uvalidate: unknown rule")
+
+	// ErrNotStruct is returned when Struct is given something else than a
+	// struct or a pointer to one.
+	ErrNotStruct = errors.New("uvalidate: not a struct")
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// FieldError is a field which failed a rule.
+type FieldError struct {
+	Field   string // path from the root, "Users[0].Email"
+	Rule    string
+	Param   string
+	Value   interface{}
+	Message string
+}
+
+// Error implements error.
+func (e *FieldError) Error() string {
+	return e.Message
+}
+
+// Errors is returned by Struct and Var when validation failed.
+type Errors []*FieldError
+
+// Error implements error.
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+type validatorOptions struct {
+	tagName      string
+	fieldNameTag string
+	messages     map[string]string
+}
+
+// Option configures a Validator.
+type Option func(*validatorOptions)
+
+// WithTagName reads the rules from tag instead of "validate".
+func WithTagName(tag string) Option {
+	return func(o *validatorOptions) {
+		o.tagName = tag
+	}
+}
+
+// WithFieldNameTag names fields in errors after a tag, like "json",
+// instead of the Go name.
+func WithFieldNameTag(tag string) Option {
+	return func(o *validatorOptions) {
+		o.fieldNameTag = tag
+	}
+}
+
+// WithMessages replaces the message templates, like MessagesZH. Rules
+// missing in messages fall back to MessagesEN.
+func WithMessages(messages map[string]string) Option {
+	return func(o *validatorOptions) {
+		o.messages = messages
+	}
+}
+
+/*
+Validator checks structs against rules in their field tags:
+
+	type User struct {
+		Name  string   `validate:"required,min=2,max=32"`
+		Email string   `validate:"required,email"`
+		Age   int      `validate:"gte=0,lte=150"`
+		Role  string   `validate:"oneof=admin user"`
+		Tags  []string `validate:"max=5,dive,alphanum"`
+		Phone *string  `validate:"omitempty,phone"`
+	}
+
+Rules are separated by commas and run in order, the first failing one is
+reported. "required" fails zero values, nil pointers and empty strings,
+slices and maps; "omitempty" skips the remaining rules for those. Other
+rules see through pointers and skip nil ones. "dive" applies the rules
+after it to every element of a slice, array or map. Nested structs, also
+inside slices and maps, are validated too; the tag "-" skips a field.
+
+min, max, len, eq, ne, gt, gte, lt and lte compare numbers by value,
+strings by rune count and slices and maps by length, except that eq and ne
+compare strings by content.
+*/
+type Validator struct {
+	opts validatorOptions
+
+	lock     sync.RWMutex
+	rules    map[string]Func
+	messages map[string]string
+}
+
+// New create a Validator with the builtin rules.
+func New(opts ...Option) *Validator {
+	o := validatorOptions{
+		tagName: "validate",
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	v := &Validator{
+		opts:     o,
+		rules:    builtinRules(),
+		messages: make(map[string]string, len(MessagesEN)),
+	}
+	for rule, message := range MessagesEN {
+		v.messages[rule] = message
+	}
+	for rule, message := range o.messages {
+		v.messages[rule] = message
+	}
+	return v
+}
+
+/*
+Register adds the rule name, replacing a builtin one of the same name.
+message is the template of its error message, "" keeps the current one or
+uses the "default" template.
+
+	v.Register("even", func(value reflect.Value, param string) bool {
+		return value.Kind() == reflect.Int && value.Int()%2 == 0
+	}, "${field} must be even")
+*/
+func (v *Validator) Register(name string, fn Func, message string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.rules[name] = fn
+	if message != "" {
+		v.messages[name] = message
+	}
+}
+
+// Struct validates s, a struct or a pointer to one. It returns Errors when
+// fields fail their rules, nil when all pass.
+func (v *Validator) Struct(s interface{}) error {
+	value := indirect(reflect.ValueOf(s))
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrNotStruct, s)
+	}
+
+	var errs Errors
+	if err := v.walk("", value, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Var validates a single value against rules, "required,email". Errors
+// name the field "value".
+func (v *Validator) Var(value interface{}, rules string) error {
+	var errs Errors
+	if err := v.validate("value", reflect.ValueOf(value), parseRules(rules), &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+func parseRules(tag string) []rule {
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		r := rule{name: kv[0]}
+		if len(kv) == 2 {
+			r.param = kv[1]
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// validate runs rules on value and then checks nested structs.
+func (v *Validator) validate(path string, value reflect.Value, rules []rule, errs *Errors) error {
+	for i, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if !hasValue(value) {
+				return nil
+			}
+			continue
+		case "required":
+			if !hasValue(value) {
+				*errs = append(*errs, v.newError(path, r, value))
+				return nil
+			}
+			continue
+		case "dive":
+			return v.dive(path, indirect(value), rules[i+1:], errs)
+		}
+
+		elem := indirect(value)
+		if !elem.IsValid() {
+			return nil
+		}
+
+		v.lock.RLock()
+		fn := v.rules[r.name]
+		v.lock.RUnlock()
+		if fn == nil {
+			return fmt.Errorf("%w %q on %s", ErrUnknownRule, r.name, path)
+		}
+		if !fn(elem, r.param) {
+			*errs = append(*errs, v.newError(path, r, elem))
+			return nil
+		}
+	}
+	return v.walk(path, indirect(value), errs)
+}
+
+func (v *Validator) dive(path string, value reflect.Value, rules []rule, errs *Errors) error {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := v.validate(fmt.Sprintf("%s[%d]", path, i), value.Index(i), rules, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(value) {
+			if err := v.validate(fmt.Sprintf("%s[%v]", path, key.Interface()), value.MapIndex(key), rules, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Invalid:
+	default:
+		return fmt.Errorf("uvalidate: dive on %s of kind %s", path, value.Kind())
+	}
+	return nil
+}
+
+// walk validates the fields of structs and the structs inside slices,
+// arrays and maps.
+func (v *Validator) walk(path string, value reflect.Value, errs *Errors) error {
+	switch value.Kind() {
+	case reflect.Struct:
+		if value.Type() == timeType {
+			return nil
+		}
+		t := value.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.PkgPath != "" {
+				continue
+			}
+			tag := sf.Tag.Get(v.opts.tagName)
+			if tag == "-" {
+				continue
+			}
+
+			fieldPath := path
+			if !sf.Anonymous {
+				fieldPath = joinPath(path, v.fieldName(sf))
+			}
+			if err := v.validate(fieldPath, value.Field(i), parseRules(tag), errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !hasNested(value.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := v.walk(fmt.Sprintf("%s[%d]", path, i), indirect(value.Index(i)), errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !hasNested(value.Type().Elem()) {
+			return nil
+		}
+		for _, key := range sortedKeys(value) {
+			if err := v.walk(fmt.Sprintf("%s[%v]", path, key.Interface()), indirect(value.MapIndex(key)), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *Validator) fieldName(sf reflect.StructField) string {
+	if v.opts.fieldNameTag != "" {
+		name := strings.Split(sf.Tag.Get(v.opts.fieldNameTag), ",")[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+func (v *Validator) newError(path string, r rule, value reflect.Value) *FieldError {
+	var iface interface{}
+	if value.IsValid() && value.CanInterface() {
+		iface = value.Interface()
+	}
+
+	v.lock.RLock()
+	tpl, ok := v.messages[r.name]
+	if !ok {
+		tpl = v.messages["default"]
+	}
+	v.lock.RUnlock()
+
+	return &FieldError{
+		Field: path,
+		Rule:  r.name,
+		Param: r.param,
+		Value: iface,
+		Message: ustring.Format(tpl, map[string]interface{}{
+			"field": path,
+			"param": r.param,
+			"value": iface,
+			"rule":  r.name,
+		}),
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// indirect follows pointers and interfaces, an invalid Value for nil.
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func hasValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Invalid:
+		return false
+	case reflect.Ptr, reflect.Interface:
+		return !value.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return value.Len() > 0
+	}
+	return !value.IsZero()
+}
+
+// hasNested reports whether values of t may contain structs to walk.
+func hasNested(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map in a stable order for errors.
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+var defaultValidator = New()
+
+// Struct validates s with the default Validator.
+func Struct(s interface{}) error {
+	return defaultValidator.Struct(s)
+}
+
+// Var validates value against rules with the default Validator.
+func Var(value interface{}, rules string) error {
+	return defaultValidator.Var(value, rules)
+}
+
+// Register adds a rule to the default Validator.
+func Register(name string, fn Func, message string) {
+	defaultValidator.Register(name, fn, message)
+}
@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uvalidate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type user struct {
+	Name      string            `json:"name" validate:"required,min=2,max=8"`
+	Email     string            `json:"email" validate:"required,email"`
+	Age       int               `json:"age" validate:"gte=0,lte=150"`
+	Role      string            `json:"role" validate:"oneof=admin user"`
+	Tags      []string          `json:"tags" validate:"max=3,dive,alphanum"`
+	Phone     *string           `json:"phone" validate:"omitempty,phone"`
+	Address   Address           `json:"address"`
+	Others    []*Address        `json:"others"`
+	Labels    map[string]string `json:"labels" validate:"dive,required"`
+	Timeout   time.Duration     `json:"timeout" validate:"min=1s"`
+	Ignored   string            `validate:"-"`
+	unexposed string
+}
+
+func validUser() user {
+	return user{
+		Name:    "huangjian",
+		Email:   "huangjian@MDGSF.com",
+		Age:     30,
+		Role:    "admin",
+		Tags:    []string{"go", "rust"},
+		Address: Address{City: "Hangzhou"},
+		Timeout: time.Second,
+	}
+}
+
+func TestStructValid(t *testing.T) {
+	u := validUser()
+	u.Name = "MDGSF"
+	assert.Equal(t, nil, Struct(u), "they should be equal")
+	assert.Equal(t, nil, Struct(&u), "they should be equal")
+}
+
+func TestStructErrors(t *testing.T) {
+	phone := "12ab"
+	u := validUser()
+	u.Email = "huangjian"
+	u.Age = -1
+	u.Role = "root"
+	u.Tags = []string{"go", "c++"}
+	u.Phone = &phone
+	u.Address.City = ""
+	u.Others = []*Address{{City: "Beijing"}, {}}
+	u.Labels = map[string]string{"a": "1", "b": ""}
+	u.Timeout = time.Millisecond
+
+	err := Struct(u)
+	var errs Errors
+	assert.Equal(t, true, errors.As(err, &errs), "they should be equal")
+
+	var got []string
+	for _, fe := range errs {
+		got = append(got, fe.Field+" "+fe.Rule)
+	}
+	assert.Equal(t, []string{
+		"Name max",
+		"Email email",
+		"Age gte",
+		"Role oneof",
+		"Tags[1] alphanum",
+		"Phone phone",
+		"Address.City required",
+		"Others[1].City required",
+		"Labels[b] required",
+		"Timeout min",
+	}, got, "they should be equal")
+	assert.Equal(t, "Name must be at most 8", errs[0].Message, "they should be equal")
+	assert.Equal(t, "8", errs[0].Param, "they should be equal")
+	assert.Equal(t, "huangjian", errs[0].Value, "they should be equal")
+}
+
+func TestMessages(t *testing.T) {
+	v := New(WithMessages(MessagesZH), WithFieldNameTag("json"))
+	u := validUser()
+	u.Name = ""
+	u.Role = "root"
+
+	err := v.Struct(u)
+	assert.Equal(t, "name为必填字段; role必须是[admin user]中的一个", err.Error(), "they should be equal")
+}
+
+func TestRegister(t *testing.T) {
+	v := New()
+	v.Register("even", func(value reflect.Value, param string) bool {
+		return value.Kind() == reflect.Int && value.Int()%2 == 0
+	}, "${field} must be even, got ${value}")
+
+	type number struct {
+		N int `validate:"even"`
+		M int `validate:"odd"`
+	}
+	err := v.Struct(number{N: 3})
+	assert.Equal(t, true, errors.Is(err, ErrUnknownRule), "they should be equal")
+
+	v.Register("odd", func(value reflect.Value, param string) bool {
+		return value.Int()%2 == 1
+	}, "")
+	err = v.Struct(number{N: 3, M: 1})
+	assert.Equal(t, "N must be even, got 3", err.Error(), "they should be equal")
+	err = v.Struct(number{N: 2, M: 2})
+	assert.Equal(t, "M is invalid (odd)", err.Error(), "they should be equal")
+}
+
+func TestVar(t *testing.T) {
+	assert.Equal(t, nil, Var("huangjian@MDGSF.com", "required,email"), "they should be equal")
+	assert.Equal(t, "value is required", Var("", "required,email").Error(), "they should be equal")
+	assert.Equal(t, nil, Var("", "omitempty,email"), "they should be equal")
+	assert.Equal(t, "value must have length 3", Var([]int{1}, "len=3").Error(), "they should be equal")
+	assert.Equal(t, nil, Var(5, "gt=4,lt=6,ne=3"), "they should be equal")
+	assert.Equal(t, nil, Var("abc", "eq=abc,contains=b"), "they should be equal")
+
+	var p *int
+	assert.Equal(t, nil, Var(p, "min=3"), "they should be equal")
+	assert.Equal(t, "value is required", Var(p, "required").Error(), "they should be equal")
+
+	assert.Equal(t, true, errors.Is(Struct(3), ErrNotStruct), "they should be equal")
+}
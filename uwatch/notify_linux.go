@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uwatch
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const notifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY |
+	syscall.IN_ATTRIB | syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_FROM |
+	syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF |
+	syscall.IN_ONLYDIR
+
+// notifier wakes the watcher through inotify when something changes in the
+// watched directories. It does not say what changed, the scan finds out.
+type notifier struct {
+	fd   int // kept apart, file.Fd would make the file blocking
+	file *os.File
+	wake chan struct{}
+
+	lock  sync.Mutex
+	paths map[string]int32
+	wds   map[int32]string
+}
+
+func newNotifier() (*notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	n := &notifier{
+		fd: fd,
+		// a non-blocking fd goes through the runtime poller, so Close
+		// unblocks Read
+		file:  os.NewFile(uintptr(fd), "inotify"),
+		wake:  make(chan struct{}, 1),
+		paths: make(map[string]int32),
+		wds:   make(map[int32]string),
+	}
+	go n.read()
+	return n, nil
+}
+
+// sync watches exactly dirs, it returns whether a watch was added.
+func (n *notifier) sync(dirs map[string]bool) (bool, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	for path, wd := range n.paths {
+		if !dirs[path] {
+			// fails if the kernel already dropped it, e.g. deleted
+			syscall.InotifyRmWatch(n.fd, uint32(wd))
+			delete(n.paths, path)
+			delete(n.wds, wd)
+		}
+	}
+	added := false
+	for path := range dirs {
+		if _, ok := n.paths[path]; ok {
+			continue
+		}
+		wd, err := syscall.InotifyAddWatch(n.fd, path, notifyMask)
+		if err == syscall.ENOENT || err == syscall.ENOTDIR {
+			// gone since the scan, the next one notices
+			continue
+		}
+		if err != nil {
+			return added, err
+		}
+		n.paths[path] = int32(wd)
+		n.wds[int32(wd)] = path
+		added = true
+	}
+	return added, nil
+}
+
+func (n *notifier) read() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		size, err := n.file.Read(buf)
+		if err != nil {
+			return
+		}
+		n.lock.Lock()
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= size; {
+			e := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			if e.Mask&syscall.IN_IGNORED != 0 {
+				// the watch is gone, sync adds it again if still wanted
+				delete(n.paths, n.wds[e.Wd])
+				delete(n.wds, e.Wd)
+			}
+			offset += syscall.SizeofInotifyEvent + int(e.Len)
+		}
+		n.lock.Unlock()
+		n.notify()
+	}
+}
+
+// notify asks for a scan, several requests before it runs are merged.
+func (n *notifier) notify() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (n *notifier) close() {
+	n.file.Close()
+}
@@ -0,0 +1,474 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uwatch
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MDGSF/utils/ufile"
+)
+
+// Op is the kind of a change.
+type Op int
+
+const (
+	// Create means a file or directory appeared.
+	Create Op = iota + 1
+	// Modify means the size or modification time of a file changed.
+	Modify
+	// Delete means a file or directory disappeared.
+	Delete
+	// Rename means a file was moved from OldPath to Path.
+	Rename
+)
+
+func (op Op) String() string {
+	switch op {
+	case Create:
+		return "CREATE"
+	case Modify:
+		return "MODIFY"
+	case Delete:
+		return "DELETE"
+	case Rename:
+		return "RENAME"
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// Event is a change of a watched path.
+type Event struct {
+	Op      Op
+	Path    string
+	OldPath string // set for Rename
+}
+
+func (e Event) String() string {
+	if e.Op == Rename {
+		return fmt.Sprintf("%s %s -> %s", e.Op, e.OldPath, e.Path)
+	}
+	return fmt.Sprintf("%s %s", e.Op, e.Path)
+}
+
+// Default options.
+const (
+	DefaultInterval = 500 * time.Millisecond
+	DefaultDebounce = 100 * time.Millisecond
+)
+
+// ErrClosed is returned when adding a path to a closed watcher.
+var ErrClosed = errors.New("uwatch: watcher closed")
+
+// Options configures a Watcher.
+type Options struct {
+	// Interval is how often watched paths are scanned when polling, and how
+	// often pending events are checked against Debounce.
+	Interval time.Duration
+	// Debounce holds events back until no change was seen for that long,
+	// so a burst of writes is delivered as one event per path.
+	Debounce time.Duration
+	// Recursive watches subdirectories of added directories too.
+	Recursive bool
+	// Include only reports paths matching one of the globs, see
+	// ufile.MatchGlob; a pattern without "/" matches the base name.
+	Include []string
+	// Exclude ignores paths matching one of the globs, for directories
+	// their whole content.
+	Exclude []string
+	// Poll scans every Interval even where file notifications are
+	// available, e.g. for network filesystems which do not deliver them.
+	Poll bool
+}
+
+/*
+Watcher reports changes of files and directories. Events come from diffing
+snapshots of the watched paths. On Linux the watched directories are
+registered with inotify and a snapshot is taken when it reports a change;
+elsewhere, with Options.Poll, or when inotify fails (e.g. out of watches),
+the paths are scanned every Interval instead. A scan costs a stat per file,
+so the watcher suits config and source trees rather than huge directories.
+*/
+type Watcher struct {
+	options Options
+	events  chan Event
+	errors  chan error
+
+	lock      sync.Mutex
+	roots     map[string]bool
+	snapshot  map[string]os.FileInfo
+	pending   map[string]Event
+	order     []string
+	lastEvent time.Time
+	closed    bool
+	notify    *notifier // nil when polling
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New create a Watcher, add paths with Add.
+func New(options Options) *Watcher {
+	if options.Interval <= 0 {
+		options.Interval = DefaultInterval
+	}
+	if options.Debounce < 0 {
+		options.Debounce = 0
+	}
+	w := &Watcher{
+		options:  options,
+		events:   make(chan Event, 64),
+		errors:   make(chan error, 8),
+		roots:    make(map[string]bool),
+		snapshot: make(map[string]os.FileInfo),
+		pending:  make(map[string]Event),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if !options.Poll {
+		if n, err := newNotifier(); err == nil {
+			w.notify = n
+		}
+	}
+	go w.run()
+	return w
+}
+
+// Events returns the channel of events, closed by Close.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Errors returns the channel of scan errors, closed by Close.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Add starts watching path, a file or a directory. Existing content does
+// not produce events.
+func (w *Watcher) Add(path string) error {
+	path = filepath.Clean(path)
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.closed {
+		return ErrClosed
+	}
+	w.roots[path] = true
+	for p, info := range w.scanRoot(path) {
+		w.snapshot[p] = info
+	}
+	if w.notify != nil {
+		w.notify.notify()
+	}
+	return nil
+}
+
+// Remove stops watching path.
+func (w *Watcher) Remove(path string) {
+	path = filepath.Clean(path)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	delete(w.roots, path)
+	for p := range w.snapshot {
+		if p == path || strings.HasPrefix(p, path+string(filepath.Separator)) {
+			delete(w.snapshot, p)
+		}
+	}
+	if w.notify != nil {
+		w.notify.notify()
+	}
+}
+
+// Close stops the watcher and closes the channels, pending events are
+// dropped.
+func (w *Watcher) Close() {
+	w.lock.Lock()
+	if w.closed {
+		w.lock.Unlock()
+		return
+	}
+	w.closed = true
+	w.lock.Unlock()
+	close(w.stop)
+	<-w.done
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	defer close(w.errors)
+	defer close(w.events)
+	defer w.closeNotifier()
+
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+	var wake chan struct{}
+	var flush <-chan time.Time
+	w.lock.Lock()
+	if w.notify != nil {
+		wake = w.notify.wake
+	}
+	w.lock.Unlock()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if wake == nil {
+				w.scan()
+			}
+		case <-wake:
+			w.scan()
+			wake = w.watchDirs()
+		case <-flush:
+		}
+		events, wait := w.due()
+		for _, e := range events {
+			select {
+			case w.events <- e:
+			case <-w.stop:
+				return
+			}
+		}
+		flush = nil
+		if wait > 0 {
+			flush = time.After(wait)
+		}
+	}
+}
+
+/*
+watchDirs points the notifier at the directories of the snapshot and the
+parents of the roots, which see a root being replaced or deleted. It returns
+the channel to wait on for changes, nil if it fell back to polling.
+*/
+func (w *Watcher) watchDirs() chan struct{} {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	dirs := make(map[string]bool)
+	for root := range w.roots {
+		dirs[filepath.Dir(root)] = true
+		if info, ok := w.snapshot[root]; ok && info.IsDir() {
+			dirs[root] = true
+		}
+	}
+	if w.options.Recursive {
+		for p, info := range w.snapshot {
+			if info.IsDir() {
+				dirs[p] = true
+			}
+		}
+	}
+
+	added, err := w.notify.sync(dirs)
+	if err != nil {
+		w.sendError(fmt.Errorf("uwatch: falling back to polling: %w", err))
+		w.notify.close()
+		w.notify = nil
+		return nil
+	}
+	if added {
+		// changes made before the new watches existed are only seen by
+		// another scan
+		w.notify.notify()
+	}
+	return w.notify.wake
+}
+
+func (w *Watcher) closeNotifier() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.notify != nil {
+		w.notify.close()
+		w.notify = nil
+	}
+}
+
+// scanRoot returns the current state of root, must hold lock.
+func (w *Watcher) scanRoot(root string) map[string]os.FileInfo {
+	result := make(map[string]os.FileInfo)
+	info, err := os.Lstat(root)
+	if err != nil {
+		return result
+	}
+	result[root] = info
+	if !info.IsDir() {
+		return result
+	}
+	w.scanDir(root, root, result)
+	return result
+}
+
+func (w *Watcher) scanDir(root, dir string, result map[string]os.FileInfo) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			w.sendError(err)
+		}
+		return
+	}
+	for _, info := range entries {
+		path := filepath.Join(dir, info.Name())
+		rel, _ := filepath.Rel(root, path)
+		if w.excluded(filepath.ToSlash(rel)) {
+			continue
+		}
+		result[path] = info
+		if info.IsDir() && w.options.Recursive {
+			w.scanDir(root, path, result)
+		}
+	}
+}
+
+func (w *Watcher) sendError(err error) {
+	select {
+	case w.errors <- err:
+	default:
+	}
+}
+
+func (w *Watcher) excluded(rel string) bool {
+	return ufile.MatchAny(w.options.Exclude, rel)
+}
+
+func (w *Watcher) included(path string) bool {
+	if len(w.options.Include) == 0 {
+		return true
+	}
+	for root := range w.roots {
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			if ufile.MatchAny(w.options.Include, filepath.ToSlash(rel)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// scan diffs the watched paths against the last snapshot.
+func (w *Watcher) scan() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	current := make(map[string]os.FileInfo)
+	for root := range w.roots {
+		for p, info := range w.scanRoot(root) {
+			current[p] = info
+		}
+	}
+
+	var created, deleted []string
+	for p, info := range current {
+		old, ok := w.snapshot[p]
+		if !ok {
+			created = append(created, p)
+		} else if !info.IsDir() && (info.Size() != old.Size() || !info.ModTime().Equal(old.ModTime())) {
+			w.record(Event{Op: Modify, Path: p})
+		}
+	}
+	for p := range w.snapshot {
+		if _, ok := current[p]; !ok {
+			deleted = append(deleted, p)
+		}
+	}
+	sort.Strings(created)
+	sort.Strings(deleted)
+
+	// a file deleted and created in the same scan is a rename
+	renamedFrom := make(map[string]bool)
+	for _, p := range created {
+		info := current[p]
+		op := Event{Op: Create, Path: p}
+		if !info.IsDir() {
+			for _, old := range deleted {
+				if !renamedFrom[old] && os.SameFile(w.snapshot[old], info) {
+					renamedFrom[old] = true
+					op = Event{Op: Rename, Path: p, OldPath: old}
+					break
+				}
+			}
+		}
+		w.record(op)
+	}
+	for _, p := range deleted {
+		if !renamedFrom[p] {
+			w.record(Event{Op: Delete, Path: p})
+		}
+	}
+	w.snapshot = current
+}
+
+// record merges e into the pending events, must hold lock.
+func (w *Watcher) record(e Event) {
+	if !w.included(e.Path) {
+		return
+	}
+	w.lastEvent = time.Now()
+	prev, ok := w.pending[e.Path]
+	if !ok {
+		w.pending[e.Path] = e
+		w.order = append(w.order, e.Path)
+		return
+	}
+	switch {
+	case prev.Op == Create && e.Op == Modify, prev.Op == Rename && e.Op == Modify:
+		// still a new file
+	case prev.Op == Create && e.Op == Delete:
+		delete(w.pending, e.Path)
+	case prev.Op == Delete && (e.Op == Create || e.Op == Rename):
+		w.pending[e.Path] = Event{Op: Modify, Path: e.Path}
+	default:
+		w.pending[e.Path] = e
+	}
+}
+
+// due returns the pending events if the debounce period passed, otherwise
+// how long until it does.
+func (w *Watcher) due() ([]Event, time.Duration) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if len(w.order) == 0 {
+		return nil, 0
+	}
+	if wait := w.options.Debounce - time.Since(w.lastEvent); wait > 0 {
+		return nil, wait
+	}
+	var events []Event
+	for _, p := range w.order {
+		if e, ok := w.pending[p]; ok {
+			events = append(events, e)
+			delete(w.pending, p)
+		}
+	}
+	w.order = w.order[:0]
+	return events, 0
+}
@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2019 Huang Jian
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package uwatch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "uwatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// collect reads events until none arrived for quiet.
+func collect(w *Watcher, quiet time.Duration) []string {
+	var events []string
+	for {
+		select {
+		case e := <-w.Events():
+			events = append(events, e.String())
+		case <-time.After(quiet):
+			sort.Strings(events)
+			return events
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644)
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+
+	w := New(Options{Interval: 5 * time.Millisecond, Debounce: 20 * time.Millisecond, Recursive: true})
+	defer w.Close()
+	assert.Equal(t, nil, w.Add(dir), "they should be equal")
+	assert.NotEqual(t, nil, w.Add(filepath.Join(dir, "none")), "they should not be equal")
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	// a burst of writes is one event
+	for i := 0; i < 5; i++ {
+		ioutil.WriteFile(path("a.txt"), []byte("huangjian"[:i+1]), 0644)
+		time.Sleep(3 * time.Millisecond)
+	}
+	ioutil.WriteFile(path("sub/c.txt"), []byte("c"), 0644)
+	os.Remove(path("b.txt"))
+	assert.Equal(t, []string{
+		"CREATE " + path("sub/c.txt"),
+		"DELETE " + path("b.txt"),
+		"MODIFY " + path("a.txt"),
+	}, collect(w, 100*time.Millisecond), "they should be equal")
+
+	os.Rename(path("sub/c.txt"), path("d.txt"))
+	assert.Equal(t, []string{
+		"RENAME " + path("sub/c.txt") + " -> " + path("d.txt"),
+	}, collect(w, 100*time.Millisecond), "they should be equal")
+
+	// created and deleted within the debounce window: nothing
+	ioutil.WriteFile(path("tmp"), []byte("x"), 0644)
+	time.Sleep(10 * time.Millisecond)
+	os.Remove(path("tmp"))
+	assert.Equal(t, 0, len(collect(w, 100*time.Millisecond)), "they should be equal")
+}
+
+func TestWatcherFilters(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	os.MkdirAll(filepath.Join(dir, "node_modules"), 0755)
+
+	w := New(Options{
+		Interval:  5 * time.Millisecond,
+		Recursive: true,
+		Include:   []string{"*.go"},
+		Exclude:   []string{"node_modules"},
+	})
+	defer w.Close()
+	w.Add(dir)
+
+	ioutil.WriteFile(filepath.Join(dir, "main.go"), nil, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "README.md"), nil, 0644)
+	ioutil.WriteFile(filepath.Join(dir, "node_modules", "x.go"), nil, 0644)
+	assert.Equal(t, []string{
+		"CREATE " + filepath.Join(dir, "main.go"),
+	}, collect(w, 100*time.Millisecond), "they should be equal")
+}
+
+func TestWatcherFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "config.json")
+	ioutil.WriteFile(file, []byte("{}"), 0644)
+
+	w := New(Options{Interval: 5 * time.Millisecond})
+	w.Add(file)
+	ioutil.WriteFile(filepath.Join(dir, "other"), nil, 0644)
+	ioutil.WriteFile(file, []byte(`{"name": "MDGSF"}`), 0644)
+	assert.Equal(t, []string{"MODIFY " + file}, collect(w, 100*time.Millisecond), "they should be equal")
+
+	w.Remove(file)
+	ioutil.WriteFile(file, []byte("{}"), 0644)
+	assert.Equal(t, 0, len(collect(w, 50*time.Millisecond)), "they should be equal")
+
+	w.Close()
+	_, ok := <-w.Events()
+	assert.Equal(t, false, ok, "they should be equal")
+	assert.Equal(t, ErrClosed, w.Add(file), "they should be equal")
+}
+
+func TestWatcherNotify(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("file notifications are only used on linux")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, "sub"), 0755)
+
+	// nothing is scanned by the interval, events come from inotify
+	w := New(Options{Interval: time.Hour, Debounce: 20 * time.Millisecond, Recursive: true})
+	defer w.Close()
+	assert.Equal(t, nil, w.Add(dir), "they should be equal")
+	time.Sleep(20 * time.Millisecond)
+
+	ioutil.WriteFile(filepath.Join(dir, "sub", "a.txt"), []byte("huangjian"), 0644)
+	assert.Equal(t, []string{
+		"CREATE " + filepath.Join(dir, "sub", "a.txt"),
+	}, collect(w, 100*time.Millisecond), "they should be equal")
+
+	// a new directory is watched too
+	os.Mkdir(filepath.Join(dir, "new"), 0755)
+	time.Sleep(20 * time.Millisecond)
+	ioutil.WriteFile(filepath.Join(dir, "new", "b.txt"), []byte("MDGSF"), 0644)
+	assert.Equal(t, []string{
+		"CREATE " + filepath.Join(dir, "new"),
+		"CREATE " + filepath.Join(dir, "new", "b.txt"),
+	}, collect(w, 100*time.Millisecond), "they should be equal")
+}
+
+func TestWatcherPoll(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	w := New(Options{Interval: 5 * time.Millisecond, Poll: true})
+	defer w.Close()
+	assert.Equal(t, nil, w.Add(dir), "they should be equal")
+	ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("huangjian"), 0644)
+	assert.Equal(t, []string{
+		"CREATE " + filepath.Join(dir, "a.txt"),
+	}, collect(w, 100*time.Millisecond), "they should be equal")
+}